@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/github/github-mcp-server/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the config file",
+	}
+
+	validateConfigCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the config file",
+		Long:  `Check the config file given with --config, or else the user and repository config files found, for syntax errors, unknown keys, values of the wrong type, keys a repository config file may not set and settings that cannot work together, and report every problem found.`,
+		Args:  cobra.NoArgs,
+		// A failed validation is not a usage error
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			files, err := config.Find(configFile)
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				return fmt.Errorf("no config file found; pass --config or create .mcp-prime.yaml at the repository root or mcp-prime/config.yaml in your XDG config directory")
+			}
+
+			for _, file := range files {
+				errs := config.Validate(file, configKeys(cmd.Root().PersistentFlags()))
+				if len(errs) > 0 {
+					var b strings.Builder
+					for _, err := range errs {
+						fmt.Fprintf(&b, "\n  %v", err)
+					}
+					return fmt.Errorf("%s is invalid:%s", file.Path, b.String())
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "OK: %s\n", file.Path)
+			}
+			return nil
+		},
+	}
+)
+
+func init() {
+	configCmd.AddCommand(validateConfigCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// configKeys maps the config file keys that have a flag to the flag.
+func configKeys(flags *pflag.FlagSet) map[string]*pflag.Flag {
+	keys := make(map[string]*pflag.Flag)
+	flags.VisitAll(func(f *pflag.Flag) {
+		switch f.Name {
+		case "config":
+		case "gh-host":
+			keys["host"] = f
+		case "tools":
+			// tools holds the per-tool settings in the file
+			keys["include-tools"] = f
+		default:
+			keys[f.Name] = f
+		}
+	})
+	return keys
+}
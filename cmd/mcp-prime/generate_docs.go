@@ -2,33 +2,46 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 
+	"github.com/github/github-mcp-server/pkg/budget"
 	"github.com/github/github-mcp-server/pkg/github"
 	"github.com/github/github-mcp-server/pkg/raw"
+	"github.com/github/github-mcp-server/pkg/repository"
 	"github.com/github/github-mcp-server/pkg/toolsets"
 	"github.com/github/github-mcp-server/pkg/translations"
 	gogithub "github.com/google/go-github/v74/github"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/shurcooL/githubv4"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var generateDocsCmd = &cobra.Command{
 	Use:   "generate-docs",
 	Short: "Generate documentation for tools and toolsets",
-	Long:  `Generate the automated sections of README.md and docs/remote-server.md with current tool and toolset information.`,
-	RunE: func(_ *cobra.Command, _ []string) error {
-		return generateAllDocs()
+	Long: `Generate the automated sections of README.md and docs/remote-server.md with current tool and toolset information, and the tool reference in --reference-dir: a Markdown page per tool with its JSON Schemas, annotations and required scopes, an index, and the tools.json catalog.
+
+Every toolset is documented: the GitHub toolsets, the dynamic toolset, the repository analysis tools and continue_result. The tools documented are those left by --tools and --exclude-tools.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if configErr != nil {
+			return configErr
+		}
+		referenceDir, _ := cmd.Flags().GetString("reference-dir")
+		return generateAllDocs(referenceDir)
 	},
 }
 
 func init() {
+	generateDocsCmd.Flags().String("reference-dir", "docs/tools", "Directory to write the tool reference pages and tools.json catalog to")
 	rootCmd.AddCommand(generateDocsCmd)
 }
 
@@ -47,8 +60,21 @@ func mockGetRawClient(_ context.Context) (*raw.Client, error) {
 	return nil, nil
 }
 
-func generateAllDocs() error {
-	if err := generateReadmeDocs("README.md"); err != nil {
+func generateAllDocs(referenceDir string) error {
+	// Create translation helper
+	t, _ := translations.TranslationHelper()
+
+	// Document the tools the server would offer
+	filter, err := toolsets.NewToolFilter(viper.GetStringSlice("include-tools"), viper.GetStringSlice("exclude-tools"))
+	if err != nil {
+		return fmt.Errorf("failed to filter tools: %w", err)
+	}
+	cat, err := buildCatalog(documentedToolsets(t, filter))
+	if err != nil {
+		return fmt.Errorf("failed to build tool catalog: %w", err)
+	}
+
+	if err := generateReadmeDocs("README.md", referenceDir, cat); err != nil {
 		return fmt.Errorf("failed to generate README docs: %w", err)
 	}
 
@@ -56,21 +82,47 @@ func generateAllDocs() error {
 		return fmt.Errorf("failed to generate remote-server docs: %w", err)
 	}
 
+	if err := generateReferenceDocs(referenceDir, cat); err != nil {
+		return fmt.Errorf("failed to generate tool reference: %w", err)
+	}
+
 	return nil
 }
 
-func generateReadmeDocs(readmePath string) error {
-	// Create translation helper
-	t, _ := translations.TranslationHelper()
-
+// documentedToolsets returns every toolset to document, sorted by name, with
+// the tools filter allows: the GitHub toolsets, the dynamic toolset, the
+// repository analysis tools and continue_result.
+func documentedToolsets(t translations.TranslationHelperFunc, filter *toolsets.ToolFilter) []*toolsets.Toolset {
 	// Create toolset group with mock clients
-	tsg := github.DefaultToolsetGroup(false, mockGetClient, mockGetGQLClient, mockGetRawClient, t, 5000)
+	tsg := github.DefaultToolsetGroup(false, mockGetClient, mockGetGQLClient, mockGetRawClient, github.NewRunLogsCache(github.DefaultRunLogsCacheSize, github.DefaultRunLogsCacheTTL), t, 5000)
+	tsg.AddToolset(github.InitDynamicToolset(server.NewMCPServer("generate-docs", ""), tsg, t))
 
-	// Generate toolsets documentation
-	toolsetsDoc := generateToolsetsDoc(tsg)
+	tsg.AddToolset(repository.Toolset())
 
-	// Generate tools documentation
-	toolsDoc := generateToolsDoc(tsg)
+	// The budget only builds the tool here; its size does not matter
+	responseBudget, _ := budget.New(1, budget.UnitChars)
+	tsg.AddToolset(toolsets.NewToolset("response_budget", "Reading tool results cut to fit --response-budget, registered when a budget is set").
+		AddReadTools(toolsets.NewServerTool(responseBudget.ContinueTool(t))))
+
+	tsg.SetToolFilter(filter)
+
+	all := make([]*toolsets.Toolset, 0, len(tsg.Toolsets))
+	for _, toolset := range tsg.Toolsets {
+		all = append(all, toolset)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+	return all
+}
+
+func generateReadmeDocs(readmePath, referenceDir string, cat *catalog) error {
+	// Link tools to their reference pages, relative to the README
+	referenceLink, err := filepath.Rel(filepath.Dir(readmePath), referenceDir)
+	if err != nil {
+		return fmt.Errorf("failed to link to the tool reference: %w", err)
+	}
+	referenceLink = filepath.ToSlash(referenceLink)
 
 	// Read the current README.md
 	// #nosec G304 - readmePath is controlled by command line flag, not user input
@@ -80,10 +132,14 @@ func generateReadmeDocs(readmePath string) error {
 	}
 
 	// Replace toolsets section
-	updatedContent := replaceSection(string(content), "START AUTOMATED TOOLSETS", "END AUTOMATED TOOLSETS", toolsetsDoc)
+	updatedContent := replaceSection(string(content), "START AUTOMATED TOOLSETS", "END AUTOMATED TOOLSETS", generateToolsetsDoc(cat))
 
 	// Replace tools section
-	updatedContent = replaceSection(updatedContent, "START AUTOMATED TOOLS", "END AUTOMATED TOOLS", toolsDoc)
+	updatedContent = replaceSection(updatedContent, "START AUTOMATED TOOLS", "END AUTOMATED TOOLS", generateToolsDoc(cat, referenceLink))
+
+	// Replace resource templates and prompts sections
+	updatedContent = replaceSection(updatedContent, "START AUTOMATED RESOURCE TEMPLATES", "END AUTOMATED RESOURCE TEMPLATES", generateResourceTemplatesDoc(cat))
+	updatedContent = replaceSection(updatedContent, "START AUTOMATED PROMPTS", "END AUTOMATED PROMPTS", generatePromptsDoc(cat))
 
 	// Write back to file
 	err = os.WriteFile(readmePath, []byte(updatedContent), 0600)
@@ -120,79 +176,73 @@ func generateRemoteServerDocs(docsPath string) error {
 	return os.WriteFile(docsPath, []byte(newContent), 0600) //#nosec G306
 }
 
-func generateToolsetsDoc(tsg *toolsets.ToolsetGroup) string {
+func generateToolsetsDoc(cat *catalog) string {
 	var lines []string
 
 	// Add table header and separator
 	lines = append(lines, "| Toolset                 | Description                                                   |")
 	lines = append(lines, "| ----------------------- | ------------------------------------------------------------- |")
 
-	// Add the context toolset row (handled separately in README)
+	// Add the context toolset row first
 	lines = append(lines, "| `context`               | **Strongly recommended**: Tools that provide context about the current user and GitHub context you are operating in |")
 
-	// Get all toolsets except context (which is handled separately above)
-	var toolsetNames []string
-	for name := range tsg.Toolsets {
-		if name != "context" && name != "dynamic" { // Skip context and dynamic toolsets as they're handled separately
-			toolsetNames = append(toolsetNames, name)
+	for _, toolset := range cat.Toolsets {
+		if toolset.Name == "context" {
+			continue
 		}
-	}
-
-	// Sort toolset names for consistent output
-	sort.Strings(toolsetNames)
-
-	for _, name := range toolsetNames {
-		toolset := tsg.Toolsets[name]
-		lines = append(lines, fmt.Sprintf("| `%s` | %s |", name, toolset.Description))
+		lines = append(lines, fmt.Sprintf("| `%s` | %s |", toolset.Name, tableCell(toolset.Description)))
 	}
 
 	return strings.Join(lines, "\n")
 }
 
-func generateToolsDoc(tsg *toolsets.ToolsetGroup) string {
+func generateToolsDoc(cat *catalog, referenceLink string) string {
 	var sections []string
 
-	// Get all toolset names and sort them alphabetically for deterministic order
-	var toolsetNames []string
-	for name := range tsg.Toolsets {
-		if name != "dynamic" { // Skip dynamic toolset as it's handled separately
-			toolsetNames = append(toolsetNames, name)
-		}
-	}
-	sort.Strings(toolsetNames)
-
-	for _, toolsetName := range toolsetNames {
-		toolset := tsg.Toolsets[toolsetName]
-
-		tools := toolset.GetAvailableTools()
+	for _, toolset := range cat.Toolsets {
+		tools := cat.toolsOf(toolset.Name)
 		if len(tools) == 0 {
 			continue
 		}
 
-		// Sort tools by name for deterministic order
-		sort.Slice(tools, func(i, j int) bool {
-			return tools[i].Tool.Name < tools[j].Tool.Name
-		})
-
 		// Generate section header - capitalize first letter and replace underscores
-		sectionName := formatToolsetName(toolsetName)
+		sectionName := formatToolsetName(toolset.Name)
 
 		var toolDocs []string
-		for _, serverTool := range tools {
-			toolDoc := generateToolDoc(serverTool.Tool)
-			toolDocs = append(toolDocs, toolDoc)
+		for _, tool := range tools {
+			toolDocs = append(toolDocs, generateToolDoc(tool, referenceLink))
 		}
 
-		if len(toolDocs) > 0 {
-			section := fmt.Sprintf("<details>\n\n<summary>%s</summary>\n\n%s\n\n</details>",
-				sectionName, strings.Join(toolDocs, "\n\n"))
-			sections = append(sections, section)
-		}
+		section := fmt.Sprintf("<details>\n\n<summary>%s</summary>\n\n%s\n\n</details>",
+			sectionName, strings.Join(toolDocs, "\n\n"))
+		sections = append(sections, section)
 	}
 
 	return strings.Join(sections, "\n\n")
 }
 
+func generateResourceTemplatesDoc(cat *catalog) string {
+	lines := []string{
+		"| Resource template | URI template | Toolset | Description |",
+		"| ----------------- | ------------ | ------- | ----------- |",
+	}
+	for _, template := range cat.ResourceTemplates {
+		lines = append(lines, fmt.Sprintf("| %s | `%s` | `%s` | %s |", tableCell(template.Name), template.URITemplate, template.Toolset, tableCell(template.Description)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func generatePromptsDoc(cat *catalog) string {
+	lines := []string{
+		"| Prompt | Toolset | Arguments | Description |",
+		"| ------ | ------- | --------- | ----------- |",
+	}
+	for _, prompt := range cat.Prompts {
+		lines = append(lines, fmt.Sprintf("| `%s` | `%s` | %s | %s |", prompt.Name, prompt.Toolset, promptArguments(prompt.Arguments), tableCell(prompt.Description)))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func formatToolsetName(name string) string {
 	switch name {
 	case "pull_requests":
@@ -205,6 +255,8 @@ func formatToolsetName(name string) string {
 		return "Secret Protection"
 	case "orgs":
 		return "Organizations"
+	case "repository":
+		return "Repository Analysis"
 	default:
 		// Fallback: capitalize first letter and replace underscores with spaces
 		parts := strings.Split(name, "_")
@@ -217,14 +269,15 @@ func formatToolsetName(name string) string {
 	}
 }
 
-func generateToolDoc(tool mcp.Tool) string {
+func generateToolDoc(tool catalogTool, referenceLink string) string {
 	var lines []string
 
-	// Tool name only (using annotation name instead of verbose description)
-	lines = append(lines, fmt.Sprintf("- **%s** - %s", tool.Name, tool.Annotations.Title))
+	// Tool name only (using annotation name instead of verbose description), linking to its reference page
+	lines = append(lines, fmt.Sprintf("- [**%s**](%s/%s.md) - %s", tool.Name, referenceLink, tool.Name, tool.Title))
 
 	// Parameters
-	schema := tool.InputSchema
+	var schema mcp.ToolInputSchema
+	_ = json.Unmarshal(tool.InputSchema, &schema)
 	if len(schema.Properties) > 0 {
 		// Get parameter names and sort them for deterministic order
 		var paramNames []string
@@ -302,7 +355,7 @@ func generateRemoteToolsetsDoc() string {
 	t, _ := translations.TranslationHelper()
 
 	// Create toolset group with mock clients
-	tsg := github.DefaultToolsetGroup(false, mockGetClient, mockGetGQLClient, mockGetRawClient, t, 5000)
+	tsg := github.DefaultToolsetGroup(false, mockGetClient, mockGetGQLClient, mockGetRawClient, github.NewRunLogsCache(github.DefaultRunLogsCacheSize, github.DefaultRunLogsCacheTTL), t, 5000)
 
 	// Generate table header
 	buf.WriteString("| Name           | Description                                      | API URL                                               | 1-Click Install (VS Code)                                                                                                                                                                                                 | Read-only Link                                                                                                 | 1-Click Read-only Install (VS Code)                                                                                                                                                                                                 |\n")
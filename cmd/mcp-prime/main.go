@@ -5,6 +5,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/github/github-mcp-server/internal/audit"
+	"github.com/github/github-mcp-server/internal/config"
 	"github.com/github/github-mcp-server/internal/ghmcp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -17,6 +19,13 @@ var commit = "commit"
 var date = "date"
 
 var (
+	// configFile is the --config flag.
+	configFile string
+	// loadedConfig is the config files in use, if any.
+	loadedConfig []config.File
+	// configErr is the error finding or loading the config file.
+	configErr error
+
 	rootCmd = &cobra.Command{
 		Use:     "mcp-prime",
 		Short:   "MCP PRIME - Repository to MCP Conversion Tool",
@@ -29,17 +38,43 @@ var (
 		Short: "Start stdio MCP server",
 		Long:  `Start an MCP server that communicates via standard input/output streams using JSON-RPC messages.`,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			stdioServerConfig := ghmcp.StdioServerConfig{
-				Version:              version,
-				EnabledToolsets:      []string{"repository"},
-				DynamicToolsets:      true,
-				ReadOnly:             false,
-				ExportTranslations:   viper.GetBool("export-translations"),
-				EnableCommandLogging: viper.GetBool("enable-command-logging"),
-				LogFilePath:          viper.GetString("log-file"),
-				ContentWindowSize:    viper.GetInt("content-window-size"),
+			if configErr != nil {
+				return configErr
+			}
+			stdioServerConfig, err := stdioConfig(viper.GetViper())
+			if err != nil {
+				return err
+			}
+			// Reloads read the flags, environment and config file afresh
+			for _, f := range loadedConfig {
+				stdioServerConfig.ConfigFiles = append(stdioServerConfig.ConfigFiles, f.Path)
+			}
+			stdioServerConfig.Reload = func() (ghmcp.StdioServerConfig, error) {
+				v := viper.New()
+				bindFlags(v)
+				if _, err := loadConfig(v); err != nil {
+					return ghmcp.StdioServerConfig{}, err
+				}
+				return stdioConfig(v)
+			}
+			return ghmcp.RunStdioServer(stdioServerConfig)
+		},
+	}
+
+	verifyAuditCmd = &cobra.Command{
+		Use:   "verify-audit <file>",
+		Short: "Verify an audit log",
+		Long:  `Verify the hash chain of an audit log written with --audit-log and report the first entry that was modified, removed or reordered.`,
+		Args:  cobra.ExactArgs(1),
+		// A failed verification is not a usage error
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := audit.VerifyFile(args[0])
+			if err != nil {
+				return fmt.Errorf("audit log verification failed after %d valid entries: %w", result.Entries, err)
 			}
-			return ghmcp.RunRepositoryStdioServer(stdioServerConfig)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "OK: %d entries, last hash %s\n", result.Entries, result.LastHash)
+			return nil
 		},
 	}
 )
@@ -51,25 +86,159 @@ func init() {
 	rootCmd.SetVersionTemplate("{{.Short}}\n{{.Version}}\n")
 
 	// Add global flags
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Path to a YAML, TOML or JSON config file (default: mcp-prime/config.<ext> in the XDG config directories, with .mcp-prime.<ext> at the repository root merged over it)")
 	rootCmd.PersistentFlags().String("log-file", "", "Path to log file")
+	rootCmd.PersistentFlags().Int("log-max-size", 0, "Rotate the log file once it grows past this many MiB (0 never rotates it)")
+	rootCmd.PersistentFlags().Int("log-max-files", 5, "Number of rotated log files to keep")
+	rootCmd.PersistentFlags().StringSlice("toolsets", []string{"repository"}, "Toolsets to enable")
+	rootCmd.PersistentFlags().Bool("dynamic-toolsets", true, "Let the model discover and enable toolsets at runtime")
+	rootCmd.PersistentFlags().Int("max-enabled-toolsets", 0, "With dynamic toolsets, the most toolsets enabled at once; enabling another disables the least recently used (0 for no limit)")
+	rootCmd.PersistentFlags().Bool("read-only", false, "Register only read-only tools")
+	rootCmd.PersistentFlags().StringSlice("tools", nil, "Glob patterns of the tools of the enabled toolsets to register (e.g. get_*,list_*); all of them by default")
+	rootCmd.PersistentFlags().StringSlice("exclude-tools", nil, "Glob patterns of tools never to register, even when matched by --tools (e.g. delete_*)")
+	rootCmd.PersistentFlags().String("gh-host", "", "Specify the GitHub hostname (for GitHub Enterprise Server, or a local fake such as http://127.0.0.1:8080)")
 	rootCmd.PersistentFlags().Bool("enable-command-logging", false, "When enabled, the server will log all command requests and responses to the log file")
+	rootCmd.PersistentFlags().StringSlice("log-redact", nil, "Regular expressions to redact from command logs, on top of GitHub tokens and Authorization values")
+	rootCmd.PersistentFlags().StringSlice("log-sensitive-arguments", nil, "Tool arguments whose values are redacted from command logs, on top of secret-looking ones such as token or password")
+	rootCmd.PersistentFlags().Int("log-max-payload", 4096, "Bytes of each message to include in command logs (0 logs only the method, id, tool and sizes)")
 	rootCmd.PersistentFlags().Bool("export-translations", false, "Save translations to a JSON file")
+	rootCmd.PersistentFlags().StringSlice("locale", nil, "Locales to translate tool, prompt and resource template text into, in order of preference (e.g. pt-BR,es); by default the locale the client asks for")
+	rootCmd.PersistentFlags().String("locales-dir", "", "Directory of the locale bundles, <locale>.json")
 	rootCmd.PersistentFlags().Int("content-window-size", 5000, "Specify the content window size")
+	rootCmd.PersistentFlags().Int("response-budget", 0, "Cut tool results larger than this, in --response-budget-unit, and let the model read the rest with continue_result (0 leaves results whole)")
+	rootCmd.PersistentFlags().String("response-budget-unit", "tokens", "Unit of --response-budget: chars, or tokens estimated at four characters each")
+	rootCmd.PersistentFlags().Int("response-cache-size", 1000, "Number of GitHub API responses to cache for conditional requests (0 disables the cache)")
+	rootCmd.PersistentFlags().String("response-cache-dir", "", "Directory to persist cached GitHub API responses across restarts, holding at most --response-cache-max-bytes")
+	rootCmd.PersistentFlags().Int64("response-cache-max-bytes", 100*1024*1024, "Bytes of responses kept in --response-cache-dir; the least recently used are removed past this (0 leaves the directory unbounded)")
+	rootCmd.PersistentFlags().String("record", "", "Record all GitHub API traffic, with tokens redacted, into cassette files in this directory")
+	rootCmd.PersistentFlags().String("replay", "", "Serve GitHub API responses from cassette files in this directory without network access")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	rootCmd.PersistentFlags().String("metrics-addr", "", "Serve Prometheus metrics on /metrics at this address (e.g. 127.0.0.1:9464)")
+	rootCmd.PersistentFlags().String("trace-exporter", "none", "Trace exporter: none, otlp, or console (writes spans to the log output)")
+	rootCmd.PersistentFlags().String("trace-endpoint", "", "OTLP/HTTP endpoint for the otlp trace exporter (e.g. http://localhost:4318); defaults to OTEL_EXPORTER_OTLP_ENDPOINT")
+	rootCmd.PersistentFlags().String("audit-log", "", "Append a tamper-evident record of every write tool call to this file")
+	rootCmd.PersistentFlags().String("policy-file", "", "YAML or JSON policy of rules allowing or denying tool calls per tool, repository, branch and path")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Make write tools return the GitHub API requests they would send, with diffs for file changes, instead of sending them")
+	rootCmd.PersistentFlags().Bool("confirm-destructive", false, "Ask the user to confirm calls to destructive tools through MCP elicitation; clients without elicitation support are refused")
+	rootCmd.PersistentFlags().StringSlice("confirm-tools", nil, "Glob patterns of further tools whose calls the user must confirm (e.g. push_files,update_*)")
 
 	// Bind flags to viper
-	_ = viper.BindPFlag("log-file", rootCmd.PersistentFlags().Lookup("log-file"))
-	_ = viper.BindPFlag("enable-command-logging", rootCmd.PersistentFlags().Lookup("enable-command-logging"))
-	_ = viper.BindPFlag("export-translations", rootCmd.PersistentFlags().Lookup("export-translations"))
-	_ = viper.BindPFlag("content-window-size", rootCmd.PersistentFlags().Lookup("content-window-size"))
+	bindFlags(viper.GetViper())
 
 	// Add subcommands
 	rootCmd.AddCommand(stdioCmd)
+	rootCmd.AddCommand(verifyAuditCmd)
+}
+
+// bindFlags binds the flags to the settings of v.
+func bindFlags(v *viper.Viper) {
+	_ = v.BindPFlag("log-file", rootCmd.PersistentFlags().Lookup("log-file"))
+	_ = v.BindPFlag("log-max-size", rootCmd.PersistentFlags().Lookup("log-max-size"))
+	_ = v.BindPFlag("log-max-files", rootCmd.PersistentFlags().Lookup("log-max-files"))
+	_ = v.BindPFlag("toolsets", rootCmd.PersistentFlags().Lookup("toolsets"))
+	_ = v.BindPFlag("dynamic-toolsets", rootCmd.PersistentFlags().Lookup("dynamic-toolsets"))
+	_ = v.BindPFlag("max-enabled-toolsets", rootCmd.PersistentFlags().Lookup("max-enabled-toolsets"))
+	_ = v.BindPFlag("read-only", rootCmd.PersistentFlags().Lookup("read-only"))
+	_ = v.BindPFlag("include-tools", rootCmd.PersistentFlags().Lookup("tools"))
+	_ = v.BindPFlag("exclude-tools", rootCmd.PersistentFlags().Lookup("exclude-tools"))
+	_ = v.BindPFlag("host", rootCmd.PersistentFlags().Lookup("gh-host"))
+	_ = v.BindPFlag("enable-command-logging", rootCmd.PersistentFlags().Lookup("enable-command-logging"))
+	_ = v.BindPFlag("log-redact", rootCmd.PersistentFlags().Lookup("log-redact"))
+	_ = v.BindPFlag("log-sensitive-arguments", rootCmd.PersistentFlags().Lookup("log-sensitive-arguments"))
+	_ = v.BindPFlag("log-max-payload", rootCmd.PersistentFlags().Lookup("log-max-payload"))
+	_ = v.BindPFlag("export-translations", rootCmd.PersistentFlags().Lookup("export-translations"))
+	_ = v.BindPFlag("locale", rootCmd.PersistentFlags().Lookup("locale"))
+	_ = v.BindPFlag("locales-dir", rootCmd.PersistentFlags().Lookup("locales-dir"))
+	_ = v.BindPFlag("content-window-size", rootCmd.PersistentFlags().Lookup("content-window-size"))
+	_ = v.BindPFlag("response-budget", rootCmd.PersistentFlags().Lookup("response-budget"))
+	_ = v.BindPFlag("response-budget-unit", rootCmd.PersistentFlags().Lookup("response-budget-unit"))
+	_ = v.BindPFlag("response-cache-size", rootCmd.PersistentFlags().Lookup("response-cache-size"))
+	_ = v.BindPFlag("response-cache-dir", rootCmd.PersistentFlags().Lookup("response-cache-dir"))
+	_ = v.BindPFlag("response-cache-max-bytes", rootCmd.PersistentFlags().Lookup("response-cache-max-bytes"))
+	_ = v.BindPFlag("record", rootCmd.PersistentFlags().Lookup("record"))
+	_ = v.BindPFlag("replay", rootCmd.PersistentFlags().Lookup("replay"))
+	_ = v.BindPFlag("metrics-addr", rootCmd.PersistentFlags().Lookup("metrics-addr"))
+	_ = v.BindPFlag("trace-exporter", rootCmd.PersistentFlags().Lookup("trace-exporter"))
+	_ = v.BindPFlag("trace-endpoint", rootCmd.PersistentFlags().Lookup("trace-endpoint"))
+	_ = v.BindPFlag("audit-log", rootCmd.PersistentFlags().Lookup("audit-log"))
+	_ = v.BindPFlag("policy-file", rootCmd.PersistentFlags().Lookup("policy-file"))
+	_ = v.BindPFlag("dry-run", rootCmd.PersistentFlags().Lookup("dry-run"))
+	_ = v.BindPFlag("confirm-destructive", rootCmd.PersistentFlags().Lookup("confirm-destructive"))
+	_ = v.BindPFlag("confirm-tools", rootCmd.PersistentFlags().Lookup("confirm-tools"))
 }
 
 func initConfig() {
-	// Initialize Viper configuration
-	viper.SetEnvPrefix("MCP_PRIME")
-	viper.AutomaticEnv()
+	// Errors are reported by the commands that need the settings, so that
+	// config validate can still run.
+	loadedConfig, configErr = loadConfig(viper.GetViper())
+}
+
+// loadConfig makes the MCP_PRIME_ environment variables and the config files,
+// if there are any, settings of v. Settings from the files rank below flags and
+// the environment.
+func loadConfig(v *viper.Viper) ([]config.File, error) {
+	v.SetEnvPrefix("MCP_PRIME")
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+
+	files, err := config.Find(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return files, config.Load(v, files...)
+}
+
+// stdioConfig returns the configuration of the stdio server from the settings
+// of v.
+func stdioConfig(v *viper.Viper) (ghmcp.StdioServerConfig, error) {
+	token, err := config.Token(v)
+	if err != nil {
+		return ghmcp.StdioServerConfig{}, err
+	}
+	tools, err := config.ToolOverrides(v)
+	if err != nil {
+		return ghmcp.StdioServerConfig{}, err
+	}
+
+	return ghmcp.StdioServerConfig{
+		Version:               version,
+		Host:                  v.GetString("host"),
+		Token:                 token,
+		EnabledToolsets:       v.GetStringSlice("toolsets"),
+		DynamicToolsets:       v.GetBool("dynamic-toolsets"),
+		MaxEnabledToolsets:    v.GetInt("max-enabled-toolsets"),
+		ReadOnly:              v.GetBool("read-only"),
+		Tools:                 v.GetStringSlice("include-tools"),
+		ExcludeTools:          v.GetStringSlice("exclude-tools"),
+		ProjectTools:          v.GetStringSlice(config.KeyProjectTools),
+		ExportTranslations:    v.GetBool("export-translations"),
+		EnableCommandLogging:  v.GetBool("enable-command-logging"),
+		LogFilePath:           v.GetString("log-file"),
+		LogMaxSize:            v.GetInt("log-max-size"),
+		LogMaxFiles:           v.GetInt("log-max-files"),
+		LogRedact:             v.GetStringSlice("log-redact"),
+		LogSensitiveArguments: v.GetStringSlice("log-sensitive-arguments"),
+		LogMaxPayload:         v.GetInt("log-max-payload"),
+		Locales:               v.GetStringSlice("locale"),
+		LocalesDir:            v.GetString("locales-dir"),
+		ContentWindowSize:     v.GetInt("content-window-size"),
+		ResponseBudget:        v.GetInt("response-budget"),
+		ResponseBudgetUnit:    v.GetString("response-budget-unit"),
+		ResponseCacheSize:     v.GetInt("response-cache-size"),
+		ResponseCacheDir:      v.GetString("response-cache-dir"),
+		ResponseCacheMaxBytes: v.GetInt64("response-cache-max-bytes"),
+		RecordDir:             v.GetString("record"),
+		ReplayDir:             v.GetString("replay"),
+		MetricsAddr:           v.GetString("metrics-addr"),
+		TraceExporter:         v.GetString("trace-exporter"),
+		TraceEndpoint:         v.GetString("trace-endpoint"),
+		AuditLogPath:          v.GetString("audit-log"),
+		PolicyFile:            v.GetString("policy-file"),
+		DryRun:                v.GetBool("dry-run"),
+		ConfirmDestructive:    v.GetBool("confirm-destructive"),
+		ConfirmTools:          append(v.GetStringSlice("confirm-tools"), config.ConfirmTools(tools)...),
+		Translations:          config.Translations(tools),
+	}, nil
 }
 
 func main() {
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// generatedHeader starts every generated reference page. Pages starting with
+// it are removed before the reference is written, so pages of removed tools
+// do not linger.
+const generatedHeader = "<!-- Code generated by mcp-prime generate-docs. DO NOT EDIT. -->"
+
+// catalog is the machine-readable list of everything the servers offer,
+// written to tools.json.
+type catalog struct {
+	Toolsets          []catalogToolset          `json:"toolsets"`
+	Tools             []catalogTool             `json:"tools"`
+	ResourceTemplates []catalogResourceTemplate `json:"resourceTemplates"`
+	Prompts           []catalogPrompt           `json:"prompts"`
+}
+
+type catalogToolset struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type catalogTool struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Toolset     string `json:"toolset"`
+	ReadOnly    bool   `json:"readOnly"`
+	// RequiredScopes are the classic OAuth scopes the tool needs on private
+	// resources, empty for tools that need none or do not call GitHub.
+	RequiredScopes []string           `json:"requiredScopes"`
+	Annotations    mcp.ToolAnnotation `json:"annotations"`
+	InputSchema    json.RawMessage    `json:"inputSchema"`
+	OutputSchema   json.RawMessage    `json:"outputSchema,omitempty"`
+}
+
+type catalogResourceTemplate struct {
+	Name        string `json:"name"`
+	URITemplate string `json:"uriTemplate"`
+	Description string `json:"description,omitempty"`
+	MIMEType    string `json:"mimeType,omitempty"`
+	Toolset     string `json:"toolset"`
+}
+
+type catalogPrompt struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Arguments   []mcp.PromptArgument `json:"arguments,omitempty"`
+	Toolset     string               `json:"toolset"`
+}
+
+// buildCatalog lists the available tools, resource templates and prompts of
+// the toolsets, each sorted by name.
+func buildCatalog(all []*toolsets.Toolset) (*catalog, error) {
+	cat := &catalog{
+		Toolsets:          []catalogToolset{},
+		Tools:             []catalogTool{},
+		ResourceTemplates: []catalogResourceTemplate{},
+		Prompts:           []catalogPrompt{},
+	}
+	for _, toolset := range all {
+		cat.Toolsets = append(cat.Toolsets, catalogToolset{Name: toolset.Name, Description: toolset.Description})
+
+		for _, serverTool := range toolset.GetAvailableTools() {
+			tool, err := newCatalogTool(toolset.Name, serverTool.Tool)
+			if err != nil {
+				return nil, err
+			}
+			cat.Tools = append(cat.Tools, tool)
+		}
+
+		for _, template := range toolset.GetAvailableResourceTemplates() {
+			uriTemplate := ""
+			if template.Template.URITemplate != nil {
+				uriTemplate = template.Template.URITemplate.Raw()
+			}
+			cat.ResourceTemplates = append(cat.ResourceTemplates, catalogResourceTemplate{
+				Name:        template.Template.Name,
+				URITemplate: uriTemplate,
+				Description: template.Template.Description,
+				MIMEType:    template.Template.MIMEType,
+				Toolset:     toolset.Name,
+			})
+		}
+
+		for _, prompt := range toolset.GetAvailablePrompts() {
+			cat.Prompts = append(cat.Prompts, catalogPrompt{
+				Name:        prompt.Prompt.Name,
+				Description: prompt.Prompt.Description,
+				Arguments:   prompt.Prompt.Arguments,
+				Toolset:     toolset.Name,
+			})
+		}
+	}
+
+	sort.Slice(cat.Toolsets, func(i, j int) bool { return cat.Toolsets[i].Name < cat.Toolsets[j].Name })
+	sort.Slice(cat.Tools, func(i, j int) bool { return cat.Tools[i].Name < cat.Tools[j].Name })
+	sort.Slice(cat.ResourceTemplates, func(i, j int) bool { return cat.ResourceTemplates[i].Name < cat.ResourceTemplates[j].Name })
+	sort.Slice(cat.Prompts, func(i, j int) bool { return cat.Prompts[i].Name < cat.Prompts[j].Name })
+	return cat, nil
+}
+
+// newCatalogTool describes tool of the toolset, with its schemas as the
+// server lists them.
+func newCatalogTool(toolset string, tool mcp.Tool) (catalogTool, error) {
+	data, err := json.Marshal(tool)
+	if err != nil {
+		return catalogTool{}, fmt.Errorf("failed to marshal tool %s: %w", tool.Name, err)
+	}
+	var schemas struct {
+		InputSchema  json.RawMessage `json:"inputSchema"`
+		OutputSchema json.RawMessage `json:"outputSchema"`
+	}
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return catalogTool{}, fmt.Errorf("failed to read schemas of tool %s: %w", tool.Name, err)
+	}
+
+	scopes := github.RequiredScopes(toolset, tool.Name)
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return catalogTool{
+		Name:           tool.Name,
+		Title:          tool.Annotations.Title,
+		Description:    tool.Description,
+		Toolset:        toolset,
+		ReadOnly:       tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint,
+		RequiredScopes: scopes,
+		Annotations:    tool.Annotations,
+		InputSchema:    schemas.InputSchema,
+		OutputSchema:   schemas.OutputSchema,
+	}, nil
+}
+
+// toolsOf returns the tools of the toolset called name.
+func (c *catalog) toolsOf(name string) []catalogTool {
+	var tools []catalogTool
+	for _, tool := range c.Tools {
+		if tool.Toolset == name {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// generateReferenceDocs writes the tool reference to dir: a page per tool,
+// an index of the toolsets, resource templates and prompts, and tools.json.
+func generateReferenceDocs(dir string, cat *catalog) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil { //#nosec G301
+		return fmt.Errorf("failed to create reference directory: %w", err)
+	}
+	if err := removeGeneratedPages(dir); err != nil {
+		return err
+	}
+
+	for _, tool := range cat.Tools {
+		if err := writeReferenceFile(dir, tool.Name+".md", generateToolPage(tool)); err != nil {
+			return err
+		}
+	}
+	if err := writeReferenceFile(dir, "README.md", generateReferenceIndex(cat)); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cat, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool catalog: %w", err)
+	}
+	if err := writeReferenceFile(dir, "tools.json", string(data)+"\n"); err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully wrote the reference of %d tools to %s\n", len(cat.Tools), dir)
+	return nil
+}
+
+// removeGeneratedPages removes the Markdown files of dir written by an
+// earlier run.
+func removeGeneratedPages(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		content, err := os.ReadFile(path) //#nosec G304
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if !bytes.HasPrefix(content, []byte(generatedHeader)) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func writeReferenceFile(dir, name, content string) error {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil { //#nosec G306
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// generateToolPage returns the reference page of tool.
+func generateToolPage(tool catalogTool) string {
+	var buf strings.Builder
+	buf.WriteString(generatedHeader + "\n\n")
+	fmt.Fprintf(&buf, "# `%s`\n\n", tool.Name)
+	if tool.Title != "" {
+		fmt.Fprintf(&buf, "**%s**\n\n", tool.Title)
+	}
+	if tool.Description != "" {
+		buf.WriteString(tool.Description + "\n\n")
+	}
+
+	access := "write"
+	if tool.ReadOnly {
+		access = "read-only"
+	}
+	buf.WriteString("| | |\n| --- | --- |\n")
+	fmt.Fprintf(&buf, "| Toolset | [`%s`](README.md#%s) |\n", tool.Toolset, anchor(formatToolsetName(tool.Toolset)))
+	fmt.Fprintf(&buf, "| Access | %s |\n", access)
+	fmt.Fprintf(&buf, "| Required scopes | %s |\n\n", formatScopes(tool.RequiredScopes))
+
+	buf.WriteString("## Annotations\n\n")
+	buf.WriteString("| Hint | Value |\n| --- | --- |\n")
+	for _, hint := range []struct {
+		name  string
+		value *bool
+	}{
+		{"readOnlyHint", tool.Annotations.ReadOnlyHint},
+		{"destructiveHint", tool.Annotations.DestructiveHint},
+		{"idempotentHint", tool.Annotations.IdempotentHint},
+		{"openWorldHint", tool.Annotations.OpenWorldHint},
+	} {
+		value := "not set"
+		if hint.value != nil {
+			value = fmt.Sprint(*hint.value)
+		}
+		fmt.Fprintf(&buf, "| `%s` | %s |\n", hint.name, value)
+	}
+
+	buf.WriteString("\n## Input schema\n\n")
+	buf.WriteString(jsonBlock(tool.InputSchema))
+	buf.WriteString("\n## Output schema\n\n")
+	if len(tool.OutputSchema) == 0 {
+		buf.WriteString("The tool declares no output schema; its results are unstructured content.\n")
+	} else {
+		buf.WriteString(jsonBlock(tool.OutputSchema))
+	}
+	return buf.String()
+}
+
+// generateReferenceIndex returns the index page of the reference.
+func generateReferenceIndex(cat *catalog) string {
+	var buf strings.Builder
+	buf.WriteString(generatedHeader + "\n\n")
+	buf.WriteString("# Tool Reference\n\n")
+	buf.WriteString("Every tool, resource template and prompt the servers offer, by toolset. `tools.json` holds the same reference, with the schemas of each tool, for programs.\n\n")
+	buf.WriteString("Required scopes are the classic OAuth scopes a token needs on private resources. Reading public resources needs none.\n")
+
+	for _, toolset := range cat.Toolsets {
+		tools := cat.toolsOf(toolset.Name)
+		if len(tools) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n## %s\n\n", formatToolsetName(toolset.Name))
+		fmt.Fprintf(&buf, "`%s`: %s\n\n", toolset.Name, toolset.Description)
+		buf.WriteString("| Tool | Title | Access | Required scopes |\n| --- | --- | --- | --- |\n")
+		for _, tool := range tools {
+			access := "write"
+			if tool.ReadOnly {
+				access = "read-only"
+			}
+			fmt.Fprintf(&buf, "| [`%s`](%s.md) | %s | %s | %s |\n", tool.Name, tool.Name, tableCell(tool.Title), access, formatScopes(tool.RequiredScopes))
+		}
+	}
+
+	if len(cat.ResourceTemplates) > 0 {
+		buf.WriteString("\n## Resource Templates\n\n")
+		buf.WriteString(generateResourceTemplatesDoc(cat) + "\n")
+	}
+	if len(cat.Prompts) > 0 {
+		buf.WriteString("\n## Prompts\n\n")
+		buf.WriteString(generatePromptsDoc(cat) + "\n")
+	}
+	return buf.String()
+}
+
+// jsonBlock returns data, indented, as a fenced JSON code block.
+func jsonBlock(data json.RawMessage) string {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, "", "  "); err != nil {
+		indented.Reset()
+		indented.Write(data)
+	}
+	return "```json\n" + indented.String() + "\n```\n"
+}
+
+func formatScopes(scopes []string) string {
+	if len(scopes) == 0 {
+		return "none"
+	}
+	quoted := make([]string, len(scopes))
+	for i, scope := range scopes {
+		quoted[i] = "`" + scope + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func promptArguments(args []mcp.PromptArgument) string {
+	if len(args) == 0 {
+		return "none"
+	}
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = "`" + arg.Name + "`"
+		if arg.Required {
+			names[i] += " (required)"
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// tableCell escapes s for a cell of a Markdown table.
+func tableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// anchor returns the GitHub anchor of a Markdown heading.
+func anchor(heading string) string {
+	return strings.ReplaceAll(strings.ToLower(heading), " ", "-")
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/github/github-mcp-server/pkg/budget"
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	translationsCmd = &cobra.Command{
+		Use:   "translations",
+		Short: "Work with locale bundles",
+	}
+
+	checkTranslationsCmd = &cobra.Command{
+		Use:   "check [locale...]",
+		Short: "Report missing and unused keys of locale bundles",
+		Long: `Compare the locale bundles in --locales-dir with the translation keys of every tool, prompt and resource template, and report the keys each locale is missing, after its fallback locales, and the keys it has that nothing uses.
+
+The locales checked are those given as arguments, else those of --locale, else every bundle in the directory. With --keys, the keys are read from a file written by --export-translations instead.`,
+		// Incomplete bundles are not a usage error
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if configErr != nil {
+				return configErr
+			}
+			dir := viper.GetString("locales-dir")
+			if dir == "" {
+				return errors.New("no locale bundles to check; pass --locales-dir")
+			}
+			locales := args
+			if len(locales) == 0 {
+				locales = viper.GetStringSlice("locale")
+			}
+			if len(locales) == 0 {
+				var err error
+				if locales, err = translations.BundleLocales(dir); err != nil {
+					return fmt.Errorf("failed to list locale bundles: %w", err)
+				}
+			}
+
+			keys := translationKeys()
+			if path, _ := cmd.Flags().GetString("keys"); path != "" {
+				dumped, err := translations.ReadBundle(path)
+				if err != nil {
+					return fmt.Errorf("failed to read translation keys: %w", err)
+				}
+				keys = dumped
+			}
+			return checkTranslations(cmd.OutOrStdout(), dir, locales, keys)
+		},
+	}
+)
+
+func init() {
+	checkTranslationsCmd.Flags().String("keys", "", "JSON file of translation keys written by --export-translations")
+	translationsCmd.AddCommand(checkTranslationsCmd)
+	rootCmd.AddCommand(translationsCmd)
+}
+
+// checkTranslations reports the missing and unused keys of the bundles of
+// locales in dir to w, and returns an error if any bundle has either.
+func checkTranslations(w io.Writer, dir string, locales []string, keys map[string]string) error {
+	incomplete := 0
+	for _, locale := range locales {
+		bundle, err := translations.LoadBundle(dir, locale)
+		if err != nil {
+			return err
+		}
+		missing, unused := translations.Check(keys, bundle)
+		_, _ = fmt.Fprintf(w, "%s: %d missing, %d unused\n", locale, len(missing), len(unused))
+		for _, key := range missing {
+			_, _ = fmt.Fprintf(w, "  missing: %s\n", key)
+		}
+		for _, key := range unused {
+			_, _ = fmt.Fprintf(w, "  unused: %s\n", key)
+		}
+		if len(missing) > 0 || len(unused) > 0 {
+			incomplete++
+		}
+	}
+	if incomplete > 0 {
+		return fmt.Errorf("%d of %d locales have missing or unused keys", incomplete, len(locales))
+	}
+	return nil
+}
+
+// translationKeys returns the translation keys of every tool, prompt and
+// resource template the server can offer, with their built-in text: the keys
+// --export-translations writes with every toolset enabled.
+func translationKeys() map[string]string {
+	t, keys := translations.KeyRecorder()
+	tsg := github.DefaultToolsetGroup(false, mockGetClient, mockGetGQLClient, mockGetRawClient, github.NewRunLogsCache(github.DefaultRunLogsCacheSize, github.DefaultRunLogsCacheTTL), t, 5000)
+	github.InitDynamicToolset(github.NewServer(version), tsg, t)
+	b, _ := budget.New(1, budget.UnitChars)
+	b.ContinueTool(t)
+	return keys
+}
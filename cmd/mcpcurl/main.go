@@ -1,29 +1,20 @@
 package main
 
 import (
-	"bytes"
-	"crypto/rand"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"math/big"
 	"os"
-	"os/exec"
 	"slices"
 	"strings"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
 type (
-	// SchemaResponse represents the top-level response containing tools
-	SchemaResponse struct {
-		Result  Result `json:"result"`
-		JSONRPC string `json:"jsonrpc"`
-		ID      int    `json:"id"`
-	}
-
 	// Result contains the list of available tools
 	Result struct {
 		Tools []Tool `json:"tools"`
@@ -62,36 +53,6 @@ type (
 		Required             []string            `json:"required,omitempty"`
 		AdditionalProperties bool                `json:"additionalProperties,omitempty"`
 	}
-
-	// JSONRPCRequest represents a JSON-RPC 2.0 request
-	JSONRPCRequest struct {
-		JSONRPC string        `json:"jsonrpc"`
-		ID      int           `json:"id"`
-		Method  string        `json:"method"`
-		Params  RequestParams `json:"params"`
-	}
-
-	// RequestParams contains the tool name and arguments
-	RequestParams struct {
-		Name      string                 `json:"name"`
-		Arguments map[string]interface{} `json:"arguments"`
-	}
-
-	// Content matches the response format of a text content response
-	Content struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
-	}
-
-	ResponseResult struct {
-		Content []Content `json:"content"`
-	}
-
-	Response struct {
-		Result  ResponseResult `json:"result"`
-		JSONRPC string         `json:"jsonrpc"`
-		ID      int            `json:"id"`
-	}
 )
 
 var (
@@ -100,18 +61,17 @@ var (
 		Use:   "mcpcurl",
 		Short: "CLI tool with dynamically generated commands",
 		Long:  "A CLI tool for interacting with MCP API based on dynamically loaded schemas",
+		// main prints the error, and failed requests are not usage errors
+		SilenceErrors: true,
+		SilenceUsage:  true,
 		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
 			// Skip validation for help and completion commands
 			if cmd.Name() == "help" || cmd.Name() == "completion" {
 				return nil
 			}
 
-			// Check if the required global flag is provided
-			serverCmd, _ := cmd.Flags().GetString("stdio-server-cmd")
-			if serverCmd == "" {
-				return fmt.Errorf("--stdio-server-cmd is required")
-			}
-			return nil
+			// Check that a server is given
+			return validateConnectionFlags(cmd.Flags())
 		},
 	}
 
@@ -119,28 +79,20 @@ var (
 	schemaCmd = &cobra.Command{
 		Use:   "schema",
 		Short: "Fetch schema from MCP server",
-		Long:  "Fetches the tools schema from the MCP server specified by --stdio-server-cmd",
+		Long:  "Fetches the tools schema from the MCP server specified by --stdio-server-cmd or --url",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			serverCmd, _ := cmd.Flags().GetString("stdio-server-cmd")
-			if serverCmd == "" {
-				return fmt.Errorf("--stdio-server-cmd is required")
-			}
-
-			// Build the JSON-RPC request for tools/list
-			jsonRequest, err := buildJSONRPCRequest("tools/list", "", nil)
+			ctx, cancel := requestContext(cmd)
+			defer cancel()
+			c, err := connect(ctx, cmd.Flags())
 			if err != nil {
-				return fmt.Errorf("failed to build JSON-RPC request: %w", err)
+				return err
 			}
 
-			// Execute the server command and pass the JSON-RPC request
-			response, err := executeServerCommand(serverCmd, jsonRequest)
+			result, err := c.ListTools(ctx, mcp.ListToolsRequest{})
 			if err != nil {
-				return fmt.Errorf("error executing server command: %w", err)
+				return fmt.Errorf("failed to list tools: %w", err)
 			}
-
-			// Output the response
-			fmt.Println(response)
-			return nil
+			return printJSON(result, prettyOutput(cmd))
 		},
 	}
 
@@ -155,90 +107,113 @@ var (
 func main() {
 	rootCmd.AddCommand(schemaCmd)
 
-	// Add global flag for stdio server command
-	rootCmd.PersistentFlags().String("stdio-server-cmd", "", "Shell command to invoke MCP server via stdio (required)")
-	_ = rootCmd.MarkPersistentFlagRequired("stdio-server-cmd")
+	// Add global flags selecting the server
+	addConnectionFlags(rootCmd.PersistentFlags())
 
 	// Add global flag for pretty printing
 	rootCmd.PersistentFlags().Bool("pretty", true, "Pretty print MCP response (only for JSON or JSONL responses)")
 
 	// Add the tools command to the root command
 	rootCmd.AddCommand(toolsCmd)
+	rootCmd.AddCommand(resourcesCmd, promptsCmd, completeCmd, sessionCmd, replCmd, runCmd)
 
-	// Execute the root command once to parse flags
+	// Execute the root command once to parse flags, skipping the flags of
+	// the tool commands, which do not exist yet
+	rootCmd.FParseErrWhitelist.UnknownFlags = true
 	_ = rootCmd.ParseFlags(os.Args[1:])
-
-	// Get pretty flag
-	prettyPrint, err := rootCmd.Flags().GetBool("pretty")
-	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "Error getting pretty flag: %v\n", err)
-		os.Exit(1)
-	}
-	// Get server command
-	serverCmd, err := rootCmd.Flags().GetString("stdio-server-cmd")
-	if err == nil && serverCmd != "" {
-		// Fetch schema from server
-		jsonRequest, err := buildJSONRPCRequest("tools/list", "", nil)
-		if err == nil {
-			response, err := executeServerCommand(serverCmd, jsonRequest)
-			if err == nil {
-				// Parse the schema response
-				var schemaResp SchemaResponse
-				if err := json.Unmarshal([]byte(response), &schemaResp); err == nil {
-					// Add all the generated commands as subcommands of tools
-					for _, tool := range schemaResp.Result.Tools {
-						addCommandFromTool(toolsCmd, &tool, prettyPrint)
-					}
-				}
-			}
+	// The tool commands are generated only when one is run, so that other
+	// commands do not list the tools first
+	target, _, err := rootCmd.Find(os.Args[1:])
+	if err == nil && target == toolsCmd && validateConnectionFlags(rootCmd.Flags()) == nil {
+		if err := loadToolCommands(rootCmd.Flags()); err != nil {
+			closeSession()
+			_, _ = fmt.Fprintf(os.Stderr, "Error fetching tools schema: %v\n", err)
+			os.Exit(1)
 		}
 	}
 
 	// Execute
-	if err := rootCmd.Execute(); err != nil {
+	err = rootCmd.Execute()
+	closeSession()
+	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Error executing command: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// toolsLoaded is set once the tool commands are generated.
+var toolsLoaded bool
+
+// loadToolCommands fetches the tools schema from the server, in the session
+// the commands then run in, and adds a command for each tool to toolsCmd.
+func loadToolCommands(flags *pflag.FlagSet) error {
+	if toolsLoaded {
+		return nil
+	}
+	ctx, cancel := timeoutContext(context.Background(), flags)
+	defer cancel()
+	c, err := connect(ctx, flags)
+	if err != nil {
+		return err
+	}
+	result, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return err
+	}
+
+	// Decode the schema into the types the commands are generated from
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	var schema Result
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return err
+	}
+	// Add all the generated commands as subcommands of tools
+	for _, tool := range schema.Tools {
+		addCommandFromTool(toolsCmd, &tool)
+	}
+	toolsLoaded = true
+	return nil
+}
+
 // addCommandFromTool creates a cobra command from a tool schema
-func addCommandFromTool(toolsCmd *cobra.Command, tool *Tool, prettyPrint bool) {
+func addCommandFromTool(toolsCmd *cobra.Command, tool *Tool) {
 	// Create command from tool
 	cmd := &cobra.Command{
 		Use:   tool.Name,
 		Short: tool.Description,
-		Run: func(cmd *cobra.Command, _ []string) {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			// Build a map of arguments from flags
 			arguments, err := buildArgumentsMap(cmd, tool)
 			if err != nil {
-				_, _ = fmt.Fprintf(os.Stderr, "failed to build arguments map: %v\n", err)
-				return
+				return fmt.Errorf("failed to build arguments map: %w", err)
 			}
 
-			jsonData, err := buildJSONRPCRequest("tools/call", tool.Name, arguments)
+			ctx, cancel := requestContext(cmd)
+			defer cancel()
+			c, err := connect(ctx, cmd.Flags())
 			if err != nil {
-				_, _ = fmt.Fprintf(os.Stderr, "failed to build JSONRPC request: %v\n", err)
-				return
+				return err
 			}
 
-			// Execute the server command
-			serverCmd, err := cmd.Flags().GetString("stdio-server-cmd")
+			request := mcp.CallToolRequest{}
+			request.Params.Name = tool.Name
+			request.Params.Arguments = arguments
+			result, err := c.CallTool(ctx, request)
 			if err != nil {
-				_, _ = fmt.Fprintf(os.Stderr, "failed to get stdio-server-cmd: %v\n", err)
-				return
+				return fmt.Errorf("failed to call tool: %w", err)
 			}
-			response, err := executeServerCommand(serverCmd, jsonData)
-			if err != nil {
-				_, _ = fmt.Fprintf(os.Stderr, "error executing server command: %v\n", err)
-				return
+			if err := printToolResult(result, prettyOutput(cmd)); err != nil {
+				return fmt.Errorf("error printing response: %w", err)
 			}
-			if err := printResponse(response, prettyPrint); err != nil {
-				_, _ = fmt.Fprintf(os.Stderr, "error printing response: %v\n", err)
-				return
+			if result.IsError {
+				return fmt.Errorf("tool %s returned an error", tool.Name)
 			}
+			return nil
 		},
 	}
-
 	// Initialize viper for this command
 	viperInit := func() {
 		viper.Reset()
@@ -354,112 +329,57 @@ func buildArgumentsMap(cmd *cobra.Command, tool *Tool) (map[string]interface{},
 	return arguments, nil
 }
 
-// buildJSONRPCRequest creates a JSON-RPC request with the given tool name and arguments
-func buildJSONRPCRequest(method, toolName string, arguments map[string]interface{}) (string, error) {
-	id, err := rand.Int(rand.Reader, big.NewInt(10000))
-	if err != nil {
-		return "", fmt.Errorf("failed to generate random ID: %w", err)
-	}
-	request := JSONRPCRequest{
-		JSONRPC: "2.0",
-		ID:      int(id.Int64()), // Random ID between 0 and 9999
-		Method:  method,
-		Params: RequestParams{
-			Name:      toolName,
-			Arguments: arguments,
-		},
-	}
-	jsonData, err := json.Marshal(request)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal JSON request: %w", err)
-	}
-	return string(jsonData), nil
+// prettyOutput returns the value of --pretty.
+func prettyOutput(cmd *cobra.Command) bool {
+	pretty, _ := cmd.Flags().GetBool("pretty")
+	return pretty
 }
 
-// executeServerCommand runs the specified command, sends the JSON request to stdin,
-// and returns the response from stdout
-func executeServerCommand(cmdStr, jsonRequest string) (string, error) {
-	// Split the command string into command and arguments
-	cmdParts := strings.Fields(cmdStr)
-	if len(cmdParts) == 0 {
-		return "", fmt.Errorf("empty command")
+// printJSON prints v as JSON, indented if prettyPrint is set.
+func printJSON(v any, prettyPrint bool) error {
+	var data []byte
+	var err error
+	if prettyPrint {
+		data, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		data, err = json.Marshal(v)
 	}
-
-	cmd := exec.Command(cmdParts[0], cmdParts[1:]...) //nolint:gosec //mcpcurl is a test command that needs to execute arbitrary shell commands
-
-	// Setup stdin pipe
-	stdin, err := cmd.StdinPipe()
 	if err != nil {
-		return "", fmt.Errorf("failed to create stdin pipe: %w", err)
-	}
-
-	// Setup stdout and stderr pipes
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	// Start the command
-	if err := cmd.Start(); err != nil {
-		return "", fmt.Errorf("failed to start command: %w", err)
-	}
-
-	// Write the JSON request to stdin
-	if _, err := io.WriteString(stdin, jsonRequest+"\n"); err != nil {
-		return "", fmt.Errorf("failed to write to stdin: %w", err)
-	}
-	_ = stdin.Close()
-
-	// Wait for the command to complete
-	if err := cmd.Wait(); err != nil {
-		return "", fmt.Errorf("command failed: %w, stderr: %s", err, stderr.String())
+		return fmt.Errorf("failed to marshal response: %w", err)
 	}
-
-	return stdout.String(), nil
+	fmt.Println(string(data))
+	return nil
 }
 
-func printResponse(response string, prettyPrint bool) error {
+// printToolResult prints the result of a tool call. With prettyPrint, the
+// text content of the result is printed, indented if it holds JSON.
+func printToolResult(result *mcp.CallToolResult, prettyPrint bool) error {
 	if !prettyPrint {
-		fmt.Println(response)
-		return nil
-	}
-
-	// Parse the JSON response
-	var resp Response
-	if err := json.Unmarshal([]byte(response), &resp); err != nil {
-		return fmt.Errorf("failed to parse JSON: %w", err)
+		return printJSON(result, false)
 	}
 
 	// Extract text from content items of type "text"
-	for _, content := range resp.Result.Content {
-		if content.Type == "text" {
-			var textContentObj map[string]interface{}
-			err := json.Unmarshal([]byte(content.Text), &textContentObj)
-
-			if err == nil {
-				prettyText, err := json.MarshalIndent(textContentObj, "", "  ")
-				if err != nil {
-					return fmt.Errorf("failed to pretty print text content: %w", err)
-				}
-				fmt.Println(string(prettyText))
-				continue
-			}
-
-			// Fallback parsing as JSONL
-			var textContentList []map[string]interface{}
-			if err := json.Unmarshal([]byte(content.Text), &textContentList); err != nil {
-				return fmt.Errorf("failed to parse text content as a list: %w", err)
-			}
-			prettyText, err := json.MarshalIndent(textContentList, "", "  ")
-			if err != nil {
-				return fmt.Errorf("failed to pretty print array content: %w", err)
-			}
-			fmt.Println(string(prettyText))
+	for _, content := range result.Content {
+		text, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		var textContent interface{}
+		if err := json.Unmarshal([]byte(text.Text), &textContent); err != nil {
+			// Not JSON, print the text as is
+			fmt.Println(text.Text)
+			continue
+		}
+		prettyText, err := json.MarshalIndent(textContent, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to pretty print text content: %w", err)
 		}
+		fmt.Println(string(prettyText))
 	}
 
-	// If no text content found, print the original response
-	if len(resp.Result.Content) == 0 {
-		fmt.Println(response)
+	// If no text content found, print the whole result
+	if len(result.Content) == 0 {
+		return printJSON(result, true)
 	}
 
 	return nil
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resourcesCmd = &cobra.Command{
+		Use:   "resources",
+		Short: "List and read resources",
+	}
+
+	listResourcesCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List resources, or resource templates with --templates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := requestContext(cmd)
+			defer cancel()
+			c, err := connect(ctx, cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			var result any
+			if templates, _ := cmd.Flags().GetBool("templates"); templates {
+				result, err = c.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+			} else {
+				result, err = c.ListResources(ctx, mcp.ListResourcesRequest{})
+			}
+			if err != nil {
+				return fmt.Errorf("failed to list resources: %w", err)
+			}
+			return printJSON(result, prettyOutput(cmd))
+		},
+	}
+
+	readResourceCmd = &cobra.Command{
+		Use:   "read <uri>",
+		Short: "Read a resource",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := requestContext(cmd)
+			defer cancel()
+			c, err := connect(ctx, cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			request := mcp.ReadResourceRequest{}
+			request.Params.URI = args[0]
+			result, err := c.ReadResource(ctx, request)
+			if err != nil {
+				return fmt.Errorf("failed to read resource: %w", err)
+			}
+			return printJSON(result, prettyOutput(cmd))
+		},
+	}
+
+	promptsCmd = &cobra.Command{
+		Use:   "prompts",
+		Short: "List and get prompts",
+	}
+
+	listPromptsCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List prompts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := requestContext(cmd)
+			defer cancel()
+			c, err := connect(ctx, cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			result, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+			if err != nil {
+				return fmt.Errorf("failed to list prompts: %w", err)
+			}
+			return printJSON(result, prettyOutput(cmd))
+		},
+	}
+
+	getPromptCmd = &cobra.Command{
+		Use:   "get <name>",
+		Short: "Get a prompt, with arguments given as --arg name=value",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := requestContext(cmd)
+			defer cancel()
+			c, err := connect(ctx, cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			request := mcp.GetPromptRequest{}
+			request.Params.Name = args[0]
+			request.Params.Arguments, _ = cmd.Flags().GetStringToString("arg")
+			result, err := c.GetPrompt(ctx, request)
+			if err != nil {
+				return fmt.Errorf("failed to get prompt: %w", err)
+			}
+			return printJSON(result, prettyOutput(cmd))
+		},
+	}
+
+	// completeCmd is not named completion, which cobra uses for shell
+	// completion scripts.
+	completeCmd = &cobra.Command{
+		Use:   "complete",
+		Short: "Complete an argument of a prompt or resource template",
+		Long:  "Asks the server for completions of --argument, whose value so far is --value, of the prompt --prompt or the resource template --resource.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			prompt, _ := cmd.Flags().GetString("prompt")
+			resource, _ := cmd.Flags().GetString("resource")
+			request := mcp.CompleteRequest{}
+			switch {
+			case prompt != "" && resource == "":
+				request.Params.Ref = mcp.PromptReference{Type: "ref/prompt", Name: prompt}
+			case resource != "" && prompt == "":
+				request.Params.Ref = mcp.ResourceReference{Type: "ref/resource", URI: resource}
+			default:
+				return fmt.Errorf("exactly one of --prompt or --resource is required")
+			}
+			request.Params.Argument.Name, _ = cmd.Flags().GetString("argument")
+			request.Params.Argument.Value, _ = cmd.Flags().GetString("value")
+
+			ctx, cancel := requestContext(cmd)
+			defer cancel()
+			c, err := connect(ctx, cmd.Flags())
+			if err != nil {
+				return err
+			}
+			result, err := c.Complete(ctx, request)
+			if err != nil {
+				return fmt.Errorf("failed to complete argument: %w", err)
+			}
+			return printJSON(result, prettyOutput(cmd))
+		},
+	}
+)
+
+func init() {
+	listResourcesCmd.Flags().Bool("templates", false, "List resource templates instead of resources")
+	resourcesCmd.AddCommand(listResourcesCmd, readResourceCmd)
+
+	getPromptCmd.Flags().StringToString("arg", nil, "Prompt argument as name=value (repeatable)")
+	promptsCmd.AddCommand(listPromptsCmd, getPromptCmd)
+
+	completeCmd.Flags().String("prompt", "", "Name of the prompt whose argument to complete")
+	completeCmd.Flags().String("resource", "", "URI template of the resource template whose argument to complete")
+	completeCmd.Flags().String("argument", "", "Name of the argument to complete")
+	completeCmd.Flags().String("value", "", "Value of the argument so far")
+	_ = completeCmd.MarkFlagRequired("argument")
+}
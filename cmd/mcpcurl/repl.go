@@ -0,0 +1,285 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/peterh/liner"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var replCmd = &cobra.Command{
+	Use:   "repl",
+	Short: "Run commands interactively in one session",
+	Long: `Reads mcpcurl commands, without the server flags, and runs them in one MCP session: "tools get_me", "resources list" or "prompts get name --arg key=value". A tool name alone calls the tool, as in "get_file_content --path README.md".
+
+Tab completes commands, tool names and flags. The history is kept in --history-file. "exit" or Ctrl-D ends the session.`,
+	Args: cobra.NoArgs,
+}
+
+// runREPL reads and runs commands until the input ends.
+func runREPL(cmd *cobra.Command, _ []string) error {
+	if err := loadToolCommands(cmd.Flags()); err != nil {
+		return fmt.Errorf("failed to fetch tools schema: %w", err)
+	}
+
+	line := liner.NewLiner()
+	defer func() { _ = line.Close() }()
+	line.SetCtrlCAborts(true)
+	line.SetWordCompleter(completeREPL)
+
+	historyFile, _ := cmd.Flags().GetString("history-file")
+	if historyFile != "" {
+		if f, err := os.Open(historyFile); err == nil {
+			_, _ = line.ReadHistory(f)
+			_ = f.Close()
+		}
+		defer func() {
+			if f, err := os.OpenFile(historyFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600); err == nil {
+				_, _ = line.WriteHistory(f)
+				_ = f.Close()
+			}
+		}()
+	}
+
+	for {
+		input, err := line.Prompt("mcp> ")
+		if errors.Is(err, liner.ErrPromptAborted) {
+			continue
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				fmt.Println()
+				return nil
+			}
+			return err
+		}
+		input = strings.TrimSpace(input)
+		if input == "" {
+			continue
+		}
+		line.AppendHistory(input)
+		if input == "exit" || input == "quit" {
+			return nil
+		}
+		if err := runREPLLine(input); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+}
+
+func init() {
+	// Set here, as the REPL refers back to replCmd
+	replCmd.RunE = runREPL
+
+	defaultHistory := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		defaultHistory = filepath.Join(home, ".mcpcurl_history")
+	}
+	replCmd.Flags().String("history-file", defaultHistory, "File to keep the command history in (empty for none)")
+}
+
+// runREPLLine runs a line of the REPL as a mcpcurl command.
+func runREPLLine(input string) error {
+	args, err := splitArgs(input)
+	if err != nil {
+		return err
+	}
+	args = replArgs(args)
+	target, _, err := rootCmd.Find(args)
+	if err != nil {
+		return err
+	}
+	if target == replCmd {
+		return fmt.Errorf("already in the REPL")
+	}
+	// Commands keep the flag values of their last run, so the flags of the
+	// command are reset and the global flags restored after the line
+	resetFlags(target.NonInheritedFlags())
+	defer restoreFlags(rootCmd.PersistentFlags(), saveFlags(rootCmd.PersistentFlags()))
+	rootCmd.SetArgs(args)
+	return rootCmd.Execute()
+}
+
+// replArgs returns the command line of args, calling the tool when the
+// first argument is the name of one.
+func replArgs(args []string) []string {
+	if len(args) > 0 && isToolName(args[0]) {
+		return append([]string{toolsCmd.Name()}, args...)
+	}
+	return args
+}
+
+func isToolName(name string) bool {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == name {
+			return false
+		}
+	}
+	for _, cmd := range toolsCmd.Commands() {
+		if cmd.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resetFlags sets flags back to their defaults.
+func resetFlags(flags *pflag.FlagSet) {
+	flags.VisitAll(func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			_ = slice.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+}
+
+// savedFlag is the state of a flag saved by saveFlags.
+type savedFlag struct {
+	value   []string
+	changed bool
+}
+
+// saveFlags returns the values of flags, to restore with restoreFlags.
+func saveFlags(flags *pflag.FlagSet) map[string]savedFlag {
+	saved := make(map[string]savedFlag)
+	flags.VisitAll(func(f *pflag.Flag) {
+		value := []string{f.Value.String()}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			value = slice.GetSlice()
+		}
+		saved[f.Name] = savedFlag{value: value, changed: f.Changed}
+	})
+	return saved
+}
+
+func restoreFlags(flags *pflag.FlagSet, saved map[string]savedFlag) {
+	flags.VisitAll(func(f *pflag.Flag) {
+		s, ok := saved[f.Name]
+		if !ok {
+			return
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			_ = slice.Replace(s.value)
+		} else {
+			_ = f.Value.Set(s.value[0])
+		}
+		f.Changed = s.changed
+	})
+}
+
+// completeREPL completes the word at pos: a command or tool name, or a flag
+// of the command before it.
+func completeREPL(line string, pos int) (string, []string, string) {
+	head, tail := line[:pos], line[pos:]
+	start := strings.LastIndexAny(head, " \t") + 1
+	word := head[start:]
+	words := strings.Fields(head[:start])
+
+	var candidates []string
+	switch {
+	case len(words) == 0:
+		candidates = append(commandNames(rootCmd), commandNames(toolsCmd)...)
+		candidates = append(candidates, "exit", "quit")
+	default:
+		cmd, _, err := rootCmd.Find(replArgs(words))
+		if err != nil {
+			return head, nil, tail
+		}
+		if strings.HasPrefix(word, "-") {
+			candidates = flagNames(cmd)
+		} else {
+			candidates = commandNames(cmd)
+		}
+	}
+
+	var completions []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, word) {
+			completions = append(completions, candidate)
+		}
+	}
+	slices.Sort(completions)
+	return head[:start], slices.Compact(completions), tail
+}
+
+func commandNames(cmd *cobra.Command) []string {
+	var names []string
+	for _, sub := range cmd.Commands() {
+		if sub.IsAvailableCommand() && sub != replCmd {
+			names = append(names, sub.Name())
+		}
+	}
+	return names
+}
+
+func flagNames(cmd *cobra.Command) []string {
+	var names []string
+	add := func(f *pflag.Flag) {
+		if !f.Hidden {
+			names = append(names, "--"+f.Name)
+		}
+	}
+	cmd.LocalFlags().VisitAll(add)
+	cmd.InheritedFlags().VisitAll(add)
+	return names
+}
+
+// splitArgs splits a line into arguments like a shell: at unquoted spaces,
+// with single and double quotes and backslash escapes.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unclosed %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/scenario"
+	"github.com/spf13/cobra"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run <scenario.yaml>...",
+	Short: "Run scenarios of requests with captures and assertions",
+	Long: `Runs the steps of each scenario file in order, in one MCP session. Each step calls a tool, reads a resource or gets a prompt, captures values of the result into variables, and checks assertions on it, selecting values with JSONPath:
+
+  name: file round trip
+  steps:
+    - tool: get_file_list
+      arguments: {extension: go, per_page: 5}
+      capture:
+        first: $.content[0].json[0]
+      assert:
+        - path: $.content[0].json
+          length: 5
+    - tool: get_file_content
+      arguments: {path: "${first}"}
+      assert:
+        - path: $.content[0].text
+          contains: package
+
+Text content holding JSON is also decoded into a json field next to its text. Assertions check equals, contains, matches (a regular expression), exists and length. A step with expect_error: true passes only if the request fails or the tool returns an error.
+
+The exit status is non-zero if any step fails.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scenarios := make([]*scenario.Scenario, 0, len(args))
+		for _, path := range args {
+			s, err := scenario.Load(path)
+			if err != nil {
+				return err
+			}
+			if s.Name == "" {
+				s.Name = path
+			}
+			scenarios = append(scenarios, s)
+		}
+		flagVars, _ := cmd.Flags().GetStringToString("var")
+		vars := make(map[string]any, len(flagVars))
+		for name, value := range flagVars {
+			vars[name] = value
+		}
+
+		ctx, cancel := requestContext(cmd)
+		c, err := connect(ctx, cmd.Flags())
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		steps, failed := 0, 0
+		for _, s := range scenarios {
+			report := scenario.Run(cmd.Context(), c, s, vars)
+			printReport(cmd.OutOrStdout(), report)
+			steps += len(report.Steps)
+			failed += report.Failed()
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%d steps, %d failed\n", steps, failed)
+		if failed > 0 {
+			return fmt.Errorf("%d of %d steps failed", failed, steps)
+		}
+		return nil
+	},
+}
+
+func init() {
+	runCmd.Flags().StringToString("var", nil, "Variable as name=value, overriding the vars of the scenarios (repeatable)")
+}
+
+// printReport writes a line per step of report, followed by its failures.
+func printReport(w io.Writer, report *scenario.Report) {
+	_, _ = fmt.Fprintf(w, "=== %s\n", report.Name)
+	for _, step := range report.Steps {
+		status := "PASS"
+		if !step.Passed() {
+			status = "FAIL"
+		}
+		_, _ = fmt.Fprintf(w, "%s %s (%s)\n", status, step.Name, step.Duration.Round(time.Millisecond))
+		for _, failure := range step.Failures {
+			_, _ = fmt.Fprintf(w, "    %s\n", failure)
+		}
+	}
+}
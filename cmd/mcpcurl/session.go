@@ -0,0 +1,376 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	transportHTTP = "http"
+	transportSSE  = "sse"
+
+	// stderrTail is the number of bytes of the server's stderr kept to report
+	// with a failed connection.
+	stderrTail = 4096
+)
+
+// session is the MCP session shared by every request of an invocation. It
+// is initialized once, on first use.
+var session struct {
+	client *client.Client
+	// info is the result of the initialize request, saved in the session
+	// file when the session is reused across invocations
+	info   *mcp.InitializeResult
+	file   string
+	stderr *tailBuffer
+}
+
+// sessionState is the content of a session file.
+type sessionState struct {
+	URL             string                `json:"url"`
+	SessionID       string                `json:"session_id"`
+	ProtocolVersion string                `json:"protocol_version"`
+	Info            *mcp.InitializeResult `json:"info,omitempty"`
+}
+
+// addConnectionFlags adds the flags selecting the server to connect to.
+func addConnectionFlags(flags *pflag.FlagSet) {
+	flags.String("stdio-server-cmd", "", "Shell command to invoke MCP server via stdio")
+	flags.String("url", "", "URL of a Streamable HTTP or SSE MCP server")
+	flags.String("transport", transportHTTP, "Transport of --url: http (Streamable HTTP) or sse")
+	flags.StringArrayP("header", "H", nil, "Header to send to --url, as 'Name: value' (repeatable)")
+	flags.String("token", "", "Bearer token to send to --url in the Authorization header")
+	flags.String("session-file", "", "File to keep the Streamable HTTP session in, so that later invocations reuse it")
+	flags.Duration("timeout", 60*time.Second, "Timeout of each request, including connecting and initializing (0 for none)")
+	flags.Bool("verbose", false, "Copy the stderr of the --stdio-server-cmd server to stderr")
+}
+
+// validateConnectionFlags checks that exactly one server is given.
+func validateConnectionFlags(flags *pflag.FlagSet) error {
+	serverCmd, _ := flags.GetString("stdio-server-cmd")
+	serverURL, _ := flags.GetString("url")
+	switch {
+	case serverCmd == "" && serverURL == "":
+		return fmt.Errorf("one of --stdio-server-cmd or --url is required")
+	case serverCmd != "" && serverURL != "":
+		return fmt.Errorf("--stdio-server-cmd and --url cannot be used together")
+	}
+	kind, _ := flags.GetString("transport")
+	if kind != transportHTTP && kind != transportSSE {
+		return fmt.Errorf("--transport must be %s or %s", transportHTTP, transportSSE)
+	}
+	if file, _ := flags.GetString("session-file"); file != "" && (serverURL == "" || kind != transportHTTP) {
+		return fmt.Errorf("--session-file requires --url with the %s transport", transportHTTP)
+	}
+	return nil
+}
+
+// requestContext returns the context of a request to the server, bounded by
+// --timeout.
+func requestContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	return timeoutContext(cmd.Context(), cmd.Flags())
+}
+
+// timeoutContext returns ctx bounded by the --timeout of flags, if not 0.
+func timeoutContext(ctx context.Context, flags *pflag.FlagSet) (context.Context, context.CancelFunc) {
+	timeout, _ := flags.GetDuration("timeout")
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// connect returns the session's client, connecting to the server and
+// initializing the session on first use. With --session-file, a session
+// saved by an earlier invocation is resumed instead, if the server still
+// knows it.
+func connect(ctx context.Context, flags *pflag.FlagSet) (*client.Client, error) {
+	if session.client != nil {
+		return session.client, nil
+	}
+	if err := validateConnectionFlags(flags); err != nil {
+		return nil, err
+	}
+
+	serverURL, _ := flags.GetString("url")
+	session.file, _ = flags.GetString("session-file")
+	if session.file != "" {
+		state, err := readSessionState(session.file)
+		if err != nil {
+			return nil, err
+		}
+		if state != nil && state.URL == serverURL {
+			c, err := resumeSession(ctx, flags, state)
+			if err == nil {
+				session.client, session.info = c, state.Info
+				return c, nil
+			}
+			if !errors.Is(err, transport.ErrSessionTerminated) {
+				return nil, err
+			}
+		}
+	}
+
+	c, err := newClient(flags, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect to MCP server: %w%s", err, session.stderr)
+	}
+	info, err := c.Initialize(ctx, mcp.InitializeRequest{
+		Params: mcp.InitializeParams{
+			ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+			ClientInfo:      mcp.Implementation{Name: "mcpcurl", Version: "dev"},
+		},
+	})
+	if err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("failed to initialize MCP session: %w%s", err, session.stderr)
+	}
+	session.client, session.info = c, info
+
+	if session.file != "" {
+		state := sessionState{
+			URL:             serverURL,
+			SessionID:       c.GetSessionId(),
+			ProtocolVersion: info.ProtocolVersion,
+			Info:            info,
+		}
+		if err := writeSessionState(session.file, state); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// resumeSession reconnects to the session of state, and checks with a ping
+// that the server still knows it.
+func resumeSession(ctx context.Context, flags *pflag.FlagSet, state *sessionState) (*client.Client, error) {
+	c, err := newClient(flags, state.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect to MCP server: %w", err)
+	}
+	if conn, ok := c.GetTransport().(transport.HTTPConnection); ok && state.ProtocolVersion != "" {
+		conn.SetProtocolVersion(state.ProtocolVersion)
+	}
+	if err := c.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// newClient creates the client of the server selected by flags. sessionID
+// resumes a Streamable HTTP session.
+func newClient(flags *pflag.FlagSet, sessionID string) (*client.Client, error) {
+	if serverCmd, _ := flags.GetString("stdio-server-cmd"); serverCmd != "" {
+		return newStdioClient(flags, serverCmd)
+	}
+
+	serverURL, _ := flags.GetString("url")
+	headers, err := requestHeaders(flags)
+	if err != nil {
+		return nil, err
+	}
+	if kind, _ := flags.GetString("transport"); kind == transportSSE {
+		sse, err := transport.NewSSE(serverURL, transport.WithHeaders(headers))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SSE transport: %w", err)
+		}
+		return client.NewClient(sse), nil
+	}
+
+	opts := []transport.StreamableHTTPCOption{transport.WithHTTPHeaders(headers)}
+	var clientOpts []client.ClientOption
+	if sessionID != "" {
+		opts = append(opts, transport.WithSession(sessionID))
+		clientOpts = append(clientOpts, client.WithSession())
+	}
+	streamable, err := transport.NewStreamableHTTP(serverURL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Streamable HTTP transport: %w", err)
+	}
+	return client.NewClient(streamable, clientOpts...), nil
+}
+
+// newStdioClient starts serverCmd. Its stderr is copied to ours with
+// --verbose, else only its tail is kept, to report with connection errors.
+func newStdioClient(flags *pflag.FlagSet, serverCmd string) (*client.Client, error) {
+	verbose, _ := flags.GetBool("verbose")
+	var opts []transport.StdioOption
+	if !verbose {
+		// The transport logs the server's stdout closing when it is stopped
+		opts = append(opts, transport.WithCommandLogger(quietLogger{}))
+	}
+	cmdParts := strings.Fields(serverCmd)
+	stdio := transport.NewStdioWithOptions(cmdParts[0], nil, cmdParts[1:], opts...)
+	if err := stdio.Start(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to start command: %w", err)
+	}
+
+	var w io.Writer = os.Stderr
+	if !verbose {
+		session.stderr = &tailBuffer{max: stderrTail}
+		w = session.stderr
+	}
+	go func() { _, _ = io.Copy(w, stdio.Stderr()) }()
+	return client.NewClient(stdio), nil
+}
+
+// requestHeaders returns the headers of --header and --token.
+func requestHeaders(flags *pflag.FlagSet) (map[string]string, error) {
+	headers := make(map[string]string)
+	values, _ := flags.GetStringArray("header")
+	for _, value := range values {
+		name, v, ok := strings.Cut(value, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q: want 'Name: value'", value)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(v)
+	}
+	if token, _ := flags.GetString("token"); token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	return headers, nil
+}
+
+// closeSession closes the session's client, which stops a stdio server.
+// A session kept in a session file is left open for later invocations.
+func closeSession() {
+	if session.client != nil && session.file == "" {
+		_ = session.client.Close()
+	}
+}
+
+func readSessionState(path string) (*sessionState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+	var state sessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse session file %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+func writeSessionState(path string, state sessionState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	return nil
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+	max int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.max {
+		b.buf = b.buf[len(b.buf)-b.max:]
+	}
+	return len(p), nil
+}
+
+// String formats the buffer for the end of an error message.
+func (b *tailBuffer) String() string {
+	if b == nil {
+		return ""
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(bytes.TrimSpace(b.buf)) == 0 {
+		return ""
+	}
+	return ", stderr: " + string(b.buf)
+}
+
+// quietLogger discards the logs of the transport.
+type quietLogger struct{}
+
+func (quietLogger) Infof(string, ...any)  {}
+func (quietLogger) Errorf(string, ...any) {}
+
+var (
+	sessionCmd = &cobra.Command{
+		Use:   "session",
+		Short: "Inspect and end the MCP session",
+	}
+
+	sessionInfoCmd = &cobra.Command{
+		Use:   "info",
+		Short: "Show the server info, capabilities and session id",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := requestContext(cmd)
+			defer cancel()
+			c, err := connect(ctx, cmd.Flags())
+			if err != nil {
+				return err
+			}
+			return printJSON(struct {
+				SessionID string                `json:"session_id,omitempty"`
+				Info      *mcp.InitializeResult `json:"info"`
+			}{c.GetSessionId(), session.info}, prettyOutput(cmd))
+		},
+	}
+
+	sessionCloseCmd = &cobra.Command{
+		Use:   "close",
+		Short: "End the session kept in --session-file",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			file, _ := cmd.Flags().GetString("session-file")
+			if file == "" {
+				return fmt.Errorf("--session-file is required")
+			}
+			state, err := readSessionState(file)
+			if err != nil || state == nil {
+				return err
+			}
+			c, err := newClient(cmd.Flags(), state.SessionID)
+			if err != nil {
+				return err
+			}
+			// Closing a Streamable HTTP client ends its session on the server
+			_ = c.Close()
+			if err := os.Remove(file); err != nil {
+				return fmt.Errorf("failed to remove session file: %w", err)
+			}
+			return nil
+		},
+	}
+)
+
+func init() {
+	sessionCmd.AddCommand(sessionInfoCmd, sessionCloseCmd)
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"slices"
 	"strings"
 	"sync"
@@ -16,6 +17,7 @@ import (
 	"time"
 
 	"github.com/github/github-mcp-server/internal/ghmcp"
+	"github.com/github/github-mcp-server/internal/githubfake"
 	"github.com/github/github-mcp-server/pkg/github"
 	"github.com/github/github-mcp-server/pkg/translations"
 	gogithub "github.com/google/go-github/v74/github"
@@ -34,11 +36,33 @@ var (
 
 	buildOnce  sync.Once
 	buildError error
+
+	fakeOnce   sync.Once
+	fakeServer *githubfake.Server
 )
 
+// isFakeMode reports whether the tests run hermetically against an in-process fake GitHub.
+func isFakeMode() bool {
+	return os.Getenv("GITHUB_MCP_SERVER_E2E_FAKE") != ""
+}
+
+// getFakeServer starts the shared fake GitHub server on first use. Tests create
+// uniquely named resources, so a single fake can be shared by parallel tests.
+func getFakeServer() *githubfake.Server {
+	fakeOnce.Do(func() {
+		fakeServer = githubfake.New()
+	})
+	return fakeServer
+}
+
 // getE2EToken ensures the environment variable is checked only once and returns the token
 func getE2EToken(t *testing.T) string {
 	getTokenOnce.Do(func() {
+		if isFakeMode() {
+			// The fake accepts any credentials
+			token = "fake-token"
+			return
+		}
 		token = os.Getenv("GITHUB_MCP_SERVER_E2E_TOKEN")
 		if token == "" {
 			t.Fatalf("GITHUB_MCP_SERVER_E2E_TOKEN environment variable is not set")
@@ -50,11 +74,25 @@ func getE2EToken(t *testing.T) string {
 // getE2EHost ensures the environment variable is checked only once and returns the host
 func getE2EHost() string {
 	getHostOnce.Do(func() {
+		if isFakeMode() {
+			host = getFakeServer().URL
+			return
+		}
 		host = os.Getenv("GITHUB_MCP_SERVER_E2E_HOST")
 	})
 	return host
 }
 
+// getCassetteDir returns a per-test cassette directory beneath the directory named by envVar,
+// or an empty string if the variable is not set. Tests run in parallel, so each needs its own cassette.
+func getCassetteDir(t *testing.T, envVar string) string {
+	dir := os.Getenv(envVar)
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, t.Name())
+}
+
 func getRESTClient(t *testing.T) *gogithub.Client {
 	// Get token and ensure Docker image is built
 	token := getE2EToken(t)
@@ -121,8 +159,9 @@ func setupMCPClient(t *testing.T, options ...clientOption) *mcpClient.Client {
 
 	// By default, we run the tests including the Docker image, but with DEBUG
 	// enabled, we run the server in-process, allowing for easier debugging.
+	// The fake GitHub server only exists in this process, so it forces in-process mode too.
 	var client *mcpClient.Client
-	if os.Getenv("GITHUB_MCP_SERVER_E2E_DEBUG") == "" {
+	if os.Getenv("GITHUB_MCP_SERVER_E2E_DEBUG") == "" && !isFakeMode() {
 		ensureDockerImageBuilt(t)
 
 		// Prepare Docker arguments
@@ -177,6 +216,8 @@ func setupMCPClient(t *testing.T, options ...clientOption) *mcpClient.Client {
 			EnabledToolsets: enabledToolsets,
 			Host:            getE2EHost(),
 			Translator:      translations.NullTranslationHelper,
+			RecordDir:       getCassetteDir(t, "GITHUB_MCP_SERVER_E2E_RECORD"),
+			ReplayDir:       getCassetteDir(t, "GITHUB_MCP_SERVER_E2E_REPLAY"),
 		})
 		require.NoError(t, err, "expected to construct MCP server successfully")
 
@@ -1624,3 +1665,102 @@ func TestPullRequestReviewDeletion(t *testing.T) {
 	require.NoError(t, err, "expected to unmarshal text content successfully")
 	require.Len(t, noReviews, 0, "expected to find no reviews")
 }
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	mcpClient := setupMCPClient(t)
+
+	ctx := context.Background()
+
+	// First, who am I
+	getMeRequest := mcp.CallToolRequest{}
+	getMeRequest.Params.Name = "get_me"
+
+	t.Log("Getting current user...")
+	resp, err := mcpClient.CallTool(ctx, getMeRequest)
+	require.NoError(t, err, "expected to call 'get_me' tool successfully")
+	require.False(t, resp.IsError, fmt.Sprintf("expected result not to be an error: %+v", resp))
+
+	textContent, ok := resp.Content[0].(mcp.TextContent)
+	require.True(t, ok, "expected content to be of type TextContent")
+
+	var trimmedGetMeText struct {
+		Login string `json:"login"`
+	}
+	err = json.Unmarshal([]byte(textContent.Text), &trimmedGetMeText)
+	require.NoError(t, err, "expected to unmarshal text content successfully")
+
+	currentOwner := trimmedGetMeText.Login
+
+	// Then create a repository with a README (via autoInit)
+	repoName := fmt.Sprintf("github-mcp-server-e2e-%s-%d", t.Name(), time.Now().UnixMilli())
+	createRepoRequest := mcp.CallToolRequest{}
+	createRepoRequest.Params.Name = "create_repository"
+	createRepoRequest.Params.Arguments = map[string]any{
+		"name":     repoName,
+		"private":  true,
+		"autoInit": true,
+	}
+	t.Logf("Creating repository %s/%s...", currentOwner, repoName)
+	resp, err = mcpClient.CallTool(ctx, createRepoRequest)
+	require.NoError(t, err, "expected to call 'create_repository' tool successfully")
+	require.False(t, resp.IsError, fmt.Sprintf("expected result not to be an error: %+v", resp))
+
+	// Cleanup the repository after the test
+	t.Cleanup(func() {
+		// MCP Server doesn't support deletions, but we can use the GitHub Client
+		ghClient := getRESTClient(t)
+		t.Logf("Deleting repository %s/%s...", currentOwner, repoName)
+		_, err := ghClient.Repositories.Delete(context.Background(), currentOwner, repoName)
+		require.NoError(t, err, "expected to delete repository successfully")
+	})
+
+	ghClient := getRESTClient(t)
+	ref, _, err := ghClient.Git.GetRef(ctx, currentOwner, repoName, "refs/heads/main")
+	require.NoError(t, err, "expected to get main branch successfully")
+	headSHA := ref.GetObject().GetSHA()
+
+	// Push files in dry-run mode
+	pushFilesRequest := mcp.CallToolRequest{}
+	pushFilesRequest.Params.Name = "push_files"
+	pushFilesRequest.Params.Arguments = map[string]any{
+		"owner":   currentOwner,
+		"repo":    repoName,
+		"branch":  "main",
+		"message": "Add dry run file",
+		"files": []any{
+			map[string]any{"path": "dry-run.txt", "content": "would be created\n"},
+		},
+		"dry_run": true,
+	}
+
+	t.Logf("Pushing files to %s/%s in dry-run mode...", currentOwner, repoName)
+	resp, err = mcpClient.CallTool(ctx, pushFilesRequest)
+	require.NoError(t, err, "expected to call 'push_files' tool successfully")
+	require.False(t, resp.IsError, fmt.Sprintf("expected result not to be an error: %+v", resp))
+
+	textContent, ok = resp.Content[0].(mcp.TextContent)
+	require.True(t, ok, "expected content to be of type TextContent")
+
+	var preview struct {
+		DryRun   bool `json:"dry_run"`
+		Requests []struct {
+			Method string `json:"method"`
+			URL    string `json:"url"`
+			Diff   string `json:"diff"`
+		} `json:"requests"`
+	}
+	err = json.Unmarshal([]byte(textContent.Text), &preview)
+	require.NoError(t, err, "expected to unmarshal text content successfully")
+	require.True(t, preview.DryRun, "expected a dry-run preview")
+	require.Len(t, preview.Requests, 3, "expected tree, commit and ref update requests")
+	require.Contains(t, preview.Requests[0].URL, "/git/trees", "expected the tree to be created first")
+	require.Contains(t, preview.Requests[0].Diff, "+would be created", "expected the diff of the new file")
+	require.Equal(t, http.MethodPatch, preview.Requests[2].Method, "expected the branch to be updated last")
+
+	// The branch was not changed
+	ref, _, err = ghClient.Git.GetRef(ctx, currentOwner, repoName, "refs/heads/main")
+	require.NoError(t, err, "expected to get main branch successfully")
+	require.Equal(t, headSHA, ref.GetObject().GetSHA(), "expected main not to move in dry-run mode")
+}
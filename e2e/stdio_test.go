@@ -0,0 +1,391 @@
+package e2e_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/githubfake"
+	mcpClient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStdioBinary builds mcp-prime and talks to "mcp-prime stdio" over its
+// stdin and stdout, against the fake GitHub, so that the flags are checked on
+// the server the binary actually runs. It needs neither a token nor Docker, so
+// it runs without the e2e build tag.
+func TestStdioBinary(t *testing.T) {
+	bin := buildMCPPrime(t)
+
+	fake := githubfake.New()
+	t.Cleanup(fake.Close)
+
+	recordDir := t.TempDir()
+	metricsAddr := freeAddr(t)
+	logFile := filepath.Join(t.TempDir(), "mcp-prime.log")
+	auditLog := filepath.Join(t.TempDir(), "audit.jsonl")
+	policyFile := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(policyFile, []byte("rules:\n  - effect: deny\n    tools: [fork_repository]\n    message: forks are not allowed\n"), 0600))
+
+	session := startStdio(t, bin,
+		[]string{"GITHUB_PERSONAL_ACCESS_TOKEN=fake-token"},
+		"--gh-host", fake.URL,
+		"--record", recordDir,
+		"--metrics-addr", metricsAddr,
+		"--trace-exporter", "console",
+		"--log-file", logFile,
+		"--audit-log", auditLog,
+		"--policy-file", policyFile,
+		"--exclude-tools", "emit_tool_json",
+		"--enable-command-logging",
+		"--log-redact", "secret-[a-z]+",
+	)
+
+	names := session.toolNames()
+	// The repository toolset is enabled, the GitHub toolsets can be enabled
+	assert.True(t, names["get_file_list"], "expected get_file_list from the repository toolset")
+	assert.True(t, names["enable_toolset"], "expected the dynamic toolset tools")
+	assert.False(t, names["get_me"], "expected the context toolset to be disabled")
+	assert.False(t, names["emit_tool_json"], "expected --exclude-tools to drop emit_tool_json")
+
+	// search_tools ranks the repository tools with the GitHub ones
+	found := session.callOK("search_tools", map[string]any{"query": "extract function signatures from source code"})
+	assert.Contains(t, found.Content[0].(mcp.TextContent).Text, "extract_signatures")
+
+	session.callOK("enable_toolset", map[string]any{"toolset": "context"})
+	session.callOK("enable_toolset", map[string]any{"toolset": "repos"})
+	me := session.callOK("get_me", nil)
+	assert.NotNil(t, me.StructuredContent, "expected get_me to return structured content")
+	session.callOK("create_repository", map[string]any{"name": "audited", "description": "secret-sauce"})
+
+	// GitHub errors come with their kind and a hint
+	missing := session.call("list_branches", map[string]any{"owner": githubfake.DefaultLogin, "repo": "missing"})
+	require.True(t, missing.IsError, "expected list_branches of a missing repository to fail")
+	assert.Contains(t, missing.Content[len(missing.Content)-1].(mcp.TextContent).Text, "[error_kind: not_found]")
+
+	// --policy-file denies the fork
+	denied := session.call("fork_repository", map[string]any{"owner": githubfake.DefaultLogin, "repo": "audited"})
+	require.True(t, denied.IsError, "expected the policy to deny fork_repository")
+	assert.Contains(t, denied.Content[0].(mcp.TextContent).Text, "forks are not allowed")
+
+	// --metrics-addr serves the tool call and API request counters
+	var body []byte
+	require.Eventually(t, func() bool {
+		metricsResponse, err := http.Get("http://" + metricsAddr + "/metrics")
+		if err != nil {
+			return false
+		}
+		defer func() { _ = metricsResponse.Body.Close() }()
+		body, err = io.ReadAll(metricsResponse.Body)
+		return err == nil
+	}, 10*time.Second, 50*time.Millisecond, "expected to scrape metrics")
+	assert.Contains(t, string(body), `mcp_tool_calls_total{tool="get_me"} 1`)
+	assert.Contains(t, string(body), "github_api_requests_total")
+
+	session.close()
+
+	// --gh-host and --record reached the GitHub client
+	cassette, err := os.ReadFile(filepath.Join(recordDir, "rest.jsonl"))
+	require.NoError(t, err, "expected a REST cassette")
+	assert.Contains(t, string(cassette), fake.URL+"/api/v3/user")
+	assert.NotContains(t, string(cassette), "fake-token")
+
+	// --trace-exporter writes the spans to the log on shutdown
+	logs, err := os.ReadFile(logFile)
+	require.NoError(t, err, "expected a log file")
+	assert.Contains(t, string(logs), `"Name":"tools/call get_me"`)
+
+	// --enable-command-logging logs the messages, with --log-redact applied
+	assert.Contains(t, string(logs), "create_repository")
+	assert.NotContains(t, string(logs), "secret-sauce")
+
+	// GitHub errors are logged to the server's log, not the default logger
+	assert.Contains(t, string(logs), `msg="GitHub API error" tool=list_branches kind=not_found`)
+
+	// --audit-log records the write tool calls, the denied one too, with their
+	// caller, in a chain verify-audit accepts
+	records, err := os.ReadFile(auditLog)
+	require.NoError(t, err, "expected an audit log")
+	assert.Contains(t, string(records), `"tool":"create_repository"`)
+	assert.Contains(t, string(records), `"login":"`+githubfake.DefaultLogin+`"`)
+	verify, err := exec.Command(bin, "verify-audit", auditLog).CombinedOutput()
+	require.NoError(t, err, "expected verify-audit to pass: %s", verify)
+	assert.Contains(t, string(verify), "OK: 2 entries")
+}
+
+// TestStdioBinaryDryRun checks that "mcp-prime stdio --dry-run" previews write
+// tool calls instead of sending them.
+func TestStdioBinaryDryRun(t *testing.T) {
+	bin := buildMCPPrime(t)
+
+	fake := githubfake.New()
+	t.Cleanup(fake.Close)
+
+	session := startStdio(t, bin,
+		[]string{"GITHUB_PERSONAL_ACCESS_TOKEN=fake-token"},
+		"--gh-host", fake.URL,
+		"--dry-run",
+	)
+	defer session.close()
+
+	session.callOK("enable_toolset", map[string]any{"toolset": "repos"})
+	preview := session.callOK("create_repository", map[string]any{"name": "previewed"})
+	text := preview.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"dry_run":true`)
+	assert.Contains(t, text, "/user/repos")
+
+	// The repository was not created
+	response, err := http.Get(fake.URL + "/api/v3/repos/" + githubfake.DefaultLogin + "/previewed")
+	require.NoError(t, err)
+	defer func() { _ = response.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+// TestStdioBinaryConfirm checks that "mcp-prime stdio --confirm-tools" refuses
+// calls it cannot confirm, this client not supporting elicitation, and lets
+// dry runs through.
+func TestStdioBinaryConfirm(t *testing.T) {
+	bin := buildMCPPrime(t)
+
+	fake := githubfake.New()
+	t.Cleanup(fake.Close)
+
+	session := startStdio(t, bin,
+		[]string{"GITHUB_PERSONAL_ACCESS_TOKEN=fake-token"},
+		"--gh-host", fake.URL,
+		"--confirm-tools", "create_*",
+	)
+	defer session.close()
+
+	session.callOK("enable_toolset", map[string]any{"toolset": "repos"})
+	refused := session.call("create_repository", map[string]any{"name": "unconfirmed"})
+	require.True(t, refused.IsError, "expected create_repository to be refused")
+	assert.Contains(t, refused.Content[0].(mcp.TextContent).Text, "confirmation_unavailable")
+
+	// A dry run sends nothing, so it needs no confirmation
+	preview := session.callOK("create_repository", map[string]any{"name": "previewed", "dry_run": true})
+	assert.Contains(t, preview.Content[0].(mcp.TextContent).Text, `"dry_run":true`)
+
+	// Neither repository was created
+	for _, name := range []string{"unconfirmed", "previewed"} {
+		response, err := http.Get(fake.URL + "/api/v3/repos/" + githubfake.DefaultLogin + "/" + name)
+		require.NoError(t, err)
+		_ = response.Body.Close()
+		assert.Equal(t, http.StatusNotFound, response.StatusCode, name)
+	}
+}
+
+// TestStdioBinaryMaxEnabledToolsets checks that "mcp-prime stdio
+// --max-enabled-toolsets" disables the least recently used toolset.
+func TestStdioBinaryMaxEnabledToolsets(t *testing.T) {
+	bin := buildMCPPrime(t)
+
+	fake := githubfake.New()
+	t.Cleanup(fake.Close)
+
+	session := startStdio(t, bin,
+		[]string{"GITHUB_PERSONAL_ACCESS_TOKEN=fake-token"},
+		"--gh-host", fake.URL,
+		"--max-enabled-toolsets", "1",
+	)
+	defer session.close()
+
+	session.callOK("enable_toolset", map[string]any{"toolset": "context"})
+	assert.True(t, session.toolNames()["get_me"], "expected the context toolset to be enabled")
+
+	session.callOK("enable_toolset", map[string]any{"toolset": "repos"})
+	names := session.toolNames()
+	assert.True(t, names["create_repository"], "expected the repos toolset to be enabled")
+	assert.False(t, names["get_me"], "expected the context toolset to be disabled")
+}
+
+// TestStdioBinaryResponseBudget checks that "mcp-prime stdio --response-budget"
+// cuts large results and continue_result returns the rest.
+func TestStdioBinaryResponseBudget(t *testing.T) {
+	bin := buildMCPPrime(t)
+
+	fake := githubfake.New()
+	t.Cleanup(fake.Close)
+
+	session := startStdio(t, bin,
+		[]string{"GITHUB_PERSONAL_ACCESS_TOKEN=fake-token"},
+		"--gh-host", fake.URL,
+		"--response-budget", "100",
+		"--response-budget-unit", "chars",
+	)
+	defer session.close()
+
+	// The repository tools read the checkout the server runs in, this directory
+	first := session.callOK("get_file_content", map[string]any{"path": "stdio_test.go"})
+	require.Len(t, first.Content, 2, "expected the result to be cut")
+	notice := first.Content[1].(mcp.TextContent).Text
+	match := regexp.MustCompile(`with token "([^"]+)"`).FindStringSubmatch(notice)
+	require.NotNil(t, match, "expected a continuation token in %q", notice)
+
+	next := session.callOK("continue_result", map[string]any{"token": match[1]})
+	assert.NotEqual(t, first.Content[0].(mcp.TextContent).Text, next.Content[0].(mcp.TextContent).Text)
+}
+
+// TestStdioBinaryLocale checks that "mcp-prime stdio --locale" describes the
+// tools with the bundle from --locales-dir.
+func TestStdioBinaryLocale(t *testing.T) {
+	bin := buildMCPPrime(t)
+
+	localesDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(localesDir, "es.json"), []byte(`{"TOOL_GET_ME_DESCRIPTION": "Obtener el usuario autenticado"}`), 0600))
+
+	session := startStdio(t, bin, nil,
+		"--toolsets", "context",
+		"--dynamic-toolsets=false",
+		"--locale", "es-MX",
+		"--locales-dir", localesDir,
+	)
+	defer session.close()
+
+	tools, err := session.client.ListTools(session.ctx, mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	descriptions := make(map[string]string, len(tools.Tools))
+	for _, tool := range tools.Tools {
+		descriptions[tool.Name] = tool.Description
+	}
+	// es-MX falls back to the es bundle
+	assert.Equal(t, "Obtener el usuario autenticado", descriptions["get_me"])
+}
+
+// TestStdioBinaryConfigFile checks that "mcp-prime stdio --config" takes its
+// settings from the file, and reloads them when it changes.
+func TestStdioBinaryConfigFile(t *testing.T) {
+	bin := buildMCPPrime(t)
+
+	fake := githubfake.New()
+	t.Cleanup(fake.Close)
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(
+		"host: "+fake.URL+"\n"+
+			"token-env: FAKE_GITHUB_TOKEN\n"+
+			"toolsets: [context, repository]\n"+
+			"dynamic-toolsets: false\n"+
+			"tools:\n  get_me:\n    description: Look up the signed-in user\n"), 0600))
+
+	session := startStdio(t, bin, []string{"FAKE_GITHUB_TOKEN=fake-token"}, "--config", configFile)
+	defer session.close()
+
+	tools, err := session.client.ListTools(session.ctx, mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	descriptions := make(map[string]string, len(tools.Tools))
+	for _, tool := range tools.Tools {
+		descriptions[tool.Name] = tool.Description
+	}
+	assert.Equal(t, "Look up the signed-in user", descriptions["get_me"])
+	assert.Contains(t, descriptions, "get_file_list")
+	assert.NotContains(t, descriptions, "enable_toolset")
+
+	me := session.callOK("get_me", nil)
+	assert.Contains(t, me.Content[0].(mcp.TextContent).Text, githubfake.DefaultLogin)
+
+	// Changing the file reloads the toolsets
+	require.NoError(t, os.WriteFile(configFile, []byte(
+		"host: "+fake.URL+"\n"+
+			"token-env: FAKE_GITHUB_TOKEN\n"+
+			"toolsets: [repository]\n"+
+			"dynamic-toolsets: false\n"), 0600))
+	require.Eventually(t, func() bool {
+		return !session.toolNames()["get_me"]
+	}, 10*time.Second, 50*time.Millisecond, "expected the reload to remove get_me")
+	assert.True(t, session.toolNames()["get_file_list"], "expected get_file_list to stay")
+}
+
+// buildMCPPrime builds the mcp-prime binary.
+func buildMCPPrime(t *testing.T) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "mcp-prime")
+	build := exec.Command("go", "build", "-o", bin, "./cmd/mcp-prime")
+	build.Dir = ".."
+	output, err := build.CombinedOutput()
+	require.NoError(t, err, "expected to build mcp-prime: %s", output)
+	return bin
+}
+
+// stdioSession is a client of a running "mcp-prime stdio".
+type stdioSession struct {
+	t      *testing.T
+	ctx    context.Context
+	client *mcpClient.Client
+}
+
+// startStdio runs "mcp-prime stdio" with env and args and initializes a client
+// of it. The server does not see the config file of the user running the test.
+func startStdio(t *testing.T, bin string, env []string, args ...string) *stdioSession {
+	t.Helper()
+	env = append(env, "XDG_CONFIG_HOME="+t.TempDir())
+	client, err := mcpClient.NewStdioMCPClient(bin, env, append([]string{"stdio"}, args...)...)
+	require.NoError(t, err, "expected to start mcp-prime stdio")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = "2025-03-26"
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "e2e-test-client", Version: "0.0.1"}
+	result, err := client.Initialize(ctx, initRequest)
+	require.NoError(t, err, "failed to initialize client")
+	assert.Equal(t, "github-mcp-server", result.ServerInfo.Name)
+	return &stdioSession{t: t, ctx: ctx, client: client}
+}
+
+// toolNames lists the tools the server offers.
+func (s *stdioSession) toolNames() map[string]bool {
+	s.t.Helper()
+	tools, err := s.client.ListTools(s.ctx, mcp.ListToolsRequest{})
+	require.NoError(s.t, err)
+	names := make(map[string]bool, len(tools.Tools))
+	for _, tool := range tools.Tools {
+		names[tool.Name] = true
+	}
+	return names
+}
+
+// call calls the tool name with args.
+func (s *stdioSession) call(name string, args map[string]any) *mcp.CallToolResult {
+	s.t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	response, err := s.client.CallTool(s.ctx, request)
+	require.NoError(s.t, err, "expected to call %s", name)
+	return response
+}
+
+// callOK calls the tool name with args and expects it to succeed.
+func (s *stdioSession) callOK(name string, args map[string]any) *mcp.CallToolResult {
+	s.t.Helper()
+	response := s.call(name, args)
+	require.False(s.t, response.IsError, "expected %s to succeed: %v", name, response.Content)
+	return response
+}
+
+// close stops the server.
+func (s *stdioSession) close() {
+	s.t.Helper()
+	require.NoError(s.t, s.client.Close(), "expected to close client")
+}
+
+// freeAddr returns a local address nothing listens on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+	return addr
+}
@@ -0,0 +1,202 @@
+// Package audit keeps a tamper-evident record of write tool invocations.
+//
+// The log is a JSON Lines file. Every line holds one Record together with the
+// hash of the previous line and its own hash, computed as
+//
+//	sha256(prev + "\n" + record)
+//
+// over the exact record bytes stored on the line. Editing or deleting a line
+// breaks the chain at that point, which Verify reports. Truncating the tail of
+// the file cannot be detected from the file alone, so Verify also returns the
+// final hash for operators who anchor it elsewhere.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GenesisHash is the previous hash of the first entry in a log.
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// Outcomes of an audited tool call.
+const (
+	OutcomeSuccess = "success"
+	// OutcomeFailed means the tool returned an error result to the client.
+	OutcomeFailed = "failed"
+	// OutcomeError means the tool handler itself returned an error.
+	OutcomeError = "error"
+)
+
+// Caller identifies who invoked a tool.
+type Caller struct {
+	// Login is the GitHub user the server's token belongs to.
+	Login string `json:"login,omitempty"`
+	// Client is the MCP client name and version reported at initialization.
+	Client  string `json:"client,omitempty"`
+	Session string `json:"session,omitempty"`
+}
+
+// Record describes a single write tool invocation.
+type Record struct {
+	Seq       uint64         `json:"seq"`
+	Time      time.Time      `json:"time"`
+	Tool      string         `json:"tool"`
+	Caller    Caller         `json:"caller"`
+	Repo      string         `json:"repo,omitempty"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+	Outcome   string         `json:"outcome"`
+	Error     string         `json:"error,omitempty"`
+	URLs      []string       `json:"urls,omitempty"`
+	SHAs      []string       `json:"shas,omitempty"`
+}
+
+// line is the on-disk form of an entry. Record is kept raw so that hashes are
+// verified against the exact bytes that were written.
+type line struct {
+	Record json.RawMessage `json:"record"`
+	Prev   string          `json:"prev"`
+	Hash   string          `json:"hash"`
+}
+
+func chainHash(prev string, record []byte) string {
+	h := sha256.New()
+	h.Write([]byte(prev))
+	h.Write([]byte("\n"))
+	h.Write(record)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Log appends hash-chained records to a file.
+type Log struct {
+	mu   sync.Mutex
+	file *os.File
+	seq  uint64
+	prev string
+	now  func() time.Time
+}
+
+// Open opens the audit log at path, creating it if needed. An existing log is
+// verified first and new records continue its chain.
+func Open(path string) (*Log, error) {
+	l := &Log{prev: GenesisHash, now: time.Now}
+
+	existing, err := os.Open(path)
+	switch {
+	case err == nil:
+		result, verifyErr := Verify(existing)
+		_ = existing.Close()
+		if verifyErr != nil {
+			return nil, fmt.Errorf("existing audit log %s is invalid: %w", path, verifyErr)
+		}
+		l.seq, l.prev = uint64(result.Entries), result.LastHash
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	l.file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return l, nil
+}
+
+// Append assigns rec the next sequence number, and the current time if unset,
+// and writes it to the log.
+func (l *Log) Append(rec Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rec.Seq = l.seq + 1
+	if rec.Time.IsZero() {
+		rec.Time = l.now().UTC()
+	}
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	hash := chainHash(l.prev, raw)
+	out, err := json.Marshal(line{Record: raw, Prev: l.prev, Hash: hash})
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	if _, err := l.file.Write(append(out, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+	l.seq, l.prev = rec.Seq, hash
+	return nil
+}
+
+// Close closes the underlying file.
+func (l *Log) Close() error {
+	return l.file.Close()
+}
+
+// VerifyResult summarises a log that verified successfully.
+type VerifyResult struct {
+	Entries  int
+	LastHash string
+}
+
+// Verify checks the hash chain and sequence numbers of the log read from r.
+// The error names the first line that does not verify.
+func Verify(r io.Reader) (VerifyResult, error) {
+	result := VerifyResult{LastHash: GenesisHash}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			return result, fmt.Errorf("line %d: empty line", lineNo)
+		}
+		var entry line
+		if err := json.Unmarshal(text, &entry); err != nil {
+			return result, fmt.Errorf("line %d: malformed entry: %w", lineNo, err)
+		}
+		if entry.Prev != result.LastHash {
+			return result, fmt.Errorf("line %d: previous hash does not chain from the preceding entry, an entry was removed or reordered", lineNo)
+		}
+		if chainHash(entry.Prev, entry.Record) != entry.Hash {
+			return result, fmt.Errorf("line %d: hash mismatch, the record was modified", lineNo)
+		}
+		var rec struct {
+			Seq uint64 `json:"seq"`
+		}
+		if err := json.Unmarshal(entry.Record, &rec); err != nil {
+			return result, fmt.Errorf("line %d: malformed record: %w", lineNo, err)
+		}
+		if rec.Seq != uint64(lineNo) {
+			return result, fmt.Errorf("line %d: expected sequence number %d, got %d", lineNo, lineNo, rec.Seq)
+		}
+		result.Entries = lineNo
+		result.LastHash = entry.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return result, nil
+}
+
+// VerifyFile verifies the audit log at path.
+func VerifyFile(path string) (VerifyResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return VerifyResult{}, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return Verify(f)
+}
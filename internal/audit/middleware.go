@@ -0,0 +1,195 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/github-mcp-server/pkg/cassette"
+	"github.com/github/github-mcp-server/pkg/dryrun"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxArgumentLength bounds string arguments such as file contents; longer
+	// values are replaced by their length and digest.
+	maxArgumentLength = 4096
+	// maxResults bounds the URLs and SHAs kept from a single result.
+	maxResults = 50
+)
+
+// secretArgument matches argument names whose values are never recorded.
+var secretArgument = regexp.MustCompile(`(?i)(token|secret|password|passphrase|private_?key|credential|authorization)`)
+
+var shaPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// Middleware returns a tool middleware that appends a Record for every call to
+// a tool for which isWriteTool returns true, except dry runs. caller resolves the identity of
+// the invoking user. Failures to write the log are reported to logger and do
+// not change the tool result, since the write has already happened upstream.
+func (l *Log) Middleware(isWriteTool func(name string) bool, caller func(ctx context.Context) Caller, logger *slog.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !isWriteTool(request.Params.Name) || dryrun.Active(ctx) {
+				return next(ctx, request)
+			}
+
+			args := request.GetArguments()
+			result, err := next(ctx, request)
+
+			rec := Record{
+				Tool:      request.Params.Name,
+				Caller:    caller(ctx),
+				Repo:      targetRepo(args),
+				Arguments: RedactArguments(args),
+				Outcome:   OutcomeSuccess,
+			}
+			switch {
+			case err != nil:
+				rec.Outcome = OutcomeError
+				rec.Error = err.Error()
+			case result != nil && result.IsError:
+				rec.Outcome = OutcomeFailed
+				rec.Error = resultText(result)
+			case result != nil:
+				rec.URLs, rec.SHAs = resultReferences(result)
+			}
+
+			if appendErr := l.Append(rec); appendErr != nil && logger != nil {
+				logger.Error("failed to write audit record", "tool", rec.Tool, "error", appendErr)
+			}
+			return result, err
+		}
+	}
+}
+
+// SessionCaller returns the Caller for ctx with the given login, filling in
+// the client and session from the MCP session.
+func SessionCaller(ctx context.Context, login string) Caller {
+	c := Caller{Login: login}
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return c
+	}
+	c.Session = session.SessionID()
+	if withInfo, ok := session.(server.SessionWithClientInfo); ok {
+		info := withInfo.GetClientInfo()
+		c.Client = strings.TrimSuffix(info.Name+"/"+info.Version, "/")
+	}
+	return c
+}
+
+// RedactArguments returns a copy of args that is safe to record: values of
+// secret-looking arguments and GitHub tokens are redacted, and long strings are
+// replaced by their length and SHA-256 digest.
+func RedactArguments(args map[string]any) map[string]any {
+	if len(args) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(args))
+	for k, v := range args {
+		if secretArgument.MatchString(k) {
+			out[k] = cassette.Redacted
+			continue
+		}
+		out[k] = redactValue(v)
+	}
+	return out
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		v = cassette.RedactString(v)
+		if len(v) > maxArgumentLength {
+			sum := sha256.Sum256([]byte(v))
+			return fmt.Sprintf("[%d bytes, sha256:%s]", len(v), hex.EncodeToString(sum[:]))
+		}
+		return v
+	case map[string]any:
+		return RedactArguments(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = redactValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func targetRepo(args map[string]any) string {
+	owner, _ := args["owner"].(string)
+	repo, _ := args["repo"].(string)
+	if owner == "" || repo == "" {
+		return ""
+	}
+	return owner + "/" + repo
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// resultReferences collects the html_url values and commit SHAs found in the
+// JSON text content of a tool result.
+func resultReferences(result *mcp.CallToolResult) (urls, shas []string) {
+	seenURLs, seenSHAs := map[string]bool{}, map[string]bool{}
+	var walk func(key string, v any)
+	walk = func(key string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, child := range v {
+				walk(k, child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(key, child)
+			}
+		case string:
+			switch {
+			case key == "html_url" && len(seenURLs) < maxResults:
+				seenURLs[v] = true
+			case shaPattern.MatchString(v) && len(seenSHAs) < maxResults:
+				seenSHAs[v] = true
+			}
+		}
+	}
+	for _, content := range result.Content {
+		text, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		var decoded any
+		if json.Unmarshal([]byte(text.Text), &decoded) == nil {
+			walk("", decoded)
+		}
+	}
+	return sortedKeys(seenURLs), sortedKeys(seenSHAs)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
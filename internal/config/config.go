@@ -0,0 +1,479 @@
+// Package config finds, loads and validates the mcp-prime configuration file.
+//
+// The file is YAML, TOML or JSON, chosen by its extension. Its top-level keys
+// are the names of the command-line flags, with include-tools for --tools,
+// plus the token and per-tool settings that have no flag. Values from the file
+// have the lowest precedence: flags override environment variables, which override the file.
+//
+// A project file, found at the root of the current repository, comes with a
+// checkout rather than from the user. It is merged over the user's file, and
+// may only narrow what the server does: it can exclude tools, restrict the
+// included ones further and turn safeguards on, but not offer more tools, point
+// the server at another host, token or path, nor lift the user's safeguards.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/github-mcp-server/internal/confirm"
+	"github.com/github/github-mcp-server/internal/policy"
+	"github.com/github/github-mcp-server/internal/tracing"
+	"github.com/github/github-mcp-server/pkg/budget"
+	mcplog "github.com/github/github-mcp-server/pkg/log"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/spf13/cast"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Keys of settings that have no command-line flag.
+const (
+	// KeyTokenEnv names the environment variable holding the GitHub token.
+	KeyTokenEnv = "token-env"
+	// KeyTokenCommand is a command printing the GitHub token, such as
+	// "gh auth token". It is run directly, not through a shell.
+	KeyTokenCommand = "token-command"
+	// KeyTools maps tool names to a ToolOverride.
+	KeyTools = "tools"
+)
+
+// KeyProjectTools holds the include-tools of a project file. Rather than
+// replacing the user's include-tools, they narrow them: only the tools matching
+// both are offered.
+const KeyProjectTools = "project-include-tools"
+
+// DefaultTokenEnv is the environment variable read for the GitHub token when
+// neither KeyTokenEnv nor KeyTokenCommand is set.
+const DefaultTokenEnv = "GITHUB_PERSONAL_ACCESS_TOKEN"
+
+// extensions are the supported file formats, in the order they are looked for.
+var extensions = []string{"yaml", "yml", "toml", "json"}
+
+// pathKeys are the settings holding file or directory paths. Relative paths in
+// a config file are relative to the file.
+var pathKeys = []string{"log-file", "locales-dir", "response-cache-dir", "record", "replay", "audit-log", "policy-file"}
+
+// projectKeys are the keys a project file may set.
+var projectKeys = map[string]bool{
+	"include-tools":       true,
+	"exclude-tools":       true,
+	"read-only":           true,
+	"dry-run":             true,
+	"confirm-destructive": true,
+	"confirm-tools":       true,
+	KeyTools:              true,
+}
+
+// projectSafeguards are the settings a project file may turn on but not off.
+var projectSafeguards = map[string]bool{
+	"read-only":           true,
+	"dry-run":             true,
+	"confirm-destructive": true,
+}
+
+// projectAdditions are the lists a project file adds to, rather than replaces,
+// so that it cannot drop the user's entries.
+var projectAdditions = []string{"exclude-tools", "confirm-tools"}
+
+// File is a config file and where it was found.
+type File struct {
+	Path string
+	// Project is set for a file found at the root of the current repository,
+	// which may only set the project keys.
+	Project bool
+}
+
+// ToolOverride customizes a single tool.
+type ToolOverride struct {
+	// Title and Description replace the tool's title and description.
+	Title       string
+	Description string
+	// Confirm makes calls to the tool wait for the user's confirmation.
+	Confirm bool
+}
+
+// Candidates returns the files searched for a config file, in order:
+// .mcp-prime.<ext> at the root of the repository containing dir, then
+// mcp-prime/config.<ext> in $XDG_CONFIG_HOME (default ~/.config) and each of
+// $XDG_CONFIG_DIRS (default /etc/xdg).
+func Candidates(dir string) []File {
+	var files []File
+	if root, ok := repoRoot(dir); ok {
+		for _, ext := range extensions {
+			files = append(files, File{Path: filepath.Join(root, ".mcp-prime."+ext), Project: true})
+		}
+	}
+
+	var dirs []string
+	if home := os.Getenv("XDG_CONFIG_HOME"); home != "" {
+		dirs = append(dirs, home)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".config"))
+	}
+	configDirs := os.Getenv("XDG_CONFIG_DIRS")
+	if configDirs == "" {
+		configDirs = "/etc/xdg"
+	}
+	dirs = append(dirs, filepath.SplitList(configDirs)...)
+	for _, d := range dirs {
+		for _, ext := range extensions {
+			files = append(files, File{Path: filepath.Join(d, "mcp-prime", "config."+ext)})
+		}
+	}
+	return files
+}
+
+// repoRoot returns the closest directory at or above dir containing .git.
+func repoRoot(dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Find returns the config files to use, in the order to load them: explicit
+// alone when set, or else the first user file of the Candidates for the
+// working directory that exists, then the first project file that does. It
+// returns no files when there is no config file.
+func Find(explicit string) ([]File, error) {
+	if explicit != "" {
+		if _, err := os.Stat(explicit); err != nil {
+			return nil, fmt.Errorf("failed to open config file: %w", err)
+		}
+		return []File{{Path: explicit}}, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	var user, project *File
+	for _, candidate := range Candidates(dir) {
+		found := &user
+		if candidate.Project {
+			found = &project
+		}
+		if *found != nil {
+			continue
+		}
+		if info, err := os.Stat(candidate.Path); err == nil && !info.IsDir() {
+			*found = &candidate
+		}
+	}
+	var files []File
+	for _, f := range []*File{user, project} {
+		if f != nil {
+			files = append(files, *f)
+		}
+	}
+	return files, nil
+}
+
+// Load adds the settings of files to v beneath its flags and environment, each
+// file over the ones before it. A project file setting a key it may not set is
+// an error, and its include-tools are set as KeyProjectTools.
+func Load(v *viper.Viper, files ...File) error {
+	merged := make(map[string]any)
+	for _, f := range files {
+		settings, err := read(f)
+		if err != nil {
+			return err
+		}
+		if f.Project {
+			for _, key := range sortedKeys(settings) {
+				if err := checkProjectKey(key, settings[key]); err != nil {
+					return fmt.Errorf("%s: %w; set it in your user config file or pass --config", f.Path, err)
+				}
+			}
+			for _, key := range projectAdditions {
+				if added, ok := settings[key]; ok {
+					settings[key] = append(cast.ToStringSlice(merged[key]), cast.ToStringSlice(added)...)
+				}
+			}
+			if include, ok := settings["include-tools"]; ok {
+				delete(settings, "include-tools")
+				settings[KeyProjectTools] = include
+			}
+		}
+		mergeSettings(merged, settings)
+	}
+	return v.MergeConfigMap(merged)
+}
+
+// mergeSettings sets the settings of src in dst, merging nested maps such as
+// the per-tool settings key by key.
+func mergeSettings(dst, src map[string]any) {
+	for key, value := range src {
+		srcMap, srcOK := value.(map[string]any)
+		dstMap, dstOK := dst[key].(map[string]any)
+		if srcOK && dstOK {
+			mergeSettings(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// checkProjectKey reports whether a project file may set key to value.
+func checkProjectKey(key string, value any) error {
+	if !projectKeys[key] {
+		return fmt.Errorf("%s is not allowed in a repository config file", key)
+	}
+	if projectSafeguards[key] {
+		if on, err := cast.ToBoolE(value); err == nil && !on {
+			return fmt.Errorf("%s cannot be turned off in a repository config file", key)
+		}
+	}
+	if key == KeyTools {
+		tools, _ := value.(map[string]any)
+		for _, name := range sortedKeys(tools) {
+			fields, _ := tools[name].(map[string]any)
+			for _, field := range sortedKeys(fields) {
+				if field != "confirm" {
+					return fmt.Errorf("%s.%s.%s is not allowed in a repository config file", KeyTools, name, field)
+				}
+				if on, err := cast.ToBoolE(fields[field]); err == nil && !on {
+					return fmt.Errorf("%s.%s.confirm cannot be turned off in a repository config file", KeyTools, name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// read parses f and resolves its relative paths.
+func read(f File) (map[string]any, error) {
+	fv := viper.New()
+	fv.SetConfigFile(f.Path)
+	if filepath.Ext(f.Path) == "" {
+		fv.SetConfigType("yaml")
+	}
+	if err := fv.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", f.Path, err)
+	}
+
+	settings := fv.AllSettings()
+	dir := filepath.Dir(f.Path)
+	for _, key := range pathKeys {
+		if p, ok := settings[key].(string); ok && p != "" && !filepath.IsAbs(p) {
+			settings[key] = filepath.Join(dir, p)
+		}
+	}
+	return settings, nil
+}
+
+// ToolOverrides returns the per-tool settings of v.
+func ToolOverrides(v *viper.Viper) (map[string]ToolOverride, error) {
+	return decodeTools(v.Get(KeyTools))
+}
+
+func decodeTools(value any) (map[string]ToolOverride, error) {
+	if value == nil {
+		return nil, nil
+	}
+	entries, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: must map tool names to settings", KeyTools)
+	}
+	tools := make(map[string]ToolOverride, len(entries))
+	for name, entry := range entries {
+		fields, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s.%s: must be a map of settings", KeyTools, name)
+		}
+		var tool ToolOverride
+		for field, v := range fields {
+			var err error
+			switch field {
+			case "title":
+				tool.Title, err = cast.ToStringE(v)
+			case "description":
+				tool.Description, err = cast.ToStringE(v)
+			case "confirm":
+				tool.Confirm, err = cast.ToBoolE(v)
+			default:
+				err = errors.New("unknown setting")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s.%s: %w", KeyTools, name, field, err)
+			}
+		}
+		tools[name] = tool
+	}
+	return tools, nil
+}
+
+// Translations returns the translation overrides applying the titles and
+// descriptions of tools.
+func Translations(tools map[string]ToolOverride) map[string]string {
+	overrides := make(map[string]string)
+	for name, tool := range tools {
+		prefix := "TOOL_" + strings.ToUpper(name)
+		if tool.Title != "" {
+			overrides[prefix+"_USER_TITLE"] = tool.Title
+		}
+		if tool.Description != "" {
+			overrides[prefix+"_DESCRIPTION"] = tool.Description
+		}
+	}
+	return overrides
+}
+
+// ConfirmTools returns the names of the tools whose calls must be confirmed.
+func ConfirmTools(tools map[string]ToolOverride) []string {
+	var names []string
+	for name, tool := range tools {
+		if tool.Confirm {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Token returns the GitHub token from the command or environment variable
+// configured in v.
+func Token(v *viper.Viper) (string, error) {
+	if command := v.GetString(KeyTokenCommand); command != "" {
+		args := strings.Fields(command)
+		out, err := exec.Command(args[0], args[1:]...).Output() //nolint:gosec // the command is configured by the user
+		if err != nil {
+			return "", fmt.Errorf("failed to run %s %q: %w", KeyTokenCommand, command, err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+	name := v.GetString(KeyTokenEnv)
+	if name == "" {
+		name = DefaultTokenEnv
+	}
+	return os.Getenv(name), nil
+}
+
+// Validate checks f and returns every problem found. keys maps the names of
+// the settings that have a flag to the flag, whose type the value must have.
+func Validate(f File, keys map[string]*pflag.Flag) []error {
+	settings, err := read(f)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, key := range sortedKeys(settings) {
+		value := settings[key]
+		switch key {
+		case KeyTools:
+			if _, err := decodeTools(value); err != nil {
+				errs = append(errs, err)
+			}
+		case KeyTokenEnv, KeyTokenCommand:
+			if _, ok := value.(string); !ok {
+				errs = append(errs, fmt.Errorf("%s: must be a string", key))
+			}
+		default:
+			flag, ok := keys[key]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown key", key))
+				continue
+			}
+			if err := checkType(flag.Value.Type(), value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			}
+		}
+		if f.Project {
+			if err := checkProjectKey(key, value); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if settings["record"] != nil && settings["replay"] != nil {
+		errs = append(errs, errors.New("record and replay cannot both be set"))
+	}
+	if exporter, ok := settings["trace-exporter"].(string); ok {
+		switch exporter {
+		case tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterConsole:
+		default:
+			errs = append(errs, fmt.Errorf("trace-exporter: unknown exporter %q", exporter))
+		}
+	}
+	if unit, ok := settings["response-budget-unit"].(string); ok {
+		if _, err := budget.New(0, unit); err != nil {
+			errs = append(errs, fmt.Errorf("response-budget-unit: %w", err))
+		}
+	}
+	if path, ok := settings["policy-file"].(string); ok && path != "" {
+		if _, err := policy.Load(path); err != nil {
+			errs = append(errs, fmt.Errorf("policy-file: %w", err))
+		}
+	}
+	if patterns, err := cast.ToStringSliceE(settings["confirm-tools"]); err == nil {
+		if _, err := confirm.MatchTools(patterns); err != nil {
+			errs = append(errs, fmt.Errorf("confirm-tools: %w", err))
+		}
+	}
+	if patterns, err := cast.ToStringSliceE(settings["log-redact"]); err == nil {
+		if _, err := mcplog.CompileRedactPatterns(patterns); err != nil {
+			errs = append(errs, fmt.Errorf("log-redact: %w", err))
+		}
+	}
+	for _, key := range []string{"include-tools", "exclude-tools"} {
+		if patterns, err := cast.ToStringSliceE(settings[key]); err == nil {
+			if _, err := toolsets.NewToolFilter(patterns, nil); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			}
+		}
+	}
+	return errs
+}
+
+// checkType reports whether value can set a flag of the given pflag type.
+func checkType(flagType string, value any) error {
+	switch flagType {
+	case "bool":
+		if _, err := cast.ToBoolE(value); err != nil {
+			return errors.New("must be a boolean")
+		}
+	case "int":
+		if _, err := cast.ToIntE(value); err != nil {
+			return errors.New("must be an integer")
+		}
+	case "string":
+		switch value.(type) {
+		case map[string]any, []any:
+			return errors.New("must be a string")
+		}
+	case "stringSlice":
+		switch value := value.(type) {
+		case string:
+		case []any:
+			for _, item := range value {
+				if _, ok := item.(string); !ok {
+					return errors.New("must be a list of strings")
+				}
+			}
+		default:
+			return errors.New("must be a list of strings")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,340 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestCandidates(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o755))
+	sub := filepath.Join(root, "a", "b")
+	require.NoError(t, os.MkdirAll(sub, 0o755))
+	t.Setenv("XDG_CONFIG_HOME", "/home/me/.config")
+	t.Setenv("XDG_CONFIG_DIRS", "/etc/xdg"+string(filepath.ListSeparator)+"/opt/xdg")
+
+	candidates := Candidates(sub)
+	require.Len(t, candidates, 16)
+	assert.Equal(t, File{Path: filepath.Join(root, ".mcp-prime.yaml"), Project: true}, candidates[0])
+	assert.Equal(t, File{Path: "/home/me/.config/mcp-prime/config.yaml"}, candidates[4])
+	assert.Equal(t, File{Path: "/etc/xdg/mcp-prime/config.toml"}, candidates[10])
+	assert.Equal(t, File{Path: "/opt/xdg/mcp-prime/config.json"}, candidates[15])
+
+	// Outside a repository only the XDG directories are searched
+	assert.Len(t, Candidates(t.TempDir()), 12)
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".git"), 0o755))
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("XDG_CONFIG_DIRS", t.TempDir())
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(root))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	files, err := Find("")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	project := filepath.Join(root, ".mcp-prime.yaml")
+	writeFile(t, project, "toolsets: [issues]\n")
+	user := filepath.Join(home, "mcp-prime", "config.toml")
+	writeFile(t, user, "toolsets = [\"context\"]\n")
+
+	// The project file is loaded over the user file
+	files, err = Find("")
+	require.NoError(t, err)
+	assert.Equal(t, []File{{Path: user}, {Path: project, Project: true}}, files)
+
+	files, err = Find(user)
+	require.NoError(t, err)
+	assert.Equal(t, []File{{Path: user}}, files)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, `
+host: file.example.com
+content-window-size: 8000
+audit-log: logs/audit.jsonl
+read-only: true
+`)
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("gh-host", "", "")
+	flags.Int("content-window-size", 5000, "")
+	flags.String("audit-log", "", "")
+	flags.Bool("read-only", false, "")
+	require.NoError(t, flags.Parse([]string{"--gh-host", "flag.example.com"}))
+
+	v := viper.New()
+	v.SetEnvPrefix("MCP_PRIME_TEST")
+	v.AutomaticEnv()
+	require.NoError(t, v.BindPFlag("host", flags.Lookup("gh-host")))
+	require.NoError(t, v.BindPFlag("content-window-size", flags.Lookup("content-window-size")))
+	require.NoError(t, v.BindPFlag("audit-log", flags.Lookup("audit-log")))
+	require.NoError(t, v.BindPFlag("read-only", flags.Lookup("read-only")))
+	t.Setenv("MCP_PRIME_TEST_HOST", "env.example.com")
+	t.Setenv("MCP_PRIME_TEST_READ-ONLY", "false")
+
+	require.NoError(t, Load(v, File{Path: path}))
+
+	// Flags over env over file
+	assert.Equal(t, "flag.example.com", v.GetString("host"))
+	assert.False(t, v.GetBool("read-only"))
+	// File over flag defaults, with paths relative to the file
+	assert.Equal(t, 8000, v.GetInt("content-window-size"))
+	assert.Equal(t, filepath.Join(dir, "logs", "audit.jsonl"), v.GetString("audit-log"))
+}
+
+func TestLoadProjectTokenCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".mcp-prime.yaml")
+	writeFile(t, path, "token-command: gh auth token\n")
+
+	err := Load(viper.New(), File{Path: path, Project: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not allowed in a repository config file")
+
+	assert.NoError(t, Load(viper.New(), File{Path: path}))
+}
+
+func TestLoadProject(t *testing.T) {
+	dir := t.TempDir()
+	user := filepath.Join(dir, "config.yaml")
+	writeFile(t, user, `
+host: github.example.com
+read-only: true
+include-tools: ["get_*", "list_*"]
+exclude-tools: [delete_file]
+confirm-tools: [merge_pull_request]
+tools:
+  get_me:
+    title: Me
+`)
+	project := filepath.Join(dir, ".mcp-prime.yaml")
+	writeFile(t, project, `
+include-tools: ["*"]
+exclude-tools: ["*_gist"]
+confirm-tools: [push_files]
+dry-run: true
+tools:
+  get_me:
+    confirm: true
+`)
+
+	v := viper.New()
+	require.NoError(t, Load(v, File{Path: user}, File{Path: project, Project: true}))
+
+	// The user's settings are kept under the project's
+	assert.Equal(t, "github.example.com", v.GetString("host"))
+	assert.True(t, v.GetBool("read-only"))
+	assert.True(t, v.GetBool("dry-run"))
+	// The project's include-tools narrow the user's rather than replace them
+	assert.Equal(t, []string{"get_*", "list_*"}, v.GetStringSlice("include-tools"))
+	assert.Equal(t, []string{"*"}, v.GetStringSlice(KeyProjectTools))
+	assert.Equal(t, []string{"delete_file", "*_gist"}, v.GetStringSlice("exclude-tools"))
+	assert.Equal(t, []string{"merge_pull_request", "push_files"}, v.GetStringSlice("confirm-tools"))
+	tools, err := ToolOverrides(v)
+	require.NoError(t, err)
+	assert.Equal(t, ToolOverride{Title: "Me", Confirm: true}, tools["get_me"])
+
+	for content, want := range map[string]string{
+		"host: evil.example.com\n":                "host is not allowed in a repository config file",
+		"token-env: OTHER_TOKEN\n":                "token-env is not allowed in a repository config file",
+		"audit-log: /dev/null\n":                  "audit-log is not allowed in a repository config file",
+		"record: cassettes\n":                     "record is not allowed in a repository config file",
+		"policy-file: allow-all.yaml\n":           "policy-file is not allowed in a repository config file",
+		"read-only: false\n":                      "read-only cannot be turned off in a repository config file",
+		"confirm-destructive: false\n":            "confirm-destructive cannot be turned off in a repository config file",
+		"tools:\n  get_me:\n    confirm: false\n": "tools.get_me.confirm cannot be turned off in a repository config file",
+		// Settings widening what the server offers
+		"toolsets: [all]\n":                          "toolsets is not allowed in a repository config file",
+		"dynamic-toolsets: true\n":                   "dynamic-toolsets is not allowed in a repository config file",
+		"max-enabled-toolsets: 100\n":                "max-enabled-toolsets is not allowed in a repository config file",
+		"response-budget: 0\n":                       "response-budget is not allowed in a repository config file",
+		"content-window-size: 1000000\n":             "content-window-size is not allowed in a repository config file",
+		"tools:\n  get_me:\n    title: Run me\n":     "tools.get_me.title is not allowed in a repository config file",
+		"tools:\n  get_me:\n    description: Safe\n": "tools.get_me.description is not allowed in a repository config file",
+		"project-include-tools: [\"*\"]\n":           "project-include-tools is not allowed in a repository config file",
+	} {
+		writeFile(t, project, content)
+		err := Load(viper.New(), File{Path: user}, File{Path: project, Project: true})
+		require.Error(t, err, content)
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestToolOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeFile(t, path, `
+[tools.get_me]
+title = "Me"
+description = "Who am I"
+
+[tools.merge_pull_request]
+confirm = true
+`)
+	v := viper.New()
+	require.NoError(t, Load(v, File{Path: path}))
+
+	tools, err := ToolOverrides(v)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"TOOL_GET_ME_USER_TITLE":  "Me",
+		"TOOL_GET_ME_DESCRIPTION": "Who am I",
+	}, Translations(tools))
+	assert.Equal(t, []string{"merge_pull_request"}, ConfirmTools(tools))
+}
+
+func TestToken(t *testing.T) {
+	t.Setenv(DefaultTokenEnv, "default-token")
+	t.Setenv("OTHER_TOKEN", "other-token")
+
+	v := viper.New()
+	token, err := Token(v)
+	require.NoError(t, err)
+	assert.Equal(t, "default-token", token)
+
+	v.Set(KeyTokenEnv, "OTHER_TOKEN")
+	token, err = Token(v)
+	require.NoError(t, err)
+	assert.Equal(t, "other-token", token)
+
+	v.Set(KeyTokenCommand, "echo command-token")
+	token, err = Token(v)
+	require.NoError(t, err)
+	assert.Equal(t, "command-token", token)
+}
+
+func TestValidate(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("gh-host", "", "")
+	flags.Int("content-window-size", 5000, "")
+	flags.String("record", "", "")
+	flags.String("replay", "", "")
+	flags.String("trace-exporter", "none", "")
+	flags.String("policy-file", "", "")
+	flags.String("response-budget-unit", "tokens", "")
+	flags.StringSlice("confirm-tools", nil, "")
+	flags.StringSlice("tools", nil, "")
+	flags.StringSlice("exclude-tools", nil, "")
+	flags.StringSlice("log-redact", nil, "")
+	flags.Bool("read-only", false, "")
+	keys := map[string]*pflag.Flag{"host": flags.Lookup("gh-host"), "include-tools": flags.Lookup("tools")}
+	for _, name := range []string{"read-only", "content-window-size", "record", "replay", "trace-exporter", "policy-file", "response-budget-unit", "confirm-tools", "exclude-tools", "log-redact"} {
+		keys[name] = flags.Lookup(name)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		project bool
+		errs    []string
+	}{
+		{
+			name: "valid",
+			content: `
+host: github.example.com
+content-window-size: 8000
+trace-exporter: otlp
+confirm-tools: [merge_pull_request, "delete_*"]
+include-tools: ["get_*", "list_*"]
+exclude-tools: [get_me]
+token-env: MY_TOKEN
+tools:
+  get_me:
+    description: Who am I
+`,
+		},
+		{
+			name:    "syntax error",
+			content: "host: [unclosed\n",
+			errs:    []string{"failed to read config file"},
+		},
+		{
+			name: "invalid values",
+			content: `
+gh-host: github.example.com
+content-window-size: lots
+confirm-tools: "delete_["
+exclude-tools: ["get_[", delete_file]
+log-redact: ["secret-\\d+", "(unclosed"]
+tools:
+  get_me:
+    colour: red
+`,
+			errs: []string{
+				"content-window-size: must be an integer",
+				"gh-host: unknown key",
+				"tools.get_me.colour: unknown setting",
+				`confirm-tools: invalid tool pattern "delete_["`,
+				`log-redact: invalid redact pattern "(unclosed"`,
+				`exclude-tools: invalid tool pattern "get_["`,
+			},
+		},
+		{
+			name: "conflicting settings",
+			content: `
+record: cassettes
+replay: cassettes
+trace-exporter: jaeger
+response-budget-unit: words
+policy-file: missing.yaml
+`,
+			errs: []string{
+				"record and replay cannot both be set",
+				`trace-exporter: unknown exporter "jaeger"`,
+				`response-budget-unit: unknown response budget unit "words"`,
+				"policy-file: failed to read policy file",
+			},
+		},
+		{
+			name:    "token command in project file",
+			content: "token-command: gh auth token\n",
+			project: true,
+			errs:    []string{"token-command is not allowed in a repository config file"},
+		},
+		{
+			name: "project file",
+			content: `
+host: github.example.com
+read-only: false
+exclude-tools: [get_me]
+`,
+			project: true,
+			errs: []string{
+				"host is not allowed in a repository config file",
+				"read-only cannot be turned off in a repository config file",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			writeFile(t, path, tc.content)
+
+			errs := Validate(File{Path: path, Project: tc.project}, keys)
+			require.Len(t, errs, len(tc.errs), "%v", errs)
+			for i, want := range tc.errs {
+				assert.Contains(t, errs[i].Error(), want)
+			}
+		})
+	}
+}
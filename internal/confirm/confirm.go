@@ -0,0 +1,229 @@
+// Package confirm asks the user to confirm tool calls before they run, using
+// MCP elicitation.
+//
+// Tool annotations such as DestructiveHint are only hints to the client. The
+// Confirmer enforces them on the server: a call to a tool that requires
+// confirmation is held until the user accepts a prompt summarizing it. When
+// the client cannot show prompts, the call is refused with guidance for the
+// model instead of running unconfirmed.
+package confirm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/github/github-mcp-server/pkg/dryrun"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Error values of a Refusal.
+const (
+	// ErrorUnavailable means the client cannot be asked for confirmation.
+	ErrorUnavailable = "confirmation_unavailable"
+	// ErrorDeclined means the user declined or dismissed the prompt.
+	ErrorDeclined = "confirmation_declined"
+	// ErrorFailed means the prompt could not be completed.
+	ErrorFailed = "confirmation_failed"
+)
+
+// Refusal explains why a call did not run. It is returned to the client as
+// the JSON text of an error result.
+type Refusal struct {
+	Error   string `json:"error"`
+	Tool    string `json:"tool"`
+	Summary string `json:"summary"`
+	Reason  string `json:"reason"`
+}
+
+// DescribeFunc summarizes a call for the confirmation prompt, as a phrase
+// completing "Allow the assistant to ...".
+type DescribeFunc func(ctx context.Context, tool string, args map[string]any) string
+
+// Confirmer holds calls to the tools that require confirmation until the user
+// confirms them.
+type Confirmer struct {
+	requires func(ctx context.Context, name string) bool
+	describe DescribeFunc
+}
+
+// New returns a Confirmer for the calls for which requires returns true.
+// describe defaults to Describe.
+func New(requires func(ctx context.Context, name string) bool, describe DescribeFunc) *Confirmer {
+	if describe == nil {
+		describe = func(_ context.Context, tool string, args map[string]any) string {
+			return Describe(tool, args)
+		}
+	}
+	return &Confirmer{requires: requires, describe: describe}
+}
+
+// MatchTools returns a function reporting whether a tool name matches any of
+// the glob patterns, such as "delete_*".
+func MatchTools(patterns []string) (func(name string) bool, error) {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+	}
+	return func(name string) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// requestedSchema is the form shown to the user: a single checkbox.
+var requestedSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"confirm": map[string]any{
+			"type":        "boolean",
+			"title":       "Confirm",
+			"description": "Allow this action to run",
+		},
+	},
+	"required": []string{"confirm"},
+}
+
+// Middleware asks for confirmation before calls to tools that require it.
+// Dry runs change nothing and are not held.
+func (c *Confirmer) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name := request.Params.Name
+		if !c.requires(ctx, name) {
+			return next(ctx, request)
+		}
+
+		summary := c.describe(ctx, name, request.GetArguments())
+		refuse := func(kind, reason string) (*mcp.CallToolResult, error) {
+			text, err := json.Marshal(Refusal{Error: kind, Tool: name, Summary: summary, Reason: reason})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal confirmation refusal: %w", err)
+			}
+			return mcp.NewToolResultError(string(text)), nil
+		}
+
+		session := server.ClientSessionFromContext(ctx)
+		elicitor, ok := session.(server.SessionWithElicitation)
+		if !ok || !supportsElicitation(session) {
+			return refuse(ErrorUnavailable, fmt.Sprintf("This tool requires the user's confirmation, but the client does not support MCP elicitation, so it was not run. "+
+				"Do not retry it. Tell the user that you intended to %s and let them carry it out themselves or switch to a client that supports elicitation. "+
+				"To show the exact changes first, call the tool again with %s: true.", summary, dryrun.Argument))
+		}
+
+		result, err := elicitor.RequestElicitation(ctx, mcp.ElicitationRequest{
+			Params: mcp.ElicitationParams{
+				Message:         fmt.Sprintf("Allow the assistant to %s?", summary),
+				RequestedSchema: requestedSchema,
+			},
+		})
+		if err != nil {
+			return refuse(ErrorFailed, fmt.Sprintf("The confirmation prompt failed, so the tool was not run: %v", err))
+		}
+		if result.Action != mcp.ElicitationResponseActionAccept || !confirmed(result.Content) {
+			return refuse(ErrorDeclined, fmt.Sprintf("The user did not confirm the action (%s), so the tool was not run. Do not retry unless the user asks you to.", result.Action))
+		}
+		return next(ctx, request)
+	}
+}
+
+func supportsElicitation(session server.ClientSession) bool {
+	withInfo, ok := session.(server.SessionWithClientInfo)
+	return ok && withInfo.GetClientCapabilities().Elicitation != nil
+}
+
+// confirmed reports whether the accepted form has the confirm box checked.
+func confirmed(content any) bool {
+	form, ok := content.(map[string]any)
+	if !ok {
+		return false
+	}
+	v, _ := form["confirm"].(bool)
+	return v
+}
+
+// Describe summarizes a call from its arguments alone. Summaries of
+// merge_pull_request name the base branch when args carry it as "base".
+func Describe(tool string, args map[string]any) string {
+	str := func(key string) string {
+		v, _ := args[key].(string)
+		return v
+	}
+	num := func(key string) string {
+		if v, ok := args[key].(float64); ok {
+			return fmt.Sprintf("%d", int64(v))
+		}
+		return "?"
+	}
+	in := ""
+	if owner, repo := str("owner"), str("repo"); owner != "" && repo != "" {
+		in = " in " + owner + "/" + repo
+	}
+	onBranch := ""
+	if branch := str("branch"); branch != "" {
+		onBranch = " on branch " + branch
+	}
+
+	switch tool {
+	case "merge_pull_request":
+		s := "merge PR #" + num("pullNumber")
+		if base := str("base"); base != "" {
+			s += " into " + base
+		}
+		method := str("merge_method")
+		if method == "" {
+			method = "merge"
+		}
+		return s + " with " + method + in
+	case "delete_file":
+		return "delete " + str("path") + onBranch + in
+	case "create_or_update_file":
+		return "write " + str("path") + onBranch + in
+	case "push_files":
+		files, _ := args["files"].([]any)
+		return fmt.Sprintf("push %d files%s%s", len(files), onBranch, in)
+	case "delete_workflow_run_logs":
+		return "delete the logs of workflow run " + num("run_id") + in
+	case "cancel_workflow_run":
+		return "cancel workflow run " + num("run_id") + in
+	case "update_issue":
+		s := "update issue #" + num("issue_number")
+		if state := str("state"); state != "" {
+			s += " (state: " + state + ")"
+		}
+		return s + in
+	}
+	return "call " + tool + in + describeArgs(args)
+}
+
+// describeArgs lists the arguments other than owner and repo, shortening long
+// values.
+func describeArgs(args map[string]any) string {
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		if key != "owner" && key != "repo" && key != dryrun.Argument {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := []rune(fmt.Sprint(args[key]))
+		if len(value) > 40 {
+			value = append(value[:37], []rune("...")...)
+		}
+		parts = append(parts, key+"="+string(value))
+	}
+	return " with " + strings.Join(parts, ", ")
+}
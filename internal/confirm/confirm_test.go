@@ -0,0 +1,216 @@
+package confirm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSession is a client session answering elicitation requests with a
+// canned result.
+type fakeSession struct {
+	capabilities mcp.ClientCapabilities
+	result       *mcp.ElicitationResult
+	err          error
+	requests     []mcp.ElicitationRequest
+}
+
+func (s *fakeSession) Initialize()                                         {}
+func (s *fakeSession) Initialized() bool                                   { return true }
+func (s *fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s *fakeSession) SessionID() string                                   { return "fake" }
+func (s *fakeSession) GetClientInfo() mcp.Implementation                   { return mcp.Implementation{} }
+func (s *fakeSession) SetClientInfo(mcp.Implementation)                    {}
+func (s *fakeSession) GetClientCapabilities() mcp.ClientCapabilities       { return s.capabilities }
+func (s *fakeSession) SetClientCapabilities(c mcp.ClientCapabilities)      { s.capabilities = c }
+
+func (s *fakeSession) RequestElicitation(_ context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	s.requests = append(s.requests, request)
+	return s.result, s.err
+}
+
+func elicitationResult(action mcp.ElicitationResponseAction, content any) *mcp.ElicitationResult {
+	return &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{Action: action, Content: content}}
+}
+
+func TestMatchTools(t *testing.T) {
+	match, err := MatchTools([]string{"delete_*", "merge_pull_request"})
+	require.NoError(t, err)
+	assert.True(t, match("delete_file"))
+	assert.True(t, match("merge_pull_request"))
+	assert.False(t, match("create_issue"))
+
+	_, err = MatchTools([]string{"delete_["})
+	assert.Error(t, err)
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		tool string
+		args map[string]any
+		want string
+	}{
+		{
+			tool: "merge_pull_request",
+			args: map[string]any{"owner": "octo", "repo": "hello", "pullNumber": float64(42), "merge_method": "squash", "base": "main"},
+			want: "merge PR #42 into main with squash in octo/hello",
+		},
+		{
+			tool: "merge_pull_request",
+			args: map[string]any{"owner": "octo", "repo": "hello", "pullNumber": float64(7)},
+			want: "merge PR #7 with merge in octo/hello",
+		},
+		{
+			tool: "delete_file",
+			args: map[string]any{"owner": "octo", "repo": "hello", "path": "README.md", "branch": "main"},
+			want: "delete README.md on branch main in octo/hello",
+		},
+		{
+			tool: "push_files",
+			args: map[string]any{"owner": "octo", "repo": "hello", "branch": "dev", "files": []any{map[string]any{}, map[string]any{}}},
+			want: "push 2 files on branch dev in octo/hello",
+		},
+		{
+			tool: "delete_workflow_run_logs",
+			args: map[string]any{"owner": "octo", "repo": "hello", "run_id": float64(1234)},
+			want: "delete the logs of workflow run 1234 in octo/hello",
+		},
+		{
+			tool: "create_branch",
+			args: map[string]any{"owner": "octo", "repo": "hello", "branch": "feature", "dry_run": true},
+			want: "call create_branch in octo/hello with branch=feature",
+		},
+		{
+			tool: "create_branch",
+			args: map[string]any{"owner": "octo", "repo": "hello", "branch": strings.Repeat("é", 41)},
+			want: "call create_branch in octo/hello with branch=" + strings.Repeat("é", 37) + "...",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.tool, func(t *testing.T) {
+			assert.Equal(t, tc.want, Describe(tc.tool, tc.args))
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "0.0.0", server.WithElicitation())
+	elicitation := mcp.ClientCapabilities{Elicitation: &struct{}{}}
+
+	tests := []struct {
+		name      string
+		tool      string
+		session   server.ClientSession
+		wantCall  bool
+		wantError string
+	}{
+		{
+			name:     "tool without confirmation",
+			tool:     "get_me",
+			session:  &fakeSession{},
+			wantCall: true,
+		},
+		{
+			name:     "confirmed",
+			tool:     "merge_pull_request",
+			session:  &fakeSession{capabilities: elicitation, result: elicitationResult(mcp.ElicitationResponseActionAccept, map[string]any{"confirm": true})},
+			wantCall: true,
+		},
+		{
+			name:      "accepted unchecked",
+			tool:      "merge_pull_request",
+			session:   &fakeSession{capabilities: elicitation, result: elicitationResult(mcp.ElicitationResponseActionAccept, map[string]any{"confirm": false})},
+			wantError: ErrorDeclined,
+		},
+		{
+			name:      "declined",
+			tool:      "merge_pull_request",
+			session:   &fakeSession{capabilities: elicitation, result: elicitationResult(mcp.ElicitationResponseActionDecline, nil)},
+			wantError: ErrorDeclined,
+		},
+		{
+			name:      "prompt failed",
+			tool:      "merge_pull_request",
+			session:   &fakeSession{capabilities: elicitation, err: errors.New("closed")},
+			wantError: ErrorFailed,
+		},
+		{
+			name:      "client without elicitation",
+			tool:      "merge_pull_request",
+			session:   &fakeSession{},
+			wantError: ErrorUnavailable,
+		},
+		{
+			name:      "no session",
+			tool:      "merge_pull_request",
+			wantError: ErrorUnavailable,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			confirmer := New(func(_ context.Context, name string) bool { return name == "merge_pull_request" }, nil)
+			called := false
+			handler := confirmer.Middleware(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				called = true
+				return mcp.NewToolResultText("merged"), nil
+			})
+
+			ctx := context.Background()
+			if tc.session != nil {
+				ctx = mcpServer.WithContext(ctx, tc.session)
+			}
+			request := mcp.CallToolRequest{}
+			request.Params.Name = tc.tool
+			request.Params.Arguments = map[string]any{"owner": "octo", "repo": "hello", "pullNumber": float64(42)}
+
+			result, err := handler(ctx, request)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantCall, called)
+			if tc.wantError == "" {
+				assert.False(t, result.IsError)
+				return
+			}
+
+			require.True(t, result.IsError)
+			var refusal Refusal
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &refusal))
+			assert.Equal(t, tc.wantError, refusal.Error)
+			assert.Equal(t, "merge_pull_request", refusal.Tool)
+			assert.Equal(t, "merge PR #42 with merge in octo/hello", refusal.Summary)
+		})
+	}
+}
+
+func TestMiddlewarePrompt(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "0.0.0", server.WithElicitation())
+	session := &fakeSession{
+		capabilities: mcp.ClientCapabilities{Elicitation: &struct{}{}},
+		result:       elicitationResult(mcp.ElicitationResponseActionAccept, map[string]any{"confirm": true}),
+	}
+	describe := func(_ context.Context, tool string, args map[string]any) string {
+		args["base"] = "main"
+		return Describe(tool, args)
+	}
+	handler := New(func(context.Context, string) bool { return true }, describe).Middleware(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("merged"), nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "merge_pull_request"
+	request.Params.Arguments = map[string]any{"owner": "octo", "repo": "hello", "pullNumber": float64(42), "merge_method": "squash"}
+	_, err := handler(mcpServer.WithContext(context.Background(), session), request)
+	require.NoError(t, err)
+
+	require.Len(t, session.requests, 1)
+	assert.Equal(t, "Allow the assistant to merge PR #42 into main with squash in octo/hello?", session.requests[0].Params.Message)
+	assert.Equal(t, requestedSchema, session.requests[0].Params.RequestedSchema)
+}
@@ -0,0 +1,309 @@
+package ghmcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/github/github-mcp-server/pkg/budget"
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/raw"
+	"github.com/github/github-mcp-server/pkg/repository"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// reloadDebounce groups the bursts of events editors cause when saving a file.
+const reloadDebounce = 200 * time.Millisecond
+
+// toolRegistry owns the toolsets registered on a server and replaces them when
+// the configuration is reloaded. Tools are swapped on the live server, which
+// notifies clients with notifications/tools/list_changed. Calls already
+// running keep the handler they started with.
+type toolRegistry struct {
+	server       *server.MCPServer
+	getClient    github.GetClientFn
+	getGQLClient github.GetGQLClientFn
+	getRawClient raw.GetRawClientFn
+	// runLogs is shared by the toolset groups of every reload, and closed with
+	// the server
+	runLogs *github.RunLogsCache
+	// budget, when set, adds continue_result to the tools
+	budget *budget.Budget
+
+	// tsg is the current toolset group. It is read on every tool call, by
+	// middleware, so it is swapped atomically rather than under mu.
+	tsg atomic.Pointer[toolsets.ToolsetGroup]
+
+	// mu serializes reloads.
+	mu        sync.Mutex
+	cfg       MCPServerConfig
+	prompts   []string
+	templates []string
+}
+
+// IsWriteTool reports whether name is a write tool of the current toolsets.
+func (r *toolRegistry) IsWriteTool(name string) bool {
+	return r.tsg.Load().IsWriteTool(name)
+}
+
+// IsDestructiveTool reports whether name is a destructive tool of the current
+// toolsets.
+func (r *toolRegistry) IsDestructiveTool(name string) bool {
+	return r.tsg.Load().IsDestructiveTool(name)
+}
+
+// trackUsage is middleware recording the calls to the tools of each toolset,
+// so that the least recently used toolsets are the first disabled when
+// enabling another goes over the cap on enabled toolsets.
+func (r *toolRegistry) trackUsage(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		r.tsg.Load().MarkUsed(request.Params.Name)
+		return next(ctx, request)
+	}
+}
+
+// Reload builds the toolsets of cfg and registers them in place of the current
+// ones. Only the toolset settings of cfg are used: EnabledToolsets,
+// DynamicToolsets, MaxEnabledToolsets, ReadOnly, Tools, ExcludeTools,
+// ProjectTools, Translator and ContentWindowSize. With dynamic toolsets,
+// toolsets enabled at runtime stay enabled as long as they fit under
+// MaxEnabledToolsets.
+func (r *toolRegistry) Reload(cfg MCPServerConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filter, err := toolsets.NewToolFilter(cfg.Tools, cfg.ExcludeTools, cfg.ProjectTools)
+	if err != nil {
+		return fmt.Errorf("failed to filter tools: %w", err)
+	}
+	tsg := github.DefaultToolsetGroup(cfg.ReadOnly, r.getClient, r.getGQLClient, r.getRawClient, r.runLogs, cfg.Translator, cfg.ContentWindowSize)
+	tsg.AddToolset(repository.Toolset())
+	tsg.SetToolFilter(filter)
+	enabledToolsets := cfg.EnabledToolsets
+	if cfg.DynamicToolsets {
+		tsg.SetMaxEnabled(cfg.MaxEnabledToolsets)
+		// filter "all" from the enabled toolsets
+		enabledToolsets = make([]string, 0, len(cfg.EnabledToolsets))
+		for _, toolset := range cfg.EnabledToolsets {
+			if toolset != "all" {
+				enabledToolsets = append(enabledToolsets, toolset)
+			}
+		}
+	}
+	if err := tsg.EnableToolsets(enabledToolsets); err != nil {
+		return fmt.Errorf("failed to enable toolsets: %w", err)
+	}
+	if previous := r.tsg.Load(); cfg.DynamicToolsets && previous != nil {
+		keepRuntimeToolsets(tsg, previous, cfg.MaxEnabledToolsets)
+	}
+
+	var tools []server.ServerTool
+	var templates []server.ServerResourceTemplate
+	var prompts []server.ServerPrompt
+	for _, toolset := range tsg.Toolsets {
+		tools = append(tools, toolset.GetActiveTools()...)
+		templates = append(templates, toolset.GetActiveResourceTemplates()...)
+		prompts = append(prompts, toolset.GetActivePrompts()...)
+	}
+	if cfg.DynamicToolsets {
+		dynamic := github.InitDynamicToolset(r.server, tsg, cfg.Translator)
+		dynamic.SetToolFilter(filter)
+		tools = append(tools, dynamic.GetActiveTools()...)
+	}
+	if r.budget != nil {
+		tools = append(tools, toolsets.NewServerTool(r.budget.ContinueTool(cfg.Translator)))
+	}
+	r.tsg.Store(tsg)
+
+	// Tools are added before the stale ones are deleted, so that tools kept
+	// across the reload are never missing for a call arriving meanwhile.
+	keep := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		keep[tool.Tool.Name] = true
+	}
+	var stale []string
+	for name := range r.server.ListTools() {
+		if !keep[name] {
+			stale = append(stale, name)
+		}
+	}
+	r.server.AddTools(tools...)
+	if len(stale) > 0 {
+		r.server.DeleteTools(stale...)
+	}
+
+	promptNames := make([]string, 0, len(prompts))
+	keep = make(map[string]bool, len(prompts))
+	for _, prompt := range prompts {
+		promptNames = append(promptNames, prompt.Prompt.Name)
+		keep[prompt.Prompt.Name] = true
+	}
+	var stalePrompts []string
+	for _, name := range r.prompts {
+		if !keep[name] {
+			stalePrompts = append(stalePrompts, name)
+		}
+	}
+	if len(prompts) > 0 {
+		r.server.AddPrompts(prompts...)
+	}
+	if len(stalePrompts) > 0 {
+		r.server.DeletePrompts(stalePrompts...)
+	}
+	r.prompts = promptNames
+
+	templateURIs := make([]string, 0, len(templates))
+	keep = make(map[string]bool, len(templates))
+	for _, template := range templates {
+		uri := template.Template.URITemplate.Raw()
+		templateURIs = append(templateURIs, uri)
+		keep[uri] = true
+	}
+	removed := false
+	for _, uri := range r.templates {
+		removed = removed || !keep[uri]
+	}
+	switch {
+	case removed:
+		// Templates cannot be removed one by one
+		r.server.SetResourceTemplates(templates...)
+	case len(templates) > 0:
+		r.server.AddResourceTemplates(templates...)
+	}
+	r.templates = templateURIs
+	r.cfg = cfg
+
+	return nil
+}
+
+// SetTranslator rebuilds the current toolsets with the text of t, such as the
+// text of the locale a client asks for.
+func (r *toolRegistry) SetTranslator(t translations.TranslationHelperFunc) error {
+	r.mu.Lock()
+	cfg := r.cfg
+	r.mu.Unlock()
+	cfg.Translator = t
+	return r.Reload(cfg)
+}
+
+// keepRuntimeToolsets enables in tsg the toolsets enabled in previous, in
+// name order and only while fewer than max toolsets are enabled, 0 for no
+// cap.
+func keepRuntimeToolsets(tsg, previous *toolsets.ToolsetGroup, max int) {
+	enabled := 0
+	for name := range tsg.Toolsets {
+		if tsg.IsEnabled(name) {
+			enabled++
+		}
+	}
+	names := make([]string, 0, len(previous.Toolsets))
+	for name := range previous.Toolsets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if max > 0 && enabled >= max {
+			return
+		}
+		if _, exists := tsg.Toolsets[name]; !exists || tsg.IsEnabled(name) || !previous.IsEnabled(name) {
+			continue
+		}
+		_ = tsg.EnableToolset(name)
+		enabled++
+	}
+}
+
+// watchReload calls reload on SIGHUP and when one of configFiles is written,
+// created or replaced, until ctx is done.
+func watchReload(ctx context.Context, configFiles []string, logger *slog.Logger, reload func()) error {
+	triggers := make(chan struct{}, 1)
+	trigger := func() {
+		select {
+		case triggers <- struct{}{}:
+		default:
+		}
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	var events chan fsnotify.Event
+	var errs chan error
+	var watcher *fsnotify.Watcher
+	watched := make(map[string]bool, len(configFiles))
+	if len(configFiles) > 0 {
+		var err error
+		if watcher, err = fsnotify.NewWatcher(); err != nil {
+			signal.Stop(hup)
+			return fmt.Errorf("failed to watch config file: %w", err)
+		}
+		for _, configFile := range configFiles {
+			watched[filepath.Clean(configFile)] = true
+			// Watch the directory, since editors often save by replacing the file
+			if err := watcher.Add(filepath.Dir(configFile)); err != nil {
+				signal.Stop(hup)
+				_ = watcher.Close()
+				return fmt.Errorf("failed to watch config file: %w", err)
+			}
+		}
+		events, errs = watcher.Events, watcher.Errors
+	}
+
+	go func() {
+		defer signal.Stop(hup)
+		if watcher != nil {
+			defer func() { _ = watcher.Close() }()
+		}
+
+		var debounce *time.Timer
+		for {
+			select {
+			case <-ctx.Done():
+				if debounce != nil {
+					debounce.Stop()
+				}
+				return
+			case <-hup:
+				logger.Info("reloading configuration", "trigger", "SIGHUP")
+				trigger()
+			case event, ok := <-events:
+				if !ok {
+					events = nil
+					continue
+				}
+				if !watched[filepath.Clean(event.Name)] || !(event.Has(fsnotify.Write) || event.Has(fsnotify.Create)) {
+					continue
+				}
+				if debounce == nil {
+					debounce = time.AfterFunc(reloadDebounce, func() {
+						logger.Info("reloading configuration", "trigger", "file change")
+						trigger()
+					})
+				} else {
+					debounce.Reset(reloadDebounce)
+				}
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				logger.Warn("error watching config files", "error", err)
+			case <-triggers:
+				reload()
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,282 @@
+package ghmcp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/budget"
+	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/raw"
+	"github.com/github/github-mcp-server/pkg/translations"
+	gogithub "github.com/google/go-github/v74/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notifiedSession is an initialized client session collecting notifications.
+type notifiedSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *notifiedSession) Initialize()       {}
+func (s *notifiedSession) Initialized() bool { return true }
+func (s *notifiedSession) SessionID() string { return "reload-test" }
+func (s *notifiedSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+// methods drains the notifications received so far.
+func (s *notifiedSession) methods() []string {
+	var methods []string
+	for {
+		select {
+		case n := <-s.notifications:
+			methods = append(methods, n.Method)
+		default:
+			return methods
+		}
+	}
+}
+
+func newTestRegistry(t *testing.T) (*toolRegistry, *notifiedSession) {
+	t.Helper()
+	registry := &toolRegistry{
+		server: github.NewServer("test"),
+		getClient: func(context.Context) (*gogithub.Client, error) {
+			return gogithub.NewClient(nil), nil
+		},
+		getGQLClient: func(context.Context) (*githubv4.Client, error) {
+			return githubv4.NewClient(nil), nil
+		},
+		getRawClient: func(context.Context) (*raw.Client, error) {
+			return nil, nil
+		},
+		runLogs: github.NewRunLogsCache(github.DefaultRunLogsCacheSize, github.DefaultRunLogsCacheTTL),
+	}
+	session := &notifiedSession{notifications: make(chan mcp.JSONRPCNotification, 100)}
+	require.NoError(t, registry.server.RegisterSession(context.Background(), session))
+	return registry, session
+}
+
+func overrides(values map[string]string) translations.TranslationHelperFunc {
+	t, _ := translations.TranslationHelperWithOverrides(values)
+	return t
+}
+
+func TestToolRegistryReload(t *testing.T) {
+	registry, session := newTestRegistry(t)
+
+	require.NoError(t, registry.Reload(MCPServerConfig{
+		EnabledToolsets: []string{"context"},
+		Translator:      translations.NullTranslationHelper,
+	}))
+	tools := registry.server.ListTools()
+	require.Contains(t, tools, "get_me")
+	assert.NotContains(t, tools, "create_issue")
+	getMe := tools["get_me"].Handler
+	session.methods()
+
+	// Enabling a toolset and overriding a description
+	require.NoError(t, registry.Reload(MCPServerConfig{
+		EnabledToolsets: []string{"context", "issues"},
+		Translator:      overrides(map[string]string{"TOOL_GET_ME_DESCRIPTION": "Who am I"}),
+	}))
+	tools = registry.server.ListTools()
+	assert.Contains(t, tools, "create_issue")
+	assert.Equal(t, "Who am I", tools["get_me"].Tool.Description)
+	assert.True(t, registry.IsWriteTool("create_issue"))
+	assert.Contains(t, session.methods(), mcp.MethodNotificationToolsListChanged)
+
+	// Handlers of the previous configuration keep working for calls in flight
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_me"
+	_, err := getMe(context.Background(), request)
+	require.NoError(t, err)
+
+	// Switching to read-only removes the write tools
+	require.NoError(t, registry.Reload(MCPServerConfig{
+		EnabledToolsets: []string{"context", "issues"},
+		ReadOnly:        true,
+		Translator:      translations.NullTranslationHelper,
+	}))
+	tools = registry.server.ListTools()
+	assert.Contains(t, tools, "get_issue")
+	assert.NotContains(t, tools, "create_issue")
+	assert.Contains(t, session.methods(), mcp.MethodNotificationToolsListChanged)
+
+	// A failed reload keeps the current tools
+	err = registry.Reload(MCPServerConfig{
+		EnabledToolsets: []string{"nope"},
+		Translator:      translations.NullTranslationHelper,
+	})
+	require.Error(t, err)
+	assert.Contains(t, registry.server.ListTools(), "get_issue")
+}
+
+func TestToolRegistryReloadKeepsDynamicToolsets(t *testing.T) {
+	registry, _ := newTestRegistry(t)
+	cfg := MCPServerConfig{
+		EnabledToolsets: []string{"context"},
+		DynamicToolsets: true,
+		Translator:      translations.NullTranslationHelper,
+	}
+	require.NoError(t, registry.Reload(cfg))
+	require.Contains(t, registry.server.ListTools(), "enable_toolset")
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "enable_toolset"
+	request.Params.Arguments = map[string]any{"toolset": "issues"}
+	result, err := registry.server.ListTools()["enable_toolset"].Handler(context.Background(), request)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	require.Contains(t, registry.server.ListTools(), "create_issue")
+
+	// Toolsets enabled at runtime survive a reload in dynamic mode
+	require.NoError(t, registry.Reload(cfg))
+	assert.Contains(t, registry.server.ListTools(), "create_issue")
+
+	// but only while they fit under the cap
+	cfg.MaxEnabledToolsets = 1
+	require.NoError(t, registry.Reload(cfg))
+	assert.NotContains(t, registry.server.ListTools(), "create_issue")
+	cfg.MaxEnabledToolsets = 0
+
+	// A configuration enabling more toolsets than the cap is rejected
+	err = registry.Reload(MCPServerConfig{
+		EnabledToolsets:    []string{"context", "issues"},
+		DynamicToolsets:    true,
+		MaxEnabledToolsets: 1,
+		Translator:         translations.NullTranslationHelper,
+	})
+	require.Error(t, err)
+	assert.Contains(t, registry.server.ListTools(), "get_me")
+
+	// Toolsets enabled at runtime go away when dynamic mode is turned off
+	require.NoError(t, registry.Reload(cfg))
+	result, err = registry.server.ListTools()["enable_toolset"].Handler(context.Background(), request)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	cfg.DynamicToolsets = false
+	require.NoError(t, registry.Reload(cfg))
+	tools := registry.server.ListTools()
+	assert.NotContains(t, tools, "create_issue")
+	assert.NotContains(t, tools, "enable_toolset")
+	assert.Contains(t, tools, "get_me")
+}
+
+func TestToolRegistryReloadFiltersTools(t *testing.T) {
+	registry, _ := newTestRegistry(t)
+	cfg := MCPServerConfig{
+		EnabledToolsets: []string{"context", "issues"},
+		DynamicToolsets: true,
+		Tools:           []string{"get_*", "enable_*"},
+		ExcludeTools:    []string{"get_issue_comments"},
+		Translator:      translations.NullTranslationHelper,
+	}
+	require.NoError(t, registry.Reload(cfg))
+	tools := registry.server.ListTools()
+	assert.Contains(t, tools, "get_me")
+	assert.Contains(t, tools, "get_issue")
+	assert.Contains(t, tools, "enable_toolset")
+	assert.NotContains(t, tools, "get_issue_comments")
+	assert.NotContains(t, tools, "create_issue")
+	assert.NotContains(t, tools, "list_available_toolsets")
+
+	// Toolsets enabled at runtime are filtered too
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "enable_toolset"
+	request.Params.Arguments = map[string]any{"toolset": "pull_requests"}
+	_, err := tools["enable_toolset"].Handler(context.Background(), request)
+	require.NoError(t, err)
+	tools = registry.server.ListTools()
+	assert.Contains(t, tools, "get_pull_request")
+	assert.NotContains(t, tools, "create_pull_request")
+
+	cfg.ExcludeTools = []string{"get_["}
+	require.Error(t, registry.Reload(cfg))
+}
+
+func TestToolRegistryReloadKeepsContinueResult(t *testing.T) {
+	registry, _ := newTestRegistry(t)
+	var err error
+	registry.budget, err = budget.New(1000, budget.UnitTokens)
+	require.NoError(t, err)
+
+	cfg := MCPServerConfig{
+		EnabledToolsets: []string{"context"},
+		Translator:      translations.NullTranslationHelper,
+	}
+	require.NoError(t, registry.Reload(cfg))
+	require.Contains(t, registry.server.ListTools(), budget.ContinueToolName)
+	cfg.EnabledToolsets = []string{"issues"}
+	require.NoError(t, registry.Reload(cfg))
+	assert.Contains(t, registry.server.ListTools(), budget.ContinueToolName)
+}
+
+func TestToolRegistrySetTranslator(t *testing.T) {
+	registry, _ := newTestRegistry(t)
+	require.NoError(t, registry.Reload(MCPServerConfig{
+		EnabledToolsets: []string{"context", "issues"},
+		Translator:      translations.NullTranslationHelper,
+	}))
+
+	// A client locale rebuilds the current toolsets with its text
+	localized, _ := translations.TranslationHelperWithLocale(nil, translations.Bundle{
+		"TOOL_GET_ME_DESCRIPTION":                "Detalhes do usuário autenticado",
+		"PROMPT_ASSIGN_CODING_AGENT_DESCRIPTION": "Atribuir tarefas ao agente",
+	})
+	require.NoError(t, registry.SetTranslator(localized))
+	tools := registry.server.ListTools()
+	assert.Equal(t, "Detalhes do usuário autenticado", tools["get_me"].Tool.Description)
+	assert.Contains(t, tools, "create_issue")
+
+	result, ok := registry.server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`)).(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	var descriptions []string
+	for _, prompt := range result.Result.(mcp.ListPromptsResult).Prompts {
+		descriptions = append(descriptions, prompt.Description)
+	}
+	assert.Contains(t, descriptions, "Atribuir tarefas ao agente")
+}
+
+func TestWatchReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("toolsets: [context]\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloads := make(chan struct{}, 10)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	require.NoError(t, watchReload(ctx, []string{path}, logger, func() { reloads <- struct{}{} }))
+
+	// Other files in the directory are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("x: 1\n"), 0o600))
+	select {
+	case <-reloads:
+		t.Fatal("reloaded for another file")
+	case <-time.After(2 * reloadDebounce):
+	}
+
+	// A burst of writes reloads once
+	for i := 0; i < 3; i++ {
+		require.NoError(t, os.WriteFile(path, []byte("toolsets: [context, issues]\n"), 0o600))
+	}
+	select {
+	case <-reloads:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload after the config file changed")
+	}
+	select {
+	case <-reloads:
+		t.Fatal("reloaded more than once for a burst of writes")
+	case <-time.After(2 * reloadDebounce):
+	}
+}
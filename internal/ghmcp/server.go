@@ -6,18 +6,32 @@ import (
 	"io"
 	"log"
 	"log/slog"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
-
+	"time"
+
+	"github.com/github/github-mcp-server/internal/audit"
+	"github.com/github/github-mcp-server/internal/confirm"
+	"github.com/github/github-mcp-server/internal/metrics"
+	"github.com/github/github-mcp-server/internal/policy"
+	"github.com/github/github-mcp-server/internal/profiler"
+	"github.com/github/github-mcp-server/internal/tracing"
+	"github.com/github/github-mcp-server/pkg/budget"
+	"github.com/github/github-mcp-server/pkg/cassette"
+	"github.com/github/github-mcp-server/pkg/dryrun"
 	"github.com/github/github-mcp-server/pkg/errors"
 	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/httpcache"
 	mcplog "github.com/github/github-mcp-server/pkg/log"
 	"github.com/github/github-mcp-server/pkg/raw"
-	"github.com/github/github-mcp-server/pkg/repository"
 	"github.com/github/github-mcp-server/pkg/translations"
 	gogithub "github.com/google/go-github/v74/github"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -43,26 +57,143 @@ type MCPServerConfig struct {
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#dynamic-tool-discovery
 	DynamicToolsets bool
 
+	// MaxEnabledToolsets caps the toolsets enabled at once with dynamic toolsets, disabling the least recently used
+	// ones when enable_toolset goes over it; 0 means no cap
+	MaxEnabledToolsets int
+
 	// ReadOnly indicates if we should only offer read-only tools
 	ReadOnly bool
 
+	// Tools are glob patterns of the tools of the enabled toolsets to offer, all of them when empty
+	Tools []string
+
+	// ExcludeTools are glob patterns of tools never to offer, taking precedence over Tools
+	ExcludeTools []string
+
+	// ProjectTools are glob patterns from a repository config file narrowing Tools: when set, only the tools
+	// matching both are offered
+	ProjectTools []string
+
 	// Translator provides translated text for the server tooling
 	Translator translations.TranslationHelperFunc
 
+	// ClientLocale, when set, returns the translator for the locale a client names in the "locale" experimental
+	// capability of its initialize request. The tools, prompts and resource templates are then rebuilt with it.
+	ClientLocale func(locale string) (translations.TranslationHelperFunc, error)
+
 	// Content window size
 	ContentWindowSize int
+
+	// ResponseBudget caps the size of tool results, in ResponseBudgetUnit; larger results are cut and continued with
+	// the continue_result tool. 0 leaves results whole
+	ResponseBudget int
+
+	// ResponseBudgetUnit is chars or tokens, estimated at four characters each
+	ResponseBudgetUnit string
+
+	// ResponseCacheSize is the number of REST and raw responses kept for conditional requests, 0 disables the cache
+	ResponseCacheSize int
+
+	// ResponseCacheDir optionally persists cached responses to disk
+	ResponseCacheDir string
+
+	// ResponseCacheMaxBytes caps the size of the cache in ResponseCacheDir, 0 leaves it unbounded
+	ResponseCacheMaxBytes int64
+
+	// RecordDir, when set, captures all GitHub API traffic into cassette files in this directory
+	RecordDir string
+
+	// ReplayDir, when set, serves GitHub API responses from cassette files in this directory without network access
+	ReplayDir string
+
+	// AuditLogPath, when set, appends a hash-chained record of every write tool call to this file
+	AuditLogPath string
+
+	// PolicyFile, when set, is a YAML or JSON policy deciding which tool calls may run
+	PolicyFile string
+
+	// DryRun makes every write tool return the API requests it would send instead of sending them
+	DryRun bool
+
+	// ConfirmDestructive asks the user, through MCP elicitation, to confirm calls to destructive tools
+	ConfirmDestructive bool
+
+	// ConfirmTools are glob patterns of further tool names whose calls the user must confirm
+	ConfirmTools []string
+
+	// Logger receives the logs of the server's middleware and hooks, slog.Default() when nil
+	Logger *slog.Logger
 }
 
 const stdioServerLogPrefix = "stdioserver"
 
+// NewMCPServer creates the GitHub MCP server. The cassette and audit log files
+// it writes stay open for the life of the process.
 func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
+	ghServer, _, _, err := newMCPServer(cfg)
+	return ghServer, err
+}
+
+// newMCPServer is NewMCPServer also returning the registry that reloads the
+// server's toolsets, and a function closing the files the server writes, to
+// call once it has stopped.
+func newMCPServer(cfg MCPServerConfig) (_ *server.MCPServer, _ *toolRegistry, _ func() error, err error) {
 	apiHost, err := parseAPIHost(cfg.Host)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse API host: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to parse API host: %w", err)
+	}
+
+	if cfg.RecordDir != "" && cfg.ReplayDir != "" {
+		return nil, nil, nil, fmt.Errorf("record and replay modes cannot be used together")
+	}
+
+	var closers []io.Closer
+	closeAll := func() error {
+		var firstErr error
+		for _, c := range closers {
+			if err := c.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+	defer func() {
+		if err != nil {
+			_ = closeAll()
+		}
+	}()
+
+	restTransport, restCloser, err := newBaseTransport(cfg, apiHost, "rest")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create REST transport: %w", err)
+	}
+	if restCloser != nil {
+		closers = append(closers, restCloser)
+	}
+
+	gqlTransport, gqlCloser, err := newBaseTransport(cfg, apiHost, "graphql")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create GraphQL transport: %w", err)
+	}
+	if gqlCloser != nil {
+		closers = append(closers, gqlCloser)
 	}
 
+	restHTTPClient, err := newRESTHTTPClient(cfg, apiHost, restTransport)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create REST HTTP client: %w", err)
+	}
+	// Write tools called in dry-run mode have their mutating requests recorded
+	// rather than sent. The token only goes to GitHub hosts, since tools also
+	// download logs from the pre-signed URLs the API redirects to.
+	restHTTPClient.Transport = dryrun.NewTransport(&bearerAuthTransport{
+		transport: restHTTPClient.Transport,
+		token:     cfg.Token,
+		hosts:     apiHost.hosts(),
+	})
+
 	// Construct our REST client
-	restClient := gogithub.NewClient(nil).WithAuthToken(cfg.Token)
+	restClient := gogithub.NewClient(restHTTPClient)
 	restClient.UserAgent = fmt.Sprintf("github-mcp-server/%s", cfg.Version)
 	restClient.BaseURL = apiHost.baseRESTURL
 	restClient.UploadURL = apiHost.uploadURL
@@ -71,10 +202,10 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 	// We're using NewEnterpriseClient here unconditionally as opposed to NewClient because we already
 	// did the necessary API host parsing so that github.com will return the correct URL anyway.
 	gqlHTTPClient := &http.Client{
-		Transport: &bearerAuthTransport{
-			transport: http.DefaultTransport,
+		Transport: dryrun.NewTransport(&bearerAuthTransport{
+			transport: gqlTransport,
 			token:     cfg.Token,
-		},
+		}),
 	} // We're going to wrap the Transport later in beforeInit
 	gqlClient := githubv4.NewEnterpriseClient(apiHost.graphqlURL.String(), gqlHTTPClient)
 
@@ -105,19 +236,7 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 			},
 		},
 	}
-
-	ghServer := github.NewServer(cfg.Version, server.WithHooks(hooks))
-
-	enabledToolsets := cfg.EnabledToolsets
-	if cfg.DynamicToolsets {
-		// filter "all" from the enabled toolsets
-		enabledToolsets = make([]string, 0, len(cfg.EnabledToolsets))
-		for _, toolset := range cfg.EnabledToolsets {
-			if toolset != "all" {
-				enabledToolsets = append(enabledToolsets, toolset)
-			}
-		}
-	}
+	tracing.RegisterHooks(hooks)
 
 	getClient := func(_ context.Context) (*gogithub.Client, error) {
 		return restClient, nil // closing over client
@@ -135,23 +254,103 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 		return raw.NewClient(client, apiHost.rawURL), nil // closing over client
 	}
 
-	// Create default toolsets
-	tsg := github.DefaultToolsetGroup(cfg.ReadOnly, getClient, getGQLClient, getRawClient, cfg.Translator, cfg.ContentWindowSize)
-	err = tsg.EnableToolsets(enabledToolsets)
-
+	responseBudget, err := budget.New(cfg.ResponseBudget, cfg.ResponseBudgetUnit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to enable toolsets: %w", err)
+		return nil, nil, nil, err
 	}
 
-	// Register all mcp functionality with the server
-	tsg.RegisterAll(ghServer)
+	runLogs := github.NewRunLogsCache(github.DefaultRunLogsCacheSize, github.DefaultRunLogsCacheTTL)
+	closers = append(closers, runLogs)
+
+	// The toolsets are built by the registry, once the server exists, and
+	// rebuilt on every reload
+	registry := &toolRegistry{
+		getClient:    getClient,
+		getGQLClient: getGQLClient,
+		getRawClient: getRawClient,
+		runLogs:      runLogs,
+		budget:       responseBudget,
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	serverOpts := []server.ServerOption{
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(tracing.ToolMiddleware),
+		server.WithToolHandlerMiddleware(metrics.ToolMiddleware),
+		server.WithToolHandlerMiddleware(errors.Middleware(logger, metrics.CountGitHubError)),
+		server.WithToolHandlerMiddleware(responseBudget.Middleware),
+		server.WithToolHandlerMiddleware(registry.trackUsage),
+		server.WithToolHandlerMiddleware(dryrun.Middleware(registry.IsWriteTool, cfg.DryRun)),
+	}
+	if cfg.AuditLogPath != "" {
+		auditLog, err := audit.Open(cfg.AuditLogPath)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+		closers = append(closers, auditLog)
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(
+			auditLog.Middleware(registry.IsWriteTool, auditCaller(restClient), logger),
+		))
+	}
+
+	if cfg.PolicyFile != "" {
+		p, err := policy.Load(cfg.PolicyFile)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		engine := policy.NewEngine(p, policyStatus(restClient), policyBase(restClient))
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(engine.Middleware))
+	}
 
-	if cfg.DynamicToolsets {
-		dynamic := github.InitDynamicToolset(ghServer, tsg, cfg.Translator)
-		dynamic.RegisterTools(ghServer)
+	if cfg.ConfirmDestructive || len(cfg.ConfirmTools) > 0 {
+		matchTools, err := confirm.MatchTools(cfg.ConfirmTools)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid --confirm-tools: %w", err)
+		}
+		// Dry runs send nothing to GitHub, so they run without confirmation.
+		// The dry-run middleware, registered above, marks them in ctx
+		requires := func(ctx context.Context, name string) bool {
+			if dryrun.Active(ctx) {
+				return false
+			}
+			return (cfg.ConfirmDestructive && registry.IsDestructiveTool(name)) || matchTools(name)
+		}
+		confirmer := confirm.New(requires, confirmSummary(restClient))
+		serverOpts = append(serverOpts,
+			server.WithElicitation(),
+			server.WithToolHandlerMiddleware(confirmer.Middleware),
+		)
 	}
 
-	return ghServer, nil
+	if cfg.ClientLocale != nil {
+		hooks.AddBeforeInitialize(func(_ context.Context, _ any, message *mcp.InitializeRequest) {
+			locale, _ := message.Params.Capabilities.Experimental["locale"].(string)
+			if locale == "" {
+				return
+			}
+			t, err := cfg.ClientLocale(locale)
+			if err == nil {
+				err = registry.SetTranslator(t)
+			}
+			if err != nil {
+				logger.Warn("keeping the default locale", "locale", locale, "error", err)
+			}
+		})
+	}
+
+	ghServer := github.NewServer(cfg.Version, serverOpts...)
+
+	// Register all mcp functionality with the server
+	registry.server = ghServer
+	if err := registry.Reload(cfg); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return ghServer, registry, closeAll, nil
 }
 
 type StdioServerConfig struct {
@@ -172,9 +371,23 @@ type StdioServerConfig struct {
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#dynamic-tool-discovery
 	DynamicToolsets bool
 
+	// MaxEnabledToolsets caps the toolsets enabled at once with dynamic toolsets, disabling the least recently used
+	// ones when enable_toolset goes over it; 0 means no cap
+	MaxEnabledToolsets int
+
 	// ReadOnly indicates if we should only register read-only tools
 	ReadOnly bool
 
+	// Tools are glob patterns of the tools of the enabled toolsets to register, all of them when empty
+	Tools []string
+
+	// ExcludeTools are glob patterns of tools never to register, taking precedence over Tools
+	ExcludeTools []string
+
+	// ProjectTools are glob patterns from a repository config file narrowing Tools: when set, only the tools
+	// matching both are registered
+	ProjectTools []string
+
 	// ExportTranslations indicates if we should export translations
 	// See: https://github.com/github/github-mcp-server?tab=readme-ov-file#i18n--overriding-descriptions
 	ExportTranslations bool
@@ -185,8 +398,141 @@ type StdioServerConfig struct {
 	// Path to the log file if not stderr
 	LogFilePath string
 
+	// Locales are the locales to translate tool, prompt and resource template text into, each falling back to its
+	// parent locales, then to the next one, then to the built-in text. When empty, the locale a client asks for is used
+	Locales []string
+
+	// LocalesDir holds the locale bundles, <locale>.json
+	LocalesDir string
+
+	// LogMaxSize rotates the log file once it grows past this many MiB, 0 never rotates it
+	LogMaxSize int
+
+	// LogMaxFiles is the number of rotated log files kept
+	LogMaxFiles int
+
+	// LogRedact are regular expressions redacted from command logs, on top of GitHub tokens and Authorization values
+	LogRedact []string
+
+	// LogSensitiveArguments are tool arguments whose values are redacted from command logs
+	LogSensitiveArguments []string
+
+	// LogMaxPayload cuts each message in command logs to this many bytes, 0 logs no message content
+	LogMaxPayload int
+
 	// Content window size
 	ContentWindowSize int
+
+	// ResponseBudget caps the size of tool results, in ResponseBudgetUnit; larger results are cut and continued with
+	// the continue_result tool. 0 leaves results whole
+	ResponseBudget int
+
+	// ResponseBudgetUnit is chars or tokens, estimated at four characters each
+	ResponseBudgetUnit string
+
+	// ResponseCacheSize is the number of REST and raw responses kept for conditional requests, 0 disables the cache
+	ResponseCacheSize int
+
+	// ResponseCacheDir optionally persists cached responses to disk
+	ResponseCacheDir string
+
+	// ResponseCacheMaxBytes caps the size of the cache in ResponseCacheDir, 0 leaves it unbounded
+	ResponseCacheMaxBytes int64
+
+	// RecordDir, when set, captures all GitHub API traffic into cassette files in this directory
+	RecordDir string
+
+	// ReplayDir, when set, serves GitHub API responses from cassette files in this directory without network access
+	ReplayDir string
+
+	// MetricsAddr, when set, serves Prometheus metrics on /metrics at this address alongside stdio
+	MetricsAddr string
+
+	// TraceExporter selects where traces are sent: none, otlp or console (the log output)
+	TraceExporter string
+
+	// TraceEndpoint is the OTLP/HTTP endpoint for the otlp exporter, defaulting to the OTEL_EXPORTER_OTLP_* environment
+	TraceEndpoint string
+
+	// AuditLogPath, when set, appends a hash-chained record of every write tool call to this file
+	AuditLogPath string
+
+	// PolicyFile, when set, is a YAML or JSON policy deciding which tool calls may run
+	PolicyFile string
+
+	// DryRun makes every write tool return the API requests it would send instead of sending them
+	DryRun bool
+
+	// ConfirmDestructive asks the user, through MCP elicitation, to confirm calls to destructive tools
+	ConfirmDestructive bool
+
+	// ConfirmTools are glob patterns of further tool names whose calls the user must confirm
+	ConfirmTools []string
+
+	// Translations override translation keys, such as tool descriptions, below GITHUB_MCP_ environment variables
+	Translations map[string]string
+
+	// Reload, when set, is called on SIGHUP and when one of ConfigFiles changes to read the configuration again. The server
+	// then swaps its toolsets, tools, descriptions and prompts for the new ones without dropping the session; the
+	// other settings take effect on restart.
+	Reload func() (StdioServerConfig, error)
+
+	// ConfigFiles are the config files watched for changes when Reload is set
+	ConfigFiles []string
+}
+
+// mcpServerConfig returns the configuration of the MCP server run by the stdio
+// server.
+func (cfg StdioServerConfig) mcpServerConfig(t translations.TranslationHelperFunc) MCPServerConfig {
+	return MCPServerConfig{
+		Version:               cfg.Version,
+		Host:                  cfg.Host,
+		Token:                 cfg.Token,
+		EnabledToolsets:       cfg.EnabledToolsets,
+		DynamicToolsets:       cfg.DynamicToolsets,
+		MaxEnabledToolsets:    cfg.MaxEnabledToolsets,
+		ReadOnly:              cfg.ReadOnly,
+		Tools:                 cfg.Tools,
+		ExcludeTools:          cfg.ExcludeTools,
+		ProjectTools:          cfg.ProjectTools,
+		Translator:            t,
+		ContentWindowSize:     cfg.ContentWindowSize,
+		ResponseBudget:        cfg.ResponseBudget,
+		ResponseBudgetUnit:    cfg.ResponseBudgetUnit,
+		ResponseCacheSize:     cfg.ResponseCacheSize,
+		ResponseCacheDir:      cfg.ResponseCacheDir,
+		ResponseCacheMaxBytes: cfg.ResponseCacheMaxBytes,
+		RecordDir:             cfg.RecordDir,
+		ReplayDir:             cfg.ReplayDir,
+		AuditLogPath:          cfg.AuditLogPath,
+		PolicyFile:            cfg.PolicyFile,
+		DryRun:                cfg.DryRun,
+		ConfirmDestructive:    cfg.ConfirmDestructive,
+		ConfirmTools:          cfg.ConfirmTools,
+	}
+}
+
+// translator returns the translator of the locales of cfg or, when it names
+// none, of clientLocale, and the function dumping the translations used. The
+// client's locale is ignored when there is no LocalesDir.
+func (cfg StdioServerConfig) translator(clientLocale string) (translations.TranslationHelperFunc, func(), error) {
+	locales := cfg.Locales
+	if len(locales) == 0 && clientLocale != "" && cfg.LocalesDir != "" {
+		locales = []string{clientLocale}
+	}
+	if len(locales) == 0 {
+		t, dump := translations.TranslationHelperWithOverrides(cfg.Translations)
+		return t, dump, nil
+	}
+	if cfg.LocalesDir == "" {
+		return nil, nil, fmt.Errorf("--locale needs --locales-dir, the directory of the locale bundles")
+	}
+	bundle, err := translations.LoadBundle(cfg.LocalesDir, locales...)
+	if err != nil {
+		return nil, nil, err
+	}
+	t, dump := translations.TranslationHelperWithLocale(cfg.Translations, bundle)
+	return t, dump, nil
 }
 
 // RunStdioServer is not concurrent safe.
@@ -195,28 +541,15 @@ func RunStdioServer(cfg StdioServerConfig) error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	t, dumpTranslations := translations.TranslationHelper()
-
-	ghServer, err := NewMCPServer(MCPServerConfig{
-		Version:           cfg.Version,
-		Host:              cfg.Host,
-		Token:             cfg.Token,
-		EnabledToolsets:   cfg.EnabledToolsets,
-		DynamicToolsets:   cfg.DynamicToolsets,
-		ReadOnly:          cfg.ReadOnly,
-		Translator:        t,
-		ContentWindowSize: cfg.ContentWindowSize,
-	})
+	t, dumpTranslations, err := cfg.translator("")
 	if err != nil {
-		return fmt.Errorf("failed to create MCP server: %w", err)
+		return err
 	}
 
-	stdioServer := server.NewStdioServer(ghServer)
-
 	var slogHandler slog.Handler
 	var logOutput io.Writer
 	if cfg.LogFilePath != "" {
-		file, err := os.OpenFile(cfg.LogFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		file, err := mcplog.OpenRotatingFile(cfg.LogFilePath, int64(cfg.LogMaxSize)<<20, cfg.LogMaxFiles)
 		if err != nil {
 			return fmt.Errorf("failed to open log file: %w", err)
 		}
@@ -228,6 +561,40 @@ func RunStdioServer(cfg StdioServerConfig) error {
 	}
 	logger := slog.New(slogHandler)
 	logger.Info("starting server", "version", cfg.Version, "host", cfg.Host, "dynamicToolsets", cfg.DynamicToolsets, "readOnly", cfg.ReadOnly)
+
+	// Without --locale, the tools are translated into the locale the client
+	// asks for, which is kept across reloads
+	var clientLocale atomic.Value
+	clientLocale.Store("")
+	mcpCfg := cfg.mcpServerConfig(t)
+	mcpCfg.Logger = logger
+	if len(cfg.Locales) == 0 {
+		mcpCfg.ClientLocale = func(locale string) (translations.TranslationHelperFunc, error) {
+			t, _, err := cfg.translator(locale)
+			if err != nil {
+				return nil, err
+			}
+			clientLocale.Store(locale)
+			return t, nil
+		}
+	}
+
+	ghServer, registry, closeServer, err := newMCPServer(mcpCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create MCP server: %w", err)
+	}
+	// Flush the cassettes and audit log once the server has stopped
+	defer func() { _ = closeServer() }()
+
+	stdioServer := server.NewStdioServer(ghServer)
+
+	profiler.InitFromEnv(logger)
+	startMetricsServer(ctx, cfg.MetricsAddr, logger)
+	shutdownTracing, err := setupTracing(ctx, cfg, "github-mcp-server", logOutput)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing()
 	stdLogger := log.New(logOutput, stdioServerLogPrefix, 0)
 	stdioServer.SetErrorLogger(stdLogger)
 
@@ -236,13 +603,41 @@ func RunStdioServer(cfg StdioServerConfig) error {
 		dumpTranslations()
 	}
 
+	if cfg.Reload != nil {
+		reload := func() {
+			next, err := cfg.Reload()
+			if err != nil {
+				logger.Error("failed to reload configuration, keeping the current tools", "error", err)
+				return
+			}
+			t, _, err := next.translator(clientLocale.Load().(string))
+			if err != nil {
+				logger.Error("failed to reload configuration, keeping the current tools", "error", err)
+				return
+			}
+			if err := registry.Reload(next.mcpServerConfig(t)); err != nil {
+				logger.Error("failed to reload configuration, keeping the current tools", "error", err)
+				return
+			}
+			logger.Info("reloaded configuration", "toolsets", next.EnabledToolsets, "dynamicToolsets", next.DynamicToolsets, "readOnly", next.ReadOnly)
+		}
+		if err := watchReload(ctx, cfg.ConfigFiles, logger, reload); err != nil {
+			return err
+		}
+	}
+
+	ioLogOpts, err := ioLoggerOptions(cfg)
+	if err != nil {
+		return err
+	}
+
 	// Start listening for messages
 	errC := make(chan error, 1)
 	go func() {
 		in, out := io.Reader(os.Stdin), io.Writer(os.Stdout)
 
 		if cfg.EnableCommandLogging {
-			loggedIO := mcplog.NewIOLogger(in, out, logger)
+			loggedIO := mcplog.NewIOLogger(in, out, logger, ioLogOpts...)
 			in, out = loggedIO, loggedIO
 		}
 		// enable GitHub errors in the context
@@ -267,90 +662,185 @@ func RunStdioServer(cfg StdioServerConfig) error {
 	return nil
 }
 
-// RunRepositoryStdioServer runs the MCP server for repository analysis
-func RunRepositoryStdioServer(cfg StdioServerConfig) error {
-	// Create app context
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
+// ioLoggerOptions returns the options of the command logger.
+func ioLoggerOptions(cfg StdioServerConfig) ([]mcplog.Option, error) {
+	patterns, err := mcplog.CompileRedactPatterns(cfg.LogRedact)
+	if err != nil {
+		return nil, err
+	}
+	return []mcplog.Option{
+		mcplog.WithRedactPatterns(patterns...),
+		mcplog.WithSensitiveArguments(cfg.LogSensitiveArguments...),
+		mcplog.WithMaxPayload(cfg.LogMaxPayload),
+	}, nil
+}
 
-	// Create MCP server for repository tools
-	repoServer := server.NewMCPServer(
-		"mcp-prime",
-		cfg.Version,
-		server.WithToolCapabilities(true),
-		server.WithLogging(),
-	)
+// startMetricsServer serves metrics on a side port until ctx is done. The stdio
+// transport has no HTTP listener of its own to mount /metrics on.
+func startMetricsServer(ctx context.Context, addr string, logger *slog.Logger) {
+	if addr == "" {
+		return
+	}
+	go func() {
+		logger.Info("serving metrics", "addr", addr)
+		if err := metrics.ListenAndServe(ctx, addr); err != nil {
+			logger.Error("metrics server failed", "addr", addr, "error", err)
+		}
+	}()
+}
 
-	// Register repository tools
-	err := registerRepositoryTools(repoServer)
+// setupTracing installs the configured trace exporter. Console traces go to the
+// log output because stdout carries the protocol. The returned function
+// flushes pending spans.
+func setupTracing(ctx context.Context, cfg StdioServerConfig, serviceName string, logOutput io.Writer) (func(), error) {
+	shutdown, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:       cfg.TraceExporter,
+		Endpoint:       cfg.TraceEndpoint,
+		ServiceName:    serviceName,
+		ServiceVersion: cfg.Version,
+		Output:         logOutput,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to register repository tools: %w", err)
+		return nil, fmt.Errorf("failed to set up tracing: %w", err)
 	}
+	return func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = shutdown(shutdownCtx)
+	}, nil
+}
 
-	stdioServer := server.NewStdioServer(repoServer)
+// auditCaller returns a function identifying the caller of audited tools. The
+// login behind the token is looked up on audited calls until a lookup
+// succeeds, and then kept. The lookup is not tied to the call, whose
+// cancellation would otherwise fail it.
+func auditCaller(client *gogithub.Client) func(context.Context) audit.Caller {
+	var mu sync.Mutex
+	var login string
+	return func(ctx context.Context) audit.Caller {
+		mu.Lock()
+		defer mu.Unlock()
+		if login == "" {
+			lookupCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if user, _, err := client.Users.Get(lookupCtx, ""); err == nil {
+				login = user.GetLogin()
+			}
+			cancel()
+		}
+		return audit.SessionCaller(ctx, login)
+	}
+}
 
-	var slogHandler slog.Handler
-	var logOutput io.Writer
-	if cfg.LogFilePath != "" {
-		file, err := os.OpenFile(cfg.LogFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+// policyStatus looks up the combined commit status for policy conditions: of
+// the head of the pull request named by pullNumber, or else of the sha, ref or
+// branch argument.
+func policyStatus(client *gogithub.Client) policy.StatusFunc {
+	return func(ctx context.Context, owner, repo string, args map[string]any) (string, error) {
+		var ref string
+		if number, ok := args["pullNumber"].(float64); ok {
+			pr, _, err := client.PullRequests.Get(ctx, owner, repo, int(number))
+			if err != nil {
+				return "", fmt.Errorf("failed to get pull request: %w", err)
+			}
+			ref = pr.GetHead().GetSHA()
+		} else {
+			for _, key := range []string{"sha", "ref", "branch"} {
+				if v, ok := args[key].(string); ok && v != "" {
+					ref = v
+					break
+				}
+			}
+		}
+		if ref == "" {
+			return "", fmt.Errorf("the call names no pull request or ref")
+		}
+		status, _, err := client.Repositories.GetCombinedStatus(ctx, owner, repo, ref, nil)
 		if err != nil {
-			return fmt.Errorf("failed to open log file: %w", err)
+			return "", fmt.Errorf("failed to get combined status: %w", err)
 		}
-		logOutput = file
-		slogHandler = slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: slog.LevelDebug})
-	} else {
-		logOutput = os.Stderr
-		slogHandler = slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: slog.LevelInfo})
+		return status.GetState(), nil
 	}
-	logger := slog.New(slogHandler)
-	logger.Info("starting MCP PRIME server", "version", cfg.Version)
-	stdLogger := log.New(logOutput, "[MCP-PRIME] ", 0)
-	stdioServer.SetErrorLogger(stdLogger)
-
-	// Start listening for messages
-	errC := make(chan error, 1)
-	go func() {
-		in, out := io.Reader(os.Stdin), io.Writer(os.Stdout)
+}
 
-		if cfg.EnableCommandLogging {
-			loggedIO := mcplog.NewIOLogger(in, out, logger)
-			in, out = loggedIO, loggedIO
+// policyBase looks up the base branch of a pull request for branch rules.
+func policyBase(client *gogithub.Client) policy.BaseFunc {
+	return func(ctx context.Context, owner, repo string, number int) (string, error) {
+		pr, _, err := client.PullRequests.Get(ctx, owner, repo, number)
+		if err != nil {
+			return "", fmt.Errorf("failed to get pull request: %w", err)
 		}
-		errC <- stdioServer.Listen(ctx, in, out)
-	}()
+		return pr.GetBase().GetRef(), nil
+	}
+}
 
-	// Output server info
-	_, _ = fmt.Fprintf(os.Stderr, "MCP PRIME Server running on stdio\n")
+// confirmSummary describes calls for confirmation prompts, naming the base
+// branch a pull request would be merged into.
+func confirmSummary(client *gogithub.Client) confirm.DescribeFunc {
+	return func(ctx context.Context, tool string, args map[string]any) string {
+		owner, _ := args["owner"].(string)
+		repo, _ := args["repo"].(string)
+		if number, ok := args["pullNumber"].(float64); ok && tool == "merge_pull_request" {
+			if pr, _, err := client.PullRequests.Get(ctx, owner, repo, int(number)); err == nil {
+				withBase := maps.Clone(args)
+				withBase["base"] = pr.GetBase().GetRef()
+				return confirm.Describe(tool, withBase)
+			}
+		}
+		return confirm.Describe(tool, args)
+	}
+}
 
-	// Wait for shutdown signal
-	select {
-	case <-ctx.Done():
-		logger.Info("shutting down server", "signal", "context done")
-	case err := <-errC:
+// newBaseTransport returns the transport that sends GitHub API requests for the
+// named client. It records traffic into, or replays it from, a cassette when
+// either mode is configured, and then also returns the recorder to close.
+// Requests are instrumented beneath any response cache, so revalidations show
+// up with their 304 status.
+func newBaseTransport(cfg MCPServerConfig, host apiHost, name string) (http.RoundTripper, io.Closer, error) {
+	if cfg.ReplayDir != "" {
+		replayer, err := cassette.NewReplayer(cfg.ReplayDir, name)
 		if err != nil {
-			logger.Error("error running server", "error", err)
-			return fmt.Errorf("error running server: %w", err)
+			return nil, nil, err
 		}
+		return instrumentTransport(replayer, host), nil, nil
 	}
-
-	return nil
+	if cfg.RecordDir != "" {
+		recorder, err := cassette.NewRecorder(cfg.RecordDir, name, http.DefaultTransport)
+		if err != nil {
+			return nil, nil, err
+		}
+		return instrumentTransport(recorder, host), recorder, nil
+	}
+	return instrumentTransport(http.DefaultTransport, host), nil, nil
 }
 
-func registerRepositoryTools(mcpServer *server.MCPServer) error {
-	// Register repository analysis tools directly
-	fileListTool, fileListHandler := repository.GetFileListTool()
-	mcpServer.AddTool(fileListTool, fileListHandler)
-
-	fileContentTool, fileContentHandler := repository.GetFileContentTool()
-	mcpServer.AddTool(fileContentTool, fileContentHandler)
+// instrumentTransport records metrics and trace spans for every request sent
+// through transport, labelling requests to hosts other than those of host as
+// such.
+func instrumentTransport(transport http.RoundTripper, host apiHost) http.RoundTripper {
+	return tracing.NewTransport(metrics.NewTransport(transport, host.hosts()...))
+}
 
-	extractSigTool, extractSigHandler := repository.ExtractSignaturesTool()
-	mcpServer.AddTool(extractSigTool, extractSigHandler)
+// newRESTHTTPClient returns the HTTP client shared by the REST and raw clients,
+// wrapped in a conditional-request cache when one is configured. Only requests
+// to the hosts of host are cached, not downloads the API redirects elsewhere.
+func newRESTHTTPClient(cfg MCPServerConfig, host apiHost, transport http.RoundTripper) (*http.Client, error) {
+	if cfg.ResponseCacheSize <= 0 {
+		return &http.Client{Transport: transport}, nil
+	}
 
-	emitToolTool, emitToolHandler := repository.EmitToolJSONTool()
-	mcpServer.AddTool(emitToolTool, emitToolHandler)
+	var store httpcache.Store = httpcache.NewMemoryStore(cfg.ResponseCacheSize)
+	if cfg.ResponseCacheDir != "" {
+		disk, err := httpcache.NewDiskStore(cfg.ResponseCacheDir, cfg.ResponseCacheMaxBytes)
+		if err != nil {
+			return nil, err
+		}
+		store = httpcache.NewTieredStore(store, disk)
+	}
 
-	return nil
+	return &http.Client{Transport: httpcache.NewTransport(transport, store,
+		httpcache.WithHosts(host.hosts()...),
+		httpcache.WithCount(metrics.CountResponseCache),
+	)}, nil
 }
 
 type apiHost struct {
@@ -360,6 +850,11 @@ type apiHost struct {
 	rawURL      *url.URL
 }
 
+// hosts returns the hosts of the API URLs.
+func (h apiHost) hosts() []string {
+	return []string{h.baseRESTURL.Host, h.graphqlURL.Host, h.uploadURL.Host, h.rawURL.Host}
+}
+
 func newDotcomHost() (apiHost, error) {
 	baseRestURL, err := url.Parse("https://api.github.com/")
 	if err != nil {
@@ -434,21 +929,23 @@ func newGHESHost(hostname string) (apiHost, error) {
 		return apiHost{}, fmt.Errorf("failed to parse GHES URL: %w", err)
 	}
 
-	restURL, err := url.Parse(fmt.Sprintf("%s://%s/api/v3/", u.Scheme, u.Hostname()))
+	// The port is kept so that development and test servers (such as the
+	// in-process fake in internal/githubfake) can be targeted.
+	restURL, err := url.Parse(fmt.Sprintf("%s://%s/api/v3/", u.Scheme, u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES REST URL: %w", err)
 	}
 
-	gqlURL, err := url.Parse(fmt.Sprintf("%s://%s/api/graphql", u.Scheme, u.Hostname()))
+	gqlURL, err := url.Parse(fmt.Sprintf("%s://%s/api/graphql", u.Scheme, u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES GraphQL URL: %w", err)
 	}
 
-	uploadURL, err := url.Parse(fmt.Sprintf("%s://%s/api/uploads/", u.Scheme, u.Hostname()))
+	uploadURL, err := url.Parse(fmt.Sprintf("%s://%s/api/uploads/", u.Scheme, u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES Upload URL: %w", err)
 	}
-	rawURL, err := url.Parse(fmt.Sprintf("%s://%s/raw/", u.Scheme, u.Hostname()))
+	rawURL, err := url.Parse(fmt.Sprintf("%s://%s/raw/", u.Scheme, u.Host))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHES Raw URL: %w", err)
 	}
@@ -461,7 +958,7 @@ func newGHESHost(hostname string) (apiHost, error) {
 	}, nil
 }
 
-// Note that this does not handle ports yet, so development environments are out.
+// Ports are only honoured for GHES hosts; dotcom and GHEC always use their well known API hosts.
 func parseAPIHost(s string) (apiHost, error) {
 	if s == "" {
 		return newDotcomHost()
@@ -501,9 +998,14 @@ func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error
 type bearerAuthTransport struct {
 	transport http.RoundTripper
 	token     string
+	// hosts limits the token to requests to these hosts; nil sends it to all
+	hosts []string
 }
 
 func (t *bearerAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.hosts != nil && !slices.Contains(t.hosts, req.URL.Host) {
+		return t.transport.RoundTrip(req)
+	}
 	req = req.Clone(req.Context())
 	req.Header.Set("Authorization", "Bearer "+t.token)
 	return t.transport.RoundTrip(req)
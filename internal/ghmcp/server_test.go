@@ -0,0 +1,162 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	gogithub "github.com/google/go-github/v74/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMCPServerServesRepositoryToolset(t *testing.T) {
+	ghServer, err := NewMCPServer(MCPServerConfig{
+		Version:         "test",
+		EnabledToolsets: []string{"repository"},
+		DynamicToolsets: true,
+		Translator:      translations.NullTranslationHelper,
+	})
+	require.NoError(t, err)
+
+	response := ghServer.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	result, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected a response, got %#v", response)
+	tools, ok := result.Result.(mcp.ListToolsResult)
+	require.True(t, ok)
+
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	assert.Subset(t, names, []string{"get_file_list", "get_file_content", "extract_signatures", "emit_tool_json", "enable_toolset"})
+	assert.NotContains(t, names, "get_me")
+}
+
+func TestParseAPIHost(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        string
+		wantREST    string
+		wantGraphQL string
+		wantRaw     string
+	}{
+		{
+			name:        "dotcom by default",
+			host:        "",
+			wantREST:    "https://api.github.com/",
+			wantGraphQL: "https://api.github.com/graphql",
+			wantRaw:     "https://raw.githubusercontent.com/",
+		},
+		{
+			name:        "GHEC",
+			host:        "https://octocorp.ghe.com",
+			wantREST:    "https://api.octocorp.ghe.com/",
+			wantGraphQL: "https://api.octocorp.ghe.com/graphql",
+			wantRaw:     "https://raw.octocorp.ghe.com/",
+		},
+		{
+			name:        "GHES keeps the port",
+			host:        "http://127.0.0.1:8080",
+			wantREST:    "http://127.0.0.1:8080/api/v3/",
+			wantGraphQL: "http://127.0.0.1:8080/api/graphql",
+			wantRaw:     "http://127.0.0.1:8080/raw/",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			host, err := parseAPIHost(tc.host)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantREST, host.baseRESTURL.String())
+			assert.Equal(t, tc.wantGraphQL, host.graphqlURL.String())
+			assert.Equal(t, tc.wantRaw, host.rawURL.String())
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestBearerAuthTransportHosts(t *testing.T) {
+	var got string
+	transport := &bearerAuthTransport{
+		transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			got = req.Header.Get("Authorization")
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+		token: "ghp_token",
+		hosts: []string{"api.github.com"},
+	}
+
+	for url, want := range map[string]string{
+		"https://api.github.com/repos/o/r/actions/jobs/1/logs":   "Bearer ghp_token",
+		"https://results.blob.core.windows.net/logs/1.txt?sig=x": "",
+	} {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		_, err = transport.RoundTrip(req)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, url)
+	}
+
+	// Without hosts, the token goes to every request
+	transport.hosts = nil
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/graphql", nil)
+	require.NoError(t, err)
+	_, err = transport.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer ghp_token", got)
+}
+
+func TestAuditCallerRetriesLookup(t *testing.T) {
+	lookups := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		lookups++
+		if lookups == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"login":"octocat"}`))
+	}))
+	defer srv.Close()
+	client := gogithub.NewClient(nil)
+	baseURL, err := url.Parse(srv.URL + "/")
+	require.NoError(t, err)
+	client.BaseURL = baseURL
+	caller := auditCaller(client)
+
+	// A failed lookup is not kept, and a cancelled call does not fail the lookup
+	assert.Empty(t, caller(context.Background()).Login)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, "octocat", caller(ctx).Login)
+	assert.Equal(t, "octocat", caller(context.Background()).Login)
+	assert.Equal(t, 2, lookups)
+}
+
+func TestStdioServerConfigTranslator(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "es.json"), []byte(`{"TOOL_GET_ME_USER_TITLE": "Mi perfil"}`), 0o600))
+
+	// Without a locales directory, the client's locale is ignored but --locale is an error
+	translate, _, err := StdioServerConfig{}.translator("es")
+	require.NoError(t, err)
+	assert.Equal(t, "My profile", translate("TOOL_GET_ME_USER_TITLE", "My profile"))
+	_, _, err = StdioServerConfig{Locales: []string{"es"}}.translator("")
+	assert.ErrorContains(t, err, "--locale needs --locales-dir")
+
+	translate, _, err = StdioServerConfig{LocalesDir: dir}.translator("es")
+	require.NoError(t, err)
+	assert.Equal(t, "Mi perfil", translate("TOOL_GET_ME_USER_TITLE", "My profile"))
+}
@@ -0,0 +1,539 @@
+package githubfake
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+type workflow struct {
+	id   int64
+	name string
+	path string
+}
+
+type workflowRun struct {
+	id         int64
+	workflow   *workflow
+	runNumber  int
+	attempt    int
+	event      string
+	branch     string
+	headSHA    string
+	status     string
+	conclusion string
+	createdAt  string
+	updatedAt  string
+	jobs       []*workflowJob
+}
+
+type workflowJob struct {
+	id         int64
+	name       string
+	status     string
+	conclusion string
+	steps      []workflowStep
+	log        string
+}
+
+type workflowStep struct {
+	name       string
+	conclusion string
+}
+
+// JobSeed describes a job of a workflow run created with SeedWorkflowRun.
+type JobSeed struct {
+	Name string
+	// Conclusion is the job conclusion, such as "success" or "failure".
+	// It defaults to the run conclusion.
+	Conclusion string
+	// Steps are the step names. A failed job marks its last step as failed.
+	Steps []string
+	// Log is the plain text job log served by the job log download URL.
+	Log string
+}
+
+// WorkflowRunSeed describes a workflow run created with SeedWorkflowRun.
+type WorkflowRunSeed struct {
+	// Workflow is the path of the workflow file, e.g. ".github/workflows/ci.yml".
+	// The workflow is created if it does not exist.
+	Workflow   string
+	Branch     string
+	Event      string
+	Status     string
+	Conclusion string
+	Jobs       []JobSeed
+}
+
+// SeedWorkflow creates a workflow for the file at workflowPath and returns its ID.
+func (s *Server) SeedWorkflow(owner, name, workflowPath, workflowName string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.repos[repoKey(owner, name)]
+	if repo == nil {
+		panic("githubfake: unknown repository " + owner + "/" + name)
+	}
+	wf := s.ensureWorkflow(repo, workflowPath)
+	if workflowName != "" {
+		wf.name = workflowName
+	}
+	return wf.id
+}
+
+// SeedWorkflowRun creates a completed (by default) workflow run and returns its ID.
+func (s *Server) SeedWorkflowRun(owner, name string, seed WorkflowRunSeed) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.repos[repoKey(owner, name)]
+	if repo == nil {
+		panic("githubfake: unknown repository " + owner + "/" + name)
+	}
+	if seed.Workflow == "" {
+		seed.Workflow = ".github/workflows/ci.yml"
+	}
+	if seed.Status == "" {
+		seed.Status = "completed"
+	}
+	if seed.Status == "completed" && seed.Conclusion == "" {
+		seed.Conclusion = "success"
+	}
+
+	run := s.newRun(repo, s.ensureWorkflow(repo, seed.Workflow), seed.Branch, seed.Event)
+	run.status, run.conclusion = seed.Status, seed.Conclusion
+	for _, js := range seed.Jobs {
+		conclusion := js.Conclusion
+		if conclusion == "" {
+			conclusion = seed.Conclusion
+		}
+		job := &workflowJob{
+			id:         s.id(),
+			name:       js.Name,
+			status:     seed.Status,
+			conclusion: conclusion,
+			log:        js.Log,
+		}
+		for i, step := range js.Steps {
+			stepConclusion := "success"
+			if conclusion == "failure" && i == len(js.Steps)-1 {
+				stepConclusion = "failure"
+			}
+			job.steps = append(job.steps, workflowStep{name: step, conclusion: stepConclusion})
+		}
+		run.jobs = append(run.jobs, job)
+	}
+	return run.id
+}
+
+func (s *Server) ensureWorkflow(repo *repository, workflowPath string) *workflow {
+	for _, wf := range repo.workflows {
+		if wf.path == workflowPath {
+			return wf
+		}
+	}
+	wf := &workflow{
+		id:   s.id(),
+		name: strings.TrimSuffix(path.Base(workflowPath), path.Ext(workflowPath)),
+		path: workflowPath,
+	}
+	repo.workflows = append(repo.workflows, wf)
+	return wf
+}
+
+func (s *Server) newRun(repo *repository, wf *workflow, branch, event string) *workflowRun {
+	if branch == "" {
+		branch = repo.defaultBranch
+	}
+	if event == "" {
+		event = "push"
+	}
+	runNumber := 1
+	for _, run := range repo.runs {
+		if run.workflow == wf {
+			runNumber++
+		}
+	}
+	now := s.timestamp()
+	run := &workflowRun{
+		id:        s.id(),
+		workflow:  wf,
+		runNumber: runNumber,
+		attempt:   1,
+		event:     event,
+		branch:    branch,
+		headSHA:   repo.git.refs["refs/heads/"+branch],
+		status:    "queued",
+		createdAt: now,
+		updatedAt: now,
+	}
+	repo.runs = append(repo.runs, run)
+	return run
+}
+
+func (s *Server) workflowJSON(repo *repository, wf *workflow) map[string]any {
+	return map[string]any{
+		"id":       wf.id,
+		"node_id":  "W_" + strconv.FormatInt(wf.id, 10),
+		"name":     wf.name,
+		"path":     wf.path,
+		"state":    "active",
+		"url":      s.repoURL(repo) + "/actions/workflows/" + strconv.FormatInt(wf.id, 10),
+		"html_url": s.repoHTMLURL(repo) + "/blob/" + repo.defaultBranch + "/" + wf.path,
+	}
+}
+
+func (s *Server) runJSON(repo *repository, run *workflowRun) map[string]any {
+	runURL := s.repoURL(repo) + "/actions/runs/" + strconv.FormatInt(run.id, 10)
+	out := map[string]any{
+		"id":           run.id,
+		"node_id":      "WFR_" + strconv.FormatInt(run.id, 10),
+		"name":         run.workflow.name,
+		"workflow_id":  run.workflow.id,
+		"run_number":   run.runNumber,
+		"run_attempt":  run.attempt,
+		"event":        run.event,
+		"head_branch":  run.branch,
+		"head_sha":     run.headSHA,
+		"status":       run.status,
+		"created_at":   run.createdAt,
+		"updated_at":   run.updatedAt,
+		"url":          runURL,
+		"jobs_url":     runURL + "/jobs",
+		"logs_url":     runURL + "/logs",
+		"html_url":     s.repoHTMLURL(repo) + "/actions/runs/" + strconv.FormatInt(run.id, 10),
+		"workflow_url": s.repoURL(repo) + "/actions/workflows/" + strconv.FormatInt(run.workflow.id, 10),
+	}
+	if run.conclusion != "" {
+		out["conclusion"] = run.conclusion
+	}
+	return out
+}
+
+func (s *Server) jobJSON(repo *repository, run *workflowRun, job *workflowJob) map[string]any {
+	steps := []map[string]any{}
+	for i, step := range job.steps {
+		steps = append(steps, map[string]any{
+			"name":       step.name,
+			"number":     i + 1,
+			"status":     job.status,
+			"conclusion": step.conclusion,
+		})
+	}
+	out := map[string]any{
+		"id":          job.id,
+		"run_id":      run.id,
+		"run_attempt": run.attempt,
+		"name":        job.name,
+		"status":      job.status,
+		"head_sha":    run.headSHA,
+		"head_branch": run.branch,
+		"steps":       steps,
+		"started_at":  run.createdAt,
+		"url":         s.repoURL(repo) + "/actions/jobs/" + strconv.FormatInt(job.id, 10),
+		"html_url":    s.repoHTMLURL(repo) + "/actions/runs/" + strconv.FormatInt(run.id, 10) + "/job/" + strconv.FormatInt(job.id, 10),
+	}
+	if job.conclusion != "" {
+		out["conclusion"] = job.conclusion
+		out["completed_at"] = run.updatedAt
+	}
+	return out
+}
+
+func (s *Server) actionsRoutes(mux *http.ServeMux) {
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/actions/workflows", s.listWorkflows)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/actions/workflows/{workflow}/runs", s.listRuns)
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/actions/workflows/{workflow}/dispatches", s.dispatchWorkflow)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/actions/runs", s.listRuns)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/actions/runs/{id}", s.getRun)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/actions/runs/{id}/jobs", s.listJobs)
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/actions/runs/{id}/rerun", s.rerunRun)
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/actions/runs/{id}/rerun-failed-jobs", s.rerunRun)
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/actions/runs/{id}/cancel", s.cancelRun)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/actions/runs/{id}/logs", s.getRunLogs)
+	s.handle(mux, "DELETE /api/v3/repos/{owner}/{repo}/actions/runs/{id}/logs", s.deleteRunLogs)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/actions/runs/{id}/timing", s.getRunTiming)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/actions/runs/{id}/artifacts", s.listArtifacts)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/actions/jobs/{id}/logs", s.getJobLogs)
+
+	// Log downloads are served outside the API, as GitHub redirects them to blob storage
+	s.handle(mux, "GET /_logs/{owner}/{repo}/jobs/{id}", s.downloadJobLogs)
+	s.handle(mux, "GET /_logs/{owner}/{repo}/runs/{id}", s.downloadRunLogs)
+}
+
+func (s *Server) listWorkflows(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	workflows := []map[string]any{}
+	for _, wf := range repo.workflows {
+		workflows = append(workflows, s.workflowJSON(repo, wf))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"total_count": len(workflows),
+		"workflows":   paginate(r, workflows),
+	})
+}
+
+// lookupWorkflow resolves the {workflow} path value, which is either a numeric
+// ID or a workflow file name.
+func (s *Server) lookupWorkflow(w http.ResponseWriter, r *http.Request, repo *repository) (*workflow, bool) {
+	ref := r.PathValue("workflow")
+	for _, wf := range repo.workflows {
+		if strconv.FormatInt(wf.id, 10) == ref || path.Base(wf.path) == ref {
+			return wf, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+	return nil, false
+}
+
+func (s *Server) listRuns(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	var wf *workflow
+	if r.PathValue("workflow") != "" {
+		if wf, ok = s.lookupWorkflow(w, r, repo); !ok {
+			return
+		}
+	}
+
+	query := r.URL.Query()
+	runs := []map[string]any{}
+	for i := len(repo.runs) - 1; i >= 0; i-- {
+		run := repo.runs[i]
+		switch {
+		case wf != nil && run.workflow != wf:
+			continue
+		case query.Get("branch") != "" && run.branch != query.Get("branch"):
+			continue
+		case query.Get("event") != "" && run.event != query.Get("event"):
+			continue
+		case query.Get("status") != "" && run.status != query.Get("status") && run.conclusion != query.Get("status"):
+			continue
+		}
+		runs = append(runs, s.runJSON(repo, run))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"total_count":   len(runs),
+		"workflow_runs": paginate(r, runs),
+	})
+}
+
+func (s *Server) dispatchWorkflow(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	wf, ok := s.lookupWorkflow(w, r, repo)
+	if !ok {
+		return
+	}
+	var body struct {
+		Ref string `json:"ref"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if _, ok := repo.git.refs["refs/heads/"+body.Ref]; !ok {
+		writeValidationError(w, "No ref found for: "+body.Ref)
+		return
+	}
+	s.newRun(repo, wf, body.Ref, "workflow_dispatch")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) lookupRun(w http.ResponseWriter, r *http.Request) (*repository, *workflowRun, bool) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return nil, nil, false
+	}
+	for _, run := range repo.runs {
+		if run.id == id {
+			return repo, run, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+	return nil, nil, false
+}
+
+func (s *Server) getRun(w http.ResponseWriter, r *http.Request) {
+	repo, run, ok := s.lookupRun(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.runJSON(repo, run))
+}
+
+func (s *Server) listJobs(w http.ResponseWriter, r *http.Request) {
+	repo, run, ok := s.lookupRun(w, r)
+	if !ok {
+		return
+	}
+	jobs := []map[string]any{}
+	for _, job := range run.jobs {
+		jobs = append(jobs, s.jobJSON(repo, run, job))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"total_count": len(jobs),
+		"jobs":        paginate(r, jobs),
+	})
+}
+
+func (s *Server) rerunRun(w http.ResponseWriter, r *http.Request) {
+	_, run, ok := s.lookupRun(w, r)
+	if !ok {
+		return
+	}
+	if run.status != "completed" {
+		writeError(w, http.StatusForbidden, "This workflow run is not completed")
+		return
+	}
+	failedOnly := strings.HasSuffix(r.URL.Path, "/rerun-failed-jobs")
+	run.attempt++
+	run.status, run.conclusion = "queued", ""
+	run.updatedAt = s.timestamp()
+	for _, job := range run.jobs {
+		if failedOnly && job.conclusion != "failure" {
+			continue
+		}
+		job.status, job.conclusion = "queued", ""
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{})
+}
+
+func (s *Server) cancelRun(w http.ResponseWriter, r *http.Request) {
+	_, run, ok := s.lookupRun(w, r)
+	if !ok {
+		return
+	}
+	if run.status == "completed" {
+		writeError(w, http.StatusConflict, "Cannot cancel a workflow run that is completed.")
+		return
+	}
+	run.status, run.conclusion = "completed", "cancelled"
+	run.updatedAt = s.timestamp()
+	for _, job := range run.jobs {
+		if job.status != "completed" {
+			job.status, job.conclusion = "completed", "cancelled"
+		}
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{})
+}
+
+func (s *Server) getRunTiming(w http.ResponseWriter, r *http.Request) {
+	_, run, ok := s.lookupRun(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"billable": map[string]any{
+			"UBUNTU": map[string]any{"total_ms": 0, "jobs": len(run.jobs)},
+		},
+		"run_duration_ms": 0,
+	})
+}
+
+func (s *Server) listArtifacts(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := s.lookupRun(w, r); !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"total_count": 0,
+		"artifacts":   []any{},
+	})
+}
+
+func (s *Server) getRunLogs(w http.ResponseWriter, r *http.Request) {
+	repo, run, ok := s.lookupRun(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("%s/_logs/%s/%s/runs/%d", s.URL, repo.owner, repo.name, run.id))
+	w.WriteHeader(http.StatusFound)
+}
+
+func (s *Server) deleteRunLogs(w http.ResponseWriter, r *http.Request) {
+	_, run, ok := s.lookupRun(w, r)
+	if !ok {
+		return
+	}
+	for _, job := range run.jobs {
+		job.log = ""
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) lookupJob(w http.ResponseWriter, r *http.Request) (*repository, *workflowJob, bool) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return nil, nil, false
+	}
+	for _, run := range repo.runs {
+		for _, job := range run.jobs {
+			if job.id == id {
+				return repo, job, true
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+	return nil, nil, false
+}
+
+func (s *Server) getJobLogs(w http.ResponseWriter, r *http.Request) {
+	repo, job, ok := s.lookupJob(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("%s/_logs/%s/%s/jobs/%d", s.URL, repo.owner, repo.name, job.id))
+	w.WriteHeader(http.StatusFound)
+}
+
+func (s *Server) downloadJobLogs(w http.ResponseWriter, r *http.Request) {
+	_, job, ok := s.lookupJob(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte(job.log))
+}
+
+// downloadRunLogs serves the run log archive in the layout GitHub uses: one
+// "<n>_<job>.txt" file per job holding the full job log.
+func (s *Server) downloadRunLogs(w http.ResponseWriter, r *http.Request) {
+	_, run, ok := s.lookupRun(w, r)
+	if !ok {
+		return
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i, job := range run.jobs {
+		f, err := zw.Create(fmt.Sprintf("%d_%s.txt", i, job.name))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		_, _ = f.Write([]byte(job.log))
+	}
+	if err := zw.Close(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,330 @@
+package githubfake
+
+import (
+	"crypto/sha1" //nolint:gosec // git object ids are sha1 by definition
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The git model is intentionally simple: trees are flat maps from file path to
+// blob sha, and object ids are derived from content in the same spirit as git
+// so that identical content yields identical shas.
+
+type commit struct {
+	sha       string
+	tree      string
+	parents   []string
+	message   string
+	author    string
+	email     string
+	timestamp string
+}
+
+type annotatedTag struct {
+	sha        string
+	tag        string
+	message    string
+	objectSHA  string
+	objectType string
+	timestamp  string
+}
+
+type gitStore struct {
+	blobs   map[string][]byte
+	trees   map[string]map[string]string
+	commits map[string]*commit
+	tags    map[string]*annotatedTag
+	// refs maps fully qualified ref names such as refs/heads/main to object shas
+	refs map[string]string
+}
+
+func newGitStore() *gitStore {
+	return &gitStore{
+		blobs:   make(map[string][]byte),
+		trees:   make(map[string]map[string]string),
+		commits: make(map[string]*commit),
+		tags:    make(map[string]*annotatedTag),
+		refs:    make(map[string]string),
+	}
+}
+
+func hashObject(kind string, data []byte) string {
+	h := sha1.New() //nolint:gosec // see import
+	_, _ = fmt.Fprintf(h, "%s %d\x00", kind, len(data))
+	_, _ = h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (g *gitStore) putBlob(content []byte) string {
+	sha := hashObject("blob", content)
+	g.blobs[sha] = append([]byte(nil), content...)
+	return sha
+}
+
+func (g *gitStore) putTree(files map[string]string) string {
+	paths := sortedPaths(files)
+	var b strings.Builder
+	for _, p := range paths {
+		fmt.Fprintf(&b, "%s\x00%s\n", p, files[p])
+	}
+	sha := hashObject("tree", []byte(b.String()))
+	copied := make(map[string]string, len(files))
+	for p, blob := range files {
+		copied[p] = blob
+	}
+	g.trees[sha] = copied
+	return sha
+}
+
+func (g *gitStore) putCommit(c *commit) string {
+	data := fmt.Sprintf("tree %s\nparents %s\nauthor %s <%s> %s\n\n%s",
+		c.tree, strings.Join(c.parents, " "), c.author, c.email, c.timestamp, c.message)
+	c.sha = hashObject("commit", []byte(data))
+	g.commits[c.sha] = c
+	return c.sha
+}
+
+func (g *gitStore) putTag(t *annotatedTag) string {
+	data := fmt.Sprintf("object %s\ntype %s\ntag %s\n%s\n\n%s", t.objectSHA, t.objectType, t.tag, t.timestamp, t.message)
+	t.sha = hashObject("tag", []byte(data))
+	g.tags[t.sha] = t
+	return t.sha
+}
+
+// objectType reports the git object type of sha, or "" if it is unknown.
+func (g *gitStore) objectType(sha string) string {
+	switch {
+	case g.commits[sha] != nil:
+		return "commit"
+	case g.tags[sha] != nil:
+		return "tag"
+	case g.trees[sha] != nil:
+		return "tree"
+	case g.blobs[sha] != nil:
+		return "blob"
+	}
+	return ""
+}
+
+// resolveCommit resolves a commit sha, branch, tag or fully qualified ref name.
+func (g *gitStore) resolveCommit(ref string) (*commit, bool) {
+	if c, ok := g.commits[ref]; ok {
+		return c, true
+	}
+	candidates := []string{ref}
+	if !strings.HasPrefix(ref, "refs/") {
+		candidates = []string{"refs/" + ref, "refs/heads/" + ref, "refs/tags/" + ref}
+	}
+	for _, name := range candidates {
+		sha, ok := g.refs[name]
+		if !ok {
+			continue
+		}
+		if t, ok := g.tags[sha]; ok {
+			sha = t.objectSHA
+		}
+		if c, ok := g.commits[sha]; ok {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// resolveTree resolves a tree sha, or the tree of anything resolveCommit accepts.
+func (g *gitStore) resolveTree(ref string) (string, bool) {
+	if _, ok := g.trees[ref]; ok {
+		return ref, true
+	}
+	if c, ok := g.resolveCommit(ref); ok {
+		return c.tree, true
+	}
+	return "", false
+}
+
+// branches returns the branch names in sorted order.
+func (g *gitStore) branches() []string {
+	var names []string
+	for ref := range g.refs {
+		if name, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// tagNames returns the tag names in sorted order.
+func (g *gitStore) tagNames() []string {
+	var names []string
+	for ref := range g.refs {
+		if name, ok := strings.CutPrefix(ref, "refs/tags/"); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// history walks first parents from sha, newest first.
+func (g *gitStore) history(sha string) []*commit {
+	var out []*commit
+	for sha != "" {
+		c, ok := g.commits[sha]
+		if !ok {
+			break
+		}
+		out = append(out, c)
+		sha = ""
+		if len(c.parents) > 0 {
+			sha = c.parents[0]
+		}
+	}
+	return out
+}
+
+// isAncestor reports whether ancestor is reachable from sha through any parent.
+func (g *gitStore) isAncestor(ancestor, sha string) bool {
+	seen := map[string]bool{}
+	queue := []string{sha}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if next == ancestor {
+			return true
+		}
+		if seen[next] {
+			continue
+		}
+		seen[next] = true
+		if c, ok := g.commits[next]; ok {
+			queue = append(queue, c.parents...)
+		}
+	}
+	return false
+}
+
+// fileChange describes how a single path differs between two trees.
+type fileChange struct {
+	path      string
+	status    string
+	oldBlob   string
+	newBlob   string
+	additions int
+	deletions int
+	patch     string
+}
+
+// diffTrees compares two flat trees, returning changes sorted by path.
+func (g *gitStore) diffTrees(oldTree, newTree string) []fileChange {
+	before, after := g.trees[oldTree], g.trees[newTree]
+	paths := map[string]bool{}
+	for p := range before {
+		paths[p] = true
+	}
+	for p := range after {
+		paths[p] = true
+	}
+
+	var changes []fileChange
+	for _, p := range sortedPaths(paths) {
+		oldBlob, newBlob := before[p], after[p]
+		if oldBlob == newBlob {
+			continue
+		}
+		change := fileChange{path: p, oldBlob: oldBlob, newBlob: newBlob, status: "modified"}
+		switch {
+		case oldBlob == "":
+			change.status = "added"
+		case newBlob == "":
+			change.status = "removed"
+		}
+		change.patch, change.additions, change.deletions = linePatch(string(g.blobs[oldBlob]), string(g.blobs[newBlob]))
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// linePatch produces a single-hunk unified diff that replaces every old line
+// with every new line after trimming the common prefix and suffix. It is not a
+// minimal diff, but it is well formed and stable, which is all tests need.
+func linePatch(before, after string) (string, int, int) {
+	oldLines, newLines := splitLines(before), splitLines(after)
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+	removed := oldLines[prefix : len(oldLines)-suffix]
+	added := newLines[prefix : len(newLines)-suffix]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(prefix, len(removed)), hunkRange(prefix, len(added)))
+	for _, line := range removed {
+		b.WriteString("-" + line + "\n")
+	}
+	for _, line := range added {
+		b.WriteString("+" + line + "\n")
+	}
+	return b.String(), len(added), len(removed)
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func sortedPaths[V any](m map[string]V) []string {
+	paths := make([]string, 0, len(m))
+	for p := range m {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// applyChanges applies changes computed against one tree to another tree.
+func applyChanges(base map[string]string, changes []fileChange) map[string]string {
+	out := make(map[string]string, len(base))
+	for p, blob := range base {
+		out[p] = blob
+	}
+	for _, change := range changes {
+		if change.newBlob == "" {
+			delete(out, change.path)
+			continue
+		}
+		out[change.path] = change.newBlob
+	}
+	return out
+}
+
+// unifiedDiff renders changes as a git style diff.
+func unifiedDiff(changes []fileChange) string {
+	var b strings.Builder
+	for _, change := range changes {
+		oldPath, newPath := "a/"+change.path, "b/"+change.path
+		switch change.status {
+		case "added":
+			oldPath = "/dev/null"
+		case "removed":
+			newPath = "/dev/null"
+		}
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\n--- %s\n+++ %s\n%s", change.path, change.path, oldPath, newPath, change.patch)
+	}
+	return b.String()
+}
@@ -0,0 +1,394 @@
+package githubfake
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+func (s *Server) gitRoutes(mux *http.ServeMux) {
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/git/ref/{ref...}", s.getRef)
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/git/refs", s.createRef)
+	s.handle(mux, "PATCH /api/v3/repos/{owner}/{repo}/git/refs/{ref...}", s.updateRef)
+	s.handle(mux, "DELETE /api/v3/repos/{owner}/{repo}/git/refs/{ref...}", s.deleteRef)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/git/commits/{sha}", s.getGitCommit)
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/git/commits", s.createGitCommit)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/git/trees/{sha...}", s.getTree)
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/git/trees", s.createTree)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/git/blobs/{sha}", s.getBlob)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/git/tags/{sha}", s.getGitTag)
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/git/tags", s.createGitTag)
+}
+
+func (s *Server) refJSON(repo *repository, name, sha string) map[string]any {
+	return map[string]any{
+		"ref":     name,
+		"node_id": "REF_" + name,
+		"url":     s.repoURL(repo) + "/git/" + name,
+		"object": map[string]any{
+			"type": repo.git.objectType(sha),
+			"sha":  sha,
+			"url":  s.repoURL(repo) + "/git/" + repo.git.objectType(sha) + "s/" + sha,
+		},
+	}
+}
+
+func (s *Server) getRef(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	name := "refs/" + strings.TrimPrefix(r.PathValue("ref"), "refs/")
+	sha, ok := repo.git.refs[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.refJSON(repo, name, sha))
+}
+
+func (s *Server) createRef(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if !strings.HasPrefix(body.Ref, "refs/") || strings.Count(body.Ref, "/") < 2 {
+		writeValidationError(w, "Reference name must start with 'refs/' and have at least two slashes.")
+		return
+	}
+	if repo.git.objectType(body.SHA) == "" {
+		writeValidationError(w, "Object does not exist")
+		return
+	}
+	if _, exists := repo.git.refs[body.Ref]; exists {
+		writeValidationError(w, "Reference already exists")
+		return
+	}
+	repo.git.refs[body.Ref] = body.SHA
+	writeJSON(w, http.StatusCreated, s.refJSON(repo, body.Ref, body.SHA))
+}
+
+func (s *Server) updateRef(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		SHA   string `json:"sha"`
+		Force bool   `json:"force"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	name := "refs/" + strings.TrimPrefix(r.PathValue("ref"), "refs/")
+	current, exists := repo.git.refs[name]
+	switch {
+	case !exists:
+		writeValidationError(w, "Reference does not exist")
+		return
+	case repo.git.objectType(body.SHA) == "":
+		writeValidationError(w, "Object does not exist")
+		return
+	case !body.Force && !repo.git.isAncestor(current, body.SHA):
+		writeValidationError(w, "Update is not a fast forward")
+		return
+	}
+	repo.git.refs[name] = body.SHA
+	writeJSON(w, http.StatusOK, s.refJSON(repo, name, body.SHA))
+}
+
+func (s *Server) deleteRef(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	name := "refs/" + strings.TrimPrefix(r.PathValue("ref"), "refs/")
+	if _, exists := repo.git.refs[name]; !exists {
+		writeValidationError(w, "Reference does not exist")
+		return
+	}
+	delete(repo.git.refs, name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) gitCommitJSON(repo *repository, c *commit) map[string]any {
+	parents := []map[string]any{}
+	for _, p := range c.parents {
+		parents = append(parents, map[string]any{
+			"sha": p,
+			"url": s.repoURL(repo) + "/git/commits/" + p,
+		})
+	}
+	signature := map[string]any{"name": c.author, "email": c.email, "date": c.timestamp}
+	return map[string]any{
+		"sha":       c.sha,
+		"node_id":   "C_" + c.sha,
+		"url":       s.repoURL(repo) + "/git/commits/" + c.sha,
+		"html_url":  s.repoHTMLURL(repo) + "/commit/" + c.sha,
+		"message":   c.message,
+		"author":    signature,
+		"committer": signature,
+		"tree":      map[string]any{"sha": c.tree, "url": s.repoURL(repo) + "/git/trees/" + c.tree},
+		"parents":   parents,
+	}
+}
+
+func (s *Server) getGitCommit(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	c, ok := repo.git.commits[r.PathValue("sha")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.gitCommitJSON(repo, c))
+}
+
+func (s *Server) createGitCommit(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Message string   `json:"message"`
+		Tree    string   `json:"tree"`
+		Parents []string `json:"parents"`
+		Author  *struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"author"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if _, ok := repo.git.trees[body.Tree]; !ok {
+		writeValidationError(w, "Tree SHA does not exist")
+		return
+	}
+	for _, p := range body.Parents {
+		if _, ok := repo.git.commits[p]; !ok {
+			writeValidationError(w, "Parent SHA does not exist or is not a commit object")
+			return
+		}
+	}
+
+	c := &commit{
+		tree:      body.Tree,
+		parents:   body.Parents,
+		message:   body.Message,
+		author:    s.login,
+		email:     s.login + "@users.noreply.github.com",
+		timestamp: s.timestamp(),
+	}
+	if body.Author != nil {
+		c.author, c.email = body.Author.Name, body.Author.Email
+	}
+	repo.git.putCommit(c)
+	writeJSON(w, http.StatusCreated, s.gitCommitJSON(repo, c))
+}
+
+// treeJSON renders a flat tree. Non-recursive listings only include the top
+// level; recursive listings also include an entry for every directory.
+func (s *Server) treeJSON(repo *repository, sha string, recursive bool) map[string]any {
+	tree := repo.git.trees[sha]
+	entries := []map[string]any{}
+	seenDirs := map[string]bool{}
+	for _, p := range sortedPaths(tree) {
+		parts := strings.Split(p, "/")
+		for i := 1; i < len(parts); i++ {
+			dir := strings.Join(parts[:i], "/")
+			if seenDirs[dir] || (!recursive && i > 1) {
+				continue
+			}
+			seenDirs[dir] = true
+			dirSHA := repo.git.putTree(subtree(tree, dir))
+			entries = append(entries, map[string]any{
+				"path": dir,
+				"mode": "040000",
+				"type": "tree",
+				"sha":  dirSHA,
+				"url":  s.repoURL(repo) + "/git/trees/" + dirSHA,
+			})
+		}
+		if !recursive && len(parts) > 1 {
+			continue
+		}
+		blob := tree[p]
+		entries = append(entries, map[string]any{
+			"path": p,
+			"mode": "100644",
+			"type": "blob",
+			"sha":  blob,
+			"size": len(repo.git.blobs[blob]),
+			"url":  s.repoURL(repo) + "/git/blobs/" + blob,
+		})
+	}
+	return map[string]any{
+		"sha":       sha,
+		"url":       s.repoURL(repo) + "/git/trees/" + sha,
+		"tree":      entries,
+		"truncated": false,
+	}
+}
+
+func (s *Server) getTree(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	sha, ok := repo.git.resolveTree(r.PathValue("sha"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	recursive := r.URL.Query().Get("recursive")
+	writeJSON(w, http.StatusOK, s.treeJSON(repo, sha, recursive != "" && recursive != "0" && recursive != "false"))
+}
+
+func (s *Server) getBlob(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	sha := r.PathValue("sha")
+	content, ok := repo.git.blobs[sha]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sha":      sha,
+		"node_id":  "BLOB_" + sha,
+		"size":     len(content),
+		"url":      s.repoURL(repo) + "/git/blobs/" + sha,
+		"encoding": "base64",
+		"content":  base64.StdEncoding.EncodeToString(content),
+	})
+}
+
+func (s *Server) createTree(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		BaseTree string `json:"base_tree"`
+		Tree     []struct {
+			Path    string  `json:"path"`
+			Type    string  `json:"type"`
+			SHA     *string `json:"sha"`
+			Content *string `json:"content"`
+		} `json:"tree"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	files := map[string]string{}
+	if body.BaseTree != "" {
+		base, ok := repo.git.trees[body.BaseTree]
+		if !ok {
+			writeValidationError(w, "base_tree is not a valid tree oid")
+			return
+		}
+		files = copyTree(base)
+	}
+	for _, entry := range body.Tree {
+		if entry.Type != "" && entry.Type != "blob" {
+			writeValidationError(w, "only blob tree entries are supported by the fake")
+			return
+		}
+		switch {
+		case entry.Content != nil:
+			files[entry.Path] = repo.git.putBlob([]byte(*entry.Content))
+		case entry.SHA != nil:
+			if _, ok := repo.git.blobs[*entry.SHA]; !ok {
+				writeValidationError(w, "tree.sha "+*entry.SHA+" is not a valid blob")
+				return
+			}
+			files[entry.Path] = *entry.SHA
+		default:
+			// A null sha without content removes the path, including
+			// everything beneath it when the path is a directory
+			delete(files, entry.Path)
+			for p := range files {
+				if strings.HasPrefix(p, entry.Path+"/") {
+					delete(files, p)
+				}
+			}
+		}
+	}
+	writeJSON(w, http.StatusCreated, s.treeJSON(repo, repo.git.putTree(files), true))
+}
+
+func (s *Server) tagJSON(repo *repository, t *annotatedTag) map[string]any {
+	return map[string]any{
+		"sha":     t.sha,
+		"node_id": "TAG_" + t.sha,
+		"tag":     t.tag,
+		"message": t.message,
+		"url":     s.repoURL(repo) + "/git/tags/" + t.sha,
+		"tagger": map[string]any{
+			"name":  s.login,
+			"email": s.login + "@users.noreply.github.com",
+			"date":  t.timestamp,
+		},
+		"object": map[string]any{
+			"type": t.objectType,
+			"sha":  t.objectSHA,
+			"url":  s.repoURL(repo) + "/git/" + t.objectType + "s/" + t.objectSHA,
+		},
+	}
+}
+
+func (s *Server) getGitTag(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	t, ok := repo.git.tags[r.PathValue("sha")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.tagJSON(repo, t))
+}
+
+func (s *Server) createGitTag(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Tag     string `json:"tag"`
+		Message string `json:"message"`
+		Object  string `json:"object"`
+		Type    string `json:"type"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	objectType := repo.git.objectType(body.Object)
+	if objectType == "" {
+		writeValidationError(w, "Object does not exist")
+		return
+	}
+	t := &annotatedTag{
+		tag:        body.Tag,
+		message:    body.Message,
+		objectSHA:  body.Object,
+		objectType: objectType,
+		timestamp:  s.timestamp(),
+	}
+	repo.git.putTag(t)
+	writeJSON(w, http.StatusCreated, s.tagJSON(repo, t))
+}
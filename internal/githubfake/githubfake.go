@@ -0,0 +1,216 @@
+// Package githubfake provides an in-process fake of the GitHub REST, GraphQL and
+// raw content APIs, backed by an in-memory model of repositories, git data,
+// issues, pull requests, reviews, workflow runs and notifications.
+//
+// The fake is served with net/http/httptest using the GitHub Enterprise Server
+// URL layout (REST under /api/v3, GraphQL at /api/graphql and raw content under
+// /raw), so the MCP server can be pointed at it with the regular --gh-host
+// setting. It is intended for hermetic end-to-end tests that exercise
+// multi-step flows without network access or a real token.
+package githubfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLogin is the login of the authenticated user unless overridden with WithLogin.
+const DefaultLogin = "octocat"
+
+// Server is a fake GitHub API. All state is held in memory and guarded by a
+// single mutex, so handlers observe a consistent view of the model.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	login         string
+	nextID        int64
+	now           func() time.Time
+	repos         map[string]*repository
+	notifications []*notification
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithLogin sets the login of the authenticated user.
+func WithLogin(login string) Option {
+	return func(s *Server) {
+		s.login = login
+	}
+}
+
+// WithClock overrides the clock used for timestamps.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// New starts a fake GitHub server. Callers must Close it when done.
+func New(opts ...Option) *Server {
+	s := &Server{
+		login:  DefaultLogin,
+		nextID: 1000,
+		now:    time.Now,
+		repos:  make(map[string]*repository),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	s.routes(mux)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Login returns the login of the authenticated user.
+func (s *Server) Login() string {
+	return s.login
+}
+
+// APIURL returns the base URL of the REST API, with a trailing slash.
+func (s *Server) APIURL() string {
+	return s.URL + "/api/v3/"
+}
+
+// GraphQLURL returns the URL of the GraphQL endpoint.
+func (s *Server) GraphQLURL() string {
+	return s.URL + "/api/graphql"
+}
+
+func (s *Server) routes(mux *http.ServeMux) {
+	s.handle(mux, "GET /api/v3/user", s.getUser)
+	s.handle(mux, "POST /api/graphql", s.graphql)
+	s.handle(mux, "GET /raw/{owner}/{repo}/{path...}", s.getRaw)
+
+	s.repoRoutes(mux)
+	s.gitRoutes(mux)
+	s.issueRoutes(mux)
+	s.pullRoutes(mux)
+	s.actionsRoutes(mux)
+	s.notificationRoutes(mux)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Not Found: %s %s is not implemented by the fake", r.Method, r.URL.Path))
+	})
+}
+
+// handle registers fn under pattern, holding the server lock for the whole request.
+func (s *Server) handle(mux *http.ServeMux, pattern string, fn http.HandlerFunc) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		fn(w, r)
+	})
+}
+
+func (s *Server) id() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format(time.RFC3339)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.user(s.login))
+}
+
+func (s *Server) user(login string) map[string]any {
+	return map[string]any{
+		"login":    login,
+		"id":       int64(len(login)) + 1,
+		"node_id":  "U_" + login,
+		"type":     "User",
+		"html_url": s.URL + "/" + login,
+		"url":      s.APIURL() + "users/" + login,
+	}
+}
+
+// apiError mirrors the error body returned by the GitHub REST API.
+type apiError struct {
+	Message string `json:"message"`
+	Errors  []any  `json:"errors,omitempty"`
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, apiError{Message: message})
+}
+
+func writeValidationError(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusUnprocessableEntity, apiError{
+		Message: "Validation Failed",
+		Errors:  []any{map[string]string{"message": message, "code": "custom"}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if r.Body == nil || r.ContentLength == 0 {
+		return true
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return false
+	}
+	return true
+}
+
+// paginate applies the page and per_page query parameters to items.
+func paginate[T any](r *http.Request, items []T) []T {
+	perPage, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = 30
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	start := (page - 1) * perPage
+	if start >= len(items) {
+		return []T{}
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
+
+func pathInt(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	n, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return 0, false
+	}
+	return n, true
+}
+
+// lookupRepo resolves the {owner} and {repo} path values, writing a 404 if the
+// repository does not exist.
+func (s *Server) lookupRepo(w http.ResponseWriter, r *http.Request) (*repository, bool) {
+	repo, ok := s.repos[repoKey(r.PathValue("owner"), r.PathValue("repo"))]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return nil, false
+	}
+	return repo, true
+}
+
+func repoKey(owner, name string) string {
+	return strings.ToLower(owner + "/" + name)
+}
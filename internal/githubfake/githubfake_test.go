@@ -0,0 +1,340 @@
+package githubfake
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClients(t *testing.T, srv *Server) (*github.Client, *githubv4.Client) {
+	t.Helper()
+	rest, err := github.NewClient(nil).WithEnterpriseURLs(srv.APIURL(), srv.APIURL())
+	require.NoError(t, err)
+	return rest, githubv4.NewEnterpriseClient(srv.GraphQLURL(), nil)
+}
+
+func TestRepositoryAndGitData(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+	rest, _ := newClients(t, srv)
+	ctx := context.Background()
+
+	repo, _, err := rest.Repositories.Create(ctx, "", &github.Repository{Name: github.Ptr("demo"), AutoInit: github.Ptr(true)})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLogin+"/demo", repo.GetFullName())
+
+	ref, _, err := rest.Git.GetRef(ctx, DefaultLogin, "demo", "refs/heads/main")
+	require.NoError(t, err)
+	baseSHA := ref.GetObject().GetSHA()
+
+	_, _, err = rest.Git.CreateRef(ctx, DefaultLogin, "demo", &github.Reference{
+		Ref:    github.Ptr("refs/heads/feature"),
+		Object: &github.GitObject{SHA: github.Ptr(baseSHA)},
+	})
+	require.NoError(t, err)
+
+	// Push a file with the git data API, the way push_files does
+	baseCommit, _, err := rest.Git.GetCommit(ctx, DefaultLogin, "demo", baseSHA)
+	require.NoError(t, err)
+	tree, _, err := rest.Git.CreateTree(ctx, DefaultLogin, "demo", baseCommit.GetTree().GetSHA(), []*github.TreeEntry{
+		{Path: github.Ptr("src/main.go"), Mode: github.Ptr("100644"), Type: github.Ptr("blob"), Content: github.Ptr("package main\n")},
+	})
+	require.NoError(t, err)
+	newCommit, _, err := rest.Git.CreateCommit(ctx, DefaultLogin, "demo", &github.Commit{
+		Message: github.Ptr("add main"),
+		Tree:    &github.Tree{SHA: tree.SHA},
+		Parents: []*github.Commit{{SHA: github.Ptr(baseSHA)}},
+	}, nil)
+	require.NoError(t, err)
+	_, _, err = rest.Git.UpdateRef(ctx, DefaultLogin, "demo", &github.Reference{
+		Ref:    github.Ptr("refs/heads/feature"),
+		Object: &github.GitObject{SHA: newCommit.SHA},
+	}, false)
+	require.NoError(t, err)
+
+	file, dir, _, err := rest.Repositories.GetContents(ctx, DefaultLogin, "demo", "src/main.go", &github.RepositoryContentGetOptions{Ref: "feature"})
+	require.NoError(t, err)
+	assert.Nil(t, dir)
+	content, err := file.GetContent()
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n", content)
+
+	_, dir, _, err = rest.Repositories.GetContents(ctx, DefaultLogin, "demo", "", &github.RepositoryContentGetOptions{Ref: "feature"})
+	require.NoError(t, err)
+	require.Len(t, dir, 2)
+	assert.Equal(t, "README.md", dir[0].GetName())
+	assert.Equal(t, "src", dir[1].GetName())
+	assert.Equal(t, "dir", dir[1].GetType())
+
+	// Raw content is served for fully qualified refs
+	resp, err := http.Get(srv.URL + "/raw/" + DefaultLogin + "/demo/refs/heads/feature/src/main.go")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n", string(raw))
+
+	// A non fast-forward update is rejected unless forced
+	_, resp2, err := rest.Git.UpdateRef(ctx, DefaultLogin, "demo", &github.Reference{
+		Ref:    github.Ptr("refs/heads/feature"),
+		Object: &github.GitObject{SHA: github.Ptr(baseSHA)},
+	}, false)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp2.StatusCode)
+}
+
+func TestContentsRequireSHAForUpdates(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+	srv.SeedRepository("octo-org", "app", map[string]string{"a.txt": "one\n"})
+	rest, _ := newClients(t, srv)
+	ctx := context.Background()
+
+	opts := &github.RepositoryContentFileOptions{Message: github.Ptr("update"), Content: []byte("two\n")}
+	_, resp, err := rest.Repositories.UpdateFile(ctx, "octo-org", "app", "a.txt", opts)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	file, _, _, err := rest.Repositories.GetContents(ctx, "octo-org", "app", "a.txt", nil)
+	require.NoError(t, err)
+	opts.SHA = file.SHA
+	result, _, err := rest.Repositories.UpdateFile(ctx, "octo-org", "app", "a.txt", opts)
+	require.NoError(t, err)
+
+	commit, _, err := rest.Repositories.GetCommit(ctx, "octo-org", "app", result.GetSHA(), nil)
+	require.NoError(t, err)
+	require.Len(t, commit.Files, 1)
+	assert.Equal(t, "modified", commit.Files[0].GetStatus())
+	assert.Equal(t, "@@ -1,1 +1,1 @@\n-one\n+two\n", commit.Files[0].GetPatch())
+}
+
+func TestIssuesAndSearch(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+	srv.SeedRepository("octo-org", "app", map[string]string{"README.md": "hi"})
+	srv.SeedIssue("octo-org", "app", IssueSeed{Title: "Old bug", Labels: []string{"bug"}, Closed: true})
+	rest, gql := newClients(t, srv)
+	ctx := context.Background()
+
+	created, _, err := rest.Issues.Create(ctx, "octo-org", "app", &github.IssueRequest{
+		Title:  github.Ptr("Crash on start"),
+		Labels: &[]string{"bug"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, created.GetNumber())
+
+	_, _, err = rest.Issues.CreateComment(ctx, "octo-org", "app", 2, &github.IssueComment{Body: github.Ptr("+1")})
+	require.NoError(t, err)
+
+	result, _, err := rest.Search.Issues(ctx, "repo:octo-org/app is:issue is:open label:bug crash", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.GetTotal())
+	assert.Equal(t, "Crash on start", result.Issues[0].GetTitle())
+	assert.Equal(t, 1, result.Issues[0].GetComments())
+
+	var q struct {
+		Repository struct {
+			Issues struct {
+				Nodes []struct {
+					Number githubv4.Int
+					State  githubv4.String
+					Labels struct {
+						Nodes []struct{ Name githubv4.String }
+					} `graphql:"labels(first: 100)"`
+				}
+				TotalCount int
+			} `graphql:"issues(first: $first, states: $states, orderBy: {field: CREATED_AT, direction: ASC})"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	err = gql.Query(ctx, &q, map[string]any{
+		"owner":  githubv4.String("octo-org"),
+		"repo":   githubv4.String("app"),
+		"first":  githubv4.Int(10),
+		"states": []githubv4.IssueState{githubv4.IssueStateOpen, githubv4.IssueStateClosed},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, q.Repository.Issues.TotalCount)
+	assert.Equal(t, githubv4.Int(1), q.Repository.Issues.Nodes[0].Number)
+	assert.Equal(t, githubv4.String("CLOSED"), q.Repository.Issues.Nodes[0].State)
+	assert.Equal(t, githubv4.String("bug"), q.Repository.Issues.Nodes[0].Labels.Nodes[0].Name)
+}
+
+func TestPullRequestReviewAndMerge(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+	srv.SeedRepository("octo-org", "app", map[string]string{"README.md": "hello\n"})
+	changed := "hello world\n"
+	srv.SeedCommit("octo-org", "app", "feature", "update readme", map[string]*string{"README.md": &changed})
+	rest, gql := newClients(t, srv)
+	ctx := context.Background()
+
+	pr, _, err := rest.PullRequests.Create(ctx, "octo-org", "app", &github.NewPullRequest{
+		Title: github.Ptr("Update README"),
+		Head:  github.Ptr("feature"),
+		Base:  github.Ptr("main"),
+	})
+	require.NoError(t, err)
+
+	files, _, err := rest.PullRequests.ListFiles(ctx, "octo-org", "app", pr.GetNumber(), nil)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "README.md", files[0].GetFilename())
+
+	// Create a pending review, comment on it and submit it over GraphQL
+	var prQuery struct {
+		Repository struct {
+			PullRequest struct{ ID githubv4.ID } `graphql:"pullRequest(number: $prNum)"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	require.NoError(t, gql.Query(ctx, &prQuery, map[string]any{
+		"owner": githubv4.String("octo-org"),
+		"repo":  githubv4.String("app"),
+		"prNum": githubv4.Int(pr.GetNumber()),
+	}))
+
+	var addReview struct {
+		AddPullRequestReview struct {
+			PullRequestReview struct{ ID githubv4.ID }
+		} `graphql:"addPullRequestReview(input: $input)"`
+	}
+	require.NoError(t, gql.Mutate(ctx, &addReview, githubv4.AddPullRequestReviewInput{
+		PullRequestID: prQuery.Repository.PullRequest.ID,
+	}, nil))
+	reviewID := addReview.AddPullRequestReview.PullRequestReview.ID
+
+	var addThread struct {
+		AddPullRequestReviewThread struct {
+			Thread struct{ ID githubv4.ID }
+		} `graphql:"addPullRequestReviewThread(input: $input)"`
+	}
+	line := githubv4.Int(1)
+	require.NoError(t, gql.Mutate(ctx, &addThread, githubv4.AddPullRequestReviewThreadInput{
+		Path:                "README.md",
+		Body:                "nice",
+		Line:                &line,
+		PullRequestReviewID: &reviewID,
+	}, nil))
+
+	var submit struct {
+		SubmitPullRequestReview struct {
+			PullRequestReview struct{ ID githubv4.ID }
+		} `graphql:"submitPullRequestReview(input: $input)"`
+	}
+	require.NoError(t, gql.Mutate(ctx, &submit, githubv4.SubmitPullRequestReviewInput{
+		PullRequestReviewID: &reviewID,
+		Event:               githubv4.PullRequestReviewEventApprove,
+	}, nil))
+
+	reviews, _, err := rest.PullRequests.ListReviews(ctx, "octo-org", "app", pr.GetNumber(), nil)
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "APPROVED", reviews[0].GetState())
+
+	comments, _, err := rest.PullRequests.ListComments(ctx, "octo-org", "app", pr.GetNumber(), nil)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "nice", comments[0].GetBody())
+
+	merge, _, err := rest.PullRequests.Merge(ctx, "octo-org", "app", pr.GetNumber(), "", &github.PullRequestOptions{MergeMethod: "squash"})
+	require.NoError(t, err)
+	assert.True(t, merge.GetMerged())
+
+	file, _, _, err := rest.Repositories.GetContents(ctx, "octo-org", "app", "README.md", nil)
+	require.NoError(t, err)
+	content, err := file.GetContent()
+	require.NoError(t, err)
+	assert.Equal(t, changed, content)
+
+	_, resp, err := rest.PullRequests.Merge(ctx, "octo-org", "app", pr.GetNumber(), "", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestWorkflowRunsAndLogs(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+	srv.SeedRepository("octo-org", "app", map[string]string{"README.md": "hi"})
+	runID := srv.SeedWorkflowRun("octo-org", "app", WorkflowRunSeed{
+		Conclusion: "failure",
+		Jobs: []JobSeed{
+			{Name: "build", Conclusion: "success", Log: "ok\n"},
+			{Name: "test", Steps: []string{"checkout", "go test"}, Log: "--- FAIL: TestX\n"},
+		},
+	})
+	rest, _ := newClients(t, srv)
+	ctx := context.Background()
+
+	runs, _, err := rest.Actions.ListWorkflowRunsByFileName(ctx, "octo-org", "app", "ci.yml", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, runs.GetTotalCount())
+	assert.Equal(t, "failure", runs.WorkflowRuns[0].GetConclusion())
+
+	jobs, _, err := rest.Actions.ListWorkflowJobs(ctx, "octo-org", "app", runID, nil)
+	require.NoError(t, err)
+	require.Len(t, jobs.Jobs, 2)
+	failed := jobs.Jobs[1]
+	assert.Equal(t, "failure", failed.GetConclusion())
+	assert.Equal(t, "failure", failed.Steps[1].GetConclusion())
+
+	logURL, _, err := rest.Actions.GetWorkflowJobLogs(ctx, "octo-org", "app", failed.GetID(), 1)
+	require.NoError(t, err)
+	resp, err := http.Get(logURL.String())
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "--- FAIL: TestX\n", string(body))
+
+	_, err = rest.Actions.RerunFailedJobsByID(ctx, "octo-org", "app", runID)
+	require.NoError(t, err)
+	run, _, err := rest.Actions.GetWorkflowRunByID(ctx, "octo-org", "app", runID)
+	require.NoError(t, err)
+	assert.Equal(t, "queued", run.GetStatus())
+	assert.Equal(t, 2, run.GetRunAttempt())
+}
+
+func TestNotifications(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+	srv.SeedRepository("octo-org", "app", nil)
+	first := srv.SeedNotification(NotificationSeed{Owner: "octo-org", Repo: "app", Title: "Build failed"})
+	srv.SeedNotification(NotificationSeed{Owner: "octo-org", Repo: "app", Title: "Review requested", Reason: "review_requested"})
+	rest, _ := newClients(t, srv)
+	ctx := context.Background()
+
+	_, err := rest.Activity.MarkThreadRead(ctx, first)
+	require.NoError(t, err)
+
+	unread, _, err := rest.Activity.ListNotifications(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, "Review requested", unread[0].GetSubject().GetTitle())
+
+	all, _, err := rest.Activity.ListNotifications(ctx, &github.NotificationListOptions{All: true})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+	_, gql := newClients(t, srv)
+
+	var q struct {
+		Repository struct {
+			Name githubv4.String
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	err := gql.Query(context.Background(), &q, map[string]any{
+		"owner": githubv4.String("nobody"),
+		"repo":  githubv4.String("missing"),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Could not resolve to a Repository with the name 'nobody/missing'.")
+}
@@ -0,0 +1,486 @@
+package githubfake
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// This file contains a deliberately small GraphQL implementation. It understands
+// the subset of the language that shurcooL/githubv4 generates from query structs:
+// operations with variable definitions, fields with aliases and arguments,
+// nested selection sets and inline fragments. There is no schema validation;
+// unknown fields resolve to null.
+
+// gqlField is a single field selection, or an inline fragment when typeCondition is set.
+type gqlField struct {
+	alias         string
+	name          string
+	args          map[string]gqlValue
+	selections    []*gqlField
+	typeCondition string
+}
+
+func (f *gqlField) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// gqlValue is an unresolved argument value which may reference variables.
+type gqlValue interface{}
+
+type gqlVariable string
+type gqlEnum string
+
+type gqlOperation struct {
+	kind       string // "query" or "mutation"
+	selections []*gqlField
+}
+
+type gqlParser struct {
+	src string
+	pos int
+}
+
+func parseGraphQL(src string) (*gqlOperation, error) {
+	p := &gqlParser{src: src}
+	op := &gqlOperation{kind: "query"}
+
+	p.skipIgnored()
+	if p.peek() != '{' {
+		kind := p.name()
+		if kind != "query" && kind != "mutation" {
+			return nil, fmt.Errorf("unsupported operation type %q", kind)
+		}
+		op.kind = kind
+		p.skipIgnored()
+		// Optional operation name
+		if isNameStart(p.peek()) {
+			p.name()
+			p.skipIgnored()
+		}
+		// Variable definitions are not needed for execution, so skip them
+		if p.peek() == '(' {
+			if err := p.skipBalanced('(', ')'); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+	return op, nil
+}
+
+func (p *gqlParser) peek() byte {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *gqlParser) skipIgnored() {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == ',' || unicode.IsSpace(rune(c)):
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *gqlParser) expect(c byte) error {
+	p.skipIgnored()
+	if p.peek() != c {
+		return fmt.Errorf("expected %q at offset %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
+
+func (p *gqlParser) name() string {
+	start := p.pos
+	for p.pos < len(p.src) && isNameChar(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *gqlParser) skipBalanced(open, closing byte) error {
+	depth := 0
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				p.pos++
+				return nil
+			}
+		}
+		p.pos++
+	}
+	return fmt.Errorf("unbalanced %q", open)
+}
+
+func (p *gqlParser) selectionSet() ([]*gqlField, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	var fields []*gqlField
+	for {
+		p.skipIgnored()
+		if p.peek() == '}' {
+			p.pos++
+			return fields, nil
+		}
+		if p.peek() == 0 {
+			return nil, fmt.Errorf("unexpected end of query")
+		}
+		field, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+}
+
+func (p *gqlParser) selection() (*gqlField, error) {
+	if strings.HasPrefix(p.src[p.pos:], "...") {
+		p.pos += 3
+		p.skipIgnored()
+		if p.name() != "on" {
+			return nil, fmt.Errorf("named fragments are not supported")
+		}
+		p.skipIgnored()
+		typeName := p.name()
+		selections, err := p.selectionSet()
+		if err != nil {
+			return nil, err
+		}
+		return &gqlField{typeCondition: typeName, selections: selections}, nil
+	}
+
+	field := &gqlField{name: p.name()}
+	if field.name == "" {
+		return nil, fmt.Errorf("expected field name at offset %d", p.pos)
+	}
+	p.skipIgnored()
+	if p.peek() == ':' {
+		p.pos++
+		p.skipIgnored()
+		field.alias = field.name
+		field.name = p.name()
+		p.skipIgnored()
+	}
+	if p.peek() == '(' {
+		args, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		field.args = args
+		p.skipIgnored()
+	}
+	if p.peek() == '{' {
+		selections, err := p.selectionSet()
+		if err != nil {
+			return nil, err
+		}
+		field.selections = selections
+	}
+	return field, nil
+}
+
+func (p *gqlParser) arguments() (map[string]gqlValue, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	args := map[string]gqlValue{}
+	for {
+		p.skipIgnored()
+		if p.peek() == ')' {
+			p.pos++
+			return args, nil
+		}
+		name := p.name()
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		args[name] = value
+	}
+}
+
+func (p *gqlParser) value() (gqlValue, error) {
+	p.skipIgnored()
+	c := p.peek()
+	switch {
+	case c == '$':
+		p.pos++
+		return gqlVariable(p.name()), nil
+	case c == '"':
+		return p.stringValue()
+	case c == '[':
+		p.pos++
+		var list []gqlValue
+		for {
+			p.skipIgnored()
+			if p.peek() == ']' {
+				p.pos++
+				return list, nil
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+	case c == '{':
+		p.pos++
+		obj := map[string]gqlValue{}
+		for {
+			p.skipIgnored()
+			if p.peek() == '}' {
+				p.pos++
+				return obj, nil
+			}
+			name := p.name()
+			if err := p.expect(':'); err != nil {
+				return nil, err
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = v
+		}
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+		return strconv.ParseFloat(p.src[start:p.pos], 64)
+	case isNameStart(c):
+		word := p.name()
+		switch word {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return gqlEnum(word), nil
+	}
+	return nil, fmt.Errorf("unexpected character %q at offset %d", c, p.pos)
+}
+
+func (p *gqlParser) stringValue() (string, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+			continue
+		case '"':
+			p.pos++
+			return strconv.Unquote(p.src[start:p.pos])
+		}
+		p.pos++
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+// resolveArgs substitutes variables into argument values and converts enums to strings.
+func resolveArgs(args map[string]gqlValue, variables map[string]any) map[string]any {
+	out := make(map[string]any, len(args))
+	for name, v := range args {
+		out[name] = resolveValue(v, variables)
+	}
+	return out
+}
+
+func resolveValue(v gqlValue, variables map[string]any) any {
+	switch val := v.(type) {
+	case gqlVariable:
+		return variables[string(val)]
+	case gqlEnum:
+		return string(val)
+	case []gqlValue:
+		list := make([]any, len(val))
+		for i, item := range val {
+			list[i] = resolveValue(item, variables)
+		}
+		return list
+	case map[string]gqlValue:
+		obj := make(map[string]any, len(val))
+		for k, item := range val {
+			obj[k] = resolveValue(item, variables)
+		}
+		return obj
+	default:
+		return val
+	}
+}
+
+// gqlObject is a resolved GraphQL object. Field values may be plain values,
+// nested objects, lists, or resolver functions that receive the field arguments.
+type gqlObject map[string]any
+
+// gqlResolver lazily computes a field value from its arguments.
+type gqlResolver func(args map[string]any) (any, error)
+
+// execute resolves selections against obj, producing a JSON-ready value.
+func execute(obj gqlObject, selections []*gqlField, variables map[string]any) (map[string]any, error) {
+	out := map[string]any{}
+	for _, field := range selections {
+		if field.typeCondition != "" {
+			if typeName, _ := obj["__typename"].(string); typeName != field.typeCondition {
+				continue
+			}
+			nested, err := execute(obj, field.selections, variables)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range nested {
+				out[k] = v
+			}
+			continue
+		}
+
+		value := obj[field.name]
+		if resolver, ok := value.(gqlResolver); ok {
+			var err error
+			value, err = resolver(resolveArgs(field.args, variables))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		resolved, err := completeValue(value, field, variables)
+		if err != nil {
+			return nil, err
+		}
+		out[field.responseKey()] = resolved
+	}
+	return out, nil
+}
+
+func completeValue(value any, field *gqlField, variables map[string]any) (any, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case gqlObject:
+		if v == nil {
+			return nil, nil
+		}
+		return execute(v, field.selections, variables)
+	case []gqlObject:
+		list := make([]any, 0, len(v))
+		for _, item := range v {
+			resolved, err := execute(item, field.selections, variables)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, resolved)
+		}
+		return list, nil
+	default:
+		return v, nil
+	}
+}
+
+// connection builds a cursor paginated GraphQL connection over nodes.
+func connection(nodes []gqlObject, args map[string]any) gqlObject {
+	total := len(nodes)
+	start := 0
+	if after, ok := args["after"].(string); ok && after != "" {
+		start = decodeCursor(after) + 1
+	}
+	if start > total {
+		start = total
+	}
+	end := total
+	if first, ok := toInt(args["first"]); ok && start+first < total {
+		end = start + first
+	}
+	page := nodes[start:end]
+
+	pageInfo := gqlObject{
+		"hasNextPage":     end < total,
+		"hasPreviousPage": start > 0,
+		"startCursor":     nil,
+		"endCursor":       nil,
+	}
+	if len(page) > 0 {
+		pageInfo["startCursor"] = encodeCursor(start)
+		pageInfo["endCursor"] = encodeCursor(end - 1)
+	}
+
+	return gqlObject{
+		"nodes":      page,
+		"totalCount": total,
+		"pageInfo":   pageInfo,
+	}
+}
+
+func encodeCursor(i int) string {
+	return "cursor:" + strconv.Itoa(i)
+}
+
+func decodeCursor(s string) int {
+	i, err := strconv.Atoi(strings.TrimPrefix(s, "cursor:"))
+	if err != nil {
+		return -1
+	}
+	return i
+}
+
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int(n), true
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+func toStrings(v any) []string {
+	list, _ := v.([]any)
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
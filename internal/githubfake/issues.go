@@ -0,0 +1,457 @@
+package githubfake
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// issue is an issue or, when pull is set, the issue half of a pull request.
+// Both share the repository's number sequence, as they do on GitHub.
+type issue struct {
+	id          int64
+	number      int
+	title       string
+	body        string
+	state       string
+	stateReason string
+	author      string
+	labels      []string
+	assignees   []string
+	createdAt   string
+	updatedAt   string
+	closedAt    string
+	comments    []*issueComment
+	pull        *pullRequest
+}
+
+type issueComment struct {
+	id        int64
+	body      string
+	author    string
+	createdAt string
+}
+
+// IssueSeed describes an issue created with SeedIssue.
+type IssueSeed struct {
+	Title     string
+	Body      string
+	Author    string
+	Labels    []string
+	Assignees []string
+	Closed    bool
+}
+
+// SeedIssue creates an issue and returns its number.
+func (s *Server) SeedIssue(owner, name string, seed IssueSeed) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.repos[repoKey(owner, name)]
+	if repo == nil {
+		panic("githubfake: unknown repository " + owner + "/" + name)
+	}
+	author := seed.Author
+	if author == "" {
+		author = s.login
+	}
+	iss := s.newIssue(repo, seed.Title, seed.Body, author)
+	iss.labels = seed.Labels
+	iss.assignees = seed.Assignees
+	if seed.Closed {
+		iss.state = "closed"
+		iss.stateReason = "completed"
+		iss.closedAt = iss.createdAt
+	}
+	return iss.number
+}
+
+func (s *Server) newIssue(repo *repository, title, body, author string) *issue {
+	now := s.timestamp()
+	iss := &issue{
+		id:        s.id(),
+		number:    repo.nextNumber,
+		title:     title,
+		body:      body,
+		state:     "open",
+		author:    author,
+		createdAt: now,
+		updatedAt: now,
+	}
+	repo.nextNumber++
+	repo.issues[iss.number] = iss
+	return iss
+}
+
+// sortedIssues returns the repository's issues and pull requests, newest first.
+func (repo *repository) sortedIssues() []*issue {
+	out := make([]*issue, 0, len(repo.issues))
+	for _, iss := range repo.issues {
+		out = append(out, iss)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].number > out[j].number })
+	return out
+}
+
+func issueNodeID(repo *repository, iss *issue) string {
+	prefix := "I_"
+	if iss.pull != nil {
+		prefix = "PR_"
+	}
+	return prefix + strconv.FormatInt(repo.id, 10) + "_" + strconv.Itoa(iss.number)
+}
+
+func (s *Server) issueHTMLURL(repo *repository, iss *issue) string {
+	kind := "/issues/"
+	if iss.pull != nil {
+		kind = "/pull/"
+	}
+	return s.repoHTMLURL(repo) + kind + strconv.Itoa(iss.number)
+}
+
+func (s *Server) labelJSON(repo *repository, name string) map[string]any {
+	return map[string]any{
+		"id":      int64(len(name)),
+		"node_id": "LA_" + name,
+		"name":    name,
+		"color":   "ededed",
+		"url":     s.repoURL(repo) + "/labels/" + name,
+	}
+}
+
+func (s *Server) issueJSON(repo *repository, iss *issue) map[string]any {
+	labels := []map[string]any{}
+	for _, name := range iss.labels {
+		labels = append(labels, s.labelJSON(repo, name))
+	}
+	assignees := []map[string]any{}
+	for _, login := range iss.assignees {
+		assignees = append(assignees, s.user(login))
+	}
+	out := map[string]any{
+		"id":             iss.id,
+		"node_id":        issueNodeID(repo, iss),
+		"number":         iss.number,
+		"title":          iss.title,
+		"body":           iss.body,
+		"state":          iss.state,
+		"user":           s.user(iss.author),
+		"labels":         labels,
+		"assignees":      assignees,
+		"comments":       len(iss.comments),
+		"created_at":     iss.createdAt,
+		"updated_at":     iss.updatedAt,
+		"url":            s.repoURL(repo) + "/issues/" + strconv.Itoa(iss.number),
+		"html_url":       s.issueHTMLURL(repo, iss),
+		"repository_url": s.repoURL(repo),
+	}
+	if len(iss.assignees) > 0 {
+		out["assignee"] = s.user(iss.assignees[0])
+	}
+	if iss.stateReason != "" {
+		out["state_reason"] = iss.stateReason
+	}
+	if iss.closedAt != "" {
+		out["closed_at"] = iss.closedAt
+	}
+	if iss.pull != nil {
+		out["pull_request"] = map[string]any{
+			"url":      s.repoURL(repo) + "/pulls/" + strconv.Itoa(iss.number),
+			"html_url": s.issueHTMLURL(repo, iss),
+		}
+	}
+	return out
+}
+
+func (s *Server) commentJSON(repo *repository, iss *issue, c *issueComment) map[string]any {
+	return map[string]any{
+		"id":         c.id,
+		"node_id":    "IC_" + strconv.FormatInt(c.id, 10),
+		"body":       c.body,
+		"user":       s.user(c.author),
+		"created_at": c.createdAt,
+		"updated_at": c.createdAt,
+		"html_url":   s.issueHTMLURL(repo, iss) + "#issuecomment-" + strconv.FormatInt(c.id, 10),
+		"url":        s.repoURL(repo) + "/issues/comments/" + strconv.FormatInt(c.id, 10),
+	}
+}
+
+func (s *Server) issueRoutes(mux *http.ServeMux) {
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/issues", s.createIssue)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/issues", s.listIssues)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/issues/{number}", s.getIssue)
+	s.handle(mux, "PATCH /api/v3/repos/{owner}/{repo}/issues/{number}", s.updateIssue)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/issues/{number}/comments", s.listIssueComments)
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/issues/{number}/comments", s.createIssueComment)
+	s.handle(mux, "GET /api/v3/search/issues", s.searchIssues)
+}
+
+// lookupIssue resolves the {number} path value to an issue or pull request.
+func (s *Server) lookupIssue(w http.ResponseWriter, r *http.Request) (*repository, *issue, bool) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	number, ok := pathInt(w, r, "number")
+	if !ok {
+		return nil, nil, false
+	}
+	iss, ok := repo.issues[int(number)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return nil, nil, false
+	}
+	return repo, iss, true
+}
+
+type issueRequest struct {
+	Title       *string   `json:"title"`
+	Body        *string   `json:"body"`
+	State       *string   `json:"state"`
+	StateReason *string   `json:"state_reason"`
+	Labels      *[]string `json:"labels"`
+	Assignees   *[]string `json:"assignees"`
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	var body issueRequest
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Title == nil || *body.Title == "" {
+		writeValidationError(w, "title is required")
+		return
+	}
+
+	iss := s.newIssue(repo, *body.Title, "", s.login)
+	applyIssueRequest(iss, body, s.timestamp())
+	writeJSON(w, http.StatusCreated, s.issueJSON(repo, iss))
+}
+
+func applyIssueRequest(iss *issue, body issueRequest, now string) {
+	if body.Title != nil {
+		iss.title = *body.Title
+	}
+	if body.Body != nil {
+		iss.body = *body.Body
+	}
+	if body.Labels != nil {
+		iss.labels = *body.Labels
+	}
+	if body.Assignees != nil {
+		iss.assignees = *body.Assignees
+	}
+	if body.State != nil && *body.State != iss.state {
+		iss.state = *body.State
+		iss.closedAt = ""
+		iss.stateReason = ""
+		if iss.state == "closed" {
+			iss.closedAt = now
+			iss.stateReason = "completed"
+		} else {
+			iss.stateReason = "reopened"
+		}
+	}
+	if body.StateReason != nil {
+		iss.stateReason = *body.StateReason
+	}
+	iss.updatedAt = now
+}
+
+func (s *Server) listIssues(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	state := r.URL.Query().Get("state")
+	if state == "" {
+		state = "open"
+	}
+	issues := []map[string]any{}
+	for _, iss := range repo.sortedIssues() {
+		if state != "all" && iss.state != state {
+			continue
+		}
+		issues = append(issues, s.issueJSON(repo, iss))
+	}
+	writeJSON(w, http.StatusOK, paginate(r, issues))
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupIssue(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.issueJSON(repo, iss))
+}
+
+func (s *Server) updateIssue(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupIssue(w, r)
+	if !ok {
+		return
+	}
+	var body issueRequest
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	applyIssueRequest(iss, body, s.timestamp())
+	writeJSON(w, http.StatusOK, s.issueJSON(repo, iss))
+}
+
+func (s *Server) listIssueComments(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupIssue(w, r)
+	if !ok {
+		return
+	}
+	comments := []map[string]any{}
+	for _, c := range iss.comments {
+		comments = append(comments, s.commentJSON(repo, iss, c))
+	}
+	writeJSON(w, http.StatusOK, paginate(r, comments))
+}
+
+func (s *Server) createIssueComment(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupIssue(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Body string `json:"body"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Body == "" {
+		writeValidationError(w, "body is required")
+		return
+	}
+	c := &issueComment{id: s.id(), body: body.Body, author: s.login, createdAt: s.timestamp()}
+	iss.comments = append(iss.comments, c)
+	iss.updatedAt = c.createdAt
+	writeJSON(w, http.StatusCreated, s.commentJSON(repo, iss, c))
+}
+
+// issueQuery is a parsed issue search query. Only the qualifiers needed by the
+// MCP tools are understood; everything else is matched as free text.
+type issueQuery struct {
+	repo     string
+	owner    string
+	kind     string
+	state    string
+	author   string
+	assignee string
+	labels   []string
+	terms    []string
+}
+
+func parseIssueQuery(q string) issueQuery {
+	var query issueQuery
+	for _, token := range strings.Fields(q) {
+		key, value, qualified := strings.Cut(token, ":")
+		value = strings.Trim(value, `"`)
+		if !qualified {
+			query.terms = append(query.terms, strings.ToLower(strings.Trim(token, `"`)))
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "repo":
+			query.repo = strings.ToLower(value)
+		case "user", "org":
+			query.owner = strings.ToLower(value)
+		case "is", "type", "state":
+			switch value {
+			case "issue", "pr":
+				query.kind = value
+			case "pull-request":
+				query.kind = "pr"
+			case "open", "closed":
+				query.state = value
+			}
+		case "author":
+			query.author = value
+		case "assignee":
+			query.assignee = value
+		case "label":
+			query.labels = append(query.labels, value)
+		default:
+			query.terms = append(query.terms, strings.ToLower(token))
+		}
+	}
+	return query
+}
+
+func (q issueQuery) matches(repo *repository, iss *issue) bool {
+	switch {
+	case q.repo != "" && q.repo != repoKey(repo.owner, repo.name):
+		return false
+	case q.owner != "" && q.owner != strings.ToLower(repo.owner):
+		return false
+	case q.kind == "issue" && iss.pull != nil, q.kind == "pr" && iss.pull == nil:
+		return false
+	case q.state != "" && q.state != iss.state:
+		return false
+	case q.author != "" && !strings.EqualFold(q.author, iss.author):
+		return false
+	case q.assignee != "" && !containsFold(iss.assignees, q.assignee):
+		return false
+	}
+	for _, label := range q.labels {
+		if !containsFold(iss.labels, label) {
+			return false
+		}
+	}
+	text := strings.ToLower(iss.title + "\n" + iss.body)
+	for _, term := range q.terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) searchIssues(w http.ResponseWriter, r *http.Request) {
+	query := parseIssueQuery(r.URL.Query().Get("q"))
+
+	type match struct {
+		repo  *repository
+		issue *issue
+	}
+	var matches []match
+	for _, key := range sortedPaths(s.repos) {
+		repo := s.repos[key]
+		for _, iss := range repo.sortedIssues() {
+			if query.matches(repo, iss) {
+				matches = append(matches, match{repo, iss})
+			}
+		}
+	}
+	if r.URL.Query().Get("order") == "asc" {
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].issue.id < matches[j].issue.id })
+	} else {
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].issue.id > matches[j].issue.id })
+	}
+
+	items := []map[string]any{}
+	for _, m := range paginate(r, matches) {
+		items = append(items, s.issueJSON(m.repo, m.issue))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"total_count":        len(matches),
+		"incomplete_results": false,
+		"items":              items,
+	})
+}
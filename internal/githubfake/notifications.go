@@ -0,0 +1,242 @@
+package githubfake
+
+import (
+	"net/http"
+	"strconv"
+)
+
+type notification struct {
+	id           string
+	repo         *repository
+	title        string
+	subjectType  string
+	reason       string
+	unread       bool
+	updatedAt    string
+	subscription map[string]any
+}
+
+// NotificationSeed describes a notification created with SeedNotification.
+type NotificationSeed struct {
+	Owner string
+	Repo  string
+	Title string
+	// Type is the subject type, e.g. "Issue" or "PullRequest". Defaults to "Issue".
+	Type string
+	// Reason is the notification reason, e.g. "mention". Defaults to "subscribed".
+	Reason string
+}
+
+// SeedNotification creates an unread notification and returns its thread ID.
+func (s *Server) SeedNotification(seed NotificationSeed) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.repos[repoKey(seed.Owner, seed.Repo)]
+	if repo == nil {
+		panic("githubfake: unknown repository " + seed.Owner + "/" + seed.Repo)
+	}
+	if seed.Type == "" {
+		seed.Type = "Issue"
+	}
+	if seed.Reason == "" {
+		seed.Reason = "subscribed"
+	}
+	n := &notification{
+		id:          strconv.FormatInt(s.id(), 10),
+		repo:        repo,
+		title:       seed.Title,
+		subjectType: seed.Type,
+		reason:      seed.Reason,
+		unread:      true,
+		updatedAt:   s.timestamp(),
+	}
+	s.notifications = append(s.notifications, n)
+	return n.id
+}
+
+func (s *Server) notificationJSON(n *notification) map[string]any {
+	threadURL := s.APIURL() + "notifications/threads/" + n.id
+	return map[string]any{
+		"id":         n.id,
+		"unread":     n.unread,
+		"reason":     n.reason,
+		"updated_at": n.updatedAt,
+		"url":        threadURL,
+		"subject": map[string]any{
+			"title": n.title,
+			"type":  n.subjectType,
+			"url":   s.repoURL(n.repo),
+		},
+		"repository":       s.repoJSON(n.repo),
+		"subscription_url": threadURL + "/subscription",
+	}
+}
+
+func (s *Server) notificationRoutes(mux *http.ServeMux) {
+	s.handle(mux, "GET /api/v3/notifications", s.listNotifications)
+	s.handle(mux, "PUT /api/v3/notifications", s.markNotificationsRead)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/notifications", s.listNotifications)
+	s.handle(mux, "PUT /api/v3/repos/{owner}/{repo}/notifications", s.markNotificationsRead)
+	s.handle(mux, "GET /api/v3/notifications/threads/{id}", s.getThread)
+	s.handle(mux, "PATCH /api/v3/notifications/threads/{id}", s.markThreadRead)
+	s.handle(mux, "DELETE /api/v3/notifications/threads/{id}", s.markThreadDone)
+	s.handle(mux, "PUT /api/v3/notifications/threads/{id}/subscription", s.setThreadSubscription)
+	s.handle(mux, "DELETE /api/v3/notifications/threads/{id}/subscription", s.deleteThreadSubscription)
+	s.handle(mux, "PUT /api/v3/repos/{owner}/{repo}/subscription", s.setRepoSubscription)
+	s.handle(mux, "DELETE /api/v3/repos/{owner}/{repo}/subscription", s.deleteRepoSubscription)
+}
+
+// scopedNotifications returns the notifications for the {owner}/{repo} path
+// values, or all notifications when the route is not repository scoped.
+func (s *Server) scopedNotifications(w http.ResponseWriter, r *http.Request) ([]*notification, bool) {
+	if r.PathValue("owner") == "" {
+		return s.notifications, true
+	}
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return nil, false
+	}
+	var out []*notification
+	for _, n := range s.notifications {
+		if n.repo == repo {
+			out = append(out, n)
+		}
+	}
+	return out, true
+}
+
+func (s *Server) listNotifications(w http.ResponseWriter, r *http.Request) {
+	scoped, ok := s.scopedNotifications(w, r)
+	if !ok {
+		return
+	}
+	all := r.URL.Query().Get("all") == "true"
+	participating := r.URL.Query().Get("participating") == "true"
+
+	items := []map[string]any{}
+	for i := len(scoped) - 1; i >= 0; i-- {
+		n := scoped[i]
+		if !all && !n.unread {
+			continue
+		}
+		if participating && n.reason == "subscribed" {
+			continue
+		}
+		items = append(items, s.notificationJSON(n))
+	}
+	writeJSON(w, http.StatusOK, paginate(r, items))
+}
+
+func (s *Server) markNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	scoped, ok := s.scopedNotifications(w, r)
+	if !ok {
+		return
+	}
+	for _, n := range scoped {
+		n.unread = false
+	}
+	w.WriteHeader(http.StatusResetContent)
+}
+
+func (s *Server) lookupThread(w http.ResponseWriter, r *http.Request) (*notification, bool) {
+	for _, n := range s.notifications {
+		if n.id == r.PathValue("id") {
+			return n, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+	return nil, false
+}
+
+func (s *Server) getThread(w http.ResponseWriter, r *http.Request) {
+	n, ok := s.lookupThread(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.notificationJSON(n))
+}
+
+func (s *Server) markThreadRead(w http.ResponseWriter, r *http.Request) {
+	n, ok := s.lookupThread(w, r)
+	if !ok {
+		return
+	}
+	n.unread = false
+	w.WriteHeader(http.StatusResetContent)
+}
+
+func (s *Server) markThreadDone(w http.ResponseWriter, r *http.Request) {
+	n, ok := s.lookupThread(w, r)
+	if !ok {
+		return
+	}
+	for i, candidate := range s.notifications {
+		if candidate == n {
+			s.notifications = append(s.notifications[:i], s.notifications[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type subscriptionRequest struct {
+	Subscribed bool `json:"subscribed"`
+	Ignored    bool `json:"ignored"`
+}
+
+func (s *Server) setThreadSubscription(w http.ResponseWriter, r *http.Request) {
+	n, ok := s.lookupThread(w, r)
+	if !ok {
+		return
+	}
+	var body subscriptionRequest
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	n.subscription = map[string]any{
+		"subscribed": !body.Ignored,
+		"ignored":    body.Ignored,
+		"created_at": s.timestamp(),
+		"url":        s.APIURL() + "notifications/threads/" + n.id + "/subscription",
+		"thread_url": s.APIURL() + "notifications/threads/" + n.id,
+	}
+	writeJSON(w, http.StatusOK, n.subscription)
+}
+
+func (s *Server) deleteThreadSubscription(w http.ResponseWriter, r *http.Request) {
+	n, ok := s.lookupThread(w, r)
+	if !ok {
+		return
+	}
+	n.subscription = nil
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) setRepoSubscription(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	var body subscriptionRequest
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	repo.subscription = map[string]any{
+		"subscribed":     body.Subscribed,
+		"ignored":        body.Ignored,
+		"created_at":     s.timestamp(),
+		"url":            s.repoURL(repo) + "/subscription",
+		"repository_url": s.repoURL(repo),
+	}
+	writeJSON(w, http.StatusOK, repo.subscription)
+}
+
+func (s *Server) deleteRepoSubscription(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	repo.subscription = nil
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,611 @@
+package githubfake
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type pullRequest struct {
+	head                string
+	base                string
+	headSHA             string
+	mergeBase           string
+	draft               bool
+	merged              bool
+	mergedAt            string
+	mergeCommitSHA      string
+	maintainerCanModify bool
+	requestedReviewers  []string
+	reviews             []*review
+}
+
+type review struct {
+	id          int64
+	author      string
+	body        string
+	state       string
+	commitID    string
+	submittedAt string
+	comments    []*reviewComment
+}
+
+type reviewComment struct {
+	id          int64
+	path        string
+	body        string
+	line        int
+	side        string
+	startLine   int
+	startSide   string
+	subjectType string
+	createdAt   string
+}
+
+func reviewNodeID(rv *review) string {
+	return "PRR_" + strconv.FormatInt(rv.id, 10)
+}
+
+// currentHead returns the sha at the tip of the pull request's head branch,
+// falling back to the last known sha once the branch has been deleted.
+func (pr *pullRequest) currentHead(repo *repository) string {
+	if pr.merged {
+		return pr.headSHA
+	}
+	if sha, ok := repo.git.refs["refs/heads/"+pr.head]; ok {
+		pr.headSHA = sha
+	}
+	return pr.headSHA
+}
+
+// changes returns the files changed on the head branch since the merge base.
+func (pr *pullRequest) changes(repo *repository) []fileChange {
+	head, ok := repo.git.commits[pr.currentHead(repo)]
+	if !ok {
+		return nil
+	}
+	base, ok := repo.git.commits[pr.mergeBase]
+	if !ok {
+		return nil
+	}
+	return repo.git.diffTrees(base.tree, head.tree)
+}
+
+func (s *Server) pullJSON(repo *repository, iss *issue) map[string]any {
+	pr := iss.pull
+	out := s.issueJSON(repo, iss)
+	delete(out, "pull_request")
+	delete(out, "labels")
+	delete(out, "comments")
+
+	reviewers := []map[string]any{}
+	for _, login := range pr.requestedReviewers {
+		reviewers = append(reviewers, s.user(login))
+	}
+	var additions, deletions int
+	changes := pr.changes(repo)
+	for _, change := range changes {
+		additions += change.additions
+		deletions += change.deletions
+	}
+	reviewComments := 0
+	for _, rv := range pr.reviews {
+		if rv.state != "PENDING" {
+			reviewComments += len(rv.comments)
+		}
+	}
+
+	baseSHA := repo.git.refs["refs/heads/"+pr.base]
+	number := strconv.Itoa(iss.number)
+	out["url"] = s.repoURL(repo) + "/pulls/" + number
+	out["diff_url"] = s.issueHTMLURL(repo, iss) + ".diff"
+	out["draft"] = pr.draft
+	out["merged"] = pr.merged
+	out["mergeable"] = !pr.merged && iss.state == "open"
+	out["mergeable_state"] = "clean"
+	out["maintainer_can_modify"] = pr.maintainerCanModify
+	out["requested_reviewers"] = reviewers
+	out["additions"] = additions
+	out["deletions"] = deletions
+	out["changed_files"] = len(changes)
+	out["comments"] = len(iss.comments)
+	out["review_comments"] = reviewComments
+	out["head"] = map[string]any{
+		"ref":   pr.head,
+		"sha":   pr.currentHead(repo),
+		"label": repo.owner + ":" + pr.head,
+		"repo":  s.repoJSON(repo),
+	}
+	out["base"] = map[string]any{
+		"ref":   pr.base,
+		"sha":   baseSHA,
+		"label": repo.owner + ":" + pr.base,
+		"repo":  s.repoJSON(repo),
+	}
+	if pr.merged {
+		out["merged_at"] = pr.mergedAt
+		out["merge_commit_sha"] = pr.mergeCommitSHA
+	}
+	return out
+}
+
+func (s *Server) reviewJSON(repo *repository, iss *issue, rv *review) map[string]any {
+	out := map[string]any{
+		"id":               rv.id,
+		"node_id":          reviewNodeID(rv),
+		"user":             s.user(rv.author),
+		"body":             rv.body,
+		"state":            rv.state,
+		"commit_id":        rv.commitID,
+		"html_url":         s.issueHTMLURL(repo, iss) + "#pullrequestreview-" + strconv.FormatInt(rv.id, 10),
+		"pull_request_url": s.repoURL(repo) + "/pulls/" + strconv.Itoa(iss.number),
+	}
+	if rv.submittedAt != "" {
+		out["submitted_at"] = rv.submittedAt
+	}
+	return out
+}
+
+func (s *Server) reviewCommentJSON(repo *repository, iss *issue, rv *review, c *reviewComment) map[string]any {
+	out := map[string]any{
+		"id":                     c.id,
+		"node_id":                "PRRC_" + strconv.FormatInt(c.id, 10),
+		"pull_request_review_id": rv.id,
+		"path":                   c.path,
+		"body":                   c.body,
+		"subject_type":           strings.ToLower(c.subjectType),
+		"commit_id":              rv.commitID,
+		"user":                   s.user(rv.author),
+		"created_at":             c.createdAt,
+		"updated_at":             c.createdAt,
+		"html_url":               s.issueHTMLURL(repo, iss) + "#discussion_r" + strconv.FormatInt(c.id, 10),
+	}
+	if c.line != 0 {
+		out["line"] = c.line
+		out["side"] = c.side
+	}
+	if c.startLine != 0 {
+		out["start_line"] = c.startLine
+		out["start_side"] = c.startSide
+	}
+	return out
+}
+
+func (s *Server) pullRoutes(mux *http.ServeMux) {
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/pulls", s.createPull)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/pulls", s.listPulls)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/pulls/{number}", s.getPull)
+	s.handle(mux, "PATCH /api/v3/repos/{owner}/{repo}/pulls/{number}", s.updatePull)
+	s.handle(mux, "PUT /api/v3/repos/{owner}/{repo}/pulls/{number}/merge", s.mergePull)
+	s.handle(mux, "PUT /api/v3/repos/{owner}/{repo}/pulls/{number}/update-branch", s.updatePullBranch)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/pulls/{number}/files", s.listPullFiles)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/pulls/{number}/comments", s.listPullComments)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/pulls/{number}/reviews", s.listReviews)
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/pulls/{number}/reviews", s.createReview)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/pulls/{number}/reviews/{id}/comments", s.listReviewComments)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/pulls/{number}/requested_reviewers", s.listRequestedReviewers)
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/pulls/{number}/requested_reviewers", s.requestReviewers)
+}
+
+// lookupPull resolves the {number} path value to a pull request.
+func (s *Server) lookupPull(w http.ResponseWriter, r *http.Request) (*repository, *issue, bool) {
+	repo, iss, ok := s.lookupIssue(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	if iss.pull == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return nil, nil, false
+	}
+	return repo, iss, true
+}
+
+func (s *Server) createPull(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Title               string `json:"title"`
+		Head                string `json:"head"`
+		Base                string `json:"base"`
+		Body                string `json:"body"`
+		Draft               bool   `json:"draft"`
+		MaintainerCanModify bool   `json:"maintainer_can_modify"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	// Cross-repository pull requests are not modelled, so an owner prefix is dropped
+	if _, branch, ok := strings.Cut(body.Head, ":"); ok {
+		body.Head = branch
+	}
+
+	headSHA, headExists := repo.git.refs["refs/heads/"+body.Head]
+	baseSHA, baseExists := repo.git.refs["refs/heads/"+body.Base]
+	switch {
+	case body.Title == "":
+		writeValidationError(w, "title is required")
+		return
+	case !headExists:
+		writeValidationError(w, "head is invalid: "+body.Head)
+		return
+	case !baseExists:
+		writeValidationError(w, "base is invalid: "+body.Base)
+		return
+	case headSHA == baseSHA:
+		writeValidationError(w, "No commits between "+body.Base+" and "+body.Head)
+		return
+	}
+	for _, existing := range repo.issues {
+		if existing.pull != nil && existing.state == "open" && existing.pull.head == body.Head && existing.pull.base == body.Base {
+			writeValidationError(w, "A pull request already exists for "+repo.owner+":"+body.Head+".")
+			return
+		}
+	}
+
+	iss := s.newIssue(repo, body.Title, body.Body, s.login)
+	iss.pull = &pullRequest{
+		head:                body.Head,
+		base:                body.Base,
+		headSHA:             headSHA,
+		mergeBase:           baseSHA,
+		draft:               body.Draft,
+		maintainerCanModify: body.MaintainerCanModify,
+	}
+	writeJSON(w, http.StatusCreated, s.pullJSON(repo, iss))
+}
+
+func (s *Server) listPulls(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	state := query.Get("state")
+	if state == "" {
+		state = "open"
+	}
+	head := query.Get("head")
+	if _, branch, ok := strings.Cut(head, ":"); ok {
+		head = branch
+	}
+
+	pulls := []map[string]any{}
+	for _, iss := range repo.sortedIssues() {
+		switch {
+		case iss.pull == nil:
+			continue
+		case state != "all" && iss.state != state:
+			continue
+		case head != "" && iss.pull.head != head:
+			continue
+		case query.Get("base") != "" && iss.pull.base != query.Get("base"):
+			continue
+		}
+		pulls = append(pulls, s.pullJSON(repo, iss))
+	}
+	writeJSON(w, http.StatusOK, paginate(r, pulls))
+}
+
+func (s *Server) getPull(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	if strings.Contains(r.Header.Get("Accept"), "diff") {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(unifiedDiff(iss.pull.changes(repo))))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.pullJSON(repo, iss))
+}
+
+func (s *Server) updatePull(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		issueRequest
+		Base                *string `json:"base"`
+		MaintainerCanModify *bool   `json:"maintainer_can_modify"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Base != nil {
+		sha, ok := repo.git.refs["refs/heads/"+*body.Base]
+		if !ok {
+			writeValidationError(w, "base is invalid: "+*body.Base)
+			return
+		}
+		iss.pull.base = *body.Base
+		iss.pull.mergeBase = sha
+	}
+	if body.MaintainerCanModify != nil {
+		iss.pull.maintainerCanModify = *body.MaintainerCanModify
+	}
+	applyIssueRequest(iss, body.issueRequest, s.timestamp())
+	writeJSON(w, http.StatusOK, s.pullJSON(repo, iss))
+}
+
+func (s *Server) mergePull(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		CommitTitle   string `json:"commit_title"`
+		CommitMessage string `json:"commit_message"`
+		MergeMethod   string `json:"merge_method"`
+		SHA           string `json:"sha"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	pr := iss.pull
+	headSHA := pr.currentHead(repo)
+	switch {
+	case pr.merged || iss.state != "open":
+		writeError(w, http.StatusMethodNotAllowed, "Pull Request is not mergeable")
+		return
+	case pr.draft:
+		writeError(w, http.StatusMethodNotAllowed, "Pull Request is still a draft")
+		return
+	case body.SHA != "" && body.SHA != headSHA:
+		writeError(w, http.StatusConflict, "Head branch was modified. Review and try the merge again.")
+		return
+	}
+
+	baseSHA := repo.git.refs["refs/heads/"+pr.base]
+	baseTree := repo.git.trees[repo.git.commits[baseSHA].tree]
+	merged := repo.git.putTree(applyChanges(baseTree, pr.changes(repo)))
+
+	title := body.CommitTitle
+	parents := []string{baseSHA}
+	switch body.MergeMethod {
+	case "squash", "rebase":
+		if title == "" {
+			title = iss.title + " (#" + strconv.Itoa(iss.number) + ")"
+		}
+	default:
+		if title == "" {
+			title = "Merge pull request #" + strconv.Itoa(iss.number) + " from " + repo.owner + "/" + pr.head
+		}
+		parents = append(parents, headSHA)
+	}
+	message := title
+	if body.CommitMessage != "" {
+		message += "\n\n" + body.CommitMessage
+	}
+
+	sha := repo.git.putCommit(&commit{
+		tree:      merged,
+		parents:   parents,
+		message:   message,
+		author:    s.login,
+		email:     s.login + "@users.noreply.github.com",
+		timestamp: s.timestamp(),
+	})
+	repo.git.refs["refs/heads/"+pr.base] = sha
+
+	now := s.timestamp()
+	pr.merged = true
+	pr.mergedAt = now
+	pr.mergeCommitSHA = sha
+	iss.state = "closed"
+	iss.closedAt = now
+	iss.updatedAt = now
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sha":     sha,
+		"merged":  true,
+		"message": "Pull Request successfully merged",
+	})
+}
+
+// updatePullBranch merges the base branch into the head branch.
+func (s *Server) updatePullBranch(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		ExpectedHeadSHA string `json:"expected_head_sha"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	pr := iss.pull
+	headSHA := pr.currentHead(repo)
+	baseSHA := repo.git.refs["refs/heads/"+pr.base]
+	switch {
+	case body.ExpectedHeadSHA != "" && body.ExpectedHeadSHA != headSHA:
+		writeValidationError(w, "expected head sha didn't match current head ref.")
+		return
+	case repo.git.isAncestor(baseSHA, headSHA):
+		writeValidationError(w, "There are no new commits on the base branch.")
+		return
+	}
+
+	mergeBase := repo.git.commits[pr.mergeBase]
+	base := repo.git.commits[baseSHA]
+	head := repo.git.commits[headSHA]
+	tree := applyChanges(repo.git.trees[head.tree], repo.git.diffTrees(mergeBase.tree, base.tree))
+	sha := repo.git.putCommit(&commit{
+		tree:      repo.git.putTree(tree),
+		parents:   []string{headSHA, baseSHA},
+		message:   "Merge branch '" + pr.base + "' into " + pr.head,
+		author:    s.login,
+		email:     s.login + "@users.noreply.github.com",
+		timestamp: s.timestamp(),
+	})
+	repo.git.refs["refs/heads/"+pr.head] = sha
+	pr.mergeBase = baseSHA
+
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"message": "Updating pull request branch.",
+		"url":     s.issueHTMLURL(repo, iss),
+	})
+}
+
+func (s *Server) listPullFiles(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	files, _, _ := s.filesJSON(repo, iss.pull.currentHead(repo), iss.pull.changes(repo))
+	writeJSON(w, http.StatusOK, paginate(r, files))
+}
+
+func (s *Server) listPullComments(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	comments := []map[string]any{}
+	for _, rv := range iss.pull.reviews {
+		if rv.state == "PENDING" {
+			continue
+		}
+		for _, c := range rv.comments {
+			comments = append(comments, s.reviewCommentJSON(repo, iss, rv, c))
+		}
+	}
+	writeJSON(w, http.StatusOK, paginate(r, comments))
+}
+
+func (s *Server) listReviews(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	reviews := []map[string]any{}
+	for _, rv := range iss.pull.reviews {
+		// Pending reviews are only visible to their author
+		if rv.state == "PENDING" && rv.author != s.login {
+			continue
+		}
+		reviews = append(reviews, s.reviewJSON(repo, iss, rv))
+	}
+	writeJSON(w, http.StatusOK, paginate(r, reviews))
+}
+
+// reviewState maps a review event to the state of the resulting review.
+func reviewState(event string) string {
+	switch event {
+	case "APPROVE":
+		return "APPROVED"
+	case "REQUEST_CHANGES":
+		return "CHANGES_REQUESTED"
+	case "COMMENT":
+		return "COMMENTED"
+	}
+	return "PENDING"
+}
+
+func (s *Server) addReview(repo *repository, iss *issue, body, event, commitID string) (*review, error) {
+	for _, existing := range iss.pull.reviews {
+		if existing.state == "PENDING" && existing.author == s.login {
+			return nil, errPendingReviewExists
+		}
+	}
+	if commitID == "" {
+		commitID = iss.pull.currentHead(repo)
+	}
+	rv := &review{
+		id:       s.id(),
+		author:   s.login,
+		body:     body,
+		state:    reviewState(event),
+		commitID: commitID,
+	}
+	if rv.state != "PENDING" {
+		rv.submittedAt = s.timestamp()
+	}
+	iss.pull.reviews = append(iss.pull.reviews, rv)
+	return rv, nil
+}
+
+func (s *Server) createReview(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Body     string `json:"body"`
+		Event    string `json:"event"`
+		CommitID string `json:"commit_id"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	rv, err := s.addReview(repo, iss, body.Body, body.Event, body.CommitID)
+	if err != nil {
+		writeValidationError(w, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.reviewJSON(repo, iss, rv))
+}
+
+func (s *Server) listReviewComments(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathInt(w, r, "id")
+	if !ok {
+		return
+	}
+	for _, rv := range iss.pull.reviews {
+		if rv.id != id {
+			continue
+		}
+		comments := []map[string]any{}
+		for _, c := range rv.comments {
+			comments = append(comments, s.reviewCommentJSON(repo, iss, rv, c))
+		}
+		writeJSON(w, http.StatusOK, paginate(r, comments))
+		return
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) requestedReviewersJSON(pr *pullRequest) map[string]any {
+	users := []map[string]any{}
+	for _, login := range pr.requestedReviewers {
+		users = append(users, s.user(login))
+	}
+	return map[string]any{"users": users, "teams": []any{}}
+}
+
+func (s *Server) listRequestedReviewers(w http.ResponseWriter, r *http.Request) {
+	_, iss, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.requestedReviewersJSON(iss.pull))
+}
+
+func (s *Server) requestReviewers(w http.ResponseWriter, r *http.Request) {
+	repo, iss, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Reviewers []string `json:"reviewers"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	for _, login := range body.Reviewers {
+		if strings.EqualFold(login, iss.author) {
+			writeValidationError(w, "Review cannot be requested from pull request author.")
+			return
+		}
+		if !containsFold(iss.pull.requestedReviewers, login) {
+			iss.pull.requestedReviewers = append(iss.pull.requestedReviewers, login)
+		}
+	}
+	writeJSON(w, http.StatusCreated, s.pullJSON(repo, iss))
+}
@@ -0,0 +1,732 @@
+package githubfake
+
+import (
+	"encoding/base64"
+	"net/http"
+	"path"
+	"strings"
+)
+
+type repository struct {
+	id            int64
+	owner         string
+	name          string
+	description   string
+	private       bool
+	defaultBranch string
+	createdAt     string
+	fork          bool
+
+	git *gitStore
+
+	issues     map[int]*issue
+	nextNumber int
+
+	workflows []*workflow
+	runs      []*workflowRun
+
+	statuses     map[string][]map[string]any
+	subscription map[string]any
+}
+
+func (s *Server) newRepository(owner, name string) *repository {
+	repo := &repository{
+		id:            s.id(),
+		owner:         owner,
+		name:          name,
+		defaultBranch: "main",
+		createdAt:     s.timestamp(),
+		git:           newGitStore(),
+		issues:        make(map[int]*issue),
+		nextNumber:    1,
+		statuses:      make(map[string][]map[string]any),
+	}
+	s.repos[repoKey(owner, name)] = repo
+	return repo
+}
+
+// SeedRepository creates a repository whose default branch "main" holds a
+// single commit with the given files, and returns the sha of that commit. If
+// files is empty the repository has no commits.
+func (s *Server) SeedRepository(owner, name string, files map[string]string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.newRepository(owner, name)
+	if len(files) == 0 {
+		return ""
+	}
+	return s.commitFiles(repo, repo.defaultBranch, "Initial commit", files)
+}
+
+// SeedCommit commits files on top of branch, creating the branch from the
+// default branch if it does not exist yet, and returns the new commit sha.
+// A nil content deletes the file.
+func (s *Server) SeedCommit(owner, name, branch, message string, files map[string]*string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.repos[repoKey(owner, name)]
+	if repo == nil {
+		panic("githubfake: unknown repository " + owner + "/" + name)
+	}
+	if _, ok := repo.git.refs["refs/heads/"+branch]; !ok {
+		if base, ok := repo.git.refs["refs/heads/"+repo.defaultBranch]; ok {
+			repo.git.refs["refs/heads/"+branch] = base
+		}
+	}
+
+	tree := map[string]string{}
+	if head, ok := repo.git.resolveCommit("refs/heads/" + branch); ok {
+		tree = repo.git.trees[head.tree]
+	}
+	updated := make(map[string]string, len(tree))
+	for p, blob := range tree {
+		updated[p] = blob
+	}
+	for p, content := range files {
+		if content == nil {
+			delete(updated, p)
+			continue
+		}
+		updated[p] = repo.git.putBlob([]byte(*content))
+	}
+	return s.commitTree(repo, branch, message, repo.git.putTree(updated))
+}
+
+// SeedCommitStatus adds a commit status for sha.
+func (s *Server) SeedCommitStatus(owner, name, sha, state, context string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo := s.repos[repoKey(owner, name)]
+	if repo == nil {
+		panic("githubfake: unknown repository " + owner + "/" + name)
+	}
+	repo.statuses[sha] = append(repo.statuses[sha], map[string]any{
+		"id":         s.id(),
+		"state":      state,
+		"context":    context,
+		"created_at": s.timestamp(),
+	})
+}
+
+// commitFiles replaces the tree of branch with files and returns the commit sha.
+func (s *Server) commitFiles(repo *repository, branch, message string, files map[string]string) string {
+	blobs := make(map[string]string, len(files))
+	for p, content := range files {
+		blobs[p] = repo.git.putBlob([]byte(content))
+	}
+	return s.commitTree(repo, branch, message, repo.git.putTree(blobs))
+}
+
+// commitTree creates a commit of tree on top of branch and advances the branch.
+func (s *Server) commitTree(repo *repository, branch, message, tree string) string {
+	var parents []string
+	if head, ok := repo.git.refs["refs/heads/"+branch]; ok {
+		parents = []string{head}
+	}
+	sha := repo.git.putCommit(&commit{
+		tree:      tree,
+		parents:   parents,
+		message:   message,
+		author:    s.login,
+		email:     s.login + "@users.noreply.github.com",
+		timestamp: s.timestamp(),
+	})
+	repo.git.refs["refs/heads/"+branch] = sha
+	return sha
+}
+
+func (s *Server) repoURL(repo *repository) string {
+	return s.APIURL() + "repos/" + repo.owner + "/" + repo.name
+}
+
+func (s *Server) repoHTMLURL(repo *repository) string {
+	return s.URL + "/" + repo.owner + "/" + repo.name
+}
+
+func (s *Server) repoJSON(repo *repository) map[string]any {
+	visibility := "public"
+	if repo.private {
+		visibility = "private"
+	}
+	return map[string]any{
+		"id":             repo.id,
+		"node_id":        "R_" + repo.owner + "_" + repo.name,
+		"name":           repo.name,
+		"full_name":      repo.owner + "/" + repo.name,
+		"owner":          s.user(repo.owner),
+		"description":    repo.description,
+		"private":        repo.private,
+		"visibility":     visibility,
+		"fork":           repo.fork,
+		"default_branch": repo.defaultBranch,
+		"created_at":     repo.createdAt,
+		"html_url":       s.repoHTMLURL(repo),
+		"url":            s.repoURL(repo),
+		"clone_url":      s.repoHTMLURL(repo) + ".git",
+	}
+}
+
+func (s *Server) repoRoutes(mux *http.ServeMux) {
+	s.handle(mux, "POST /api/v3/user/repos", s.createRepo)
+	s.handle(mux, "POST /api/v3/orgs/{org}/repos", s.createRepo)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}", s.getRepo)
+	s.handle(mux, "DELETE /api/v3/repos/{owner}/{repo}", s.deleteRepo)
+	s.handle(mux, "POST /api/v3/repos/{owner}/{repo}/forks", s.forkRepo)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/branches", s.listBranches)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/tags", s.listTags)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/commits", s.listCommits)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/commits/{ref}", s.getCommit)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/commits/{ref}/status", s.getCombinedStatus)
+	s.handle(mux, "GET /api/v3/repos/{owner}/{repo}/contents/{path...}", s.getContents)
+	s.handle(mux, "PUT /api/v3/repos/{owner}/{repo}/contents/{path...}", s.putContents)
+	s.handle(mux, "DELETE /api/v3/repos/{owner}/{repo}/contents/{path...}", s.deleteContents)
+}
+
+func (s *Server) createRepo(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Private     bool   `json:"private"`
+		AutoInit    bool   `json:"auto_init"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Name == "" {
+		writeValidationError(w, "name is required")
+		return
+	}
+	owner := r.PathValue("org")
+	if owner == "" {
+		owner = s.login
+	}
+	if _, exists := s.repos[repoKey(owner, body.Name)]; exists {
+		writeValidationError(w, "name already exists on this account")
+		return
+	}
+
+	repo := s.newRepository(owner, body.Name)
+	repo.description = body.Description
+	repo.private = body.Private
+	if body.AutoInit {
+		s.commitFiles(repo, repo.defaultBranch, "Initial commit", map[string]string{
+			"README.md": "# " + body.Name + "\n",
+		})
+	}
+	writeJSON(w, http.StatusCreated, s.repoJSON(repo))
+}
+
+func (s *Server) getRepo(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.repoJSON(repo))
+}
+
+func (s *Server) deleteRepo(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	delete(s.repos, repoKey(repo.owner, repo.name))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) forkRepo(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Organization string `json:"organization"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	owner := body.Organization
+	if owner == "" {
+		owner = s.login
+	}
+	if existing, exists := s.repos[repoKey(owner, repo.name)]; exists {
+		writeJSON(w, http.StatusAccepted, s.repoJSON(existing))
+		return
+	}
+
+	fork := s.newRepository(owner, repo.name)
+	fork.description = repo.description
+	fork.defaultBranch = repo.defaultBranch
+	fork.fork = true
+	// Git objects are immutable, so the fork can share them with its parent
+	// while keeping its own refs.
+	fork.git = &gitStore{
+		blobs:   repo.git.blobs,
+		trees:   repo.git.trees,
+		commits: repo.git.commits,
+		tags:    repo.git.tags,
+		refs:    make(map[string]string, len(repo.git.refs)),
+	}
+	for name, sha := range repo.git.refs {
+		fork.git.refs[name] = sha
+	}
+	writeJSON(w, http.StatusAccepted, s.repoJSON(fork))
+}
+
+func (s *Server) listBranches(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	branches := []map[string]any{}
+	for _, name := range repo.git.branches() {
+		sha := repo.git.refs["refs/heads/"+name]
+		branches = append(branches, map[string]any{
+			"name":      name,
+			"protected": false,
+			"commit": map[string]any{
+				"sha": sha,
+				"url": s.repoURL(repo) + "/commits/" + sha,
+			},
+		})
+	}
+	writeJSON(w, http.StatusOK, paginate(r, branches))
+}
+
+func (s *Server) listTags(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	tags := []map[string]any{}
+	for _, name := range repo.git.tagNames() {
+		c, _ := repo.git.resolveCommit("refs/tags/" + name)
+		sha := ""
+		if c != nil {
+			sha = c.sha
+		}
+		tags = append(tags, map[string]any{
+			"name": name,
+			"commit": map[string]any{
+				"sha": sha,
+				"url": s.repoURL(repo) + "/commits/" + sha,
+			},
+			"zipball_url": s.repoURL(repo) + "/zipball/" + name,
+			"tarball_url": s.repoURL(repo) + "/tarball/" + name,
+		})
+	}
+	writeJSON(w, http.StatusOK, paginate(r, tags))
+}
+
+func (s *Server) listCommits(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	ref := r.URL.Query().Get("sha")
+	if ref == "" {
+		ref = repo.defaultBranch
+	}
+	head, ok := repo.git.resolveCommit(ref)
+	if !ok {
+		if len(repo.git.commits) == 0 {
+			writeError(w, http.StatusConflict, "Git Repository is empty.")
+			return
+		}
+		writeError(w, http.StatusNotFound, "No commit found for SHA: "+ref)
+		return
+	}
+
+	author := r.URL.Query().Get("author")
+	commits := []map[string]any{}
+	for _, c := range repo.git.history(head.sha) {
+		if author != "" && !strings.EqualFold(c.author, author) {
+			continue
+		}
+		commits = append(commits, s.repoCommitJSON(repo, c, false))
+	}
+	writeJSON(w, http.StatusOK, paginate(r, commits))
+}
+
+func (s *Server) getCommit(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	c, ok := repo.git.resolveCommit(r.PathValue("ref"))
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "No commit found for SHA: "+r.PathValue("ref"))
+		return
+	}
+	if strings.Contains(r.Header.Get("Accept"), "diff") {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(unifiedDiff(repo.git.diffTrees(parentTree(repo.git, c), c.tree))))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.repoCommitJSON(repo, c, true))
+}
+
+func parentTree(g *gitStore, c *commit) string {
+	if len(c.parents) == 0 {
+		return ""
+	}
+	if parent, ok := g.commits[c.parents[0]]; ok {
+		return parent.tree
+	}
+	return ""
+}
+
+// repoCommitJSON renders a commit as returned by the repository commits API,
+// optionally including the files it changed.
+func (s *Server) repoCommitJSON(repo *repository, c *commit, withFiles bool) map[string]any {
+	parents := []map[string]any{}
+	for _, p := range c.parents {
+		parents = append(parents, map[string]any{
+			"sha":      p,
+			"url":      s.repoURL(repo) + "/commits/" + p,
+			"html_url": s.repoHTMLURL(repo) + "/commit/" + p,
+		})
+	}
+	signature := map[string]any{"name": c.author, "email": c.email, "date": c.timestamp}
+	out := map[string]any{
+		"sha":      c.sha,
+		"node_id":  "C_" + c.sha,
+		"url":      s.repoURL(repo) + "/commits/" + c.sha,
+		"html_url": s.repoHTMLURL(repo) + "/commit/" + c.sha,
+		"commit": map[string]any{
+			"message":   c.message,
+			"author":    signature,
+			"committer": signature,
+			"tree":      map[string]any{"sha": c.tree, "url": s.repoURL(repo) + "/git/trees/" + c.tree},
+		},
+		"author":    s.user(c.author),
+		"committer": s.user(c.author),
+		"parents":   parents,
+	}
+	if withFiles {
+		changes := repo.git.diffTrees(parentTree(repo.git, c), c.tree)
+		files, additions, deletions := s.filesJSON(repo, c.sha, changes)
+		out["files"] = files
+		out["stats"] = map[string]any{
+			"additions": additions,
+			"deletions": deletions,
+			"total":     additions + deletions,
+		}
+	}
+	return out
+}
+
+// filesJSON renders changes as the files array used by commits and pull requests.
+func (s *Server) filesJSON(repo *repository, ref string, changes []fileChange) ([]map[string]any, int, int) {
+	files := []map[string]any{}
+	var additions, deletions int
+	for _, change := range changes {
+		sha := change.newBlob
+		if sha == "" {
+			sha = change.oldBlob
+		}
+		files = append(files, map[string]any{
+			"sha":       sha,
+			"filename":  change.path,
+			"status":    change.status,
+			"additions": change.additions,
+			"deletions": change.deletions,
+			"changes":   change.additions + change.deletions,
+			"patch":     change.patch,
+			"blob_url":  s.repoHTMLURL(repo) + "/blob/" + ref + "/" + change.path,
+			"raw_url":   s.URL + "/raw/" + repo.owner + "/" + repo.name + "/" + ref + "/" + change.path,
+		})
+		additions += change.additions
+		deletions += change.deletions
+	}
+	return files, additions, deletions
+}
+
+func (s *Server) getCombinedStatus(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	c, ok := repo.git.resolveCommit(r.PathValue("ref"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "No commit found for SHA: "+r.PathValue("ref"))
+		return
+	}
+
+	statuses := repo.statuses[c.sha]
+	state := "pending"
+	if len(statuses) > 0 {
+		state = "success"
+		for _, status := range statuses {
+			switch status["state"] {
+			case "failure", "error":
+				state = "failure"
+			case "pending":
+				if state == "success" {
+					state = "pending"
+				}
+			}
+		}
+	}
+	if statuses == nil {
+		statuses = []map[string]any{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"state":       state,
+		"sha":         c.sha,
+		"total_count": len(statuses),
+		"statuses":    statuses,
+	})
+}
+
+// contentsRef returns the commit addressed by the ref query parameter, which
+// defaults to the repository's default branch.
+func contentsRef(repo *repository, r *http.Request) (*commit, string, bool) {
+	ref := r.URL.Query().Get("ref")
+	if ref == "" {
+		ref = repo.defaultBranch
+	}
+	c, ok := repo.git.resolveCommit(ref)
+	return c, ref, ok
+}
+
+func (s *Server) getContents(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	c, ref, ok := contentsRef(repo, r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "No commit found for the ref "+ref)
+		return
+	}
+	p := strings.Trim(r.PathValue("path"), "/")
+	tree := repo.git.trees[c.tree]
+
+	if blob, ok := tree[p]; ok {
+		writeJSON(w, http.StatusOK, s.contentJSON(repo, ref, p, blob, true))
+		return
+	}
+
+	entries := s.listDirectory(repo, ref, tree, p)
+	if len(entries) == 0 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// listDirectory returns the immediate children of dir in a flat tree.
+func (s *Server) listDirectory(repo *repository, ref string, tree map[string]string, dir string) []map[string]any {
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+	seenDirs := map[string]bool{}
+	entries := []map[string]any{}
+	for _, p := range sortedPaths(tree) {
+		rest, ok := strings.CutPrefix(p, prefix)
+		if !ok {
+			continue
+		}
+		if child, _, isDir := strings.Cut(rest, "/"); isDir {
+			if seenDirs[child] {
+				continue
+			}
+			seenDirs[child] = true
+			childPath := prefix + child
+			entries = append(entries, map[string]any{
+				"type":     "dir",
+				"name":     child,
+				"path":     childPath,
+				"sha":      repo.git.putTree(subtree(tree, childPath)),
+				"url":      s.repoURL(repo) + "/contents/" + childPath + "?ref=" + ref,
+				"html_url": s.repoHTMLURL(repo) + "/tree/" + ref + "/" + childPath,
+			})
+			continue
+		}
+		entries = append(entries, s.contentJSON(repo, ref, p, tree[p], false))
+	}
+	return entries
+}
+
+// subtree returns the files below dir with the dir prefix removed.
+func subtree(tree map[string]string, dir string) map[string]string {
+	out := map[string]string{}
+	for p, blob := range tree {
+		if rest, ok := strings.CutPrefix(p, dir+"/"); ok {
+			out[rest] = blob
+		}
+	}
+	return out
+}
+
+func (s *Server) contentJSON(repo *repository, ref, p, blob string, withContent bool) map[string]any {
+	out := map[string]any{
+		"type":         "file",
+		"name":         path.Base(p),
+		"path":         p,
+		"sha":          blob,
+		"size":         len(repo.git.blobs[blob]),
+		"url":          s.repoURL(repo) + "/contents/" + p + "?ref=" + ref,
+		"git_url":      s.repoURL(repo) + "/git/blobs/" + blob,
+		"html_url":     s.repoHTMLURL(repo) + "/blob/" + ref + "/" + p,
+		"download_url": s.URL + "/raw/" + repo.owner + "/" + repo.name + "/" + ref + "/" + p,
+	}
+	if withContent {
+		out["encoding"] = "base64"
+		out["content"] = base64.StdEncoding.EncodeToString(repo.git.blobs[blob])
+	}
+	return out
+}
+
+type contentsRequest struct {
+	Message string `json:"message"`
+	Content string `json:"content"`
+	SHA     string `json:"sha"`
+	Branch  string `json:"branch"`
+}
+
+func (s *Server) putContents(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	var body contentsRequest
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	content, err := base64.StdEncoding.DecodeString(body.Content)
+	if err != nil {
+		writeValidationError(w, "content is not valid Base64")
+		return
+	}
+	p := strings.Trim(r.PathValue("path"), "/")
+
+	branch, tree, ok := s.contentsBranch(w, repo, body.Branch)
+	if !ok {
+		return
+	}
+	existing, exists := tree[p]
+	switch {
+	case exists && body.SHA == "":
+		writeValidationError(w, "Invalid request.\n\n\"sha\" wasn't supplied.")
+		return
+	case exists && body.SHA != existing:
+		writeError(w, http.StatusConflict, p+" does not match "+body.SHA)
+		return
+	}
+
+	updated := copyTree(tree)
+	updated[p] = repo.git.putBlob(content)
+	sha := s.commitTree(repo, branch, body.Message, repo.git.putTree(updated))
+
+	status := http.StatusCreated
+	if exists {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, map[string]any{
+		"content": s.contentJSON(repo, branch, p, updated[p], false),
+		"commit":  s.gitCommitJSON(repo, repo.git.commits[sha]),
+	})
+}
+
+func (s *Server) deleteContents(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	var body contentsRequest
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	p := strings.Trim(r.PathValue("path"), "/")
+
+	branch, tree, ok := s.contentsBranch(w, repo, body.Branch)
+	if !ok {
+		return
+	}
+	existing, exists := tree[p]
+	switch {
+	case !exists:
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	case body.SHA != existing:
+		writeError(w, http.StatusConflict, p+" does not match "+body.SHA)
+		return
+	}
+
+	updated := copyTree(tree)
+	delete(updated, p)
+	sha := s.commitTree(repo, branch, body.Message, repo.git.putTree(updated))
+	writeJSON(w, http.StatusOK, map[string]any{
+		"content": nil,
+		"commit":  s.gitCommitJSON(repo, repo.git.commits[sha]),
+	})
+}
+
+// contentsBranch resolves the branch targeted by a contents write, defaulting
+// to the default branch. Writing to an empty repository is allowed and creates
+// the branch.
+func (s *Server) contentsBranch(w http.ResponseWriter, repo *repository, branch string) (string, map[string]string, bool) {
+	if branch == "" {
+		branch = repo.defaultBranch
+	}
+	head, ok := repo.git.resolveCommit("refs/heads/" + branch)
+	if ok {
+		return branch, repo.git.trees[head.tree], true
+	}
+	if len(repo.git.commits) == 0 && branch == repo.defaultBranch {
+		return branch, map[string]string{}, true
+	}
+	writeError(w, http.StatusNotFound, "Branch "+branch+" not found")
+	return "", nil, false
+}
+
+func copyTree(tree map[string]string) map[string]string {
+	out := make(map[string]string, len(tree))
+	for p, blob := range tree {
+		out[p] = blob
+	}
+	return out
+}
+
+// getRaw serves raw file content. The ref is either a single path segment
+// (a branch, tag, sha or HEAD) or a fully qualified refs/heads/... or
+// refs/tags/... name.
+func (s *Server) getRaw(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookupRepo(w, r)
+	if !ok {
+		return
+	}
+	segments := strings.Split(r.PathValue("path"), "/")
+	refSegments := 1
+	if len(segments) > 3 && segments[0] == "refs" {
+		refSegments = 3
+	}
+	if len(segments) <= refSegments {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	ref := strings.Join(segments[:refSegments], "/")
+	if ref == "HEAD" {
+		ref = repo.defaultBranch
+	}
+	p := strings.Join(segments[refSegments:], "/")
+
+	c, ok := repo.git.resolveCommit(ref)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	blob, ok := repo.git.trees[c.tree][p]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	content := repo.git.blobs[blob]
+	w.Header().Set("Content-Type", http.DetectContentType(content))
+	_, _ = w.Write(content)
+}
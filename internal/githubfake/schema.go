@@ -0,0 +1,374 @@
+package githubfake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var errPendingReviewExists = errors.New("user can only have one pending review per pull request")
+
+// graphql serves POST /api/graphql. Errors are reported the way GitHub does,
+// as a 200 response carrying an errors array.
+func (s *Server) graphql(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+
+	op, err := parseGraphQL(body.Query)
+	if err != nil {
+		writeGraphQLError(w, err)
+		return
+	}
+	root := s.queryRoot()
+	if op.kind == "mutation" {
+		root = s.mutationRoot()
+	}
+	data, err := execute(root, op.selections, body.Variables)
+	if err != nil {
+		writeGraphQLError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": data})
+}
+
+func writeGraphQLError(w http.ResponseWriter, err error) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data":   nil,
+		"errors": []map[string]any{{"message": err.Error()}},
+	})
+}
+
+func (s *Server) queryRoot() gqlObject {
+	return gqlObject{
+		"viewer": gqlObject{"__typename": "User", "login": s.login},
+		"repository": gqlResolver(func(args map[string]any) (any, error) {
+			owner, _ := args["owner"].(string)
+			name, _ := args["name"].(string)
+			repo, ok := s.repos[repoKey(owner, name)]
+			if !ok {
+				return nil, fmt.Errorf("Could not resolve to a Repository with the name '%s/%s'.", owner, name) //nolint:staticcheck // mirrors the GitHub message
+			}
+			return s.repoObject(repo), nil
+		}),
+	}
+}
+
+func (s *Server) repoObject(repo *repository) gqlObject {
+	return gqlObject{
+		"__typename":    "Repository",
+		"id":            "R_" + repo.owner + "_" + repo.name,
+		"databaseId":    repo.id,
+		"name":          repo.name,
+		"nameWithOwner": repo.owner + "/" + repo.name,
+		"url":           s.repoHTMLURL(repo),
+		"issues": gqlResolver(func(args map[string]any) (any, error) {
+			return connection(s.filterIssues(repo, args), args), nil
+		}),
+		"issue": gqlResolver(func(args map[string]any) (any, error) {
+			number, _ := toInt(args["number"])
+			iss, ok := repo.issues[number]
+			if !ok || iss.pull != nil {
+				return nil, fmt.Errorf("Could not resolve to an Issue with the number of %d.", number) //nolint:staticcheck // mirrors the GitHub message
+			}
+			return s.issueObject(repo, iss), nil
+		}),
+		"pullRequest": gqlResolver(func(args map[string]any) (any, error) {
+			number, _ := toInt(args["number"])
+			iss, ok := repo.issues[number]
+			if !ok || iss.pull == nil {
+				return nil, fmt.Errorf("Could not resolve to a PullRequest with the number of %d.", number) //nolint:staticcheck // mirrors the GitHub message
+			}
+			return s.pullObject(repo, iss), nil
+		}),
+	}
+}
+
+// filterIssues applies the arguments of Repository.issues.
+func (s *Server) filterIssues(repo *repository, args map[string]any) []gqlObject {
+	states := toStrings(args["states"])
+	labels := toStrings(args["labels"])
+	var since string
+	if filterBy, ok := args["filterBy"].(map[string]any); ok {
+		since, _ = filterBy["since"].(string)
+	}
+
+	var issues []*issue
+	for _, iss := range repo.issues {
+		switch {
+		case iss.pull != nil:
+			continue
+		case len(states) > 0 && !containsFold(states, iss.state):
+			continue
+		case since != "" && iss.updatedAt < since:
+			continue
+		}
+		if len(labels) > 0 {
+			matched := false
+			for _, label := range labels {
+				matched = matched || containsFold(iss.labels, label)
+			}
+			if !matched {
+				continue
+			}
+		}
+		issues = append(issues, iss)
+	}
+
+	field, direction := "CREATED_AT", "DESC"
+	if orderBy, ok := args["orderBy"].(map[string]any); ok {
+		if f, ok := orderBy["field"].(string); ok && f != "" {
+			field = f
+		}
+		if d, ok := orderBy["direction"].(string); ok && d != "" {
+			direction = d
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if direction == "DESC" {
+			a, b = b, a
+		}
+		switch field {
+		case "UPDATED_AT":
+			if a.updatedAt != b.updatedAt {
+				return a.updatedAt < b.updatedAt
+			}
+		case "COMMENTS":
+			if len(a.comments) != len(b.comments) {
+				return len(a.comments) < len(b.comments)
+			}
+		}
+		return a.number < b.number
+	})
+
+	nodes := make([]gqlObject, 0, len(issues))
+	for _, iss := range issues {
+		nodes = append(nodes, s.issueObject(repo, iss))
+	}
+	return nodes
+}
+
+func (s *Server) issueObject(repo *repository, iss *issue) gqlObject {
+	return gqlObject{
+		"__typename": "Issue",
+		"id":         issueNodeID(repo, iss),
+		"databaseId": iss.id,
+		"number":     iss.number,
+		"title":      iss.title,
+		"body":       iss.body,
+		"state":      strings.ToUpper(iss.state),
+		"url":        s.issueHTMLURL(repo, iss),
+		"createdAt":  iss.createdAt,
+		"updatedAt":  iss.updatedAt,
+		"author":     gqlObject{"__typename": "User", "login": iss.author},
+		"labels": gqlResolver(func(args map[string]any) (any, error) {
+			var nodes []gqlObject
+			for _, name := range iss.labels {
+				nodes = append(nodes, gqlObject{"__typename": "Label", "id": "LA_" + name, "name": name, "description": ""})
+			}
+			return connection(nodes, args), nil
+		}),
+		"assignees": gqlResolver(func(args map[string]any) (any, error) {
+			var nodes []gqlObject
+			for _, login := range iss.assignees {
+				nodes = append(nodes, gqlObject{"__typename": "User", "id": "U_" + login, "login": login})
+			}
+			return connection(nodes, args), nil
+		}),
+		"comments": gqlResolver(func(args map[string]any) (any, error) {
+			var nodes []gqlObject
+			for _, c := range iss.comments {
+				nodes = append(nodes, gqlObject{
+					"__typename": "IssueComment",
+					"id":         "IC_" + strconv.FormatInt(c.id, 10),
+					"body":       c.body,
+					"author":     gqlObject{"__typename": "User", "login": c.author},
+				})
+			}
+			return connection(nodes, args), nil
+		}),
+	}
+}
+
+func (s *Server) pullObject(repo *repository, iss *issue) gqlObject {
+	obj := s.issueObject(repo, iss)
+	obj["__typename"] = "PullRequest"
+	obj["isDraft"] = iss.pull.draft
+	if iss.pull.merged {
+		obj["state"] = "MERGED"
+	}
+	obj["headRefName"] = iss.pull.head
+	obj["baseRefName"] = iss.pull.base
+	obj["reviews"] = gqlResolver(func(args map[string]any) (any, error) {
+		author, _ := args["author"].(string)
+		var nodes []gqlObject
+		for _, rv := range iss.pull.reviews {
+			if author != "" && !strings.EqualFold(rv.author, author) {
+				continue
+			}
+			nodes = append(nodes, s.reviewObject(repo, iss, rv))
+		}
+		return connection(nodes, args), nil
+	})
+	return obj
+}
+
+func (s *Server) reviewObject(repo *repository, iss *issue, rv *review) gqlObject {
+	return gqlObject{
+		"__typename": "PullRequestReview",
+		"id":         reviewNodeID(rv),
+		"databaseId": rv.id,
+		"state":      rv.state,
+		"body":       rv.body,
+		"url":        s.issueHTMLURL(repo, iss) + "#pullrequestreview-" + strconv.FormatInt(rv.id, 10),
+		"author":     gqlObject{"__typename": "User", "login": rv.author},
+	}
+}
+
+// findPull resolves a pull request node ID.
+func (s *Server) findPull(nodeID any) (*repository, *issue, error) {
+	id, _ := nodeID.(string)
+	if rest, ok := strings.CutPrefix(id, "PR_"); ok {
+		repoID, number, _ := strings.Cut(rest, "_")
+		n, _ := strconv.Atoi(number)
+		for _, repo := range s.repos {
+			if strconv.FormatInt(repo.id, 10) != repoID {
+				continue
+			}
+			if iss, ok := repo.issues[n]; ok && iss.pull != nil {
+				return repo, iss, nil
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("Could not resolve to a node with the global id of '%s'", id) //nolint:staticcheck // mirrors the GitHub message
+}
+
+// findReview resolves a pull request review node ID.
+func (s *Server) findReview(nodeID any) (*repository, *issue, *review, error) {
+	id, _ := nodeID.(string)
+	for _, repo := range s.repos {
+		for _, iss := range repo.issues {
+			if iss.pull == nil {
+				continue
+			}
+			for _, rv := range iss.pull.reviews {
+				if reviewNodeID(rv) == id {
+					return repo, iss, rv, nil
+				}
+			}
+		}
+	}
+	return nil, nil, nil, fmt.Errorf("Could not resolve to a node with the global id of '%s'", id) //nolint:staticcheck // mirrors the GitHub message
+}
+
+func inputArg(args map[string]any) map[string]any {
+	input, _ := args["input"].(map[string]any)
+	if input == nil {
+		input = map[string]any{}
+	}
+	return input
+}
+
+func (s *Server) mutationRoot() gqlObject {
+	return gqlObject{
+		"addPullRequestReview": gqlResolver(func(args map[string]any) (any, error) {
+			input := inputArg(args)
+			repo, iss, err := s.findPull(input["pullRequestId"])
+			if err != nil {
+				return nil, err
+			}
+			body, _ := input["body"].(string)
+			event, _ := input["event"].(string)
+			commitID, _ := input["commitOID"].(string)
+			rv, err := s.addReview(repo, iss, body, event, commitID)
+			if err != nil {
+				return nil, err
+			}
+			return gqlObject{"pullRequestReview": s.reviewObject(repo, iss, rv)}, nil
+		}),
+		"submitPullRequestReview": gqlResolver(func(args map[string]any) (any, error) {
+			input := inputArg(args)
+			repo, iss, rv, err := s.findReview(input["pullRequestReviewId"])
+			if err != nil {
+				return nil, err
+			}
+			if rv.state != "PENDING" {
+				return nil, errors.New("review has already been submitted")
+			}
+			event, _ := input["event"].(string)
+			if body, ok := input["body"].(string); ok {
+				rv.body = body
+			}
+			rv.state = reviewState(event)
+			rv.submittedAt = s.timestamp()
+			return gqlObject{"pullRequestReview": s.reviewObject(repo, iss, rv)}, nil
+		}),
+		"deletePullRequestReview": gqlResolver(func(args map[string]any) (any, error) {
+			input := inputArg(args)
+			repo, iss, rv, err := s.findReview(input["pullRequestReviewId"])
+			if err != nil {
+				return nil, err
+			}
+			if rv.state != "PENDING" {
+				return nil, errors.New("can not delete a non-pending pull request review")
+			}
+			for i, candidate := range iss.pull.reviews {
+				if candidate == rv {
+					iss.pull.reviews = append(iss.pull.reviews[:i], iss.pull.reviews[i+1:]...)
+					break
+				}
+			}
+			return gqlObject{"pullRequestReview": s.reviewObject(repo, iss, rv)}, nil
+		}),
+		"addPullRequestReviewThread": gqlResolver(func(args map[string]any) (any, error) {
+			input := inputArg(args)
+			_, _, rv, err := s.findReview(input["pullRequestReviewId"])
+			if err != nil {
+				return nil, err
+			}
+			c := &reviewComment{id: s.id(), createdAt: s.timestamp(), subjectType: "LINE", side: "RIGHT"}
+			c.path, _ = input["path"].(string)
+			c.body, _ = input["body"].(string)
+			c.line, _ = toInt(input["line"])
+			c.startLine, _ = toInt(input["startLine"])
+			if side, ok := input["side"].(string); ok {
+				c.side = side
+			}
+			c.startSide, _ = input["startSide"].(string)
+			if subjectType, ok := input["subjectType"].(string); ok {
+				c.subjectType = subjectType
+			}
+			rv.comments = append(rv.comments, c)
+			return gqlObject{"thread": gqlObject{
+				"__typename": "PullRequestReviewThread",
+				"id":         "PRRT_" + strconv.FormatInt(c.id, 10),
+			}}, nil
+		}),
+		"convertPullRequestToDraft": gqlResolver(func(args map[string]any) (any, error) {
+			return s.setDraft(inputArg(args), true)
+		}),
+		"markPullRequestReadyForReview": gqlResolver(func(args map[string]any) (any, error) {
+			return s.setDraft(inputArg(args), false)
+		}),
+	}
+}
+
+func (s *Server) setDraft(input map[string]any, draft bool) (any, error) {
+	repo, iss, err := s.findPull(input["pullRequestId"])
+	if err != nil {
+		return nil, err
+	}
+	iss.pull.draft = draft
+	iss.updatedAt = s.timestamp()
+	return gqlObject{"pullRequest": s.pullObject(repo, iss)}, nil
+}
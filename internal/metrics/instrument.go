@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ToolMiddleware records call counts, errors, latency and response size for
+// every tool call. Install it with server.WithToolHandlerMiddleware.
+func ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tool := request.Params.Name
+		start := time.Now()
+
+		result, err := next(ctx, request)
+
+		ToolCalls.WithLabelValues(tool).Inc()
+		ToolDuration.WithLabelValues(tool).Observe(time.Since(start).Seconds())
+		if err != nil || (result != nil && result.IsError) {
+			ToolErrors.WithLabelValues(tool).Inc()
+		}
+		if result != nil {
+			ToolResponseBytes.WithLabelValues(tool).Add(float64(resultBytes(result)))
+		}
+		return result, err
+	}
+}
+
+// resultBytes adds up the text and data in the content of result, which is
+// what the client reads, without encoding the whole result.
+func resultBytes(result *mcp.CallToolResult) int {
+	n := 0
+	for _, content := range result.Content {
+		switch content := content.(type) {
+		case mcp.TextContent:
+			n += len(content.Text)
+		case mcp.ImageContent:
+			n += len(content.Data)
+		case mcp.AudioContent:
+			n += len(content.Data)
+		case mcp.EmbeddedResource:
+			switch resource := content.Resource.(type) {
+			case mcp.TextResourceContents:
+				n += len(resource.Text)
+			case mcp.BlobResourceContents:
+				n += len(resource.Blob)
+			}
+		}
+	}
+	return n
+}
+
+// OtherHost is the endpoint label of requests to hosts other than the GitHub
+// API hosts, such as the pre-signed URLs that log downloads are redirected to.
+const OtherHost = "{other}"
+
+// Transport is an http.RoundTripper that counts GitHub API requests and
+// tracks the rate limit reported in response headers.
+type Transport struct {
+	transport http.RoundTripper
+	hosts     []string
+}
+
+// NewTransport wraps transport so that every request through it is recorded.
+// Requests to hosts other than the API hosts given are labelled OtherHost
+// instead of their endpoint; with no hosts, every request gets its endpoint.
+func NewTransport(transport http.RoundTripper, hosts ...string) *Transport {
+	return &Transport{transport: transport, hosts: hosts}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	client := Client(req.URL)
+	start := time.Now()
+
+	resp, err := t.transport.RoundTrip(req)
+
+	APIDuration.WithLabelValues(client).Observe(time.Since(start).Seconds())
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+		if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+			if v, parseErr := strconv.ParseFloat(remaining, 64); parseErr == nil {
+				resource := resp.Header.Get("X-RateLimit-Resource")
+				if resource == "" {
+					resource = "core"
+				}
+				RateLimitRemaining.WithLabelValues(resource).Set(v)
+			}
+		}
+	}
+	endpoint := OtherHost
+	if len(t.hosts) == 0 || slices.Contains(t.hosts, req.URL.Host) {
+		endpoint = Endpoint(req.URL)
+	}
+	APIRequests.WithLabelValues(client, endpoint, status).Inc()
+	return resp, err
+}
+
+// Client classifies a GitHub API URL as "graphql", "raw" or "rest".
+func Client(u *url.URL) string {
+	switch {
+	case strings.HasSuffix(u.Path, "/graphql"):
+		return "graphql"
+	case strings.HasPrefix(u.Host, "raw."), strings.HasPrefix(u.Path, "/raw/"):
+		return "raw"
+	default:
+		return "rest"
+	}
+}
+
+var numericSegment = regexp.MustCompile(`^[0-9]+$`)
+
+// Endpoint normalises a GitHub API URL into a low cardinality endpoint label,
+// e.g. /repos/github/docs/issues/12 becomes /repos/{owner}/{repo}/issues/{id}.
+func Endpoint(u *url.URL) string {
+	path := strings.TrimPrefix(u.Path, "/api/v3")
+	if strings.HasSuffix(path, "/graphql") {
+		return "/graphql"
+	}
+	if Client(u) == "raw" {
+		return "/raw/{owner}/{repo}/{ref}/{path}"
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	out := make([]string, 0, len(segments))
+	for i, segment := range segments {
+		var previous string
+		if i > 0 {
+			previous = segments[i-1]
+		}
+		switch {
+		case i == 1 && segments[0] == "repos", previous == "users", previous == "orgs":
+			out = append(out, "{owner}")
+		case i == 2 && segments[0] == "repos":
+			out = append(out, "{repo}")
+		case previous == "contents":
+			// File paths may contain any number of segments.
+			return "/" + strings.Join(append(out, "{path}"), "/")
+		case (previous == "ref" || previous == "refs") && i > 1 && segments[i-2] == "git":
+			// So may ref names such as heads/feature/x.
+			return "/" + strings.Join(append(out, "{ref}"), "/")
+		case previous == "branches":
+			// And branch names.
+			return "/" + strings.Join(append(out, "{branch}"), "/")
+		case previous == "commits", previous == "trees", previous == "tags", previous == "blobs", previous == "compare":
+			out = append(out, "{sha}")
+		case previous == "workflows" && !numericSegment.MatchString(segment):
+			out = append(out, "{workflow}")
+		case numericSegment.MatchString(segment):
+			out = append(out, "{id}")
+		default:
+			out = append(out, segment)
+		}
+	}
+	return "/" + strings.Join(out, "/")
+}
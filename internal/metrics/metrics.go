@@ -0,0 +1,119 @@
+// Package metrics records Prometheus metrics about tool calls and GitHub API
+// usage, and serves them for scraping.
+//
+// The metrics are registered with a registry of their own rather than the
+// Prometheus default one, so that /metrics only exposes what the server
+// records.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry is the registry that the server's metrics are registered with.
+var Registry = prometheus.NewRegistry()
+
+// DefaultBuckets are the latency histogram buckets, in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+var factory = promauto.With(Registry)
+
+// Built-in metrics recorded by ToolMiddleware and Transport, GitHubErrors,
+// recorded by the GitHub error middleware, and ResponseCacheRequests, recorded
+// by the response cache.
+var (
+	ToolCalls = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_tool_calls_total",
+		Help: "Number of MCP tool calls.",
+	}, []string{"tool"})
+	ToolErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_tool_errors_total",
+		Help: "Number of MCP tool calls that failed or returned an error result.",
+	}, []string{"tool"})
+	ToolDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mcp_tool_duration_seconds",
+		Help:    "Latency of MCP tool calls in seconds.",
+		Buckets: DefaultBuckets,
+	}, []string{"tool"})
+	ToolResponseBytes = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_tool_response_bytes_total",
+		Help: "Bytes of text and data in the tool results returned to the client.",
+	}, []string{"tool"})
+
+	APIRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "github_api_requests_total",
+		Help: "Number of GitHub API requests by client, endpoint and status.",
+	}, []string{"client", "endpoint", "status"})
+	APIDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "github_api_request_duration_seconds",
+		Help:    "Latency of GitHub API requests in seconds.",
+		Buckets: DefaultBuckets,
+	}, []string{"client"})
+	RateLimitRemaining = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "github_rate_limit_remaining",
+		Help: "Requests remaining in the current GitHub rate limit window.",
+	}, []string{"resource"})
+	GitHubErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "github_errors_total",
+		Help: "Number of GitHub API errors returned to tools, by tool and error kind.",
+	}, []string{"tool", "kind"})
+	ResponseCacheRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "github_response_cache_requests_total",
+		Help: "Number of cacheable GitHub API requests, by whether the response cache served them (hit) or not (miss).",
+	}, []string{"result"})
+)
+
+// CountGitHubError counts a GitHub API error returned to tool, of the given
+// kind, in GitHubErrors.
+func CountGitHubError(tool, kind string) {
+	GitHubErrors.WithLabelValues(tool, kind).Inc()
+}
+
+// CountResponseCache counts a cacheable GitHub API request by its result, hit
+// or miss, in ResponseCacheRequests.
+func CountResponseCache(result string) {
+	ResponseCacheRequests.WithLabelValues(result).Inc()
+}
+
+// Handler serves the registry. Mount it on /metrics when running an HTTP
+// transport.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ListenAndServe serves the registry on /metrics at addr until ctx is done. It
+// is used to expose metrics on a side port when running over stdio.
+func ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return Serve(ctx, listener)
+}
+
+// Serve serves the registry on /metrics using listener until ctx is done.
+func Serve(ctx context.Context, listener net.Listener) error {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", Handler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
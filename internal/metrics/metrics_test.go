@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoint(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://api.github.com/user", "/user"},
+		{"https://api.github.com/repos/github/docs/issues/12/comments", "/repos/{owner}/{repo}/issues/{id}/comments"},
+		{"https://api.github.com/repos/github/docs/contents/docs/README.md", "/repos/{owner}/{repo}/contents/{path}"},
+		{"https://api.github.com/repos/github/docs/git/ref/heads/feature/x", "/repos/{owner}/{repo}/git/ref/{ref}"},
+		{"https://api.github.com/repos/github/docs/git/refs", "/repos/{owner}/{repo}/git/refs"},
+		{"https://api.github.com/repos/github/docs/commits/abc123/status", "/repos/{owner}/{repo}/commits/{sha}/status"},
+		{"https://api.github.com/repos/github/docs/branches/main", "/repos/{owner}/{repo}/branches/{branch}"},
+		{"https://api.github.com/repos/github/docs/branches/feature/x/protection", "/repos/{owner}/{repo}/branches/{branch}"},
+		{"https://api.github.com/repos/github/docs/actions/workflows/ci.yml/runs", "/repos/{owner}/{repo}/actions/workflows/{workflow}/runs"},
+		{"https://api.github.com/orgs/github/repos", "/orgs/{owner}/repos"},
+		{"https://ghes.example.com/api/v3/notifications/threads/42", "/notifications/threads/{id}"},
+		{"https://ghes.example.com/api/graphql", "/graphql"},
+		{"https://raw.githubusercontent.com/github/docs/main/README.md", "/raw/{owner}/{repo}/{ref}/{path}"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.url, func(t *testing.T) {
+			u, err := url.Parse(tc.url)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, Endpoint(u))
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	for rawURL, expected := range map[string]string{
+		"https://api.github.com/graphql":                          "graphql",
+		"https://ghes.example.com/api/graphql":                    "graphql",
+		"https://raw.githubusercontent.com/o/r/main/README.md":    "raw",
+		"https://ghes.example.com/raw/o/r/main/README.md":         "raw",
+		"https://ghes.example.com/api/v3/repos/o/r/issues?page=2": "rest",
+	} {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		assert.Equal(t, expected, Client(u), rawURL)
+	}
+}
+
+func TestToolMiddleware(t *testing.T) {
+	tool := "metrics_test_tool"
+	callsBefore := testutil.ToFloat64(ToolCalls.WithLabelValues(tool))
+	errorsBefore := testutil.ToFloat64(ToolErrors.WithLabelValues(tool))
+	bytesBefore := testutil.ToFloat64(ToolResponseBytes.WithLabelValues(tool))
+	countBefore := histogramCount(t, ToolDuration, tool)
+
+	var request mcp.CallToolRequest
+	request.Params.Name = tool
+
+	ok := ToolMiddleware(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("hello"), nil
+	})
+	_, err := ok(context.Background(), request)
+	require.NoError(t, err)
+
+	toolError := ToolMiddleware(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("boom"), nil
+	})
+	_, err = toolError(context.Background(), request)
+	require.NoError(t, err)
+
+	failed := ToolMiddleware(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("boom")
+	})
+	_, err = failed(context.Background(), request)
+	require.Error(t, err)
+
+	assert.Equal(t, 3.0, testutil.ToFloat64(ToolCalls.WithLabelValues(tool))-callsBefore)
+	assert.Equal(t, 2.0, testutil.ToFloat64(ToolErrors.WithLabelValues(tool))-errorsBefore)
+	assert.Equal(t, uint64(3), histogramCount(t, ToolDuration, tool)-countBefore)
+	// "hello" and "boom"
+	assert.Equal(t, 9.0, testutil.ToFloat64(ToolResponseBytes.WithLabelValues(tool))-bytesBefore)
+}
+
+func TestResultBytes(t *testing.T) {
+	result := &mcp.CallToolResult{Content: []mcp.Content{
+		mcp.NewTextContent("hello"),
+		mcp.NewImageContent("aW1hZ2U=", "image/png"),
+		mcp.NewEmbeddedResource(mcp.TextResourceContents{URI: "repo://o/r/README.md", Text: "readme"}),
+		mcp.NewEmbeddedResource(mcp.BlobResourceContents{URI: "repo://o/r/logo.png", Blob: "YmxvYg=="}),
+	}}
+	assert.Equal(t, 5+8+6+8, resultBytes(result))
+}
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Resource", "metrics_test")
+		if strings.HasSuffix(r.URL.Path, "/labels") {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	apiURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	ok := APIRequests.WithLabelValues("rest", "/repos/{owner}/{repo}/issues/{id}", "200")
+	notFound := APIRequests.WithLabelValues("rest", "/repos/{owner}/{repo}/issues/{id}/labels", "404")
+	okBefore, notFoundBefore := testutil.ToFloat64(ok), testutil.ToFloat64(notFound)
+
+	client := &http.Client{Transport: NewTransport(http.DefaultTransport, apiURL.Host)}
+	for _, path := range []string{"/api/v3/repos/o/r/issues/1", "/api/v3/repos/o/r/issues/2/labels"} {
+		resp, err := client.Get(srv.URL + path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(ok)-okBefore)
+	assert.Equal(t, 1.0, testutil.ToFloat64(notFound)-notFoundBefore)
+	assert.Equal(t, 4321.0, testutil.ToFloat64(RateLimitRemaining.WithLabelValues("metrics_test")))
+}
+
+func TestTransportLabelsOtherHosts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	defer srv.Close()
+
+	other := APIRequests.WithLabelValues("rest", OtherHost, "200")
+	otherBefore := testutil.ToFloat64(other)
+
+	// Pre-signed download URLs would otherwise each make an endpoint
+	client := &http.Client{Transport: NewTransport(http.DefaultTransport, "api.github.com")}
+	resp, err := client.Get(srv.URL + "/blobs/job-logs/ab12cd34.txt?sig=xyz")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(other)-otherBefore)
+}
+
+// histogramCount returns the number of observations of h for labelValues.
+func histogramCount(t *testing.T, h *prometheus.HistogramVec, labelValues ...string) uint64 {
+	t.Helper()
+	var m dto.Metric
+	require.NoError(t, h.WithLabelValues(labelValues...).(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestServe(t *testing.T) {
+	ToolCalls.WithLabelValues("metrics_test_serve").Inc()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errC := make(chan error, 1)
+	go func() { errC <- Serve(ctx, listener) }()
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/metrics")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, err)
+
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+	assert.Contains(t, string(body), `mcp_tool_calls_total{tool="metrics_test_serve"}`)
+
+	cancel()
+	require.NoError(t, <-errC)
+}
@@ -0,0 +1,248 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// StatusFunc returns the combined commit status (success, pending, failure or
+// error) that a call to a tool in owner/repo with args would act on.
+type StatusFunc func(ctx context.Context, owner, repo string, args map[string]any) (string, error)
+
+// BaseFunc returns the base branch of pull request number in owner/repo.
+type BaseFunc func(ctx context.Context, owner, repo string, number int) (string, error)
+
+// Denial explains why a call was refused. It is returned to the client as the
+// JSON text of an error result.
+type Denial struct {
+	Error string `json:"error"`
+	Tool  string `json:"tool"`
+	Repo  string `json:"repo,omitempty"`
+	// Rule is the name of the rule that fired, empty when the default denied the call.
+	Rule string `json:"rule,omitempty"`
+	// RuleIndex is the 1-based position of the rule in the policy file.
+	RuleIndex int    `json:"rule_index,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// DenialError is the Error value of every Denial.
+const DenialError = "policy_denied"
+
+// Engine evaluates tool calls against a Policy.
+type Engine struct {
+	policy *Policy
+	status StatusFunc
+	base   BaseFunc
+}
+
+// NewEngine returns an Engine for p. status resolves status conditions and may
+// be nil when the policy has none. base looks up the branch of calls on a pull
+// request that name no branch; when it is nil, or fails, their branch is
+// unknown.
+func NewEngine(p *Policy, status StatusFunc, base BaseFunc) *Engine {
+	return &Engine{policy: p, status: status, base: base}
+}
+
+// call holds the facts about a tool call that rules match against. The commit
+// status and the base branch of a pull request are only looked up when a rule
+// needs them.
+type call struct {
+	tool       string
+	owner      string
+	repo       string
+	pullNumber int
+	args       map[string]any
+	branches   []string
+	paths      []string
+
+	statusLoaded bool
+	status       string
+	statusErr    error
+
+	baseLoaded bool
+	baseErr    error
+}
+
+func newCall(tool string, args map[string]any) *call {
+	c := &call{tool: tool, args: args}
+	c.owner, _ = args["owner"].(string)
+	c.repo, _ = args["repo"].(string)
+	if number, ok := args["pullNumber"].(float64); ok {
+		c.pullNumber = int(number)
+	}
+
+	for _, key := range []string{"branch", "base", "ref"} {
+		if v, ok := args[key].(string); ok && v != "" {
+			c.branches = append(c.branches, strings.TrimPrefix(v, "refs/heads/"))
+		}
+	}
+	if v, ok := args["path"].(string); ok && v != "" {
+		c.paths = append(c.paths, strings.TrimPrefix(v, "/"))
+	}
+	if files, ok := args["files"].([]any); ok {
+		for _, f := range files {
+			if file, ok := f.(map[string]any); ok {
+				if v, ok := file["path"].(string); ok && v != "" {
+					c.paths = append(c.paths, strings.TrimPrefix(v, "/"))
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c *call) fullRepo() string {
+	if c.owner == "" || c.repo == "" {
+		return ""
+	}
+	return c.owner + "/" + c.repo
+}
+
+// Evaluate returns the Denial for a call to tool with args, or nil when the
+// policy allows it.
+func (e *Engine) Evaluate(ctx context.Context, tool string, args map[string]any) *Denial {
+	c := newCall(tool, args)
+	for i := range e.policy.Rules {
+		r := &e.policy.Rules[i]
+		if !e.matches(ctx, r, c) {
+			continue
+		}
+		if r.Effect == Allow {
+			return nil
+		}
+		reason := r.Message
+		if reason == "" {
+			reason = fmt.Sprintf("denied by policy rule %q", r.Name)
+		}
+		if c.statusErr != nil && r.usesStatus() {
+			reason += fmt.Sprintf(" (commit status could not be checked: %v)", c.statusErr)
+		}
+		if c.baseErr != nil && len(r.branches) > 0 {
+			reason += fmt.Sprintf(" (pull request base branch could not be checked: %v)", c.baseErr)
+		}
+		return &Denial{Error: DenialError, Tool: tool, Repo: c.fullRepo(), Rule: r.Name, RuleIndex: i + 1, Reason: reason}
+	}
+	if e.policy.Default == Deny {
+		return &Denial{Error: DenialError, Tool: tool, Repo: c.fullRepo(), Reason: "no policy rule allows this call and the default is deny"}
+	}
+	return nil
+}
+
+func (r *Rule) usesStatus() bool {
+	return (r.When != nil && r.When.Status != "") || (r.Unless != nil && r.Unless.Status != "")
+}
+
+func (e *Engine) matches(ctx context.Context, r *Rule, c *call) bool {
+	if len(r.tools) > 0 && !matchAny(r.tools, c.tool) {
+		return false
+	}
+	if len(r.repos) > 0 && (c.fullRepo() == "" || !matchAny(r.repos, strings.ToLower(c.fullRepo()))) {
+		return false
+	}
+	if len(r.branches) > 0 && !e.matchBranches(ctx, r, c) {
+		return false
+	}
+	if len(r.paths) > 0 && !matchValues(r.paths, c.paths, r.Effect) {
+		return false
+	}
+	if r.When != nil && !e.holds(ctx, r.When, c) {
+		return false
+	}
+	if r.Unless != nil && e.holds(ctx, r.Unless, c) {
+		return false
+	}
+	return true
+}
+
+// matchBranches matches the branches of c against r. A call on a pull request
+// that names no branch acts on the pull request's base branch, which is looked
+// up. When it cannot be, the branch is unknown and matches deny rules but never
+// allow rules, so that a protected branch is not reached by omission.
+func (e *Engine) matchBranches(ctx context.Context, r *Rule, c *call) bool {
+	if !c.baseLoaded {
+		c.baseLoaded = true
+		if len(c.branches) == 0 && c.pullNumber > 0 {
+			switch {
+			case e.base == nil:
+				c.baseErr = fmt.Errorf("no base branch lookup configured")
+			case c.owner == "" || c.repo == "":
+				c.baseErr = fmt.Errorf("the call does not name a repository")
+			default:
+				var base string
+				if base, c.baseErr = e.base(ctx, c.owner, c.repo, c.pullNumber); c.baseErr == nil {
+					c.branches = append(c.branches, base)
+				}
+			}
+		}
+	}
+	if c.baseErr != nil {
+		return r.Effect == Deny
+	}
+	return matchValues(r.branches, c.branches, r.Effect)
+}
+
+// matchValues matches a deny rule when any value matches and an allow rule
+// when all values do, so that a mix of permitted and forbidden targets is
+// never allowed. Calls without values never match.
+func matchValues(globs []*regexp.Regexp, values []string, effect string) bool {
+	if len(values) == 0 {
+		return false
+	}
+	for _, v := range values {
+		matched := matchAny(globs, v)
+		if effect == Deny && matched {
+			return true
+		}
+		if effect == Allow && !matched {
+			return false
+		}
+	}
+	return effect == Allow
+}
+
+// holds reports whether every field of cond holds for c. A status that cannot
+// be looked up never holds.
+func (e *Engine) holds(ctx context.Context, cond *Condition, c *call) bool {
+	for name, re := range cond.args {
+		v, ok := c.args[name]
+		if !ok || !re.MatchString(fmt.Sprint(v)) {
+			return false
+		}
+	}
+	if cond.Status == "" {
+		return true
+	}
+	if !c.statusLoaded {
+		c.statusLoaded = true
+		switch {
+		case e.status == nil:
+			c.statusErr = fmt.Errorf("no status lookup configured")
+		case c.owner == "" || c.repo == "":
+			c.statusErr = fmt.Errorf("the call does not name a repository")
+		default:
+			c.status, c.statusErr = e.status(ctx, c.owner, c.repo, c.args)
+		}
+	}
+	return c.statusErr == nil && c.status == cond.Status
+}
+
+// Middleware refuses calls that the policy denies before their handler runs.
+func (e *Engine) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		denial := e.Evaluate(ctx, request.Params.Name, request.GetArguments())
+		if denial == nil {
+			return next(ctx, request)
+		}
+		text, err := json.Marshal(denial)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal policy denial: %w", err)
+		}
+		return mcp.NewToolResultError(string(text)), nil
+	}
+}
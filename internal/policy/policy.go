@@ -0,0 +1,231 @@
+// Package policy decides whether a tool call may run, based on a declarative
+// policy file of allow and deny rules.
+//
+// Rules are evaluated in order and the first rule that matches a call decides
+// it; calls that no rule matches get the policy's default effect. A rule
+// matches when every criterion it sets matches:
+//
+//   - tools: globs on the tool name
+//   - repos: globs on "owner/repo", compared case-insensitively
+//   - branches: globs on the branch arguments (branch, base, ref), or on the
+//     base branch of the pull request a call names with pullNumber
+//   - paths: globs on the file path arguments (path, files[].path)
+//   - when: a condition that must hold
+//   - unless: a condition that must not hold
+//
+// In globs "*" matches within a path segment and "**" across segments. A
+// call with several branches or paths matches a deny rule when any of them
+// matches, and an allow rule only when all of them do.
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Effects of a rule.
+const (
+	Allow = "allow"
+	Deny  = "deny"
+)
+
+// Combined commit states accepted by the status condition.
+var statusStates = []string{"success", "pending", "failure", "error"}
+
+// Policy is a parsed policy file.
+type Policy struct {
+	// Default is the effect for calls no rule matches, allow unless set.
+	Default string `yaml:"default"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+// Rule allows or denies the calls it matches.
+type Rule struct {
+	Name     string     `yaml:"name"`
+	Effect   string     `yaml:"effect"`
+	Tools    []string   `yaml:"tools"`
+	Repos    []string   `yaml:"repos"`
+	Branches []string   `yaml:"branches"`
+	Paths    []string   `yaml:"paths"`
+	When     *Condition `yaml:"when"`
+	Unless   *Condition `yaml:"unless"`
+	// Message is shown to the caller when the rule denies a call.
+	Message string `yaml:"message"`
+
+	tools, repos, branches, paths []*regexp.Regexp
+}
+
+// Condition holds when all of its fields hold.
+type Condition struct {
+	// Status is the combined commit status of the pull request head, or of the
+	// ref the call targets: success, pending, failure or error.
+	Status string `yaml:"status"`
+	// Args maps argument names to globs their values must match.
+	Args map[string]string `yaml:"args"`
+
+	args map[string]*regexp.Regexp
+}
+
+// Load reads and parses the policy file at path.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Parse parses and validates a YAML or JSON policy.
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&p); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("policy is empty")
+		}
+		return nil, err
+	}
+	if err := p.compile(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (p *Policy) compile() error {
+	switch p.Default {
+	case "":
+		p.Default = Allow
+	case Allow, Deny:
+	default:
+		return fmt.Errorf("default must be %q or %q, got %q", Allow, Deny, p.Default)
+	}
+
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if r.Effect != Allow && r.Effect != Deny {
+			return fmt.Errorf("%s: effect must be %q or %q, got %q", r.Name, Allow, Deny, r.Effect)
+		}
+		var err error
+		if r.tools, err = compileGlobs(r.Tools); err != nil {
+			return fmt.Errorf("%s: tools: %w", r.Name, err)
+		}
+		// GitHub owner and repository names are case-insensitive
+		repos := make([]string, len(r.Repos))
+		for i, repo := range r.Repos {
+			repos[i] = strings.ToLower(repo)
+		}
+		if r.repos, err = compileGlobs(repos); err != nil {
+			return fmt.Errorf("%s: repos: %w", r.Name, err)
+		}
+		if r.branches, err = compileGlobs(r.Branches); err != nil {
+			return fmt.Errorf("%s: branches: %w", r.Name, err)
+		}
+		if r.paths, err = compileGlobs(r.Paths); err != nil {
+			return fmt.Errorf("%s: paths: %w", r.Name, err)
+		}
+		for field, c := range map[string]*Condition{"when": r.When, "unless": r.Unless} {
+			if c == nil {
+				continue
+			}
+			if err := c.compile(); err != nil {
+				return fmt.Errorf("%s: %s: %w", r.Name, field, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Condition) compile() error {
+	if c.Status != "" && !contains(statusStates, c.Status) {
+		return fmt.Errorf("status must be one of %s, got %q", strings.Join(statusStates, ", "), c.Status)
+	}
+	c.args = make(map[string]*regexp.Regexp, len(c.Args))
+	for name, pattern := range c.Args {
+		re, err := compileGlob(pattern)
+		if err != nil {
+			return fmt.Errorf("args.%s: %w", name, err)
+		}
+		c.args[name] = re
+	}
+	return nil
+}
+
+func compileGlobs(patterns []string) ([]*regexp.Regexp, error) {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := compileGlob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+// compileGlob converts a glob to an anchored regular expression. "**" matches
+// any characters, "*" any characters except "/", and "?" a single character
+// except "/".
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				i++
+				// "**/" also matches no directories at all
+				if i+1 < len(runes) && runes[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+func matchAny(globs []*regexp.Regexp, value string) bool {
+	for _, re := range globs {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
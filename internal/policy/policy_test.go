@@ -0,0 +1,338 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `
+default: allow
+rules:
+  - name: protect-workflows
+    effect: deny
+    tools: [create_or_update_file, delete_file, push_files]
+    paths: [".github/**"]
+    message: Workflow files are managed by the platform team
+  - name: protect-main
+    effect: deny
+    tools: [create_or_update_file, push_files]
+    repos: ["octo-org/*"]
+    branches: [main, "release/*"]
+  - name: merge-when-green
+    effect: deny
+    tools: [merge_pull_request]
+    unless:
+      status: success
+      args:
+        merge_method: squash
+  - effect: deny
+    tools: ["delete_*"]
+    repos: ["octo-org/infra"]
+`
+
+func TestGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		match   bool
+	}{
+		{"octo-org/*", "octo-org/hello", true},
+		{"octo-org/*", "other/hello", false},
+		{"release/*", "release/v1", true},
+		{"release/*", "release/v1/hotfix", false},
+		{".github/**", ".github/workflows/ci.yml", true},
+		{"**/*.md", "README.md", true},
+		{"**/*.md", "docs/guide/intro.md", true},
+		{"**/*.md", "docs/guide/intro.mdx", false},
+		{"delete_?ile", "delete_file", true},
+		{"a.b", "axb", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.pattern+" "+tc.value, func(t *testing.T) {
+			re, err := compileGlob(tc.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tc.match, re.MatchString(tc.value))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+	assert.Equal(t, Allow, p.Default)
+	require.Len(t, p.Rules, 4)
+	assert.Equal(t, "rule 4", p.Rules[3].Name)
+
+	json, err := Parse([]byte(`{"default": "deny", "rules": [{"effect": "allow", "tools": ["get_*"]}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, Deny, json.Default)
+
+	for name, tc := range map[string]struct {
+		policy string
+		err    string
+	}{
+		"empty":          {"", "policy is empty"},
+		"bad default":    {"default: maybe", `default must be "allow" or "deny"`},
+		"bad effect":     {"rules: [{name: r, effect: permit}]", `r: effect must be "allow" or "deny"`},
+		"bad status":     {"rules: [{name: r, effect: deny, when: {status: green}}]", "r: when: status must be one of"},
+		"unknown field":  {"rules: [{name: r, effect: deny, tool: [x]}]", "field tool not found"},
+		"empty glob":     {`rules: [{name: r, effect: deny, repos: [""]}]`, "r: repos: empty pattern"},
+		"empty arg glob": {`rules: [{name: r, effect: deny, unless: {args: {a: ""}}}]`, "r: unless: args.a: empty pattern"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.policy))
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+
+	var statusCalls int
+	status := "success"
+	engine := NewEngine(p, func(_ context.Context, owner, repo string, args map[string]any) (string, error) {
+		statusCalls++
+		if owner == "broken" {
+			return "", errors.New("API unavailable")
+		}
+		assert.Equal(t, float64(7), args["pullNumber"])
+		return status, nil
+	}, nil)
+
+	tests := []struct {
+		name   string
+		tool   string
+		args   map[string]any
+		status string
+		rule   string
+		reason string
+	}{
+		{
+			name: "read tools are allowed by default",
+			tool: "get_file_contents",
+			args: map[string]any{"owner": "octo-org", "repo": "hello", "path": ".github/workflows/ci.yml"},
+		},
+		{
+			name:   "writing a workflow file is denied",
+			tool:   "create_or_update_file",
+			args:   map[string]any{"owner": "someone", "repo": "hello", "path": ".github/workflows/ci.yml", "branch": "feature"},
+			rule:   "protect-workflows",
+			reason: "Workflow files are managed by the platform team",
+		},
+		{
+			name: "pushing any workflow file among others is denied",
+			tool: "push_files",
+			args: map[string]any{"owner": "someone", "repo": "hello", "branch": "feature", "files": []any{
+				map[string]any{"path": "README.md"},
+				map[string]any{"path": ".github/dependabot.yml"},
+			}},
+			rule: "protect-workflows",
+		},
+		{
+			name: "writing to a protected branch is denied",
+			tool: "create_or_update_file",
+			args: map[string]any{"owner": "octo-org", "repo": "hello", "path": "README.md", "branch": "release/v2"},
+			rule: "protect-main",
+		},
+		{
+			name: "repositories are matched case-insensitively",
+			tool: "create_or_update_file",
+			args: map[string]any{"owner": "Octo-Org", "repo": "Hello", "path": "README.md", "branch": "main"},
+			rule: "protect-main",
+		},
+		{
+			name: "writing to a feature branch is allowed",
+			tool: "create_or_update_file",
+			args: map[string]any{"owner": "octo-org", "repo": "hello", "path": "README.md", "branch": "feature"},
+		},
+		{
+			name:   "squash merging a green pull request is allowed",
+			tool:   "merge_pull_request",
+			args:   map[string]any{"owner": "octo-org", "repo": "hello", "pullNumber": float64(7), "merge_method": "squash"},
+			status: "success",
+		},
+		{
+			name:   "merging a failing pull request is denied",
+			tool:   "merge_pull_request",
+			args:   map[string]any{"owner": "octo-org", "repo": "hello", "pullNumber": float64(7), "merge_method": "squash"},
+			status: "failure",
+			rule:   "merge-when-green",
+		},
+		{
+			name:   "merge commits are denied even when green",
+			tool:   "merge_pull_request",
+			args:   map[string]any{"owner": "octo-org", "repo": "hello", "pullNumber": float64(7), "merge_method": "merge"},
+			status: "success",
+			rule:   "merge-when-green",
+		},
+		{
+			name:   "merging when the status cannot be checked is denied",
+			tool:   "merge_pull_request",
+			args:   map[string]any{"owner": "broken", "repo": "hello", "pullNumber": float64(7), "merge_method": "squash"},
+			rule:   "merge-when-green",
+			reason: `denied by policy rule "merge-when-green" (commit status could not be checked: API unavailable)`,
+		},
+		{
+			name: "unnamed rules are reported by position",
+			tool: "delete_file",
+			args: map[string]any{"owner": "octo-org", "repo": "infra", "path": "main.tf", "branch": "feature"},
+			rule: "rule 4",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			status = tc.status
+			denial := engine.Evaluate(context.Background(), tc.tool, tc.args)
+			if tc.rule == "" {
+				assert.Nil(t, denial)
+				return
+			}
+			require.NotNil(t, denial)
+			assert.Equal(t, DenialError, denial.Error)
+			assert.Equal(t, tc.tool, denial.Tool)
+			assert.Equal(t, tc.rule, denial.Rule)
+			if tc.reason != "" {
+				assert.Equal(t, tc.reason, denial.Reason)
+			}
+		})
+	}
+
+	t.Run("status is only looked up for rules that need it", func(t *testing.T) {
+		statusCalls = 0
+		engine.Evaluate(context.Background(), "create_issue", map[string]any{"owner": "octo-org", "repo": "hello"})
+		assert.Zero(t, statusCalls)
+	})
+}
+
+func TestEvaluateAllowRules(t *testing.T) {
+	p, err := Parse([]byte(`
+default: deny
+rules:
+  - name: docs-only
+    effect: allow
+    tools: [push_files]
+    paths: ["docs/**"]
+`))
+	require.NoError(t, err)
+	engine := NewEngine(p, nil, nil)
+
+	files := func(paths ...string) map[string]any {
+		var out []any
+		for _, path := range paths {
+			out = append(out, map[string]any{"path": path})
+		}
+		return map[string]any{"owner": "o", "repo": "r", "files": out}
+	}
+
+	assert.Nil(t, engine.Evaluate(context.Background(), "push_files", files("docs/a.md", "docs/b/c.md")))
+
+	denial := engine.Evaluate(context.Background(), "push_files", files("docs/a.md", "main.go"))
+	require.NotNil(t, denial, "allow rules must match every path")
+	assert.Empty(t, denial.Rule)
+	assert.Contains(t, denial.Reason, "default is deny")
+
+	assert.NotNil(t, engine.Evaluate(context.Background(), "get_me", nil))
+}
+
+func TestEvaluatePullRequestBase(t *testing.T) {
+	p, err := Parse([]byte(`
+rules:
+  - name: protect-main
+    effect: deny
+    tools: [merge_pull_request, update_pull_request_branch]
+    branches: [main]
+  - name: releases
+    effect: allow
+    tools: [update_pull_request_branch]
+    branches: ["release/*"]
+default: deny
+`))
+	require.NoError(t, err)
+
+	var baseCalls int
+	engine := NewEngine(p, nil, func(_ context.Context, owner, _ string, number int) (string, error) {
+		baseCalls++
+		if owner == "broken" {
+			return "", errors.New("API unavailable")
+		}
+		return map[int]string{1: "main", 2: "feature", 3: "release/v1"}[number], nil
+	})
+	pr := func(owner string, number int) map[string]any {
+		return map[string]any{"owner": owner, "repo": "hello", "pullNumber": float64(number)}
+	}
+
+	denial := engine.Evaluate(context.Background(), "merge_pull_request", pr("octo-org", 1))
+	require.NotNil(t, denial, "merging into main is denied")
+	assert.Equal(t, "protect-main", denial.Rule)
+
+	denial = engine.Evaluate(context.Background(), "merge_pull_request", pr("octo-org", 2))
+	require.NotNil(t, denial)
+	assert.Empty(t, denial.Rule, "merging into a feature branch falls through to the default")
+
+	assert.Nil(t, engine.Evaluate(context.Background(), "update_pull_request_branch", pr("octo-org", 3)))
+
+	baseCalls = 0
+	denial = engine.Evaluate(context.Background(), "update_pull_request_branch", pr("broken", 3))
+	require.NotNil(t, denial, "an unknown base branch matches deny rules")
+	assert.Equal(t, "protect-main", denial.Rule)
+	assert.Equal(t, `denied by policy rule "protect-main" (pull request base branch could not be checked: API unavailable)`, denial.Reason)
+	assert.Equal(t, 1, baseCalls, "the base branch is looked up once per call")
+
+	// Without a lookup, the branch of a pull request is unknown too
+	denial = NewEngine(p, nil, nil).Evaluate(context.Background(), "merge_pull_request", pr("octo-org", 2))
+	require.NotNil(t, denial)
+	assert.Equal(t, "protect-main", denial.Rule)
+
+	baseCalls = 0
+	args := pr("octo-org", 1)
+	args["base"] = "feature"
+	assert.Equal(t, "", engine.Evaluate(context.Background(), "merge_pull_request", args).Rule)
+	assert.Zero(t, baseCalls, "branch arguments are used as given")
+}
+
+func TestMiddleware(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+	engine := NewEngine(p, nil, nil)
+
+	called := false
+	handler := engine.Middleware(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	var request mcp.CallToolRequest
+	request.Params.Name = "delete_file"
+	request.Params.Arguments = map[string]any{"owner": "o", "repo": "r", "path": ".github/CODEOWNERS", "branch": "main"}
+	result, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	assert.False(t, called)
+	require.True(t, result.IsError)
+
+	var denial Denial
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &denial))
+	assert.Equal(t, Denial{
+		Error:     DenialError,
+		Tool:      "delete_file",
+		Repo:      "o/r",
+		Rule:      "protect-workflows",
+		RuleIndex: 1,
+		Reason:    "Workflow files are managed by the platform team",
+	}, denial)
+
+	request.Params.Arguments = map[string]any{"owner": "o", "repo": "r", "path": "README.md", "branch": "main"}
+	result, err = handler(context.Background(), request)
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.False(t, result.IsError)
+}
@@ -0,0 +1,154 @@
+package scenario
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type segmentKind int
+
+const (
+	memberSegment segmentKind = iota
+	indexSegment
+	wildcardSegment
+)
+
+// segment is one step of a JSONPath.
+type segment struct {
+	kind  segmentKind
+	name  string
+	index int
+}
+
+// Path is a parsed JSONPath. The subset supported is the root $, members
+// .name and ['name'], array indexes [n], negative from the end, and the
+// wildcards .* and [*], which select every member or element.
+type Path struct {
+	source   string
+	segments []segment
+	wildcard bool
+}
+
+// ParsePath parses a JSONPath.
+func ParsePath(path string) (*Path, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(path), "$")
+	if !ok {
+		return nil, fmt.Errorf("invalid path %q: must start with $", path)
+	}
+	p := &Path{source: path}
+	for rest != "" {
+		var seg segment
+		var err error
+		switch rest[0] {
+		case '.':
+			seg, rest, err = parseMember(rest[1:])
+		case '[':
+			seg, rest, err = parseBracket(rest[1:])
+		default:
+			err = fmt.Errorf("unexpected %q", rest[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %q: %w", path, err)
+		}
+		p.wildcard = p.wildcard || seg.kind == wildcardSegment
+		p.segments = append(p.segments, seg)
+	}
+	return p, nil
+}
+
+func parseMember(s string) (segment, string, error) {
+	if strings.HasPrefix(s, "*") {
+		return segment{kind: wildcardSegment}, s[1:], nil
+	}
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		end = len(s)
+	}
+	if end == 0 {
+		return segment{}, "", fmt.Errorf("empty member name")
+	}
+	return segment{kind: memberSegment, name: s[:end]}, s[end:], nil
+}
+
+func parseBracket(s string) (segment, string, error) {
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return segment{}, "", fmt.Errorf("unclosed [")
+	}
+	inner, rest := strings.TrimSpace(s[:end]), s[end+1:]
+	if inner == "*" {
+		return segment{kind: wildcardSegment}, rest, nil
+	}
+	if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+		return segment{kind: memberSegment, name: inner[1 : len(inner)-1]}, rest, nil
+	}
+	index, err := strconv.Atoi(inner)
+	if err != nil {
+		return segment{}, "", fmt.Errorf("invalid index %q", inner)
+	}
+	return segment{kind: indexSegment, index: index}, rest, nil
+}
+
+// String returns the source of the path.
+func (p *Path) String() string {
+	return p.source
+}
+
+// Select returns the value p selects in doc, a decoded JSON value. A path
+// with wildcards selects the array of every value matched, which may be
+// empty; other paths report whether the value exists.
+func (p *Path) Select(doc any) (any, bool) {
+	values := []any{doc}
+	for _, seg := range p.segments {
+		var next []any
+		for _, v := range values {
+			next = append(next, seg.apply(v)...)
+		}
+		values = next
+	}
+	if p.wildcard {
+		if values == nil {
+			values = []any{}
+		}
+		return values, true
+	}
+	if len(values) == 0 {
+		return nil, false
+	}
+	return values[0], true
+}
+
+func (seg segment) apply(v any) []any {
+	switch seg.kind {
+	case memberSegment:
+		if obj, ok := v.(map[string]any); ok {
+			if member, ok := obj[seg.name]; ok {
+				return []any{member}
+			}
+		}
+	case indexSegment:
+		if arr, ok := v.([]any); ok {
+			i := seg.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				return []any{arr[i]}
+			}
+		}
+	case wildcardSegment:
+		switch v := v.(type) {
+		case []any:
+			return v
+		case map[string]any:
+			keys := sortedKeys(v)
+			out := make([]any, 0, len(keys))
+			for _, key := range keys {
+				out = append(out, v[key])
+			}
+			return out
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package scenario
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathSelect(t *testing.T) {
+	var doc any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"content": [{"type": "text", "text": "a"}, {"type": "text", "text": "b"}],
+		"structuredContent": {"total_count": 2, "odd key": true}
+	}`), &doc))
+
+	tests := []struct {
+		path  string
+		value any
+		ok    bool
+	}{
+		{"$", doc, true},
+		{"$.content[0].text", "a", true},
+		{"$.content[-1].text", "b", true},
+		{"$['structuredContent']['odd key']", true, true},
+		{"$.structuredContent.total_count", float64(2), true},
+		{"$.content[*].text", []any{"a", "b"}, true},
+		{"$.structuredContent.*", []any{true, float64(2)}, true},
+		{"$.missing[*]", []any{}, true},
+		{"$.content[2]", nil, false},
+		{"$.missing", nil, false},
+		{"$.content.text", nil, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			p, err := ParsePath(tc.path)
+			require.NoError(t, err)
+			value, ok := p.Select(doc)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.value, value)
+		})
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	for _, path := range []string{"content", "$.", "$[0", "$[x]", "$x"} {
+		_, err := ParsePath(path)
+		assert.Error(t, err, path)
+	}
+}
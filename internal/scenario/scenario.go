@@ -0,0 +1,590 @@
+// Package scenario runs scripted sequences of MCP requests against a server
+// and checks their results, for black-box regression tests of any build of
+// the server.
+//
+// A scenario file lists steps. Each step calls a tool, reads a resource or
+// gets a prompt, then captures values of the result into variables and
+// checks assertions on it:
+//
+//	name: file round trip
+//	vars:
+//	  extension: go
+//	steps:
+//	  - name: list files
+//	    tool: get_file_list
+//	    arguments: {extension: "${extension}", per_page: 5}
+//	    capture:
+//	      first: $.content[0].json[0]
+//	    assert:
+//	      - path: $.content[0].json
+//	        length: 5
+//	  - tool: get_file_content
+//	    arguments: {path: "${first}"}
+//	    assert:
+//	      - path: $.content[0].text
+//	        contains: package
+//
+// Values are selected with JSONPath (see Path) in the JSON of the result.
+// Text content that holds JSON, such as most tool results, is also decoded
+// into a json field next to its text. A request that fails selects from
+// {"error": message}.
+//
+// "${name}" in arguments, URIs, prompt names and expected values is replaced
+// by the variable name. A string that is only a reference takes the
+// variable's value as is, so that numbers and objects keep their type.
+package scenario
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a parsed scenario file.
+type Scenario struct {
+	Name string `yaml:"name"`
+	// Vars are the initial variables, which Run can override.
+	Vars  map[string]any `yaml:"vars"`
+	Steps []Step         `yaml:"steps"`
+}
+
+// Step is a request and the checks of its result. Exactly one of Tool,
+// Resource and Prompt is set.
+type Step struct {
+	Name     string `yaml:"name"`
+	Tool     string `yaml:"tool"`
+	Resource string `yaml:"resource"`
+	Prompt   string `yaml:"prompt"`
+	// Arguments of the tool or prompt. Prompt arguments that are not
+	// strings are sent as JSON.
+	Arguments map[string]any `yaml:"arguments"`
+	// ExpectError expects the request to fail, or the tool to return an
+	// error result.
+	ExpectError bool `yaml:"expect_error"`
+	// Capture maps variable names to the paths of their values.
+	Capture map[string]string `yaml:"capture"`
+	Assert  []Assertion       `yaml:"assert"`
+
+	capture map[string]*Path
+}
+
+// Assertion checks the value at Path with every check it sets.
+type Assertion struct {
+	Path string `yaml:"path"`
+	// Equals is the expected value, compared as JSON.
+	Equals yaml.Node `yaml:"equals"`
+	// Contains is a substring of a string, an element of an array or a key
+	// of an object.
+	Contains yaml.Node `yaml:"contains"`
+	// Matches is a regular expression matching a string, or the JSON of
+	// any other value.
+	Matches string `yaml:"matches"`
+	// Exists checks whether the path selects a value.
+	Exists *bool `yaml:"exists"`
+	// Length is the length of a string, array or object.
+	Length *int `yaml:"length"`
+
+	path    *Path
+	matches *regexp.Regexp
+}
+
+// Load reads and parses the scenario file at path.
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario file: %w", err)
+	}
+	s, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scenario file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Parse parses and validates a YAML or JSON scenario.
+func Parse(data []byte) (*Scenario, error) {
+	var s Scenario
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&s); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("scenario is empty")
+		}
+		return nil, err
+	}
+	if len(s.Steps) == 0 {
+		return nil, fmt.Errorf("scenario has no steps")
+	}
+	for i := range s.Steps {
+		if err := s.Steps[i].compile(i); err != nil {
+			return nil, err
+		}
+	}
+	return &s, nil
+}
+
+func (st *Step) compile(i int) error {
+	set := 0
+	for _, target := range []string{st.Tool, st.Resource, st.Prompt} {
+		if target != "" {
+			set++
+		}
+	}
+	if st.Name == "" {
+		st.Name = fmt.Sprintf("step %d", i+1)
+		if st.Tool != "" {
+			st.Name += ": " + st.Tool
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("%s: exactly one of tool, resource and prompt is required", st.Name)
+	}
+
+	st.capture = make(map[string]*Path, len(st.Capture))
+	for name, source := range st.Capture {
+		p, err := ParsePath(source)
+		if err != nil {
+			return fmt.Errorf("%s: capture %s: %w", st.Name, name, err)
+		}
+		st.capture[name] = p
+	}
+	for j := range st.Assert {
+		a := &st.Assert[j]
+		var err error
+		if a.path, err = ParsePath(a.Path); err != nil {
+			return fmt.Errorf("%s: assertion %d: %w", st.Name, j+1, err)
+		}
+		if a.Matches != "" {
+			if a.matches, err = regexp.Compile(a.Matches); err != nil {
+				return fmt.Errorf("%s: assertion %d: invalid matches: %w", st.Name, j+1, err)
+			}
+		}
+		if a.Equals.Kind == 0 && a.Contains.Kind == 0 && a.matches == nil && a.Exists == nil && a.Length == nil {
+			return fmt.Errorf("%s: assertion %d: no check given", st.Name, j+1)
+		}
+	}
+	return nil
+}
+
+// StepResult is the outcome of a step.
+type StepResult struct {
+	Name     string
+	Duration time.Duration
+	// Failures are the failed checks of the step, if any.
+	Failures []string
+}
+
+// Passed reports whether every check of the step passed.
+func (r StepResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// Report is the outcome of a scenario.
+type Report struct {
+	Name  string
+	Steps []StepResult
+	// Vars are the variables at the end of the scenario.
+	Vars map[string]any
+}
+
+// Failed returns the number of steps that failed.
+func (r *Report) Failed() int {
+	failed := 0
+	for _, step := range r.Steps {
+		if !step.Passed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Run runs the steps of s in order with c, an initialized client. vars
+// override the variables of the scenario. A failed step does not stop the
+// scenario, though later steps using the variables it did not capture fail
+// too.
+func Run(ctx context.Context, c client.MCPClient, s *Scenario, vars map[string]any) *Report {
+	report := &Report{Name: s.Name, Vars: make(map[string]any, len(s.Vars)+len(vars))}
+	for name, value := range s.Vars {
+		report.Vars[name] = normalize(value)
+	}
+	for name, value := range vars {
+		report.Vars[name] = normalize(value)
+	}
+	for i := range s.Steps {
+		start := time.Now()
+		failures := runStep(ctx, c, &s.Steps[i], report.Vars)
+		report.Steps = append(report.Steps, StepResult{
+			Name:     s.Steps[i].Name,
+			Duration: time.Since(start),
+			Failures: failures,
+		})
+	}
+	return report
+}
+
+func runStep(ctx context.Context, c client.MCPClient, st *Step, vars map[string]any) []string {
+	doc, isError, err := send(ctx, c, st, vars)
+	var failures []string
+	switch {
+	case err != nil && doc == nil:
+		// The step could not be sent
+		return []string{err.Error()}
+	case st.ExpectError && !isError:
+		failures = append(failures, "expected an error, got a result")
+	case !st.ExpectError && isError:
+		failures = append(failures, fmt.Sprintf("request failed: %s", errorText(doc, err)))
+		return failures
+	}
+
+	for _, name := range sortedKeys(st.capture) {
+		value, ok := st.capture[name].Select(doc)
+		if !ok {
+			failures = append(failures, fmt.Sprintf("capture %s: %s selects nothing", name, st.capture[name]))
+			continue
+		}
+		vars[name] = value
+	}
+	for _, a := range st.Assert {
+		if failure := a.check(doc, vars); failure != "" {
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+// send sends the request of st and returns the document its result is
+// selected from, and whether the request failed. A non-nil error with a nil
+// document means the request could not be built.
+func send(ctx context.Context, c client.MCPClient, st *Step, vars map[string]any) (any, bool, error) {
+	expanded, err := expand(st.Arguments, vars)
+	if err != nil {
+		return nil, false, err
+	}
+	args, _ := expanded.(map[string]any)
+
+	var result any
+	switch {
+	case st.Tool != "":
+		name, err := expandString(st.Tool, vars)
+		if err != nil {
+			return nil, false, err
+		}
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = args
+		toolResult, err := c.CallTool(ctx, request)
+		if err != nil {
+			return errorDocument(err), true, err
+		}
+		doc, err := document(toolResult)
+		return doc, toolResult.IsError, err
+	case st.Resource != "":
+		uri, err := expandString(st.Resource, vars)
+		if err != nil {
+			return nil, false, err
+		}
+		request := mcp.ReadResourceRequest{}
+		request.Params.URI = uri
+		result, err = c.ReadResource(ctx, request)
+		if err != nil {
+			return errorDocument(err), true, err
+		}
+	default:
+		name, err := expandString(st.Prompt, vars)
+		if err != nil {
+			return nil, false, err
+		}
+		request := mcp.GetPromptRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = make(map[string]string, len(args))
+		for key, value := range args {
+			request.Params.Arguments[key] = format(value)
+		}
+		result, err = c.GetPrompt(ctx, request)
+		if err != nil {
+			return errorDocument(err), true, err
+		}
+	}
+	doc, err := document(result)
+	return doc, false, err
+}
+
+// document returns the JSON of result as a decoded value, with the text of
+// text content and resource contents also decoded into a json field when it
+// holds JSON.
+func document(result any) (any, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	for _, field := range []string{"content", "contents"} {
+		items, _ := doc[field].([]any)
+		for _, item := range items {
+			obj, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			text, ok := obj["text"].(string)
+			if !ok {
+				continue
+			}
+			var decoded any
+			if err := json.Unmarshal([]byte(text), &decoded); err == nil {
+				obj["json"] = decoded
+			}
+		}
+	}
+	return doc, nil
+}
+
+func errorDocument(err error) any {
+	return map[string]any{"error": err.Error()}
+}
+
+// errorText describes the error of a failed request.
+func errorText(doc any, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	// A tool error result describes the error in its text content
+	if v, ok := errorTextPath.Select(doc); ok {
+		var texts []string
+		for _, text := range v.([]any) {
+			texts = append(texts, format(text))
+		}
+		if len(texts) > 0 {
+			return strings.Join(texts, "\n")
+		}
+	}
+	return "tool returned an error"
+}
+
+func (a Assertion) check(doc any, vars map[string]any) string {
+	value, ok := a.path.Select(doc)
+	if a.Exists != nil && ok != *a.Exists {
+		if *a.Exists {
+			return fmt.Sprintf("%s: expected a value, got none", a.Path)
+		}
+		return fmt.Sprintf("%s: expected no value, got %s", a.Path, excerpt(value))
+	}
+	if !ok {
+		if a.Exists != nil {
+			return ""
+		}
+		return fmt.Sprintf("%s: selects nothing", a.Path)
+	}
+
+	if a.Equals.Kind != 0 {
+		want, err := expected(&a.Equals, vars)
+		if err != nil {
+			return fmt.Sprintf("%s: equals: %v", a.Path, err)
+		}
+		if !reflect.DeepEqual(value, want) {
+			return fmt.Sprintf("%s: expected %s, got %s", a.Path, excerpt(want), excerpt(value))
+		}
+	}
+	if a.Contains.Kind != 0 {
+		want, err := expected(&a.Contains, vars)
+		if err != nil {
+			return fmt.Sprintf("%s: contains: %v", a.Path, err)
+		}
+		if !contains(value, want) {
+			return fmt.Sprintf("%s: %s does not contain %s", a.Path, excerpt(value), excerpt(want))
+		}
+	}
+	if a.matches != nil && !a.matches.MatchString(excerpt(value)) {
+		return fmt.Sprintf("%s: %s does not match %s", a.Path, excerpt(value), a.Matches)
+	}
+	if a.Length != nil {
+		n, ok := length(value)
+		if !ok {
+			return fmt.Sprintf("%s: %s has no length", a.Path, excerpt(value))
+		}
+		if n != *a.Length {
+			return fmt.Sprintf("%s: expected length %d, got %d", a.Path, *a.Length, n)
+		}
+	}
+	return ""
+}
+
+// expected decodes an expected value and expands its variables.
+func expected(node *yaml.Node, vars map[string]any) (any, error) {
+	var v any
+	if err := node.Decode(&v); err != nil {
+		return nil, err
+	}
+	v, err := expand(v, vars)
+	if err != nil {
+		return nil, err
+	}
+	return normalize(v), nil
+}
+
+func contains(value, want any) bool {
+	switch value := value.(type) {
+	case string:
+		s, ok := want.(string)
+		return ok && strings.Contains(value, s)
+	case []any:
+		for _, element := range value {
+			if reflect.DeepEqual(element, want) {
+				return true
+			}
+		}
+	case map[string]any:
+		if key, ok := want.(string); ok {
+			_, ok := value[key]
+			return ok
+		}
+	}
+	return false
+}
+
+func length(value any) (int, bool) {
+	switch value := value.(type) {
+	case string:
+		return len([]rune(value)), true
+	case []any:
+		return len(value), true
+	case map[string]any:
+		return len(value), true
+	}
+	return 0, false
+}
+
+// errorTextPath selects the text of a tool error result.
+var errorTextPath = mustPath("$.content[*].text")
+
+var variableRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expand replaces the variable references in the strings of v.
+func expand(v any, vars map[string]any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		if m := variableRef.FindStringSubmatch(v); m != nil && m[0] == v {
+			value, ok := vars[m[1]]
+			if !ok {
+				return nil, fmt.Errorf("undefined variable %s", m[1])
+			}
+			return value, nil
+		}
+		return expandString(v, vars)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, value := range v {
+			expanded, err := expand(value, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = expanded
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, value := range v {
+			expanded, err := expand(value, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// expandString replaces the variable references in s with their values,
+// formatted as text.
+func expandString(s string, vars map[string]any) (string, error) {
+	var undefined []string
+	out := variableRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := variableRef.FindStringSubmatch(ref)[1]
+		value, ok := vars[name]
+		if !ok {
+			undefined = append(undefined, name)
+			return ref
+		}
+		return format(value)
+	})
+	if len(undefined) > 0 {
+		return "", fmt.Errorf("undefined variable %s", strings.Join(undefined, ", "))
+	}
+	return out, nil
+}
+
+// format returns strings as is, and other values as JSON.
+func format(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// maxExcerpt is the number of bytes of a value shown in a failure.
+const maxExcerpt = 200
+
+// excerpt formats v for a failure message, cut to maxExcerpt bytes.
+func excerpt(v any) string {
+	s := format(v)
+	if len(s) <= maxExcerpt {
+		return s
+	}
+	cut := maxExcerpt
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s…[%d more bytes]", s[:cut], len(s)-cut)
+}
+
+// normalize returns v as decoded from its JSON, so that values from YAML
+// compare equal to the values of results.
+func normalize(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func mustPath(path string) *Path {
+	p, err := ParsePath(path)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,164 @@
+package scenario
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testScenario = `
+name: issue round trip
+vars:
+  owner: octo-org
+steps:
+  - name: create issue
+    tool: create_issue
+    arguments: {owner: "${owner}", title: "Hello ${owner}", labels: [bug]}
+    capture:
+      number: $.content[0].json.number
+    assert:
+      - path: $.content[0].json.title
+        equals: Hello octo-org
+      - path: $.content[0].json.labels
+        contains: bug
+        length: 1
+  - name: get issue
+    tool: get_issue
+    arguments: {number: "${number}"}
+    assert:
+      - path: $.content[0].json.number
+        equals: 42
+      - path: $.content[0].json.body
+        exists: false
+  - name: missing issue
+    tool: get_issue
+    arguments: {number: 7}
+    expect_error: true
+    assert:
+      - path: $.content[0].text
+        matches: "^issue 7 not found$"
+  - prompt: triage
+    arguments: {number: "${number}"}
+    assert:
+      - path: $.messages[0].content.text
+        equals: Triage issue 42
+  - resource: repo://${owner}/readme
+    assert:
+      - path: $.contents[0].text
+        contains: octo
+`
+
+func newTestClient(t *testing.T) *client.Client {
+	t.Helper()
+	s := server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(false, false), server.WithPromptCapabilities(false))
+	s.AddTool(mcp.NewTool("create_issue"), func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		return mcp.NewToolResultStructuredOnly(map[string]any{"number": 42, "title": args["title"], "labels": args["labels"]}), nil
+	})
+	s.AddTool(mcp.NewTool("get_issue"), func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		number := request.GetInt("number", 0)
+		if number != 42 {
+			return mcp.NewToolResultErrorf("issue %d not found", number), nil
+		}
+		return mcp.NewToolResultStructuredOnly(map[string]any{"number": number}), nil
+	})
+	s.AddPrompt(mcp.NewPrompt("triage"), func(_ context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return mcp.NewGetPromptResult("", []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("Triage issue "+request.Params.Arguments["number"])),
+		}), nil
+	})
+	s.AddResource(mcp.NewResource("repo://octo-org/readme", "readme"), func(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "# octo"}}, nil
+	})
+
+	c, err := client.NewInProcessClient(s)
+	require.NoError(t, err)
+	require.NoError(t, c.Start(context.Background()))
+	_, err = c.Initialize(context.Background(), mcp.InitializeRequest{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestRun(t *testing.T) {
+	s, err := Parse([]byte(testScenario))
+	require.NoError(t, err)
+	assert.Equal(t, "step 4", s.Steps[3].Name)
+
+	report := Run(context.Background(), newTestClient(t), s, nil)
+	for _, step := range report.Steps {
+		assert.Empty(t, step.Failures, step.Name)
+	}
+	assert.Equal(t, 0, report.Failed())
+	assert.Equal(t, float64(42), report.Vars["number"])
+
+	// Overridden variables change the calls
+	report = Run(context.Background(), newTestClient(t), s, map[string]any{"owner": "other"})
+	assert.Equal(t, 2, report.Failed())
+	assert.Equal(t, []string{"$.content[0].json.title: expected Hello octo-org, got Hello other"}, report.Steps[0].Failures)
+	require.Len(t, report.Steps[4].Failures, 1)
+	assert.Contains(t, report.Steps[4].Failures[0], "request failed")
+}
+
+func TestRunFailures(t *testing.T) {
+	s, err := Parse([]byte(`
+steps:
+  - tool: get_issue
+    arguments: {number: 7}
+  - tool: get_issue
+    arguments: {number: 42}
+    expect_error: true
+    capture:
+      title: $.content[0].json.title
+  - tool: get_issue
+    arguments: {number: "${title}"}
+  - tool: missing_tool
+    expect_error: true
+    assert:
+      - path: $.error
+        matches: missing_tool
+`))
+	require.NoError(t, err)
+
+	report := Run(context.Background(), newTestClient(t), s, nil)
+	assert.Equal(t, []string{"request failed: issue 7 not found"}, report.Steps[0].Failures)
+	assert.Equal(t, []string{
+		"expected an error, got a result",
+		"capture title: $.content[0].json.title selects nothing",
+	}, report.Steps[1].Failures)
+	assert.Equal(t, []string{"undefined variable title"}, report.Steps[2].Failures)
+	assert.Empty(t, report.Steps[3].Failures)
+	assert.Equal(t, 3, report.Failed())
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		scenario string
+		err      string
+	}{
+		{``, "scenario is empty"},
+		{`name: empty`, "scenario has no steps"},
+		{`steps: [{tool: a, prompt: b}]`, "step 1: a: exactly one of tool, resource and prompt is required"},
+		{`steps: [{tool: a, assert: [{path: $.x}]}]`, "assertion 1: no check given"},
+		{`steps: [{tool: a, assert: [{path: x, exists: true}]}]`, "must start with $"},
+		{`steps: [{tool: a, capture: {x: "$["}}]`, "capture x"},
+		{`steps: [{tool: a, assert: [{path: $, matches: "("}]}]`, "invalid matches"},
+		{`steps: [{tool: a, unknown: 1}]`, "field unknown not found"},
+	}
+	for _, tc := range tests {
+		_, err := Parse([]byte(tc.scenario))
+		assert.ErrorContains(t, err, tc.err, tc.scenario)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	assert.Equal(t, `{"a":1}`, excerpt(map[string]any{"a": 1}))
+	long := strings.Repeat("é", maxExcerpt)
+	assert.Equal(t, strings.Repeat("é", maxExcerpt/2)+"…[200 more bytes]", excerpt(long))
+}
@@ -0,0 +1,164 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys.
+const (
+	AttrMethod    = attribute.Key("mcp.method.name")
+	AttrRequestID = attribute.Key("mcp.request.id")
+	AttrSessionID = attribute.Key("mcp.session.id")
+	AttrToolName  = attribute.Key("gen_ai.tool.name")
+	AttrOwner     = attribute.Key("github.owner")
+	AttrRepo      = attribute.Key("github.repo")
+)
+
+type requestKey struct {
+	session string
+	id      string
+}
+
+// requestSpans holds the server span of every in-flight request until the
+// success or error hook ends it.
+var requestSpans sync.Map
+
+// toolSpans hands the server span of every in-flight tool call over to its
+// handler, see ToolMiddleware. It is keyed by the request's context, which
+// mcp-go passes to both the hooks and the handler.
+var toolSpans sync.Map
+
+func keyFor(ctx context.Context, id any) requestKey {
+	var session string
+	if s := server.ClientSessionFromContext(ctx); s != nil {
+		session = s.SessionID()
+	}
+	return requestKey{session: session, id: fmt.Sprint(id)}
+}
+
+// RegisterHooks adds the hooks that start and end a server span for every MCP
+// request.
+func RegisterHooks(hooks *server.Hooks) {
+	hooks.AddBeforeAny(beforeAny)
+	hooks.AddOnSuccess(onSuccess)
+	hooks.AddOnError(onError)
+}
+
+func beforeAny(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+	key := keyFor(ctx, id)
+	meta := requestMeta(message)
+
+	// Continue the client's trace when it sent one
+	parent := propagator.Extract(ctx, metaCarrier(meta))
+
+	name := string(method)
+	attrs := []attribute.KeyValue{
+		AttrMethod.String(string(method)),
+		AttrRequestID.String(key.id),
+	}
+	if key.session != "" {
+		attrs = append(attrs, AttrSessionID.String(key.session))
+	}
+	if call, ok := message.(*mcp.CallToolRequest); ok {
+		name += " " + call.Params.Name
+		attrs = append(attrs, AttrToolName.String(call.Params.Name))
+		attrs = append(attrs, repoAttributes(call.GetArguments())...)
+	}
+
+	_, span := tracer().Start(parent, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+	)
+	requestSpans.Store(key, span)
+	if _, ok := message.(*mcp.CallToolRequest); ok {
+		toolSpans.Store(ctx, span)
+	}
+}
+
+func onSuccess(ctx context.Context, id any, _ mcp.MCPMethod, _ any, result any) {
+	span, ok := takeSpan(ctx, id)
+	if !ok {
+		return
+	}
+	if r, ok := result.(*mcp.CallToolResult); ok && r != nil && r.IsError {
+		span.SetStatus(codes.Error, "tool returned an error result")
+	}
+	span.End()
+}
+
+func onError(ctx context.Context, id any, _ mcp.MCPMethod, _ any, err error) {
+	span, ok := takeSpan(ctx, id)
+	if !ok {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.End()
+}
+
+func takeSpan(ctx context.Context, id any) (trace.Span, bool) {
+	toolSpans.Delete(ctx)
+	span, ok := requestSpans.LoadAndDelete(keyFor(ctx, id))
+	if !ok {
+		return nil, false
+	}
+	return span.(trace.Span), true
+}
+
+// ToolMiddleware puts the request span started by the hooks in the handler's
+// context so that spans created while handling the call, such as those from
+// Transport, become its children. It must be the outermost tool middleware,
+// as it looks the span up by the context the hooks saw.
+func ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if span, ok := toolSpans.Load(ctx); ok {
+			ctx = trace.ContextWithSpan(ctx, span.(trace.Span))
+		}
+		return next(ctx, request)
+	}
+}
+
+// requestMeta returns the _meta of requests that carry one. Only tool calls
+// expose it in mcp-go.
+func requestMeta(message any) *mcp.Meta {
+	if call, ok := message.(*mcp.CallToolRequest); ok {
+		return call.Params.Meta
+	}
+	return nil
+}
+
+// metaCarrier exposes the string fields of meta as a propagation carrier.
+func metaCarrier(meta *mcp.Meta) propagation.MapCarrier {
+	carrier := propagation.MapCarrier{}
+	if meta == nil {
+		return carrier
+	}
+	for k, v := range meta.AdditionalFields {
+		if s, ok := v.(string); ok {
+			carrier[k] = s
+		}
+	}
+	return carrier
+}
+
+// repoAttributes returns the owner and repo attributes for tool arguments that
+// name a repository.
+func repoAttributes(args map[string]any) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if owner, ok := args["owner"].(string); ok && owner != "" {
+		attrs = append(attrs, AttrOwner.String(owner))
+	}
+	if repo, ok := args["repo"].(string); ok && repo != "" {
+		attrs = append(attrs, AttrRepo.String(repo))
+	}
+	return attrs
+}
@@ -0,0 +1,106 @@
+// Package tracing exports OpenTelemetry traces for MCP requests and the GitHub
+// API round trips they make.
+//
+// Every MCP request gets a server span, started and ended by the hooks
+// registered with RegisterHooks. A client's trace context in the request's
+// _meta.traceparent field is continued. Tool handlers run after the span is
+// started, but mcp-go does not let hooks replace the handler context, so
+// ToolMiddleware looks the span up and puts it in the context. Transport then
+// records a client span for every HTTP request made with that context.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies the spans created by this package.
+const InstrumentationName = "github.com/github/github-mcp-server"
+
+// Supported exporters.
+const (
+	ExporterNone    = "none"
+	ExporterOTLP    = "otlp"
+	ExporterConsole = "console"
+)
+
+// Config configures trace export.
+type Config struct {
+	// Exporter is one of ExporterNone, ExporterOTLP or ExporterConsole. Empty means none.
+	Exporter string
+
+	// Endpoint is the OTLP/HTTP endpoint URL, e.g. http://localhost:4318. When
+	// empty the standard OTEL_EXPORTER_OTLP_* environment variables apply.
+	Endpoint string
+
+	// ServiceName and ServiceVersion describe this process in exported spans.
+	ServiceName    string
+	ServiceVersion string
+
+	// Output receives spans from the console exporter. It must not be stdout
+	// when serving over stdio.
+	Output io.Writer
+}
+
+var propagator = propagation.TraceContext{}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// Setup installs a global tracer provider for cfg and returns a function that
+// flushes and stops it. With no exporter configured, tracing stays disabled
+// and the returned function does nothing.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			if _, err := url.Parse(cfg.Endpoint); err != nil {
+				return nil, fmt.Errorf("invalid trace endpoint: %w", err)
+			}
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		otlp, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		exporter = otlp
+	case ExporterConsole:
+		if cfg.Output == nil {
+			return nil, fmt.Errorf("console trace exporter requires an output")
+		}
+		console, err := stdouttrace.New(stdouttrace.WithWriter(cfg.Output))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create console exporter: %w", err)
+		}
+		exporter = console
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q (expected %s, %s or %s)", cfg.Exporter, ExporterNone, ExporterOTLP, ExporterConsole)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cfg.ServiceName),
+			attribute.String("service.version", cfg.ServiceVersion),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	return provider.Shutdown, nil
+}
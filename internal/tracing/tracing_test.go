@@ -0,0 +1,250 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// installInMemory installs a global tracer provider that keeps finished spans
+// in memory. The returned function restores the previous provider.
+func installInMemory() (*tracetest.InMemoryExporter, func()) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	return exporter, func() {
+		_ = provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previous)
+	}
+}
+
+// newTracedServer returns an MCP server with a get_issue tool that calls the
+// GitHub API at apiURL through a traced transport.
+func newTracedServer(t *testing.T, apiURL string) *server.MCPServer {
+	t.Helper()
+	hooks := &server.Hooks{}
+	RegisterHooks(hooks)
+	s := server.NewMCPServer("test", "0.0.1",
+		server.WithToolCapabilities(true),
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(ToolMiddleware),
+	)
+
+	client := &http.Client{Transport: NewTransport(http.DefaultTransport)}
+	s.AddTool(mcp.NewTool("get_issue"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"/repos/"+args["owner"].(string)+"/"+args["repo"].(string)+"/issues/1", nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return mcp.NewToolResultError(resp.Status), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+	return s
+}
+
+func callTool(t *testing.T, s *server.MCPServer, meta map[string]any) {
+	t.Helper()
+	params := map[string]any{
+		"name":      "get_issue",
+		"arguments": map[string]any{"owner": "octocat", "repo": "hello-world"},
+	}
+	if meta != nil {
+		params["_meta"] = meta
+	}
+	message, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": params})
+	require.NoError(t, err)
+	s.HandleMessage(context.Background(), message)
+}
+
+func attributes(stub tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range stub.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, kind trace.SpanKind) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range spans {
+		if span.SpanKind == kind {
+			return span
+		}
+	}
+	require.Failf(t, "span not found", "no %s span in %d spans", kind, len(spans))
+	return tracetest.SpanStub{}
+}
+
+func TestToolCallSpans(t *testing.T) {
+	exporter, restore := installInMemory()
+	defer restore()
+
+	status := http.StatusOK
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer api.Close()
+	s := newTracedServer(t, api.URL)
+
+	t.Run("request span with a child span per round trip", func(t *testing.T) {
+		exporter.Reset()
+		callTool(t, s, nil)
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 2)
+		root := findSpan(t, spans, trace.SpanKindServer)
+		child := findSpan(t, spans, trace.SpanKindClient)
+
+		assert.Equal(t, "tools/call get_issue", root.Name)
+		assert.False(t, root.Parent.IsValid())
+		rootAttrs := attributes(root)
+		assert.Equal(t, "get_issue", rootAttrs[AttrToolName].AsString())
+		assert.Equal(t, "octocat", rootAttrs[AttrOwner].AsString())
+		assert.Equal(t, "hello-world", rootAttrs[AttrRepo].AsString())
+		assert.Equal(t, codes.Unset, root.Status.Code)
+
+		assert.Equal(t, "GET /repos/{owner}/{repo}/issues/{id}", child.Name)
+		assert.Equal(t, root.SpanContext.SpanID(), child.Parent.SpanID())
+		assert.Equal(t, root.SpanContext.TraceID(), child.SpanContext.TraceID())
+		childAttrs := attributes(child)
+		assert.Equal(t, int64(200), childAttrs[AttrHTTPStatusCode].AsInt64())
+		assert.Equal(t, "rest", childAttrs[AttrClient].AsString())
+		assert.Equal(t, "octocat", childAttrs[AttrOwner].AsString())
+	})
+
+	t.Run("errors are recorded on both spans", func(t *testing.T) {
+		exporter.Reset()
+		status = http.StatusNotFound
+		defer func() { status = http.StatusOK }()
+		callTool(t, s, nil)
+
+		spans := exporter.GetSpans()
+		assert.Equal(t, codes.Error, findSpan(t, spans, trace.SpanKindServer).Status.Code)
+		child := findSpan(t, spans, trace.SpanKindClient)
+		assert.Equal(t, codes.Error, child.Status.Code)
+		assert.Equal(t, int64(404), attributes(child)[AttrHTTPStatusCode].AsInt64())
+	})
+
+	t.Run("continues the trace sent in _meta", func(t *testing.T) {
+		exporter.Reset()
+		traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		callTool(t, s, map[string]any{"traceparent": traceparent})
+
+		root := findSpan(t, exporter.GetSpans(), trace.SpanKindServer)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", root.SpanContext.TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", root.Parent.SpanID().String())
+	})
+}
+
+func TestToolMiddlewareLeavesMetaUntouched(t *testing.T) {
+	_, restore := installInMemory()
+	defer restore()
+
+	hooks := &server.Hooks{}
+	RegisterHooks(hooks)
+	s := server.NewMCPServer("test", "0.0.1",
+		server.WithToolCapabilities(true),
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(ToolMiddleware),
+	)
+	var meta *mcp.Meta
+	var spanValid bool
+	s.AddTool(mcp.NewTool("get_issue"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		meta = request.Params.Meta
+		spanValid = trace.SpanContextFromContext(ctx).IsValid()
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	callTool(t, s, nil)
+	assert.True(t, spanValid)
+	assert.Nil(t, meta)
+
+	callTool(t, s, map[string]any{"progressToken": "p1"})
+	require.NotNil(t, meta)
+	assert.NotContains(t, meta.AdditionalFields, "traceparent")
+
+	// The span of a finished call is not kept
+	toolSpans.Range(func(key, _ any) bool {
+		t.Errorf("span left for context %v", key)
+		return true
+	})
+}
+
+func TestTransportWithoutSpan(t *testing.T) {
+	exporter, restore := installInMemory()
+	defer restore()
+
+	api := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer api.Close()
+
+	client := &http.Client{Transport: NewTransport(http.DefaultTransport)}
+	resp, err := client.Get(api.URL + "/user")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Empty(t, exporter.GetSpans())
+}
+
+func TestURLRepoAttributes(t *testing.T) {
+	tests := []struct {
+		url    string
+		client string
+		owner  string
+	}{
+		{"https://api.github.com/repos/octocat/hello-world/issues", "rest", "octocat"},
+		{"https://ghes.example.com/api/v3/repos/octocat/hello-world", "rest", "octocat"},
+		{"https://raw.githubusercontent.com/octocat/hello-world/main/README.md", "raw", "octocat"},
+		{"https://ghes.example.com/raw/octocat/hello-world/main/README.md", "raw", "octocat"},
+		{"https://api.github.com/user", "rest", ""},
+		{"https://api.github.com/graphql", "graphql", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.url, func(t *testing.T) {
+			u, err := url.Parse(tc.url)
+			require.NoError(t, err)
+			attrs := urlRepoAttributes(u, tc.client)
+			if tc.owner == "" {
+				assert.Empty(t, attrs)
+				return
+			}
+			require.Len(t, attrs, 2)
+			assert.Equal(t, tc.owner, attrs[0].Value.AsString())
+			assert.Equal(t, "hello-world", attrs[1].Value.AsString())
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	_, err = Setup(context.Background(), Config{Exporter: "zipkin"})
+	require.ErrorContains(t, err, `unknown trace exporter "zipkin"`)
+
+	_, err = Setup(context.Background(), Config{Exporter: ExporterConsole})
+	require.Error(t, err)
+}
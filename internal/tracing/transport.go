@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/github/github-mcp-server/internal/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTP span attribute keys.
+const (
+	AttrHTTPMethod     = attribute.Key("http.request.method")
+	AttrHTTPStatusCode = attribute.Key("http.response.status_code")
+	AttrServerAddress  = attribute.Key("server.address")
+	AttrClient         = attribute.Key("github.client")
+	AttrEndpoint       = attribute.Key("github.endpoint")
+)
+
+// Transport is an http.RoundTripper that records a client span for every
+// GitHub API request. Spans are only created when the request context
+// already carries a span, so background requests do not start new traces.
+type Transport struct {
+	transport http.RoundTripper
+}
+
+// NewTransport wraps transport so that every request through it is traced.
+func NewTransport(transport http.RoundTripper) *Transport {
+	return &Transport{transport: transport}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return t.transport.RoundTrip(req)
+	}
+
+	client := metrics.Client(req.URL)
+	endpoint := metrics.Endpoint(req.URL)
+	attrs := []attribute.KeyValue{
+		AttrHTTPMethod.String(req.Method),
+		AttrServerAddress.String(req.URL.Hostname()),
+		AttrClient.String(client),
+		AttrEndpoint.String(endpoint),
+	}
+	attrs = append(attrs, urlRepoAttributes(req.URL, client)...)
+
+	name := req.Method + " " + endpoint
+	if client == "graphql" {
+		name = "POST graphql"
+	}
+	ctx, span := tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	resp, err := t.transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(AttrHTTPStatusCode.Int(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
+// urlRepoAttributes returns the owner and repo attributes of REST and raw URLs
+// that address a repository.
+func urlRepoAttributes(u *url.URL, client string) []attribute.KeyValue {
+	var segments []string
+	switch client {
+	case "rest":
+		segments = strings.Split(strings.TrimPrefix(strings.TrimPrefix(u.Path, "/api/v3"), "/"), "/")
+		if segments[0] != "repos" {
+			return nil
+		}
+		segments = segments[1:]
+	case "raw":
+		segments = strings.Split(strings.TrimPrefix(strings.TrimPrefix(u.Path, "/raw"), "/"), "/")
+	default:
+		return nil
+	}
+	if len(segments) < 2 {
+		return nil
+	}
+	return []attribute.KeyValue{AttrOwner.String(segments[0]), AttrRepo.String(segments[1])}
+}
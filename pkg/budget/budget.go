@@ -0,0 +1,265 @@
+// Package budget caps the size of tool results.
+//
+// A result whose text goes over the budget is cut, and the rest is kept on
+// the server under an opaque continuation token. The model reads it part by
+// part with the continue_result tool, instead of one large result pushing the
+// rest of the conversation out of its context.
+package budget
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Units the budget is counted in.
+const (
+	UnitChars  = "chars"
+	UnitTokens = "tokens"
+)
+
+// CharsPerToken estimates the characters in a token, for budgets in tokens.
+const CharsPerToken = 4
+
+// ContinueToolName is the name of the tool returning the next part of a cut
+// result.
+const ContinueToolName = "continue_result"
+
+// defaultMaxResults is the number of cut results kept for continuation.
+const defaultMaxResults = 100
+
+// defaultMaxBytes is the total size of the cut results kept for continuation.
+const defaultMaxBytes = 64 * 1024 * 1024
+
+// Budget cuts tool results down to a maximum number of characters.
+type Budget struct {
+	maxChars int
+
+	mu         sync.Mutex
+	maxResults int
+	maxBytes   int
+	bytes      int
+	order      *list.List
+	results    map[string]*list.Element
+}
+
+// cutResult is the full text of a result that was cut.
+type cutResult struct {
+	id   string
+	tool string
+	text string
+}
+
+// New returns a budget of size units, UnitChars or UnitTokens. It returns nil,
+// a budget leaving results whole, when size is 0.
+func New(size int, unit string) (*Budget, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("response budget must not be negative, got %d", size)
+	}
+	maxChars := size
+	switch unit {
+	case UnitChars, "":
+	case UnitTokens:
+		maxChars = size * CharsPerToken
+	default:
+		return nil, fmt.Errorf("unknown response budget unit %q, want %s or %s", unit, UnitChars, UnitTokens)
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	return &Budget{
+		maxChars:   maxChars,
+		maxResults: defaultMaxResults,
+		maxBytes:   defaultMaxBytes,
+		order:      list.New(),
+		results:    make(map[string]*list.Element),
+	}, nil
+}
+
+// Middleware cuts the text of successful results going over the budget and
+// appends a continuation token for the rest. A cut result drops its
+// structured content, which would otherwise carry the whole result past the
+// budget. Results with content other than text are left whole.
+func (b *Budget) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if b == nil {
+		return next
+	}
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil || result == nil || result.IsError || request.Params.Name == ContinueToolName {
+			return result, err
+		}
+		text, ok := resultText(result)
+		if !ok || utf8.RuneCountInString(text) <= b.maxChars {
+			return result, nil
+		}
+		id, err := b.store(request.Params.Name, text)
+		if err != nil {
+			return nil, err
+		}
+		return b.part(&cutResult{id: id, tool: request.Params.Name, text: text}, 0), nil
+	}
+}
+
+// ContinueTool returns the continue_result tool.
+func (b *Budget) ContinueTool(t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool(ContinueToolName,
+			mcp.WithDescription(t("TOOL_CONTINUE_RESULT_DESCRIPTION", "Get the next part of a tool result that was cut to fit the response budget, using the continuation token given at the end of the previous part")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_CONTINUE_RESULT_USER_TITLE", "Continue a cut result"),
+				ReadOnlyHint: boolPtr(true),
+			}),
+			mcp.WithString("token",
+				mcp.Required(),
+				mcp.Description("Continuation token from the end of the previous part"),
+			),
+		),
+		func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			token, err := request.RequireString("token")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			id, offset, ok := parseToken(token)
+			if !ok {
+				return mcp.NewToolResultError("invalid continuation token"), nil
+			}
+			cut, ok := b.load(id)
+			if !ok {
+				return mcp.NewToolResultError("continuation token expired or unknown; call the original tool again"), nil
+			}
+			return b.part(cut, offset), nil
+		}
+}
+
+// part returns the part of cut starting at byte offset, followed by a notice
+// with the token for the next part if there is one. An offset past the end or
+// inside a character, which no token given out holds, is refused.
+func (b *Budget) part(cut *cutResult, offset int) *mcp.CallToolResult {
+	if offset > len(cut.text) || (offset < len(cut.text) && !utf8.RuneStart(cut.text[offset])) {
+		return mcp.NewToolResultError("invalid continuation token")
+	}
+	rest := cut.text[offset:]
+	chunk := truncate(rest, b.maxChars)
+	end := offset + len(chunk)
+	total := utf8.RuneCountInString(cut.text)
+	from := utf8.RuneCountInString(cut.text[:offset])
+	to := from + utf8.RuneCountInString(chunk)
+
+	var notice string
+	if end < len(cut.text) {
+		notice = fmt.Sprintf("[Result of %s cut to fit the response budget: characters %d-%d of %d. Call %s with token %q for the next part.]",
+			cut.tool, from, to, total, ContinueToolName, formatToken(cut.id, end))
+	} else {
+		notice = fmt.Sprintf("[End of the result of %s: characters %d-%d of %d.]", cut.tool, from, to, total)
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{
+		mcp.NewTextContent(chunk),
+		mcp.NewTextContent(notice),
+	}}
+}
+
+// truncate returns the longest prefix of s of at most n characters, ending
+// at a line break when one falls in its last quarter.
+func truncate(s string, n int) string {
+	count := 0
+	for i := range s {
+		if count == n {
+			if nl := strings.LastIndexByte(s[:i], '\n'); nl >= 0 && utf8.RuneCountInString(s[:nl+1]) > n*3/4 {
+				return s[:nl+1]
+			}
+			return s[:i]
+		}
+		count++
+	}
+	return s
+}
+
+// resultText joins the text of result, and returns false when it has content
+// other than text.
+func resultText(result *mcp.CallToolResult) (string, bool) {
+	var parts []string
+	for _, content := range result.Content {
+		switch content := content.(type) {
+		case mcp.TextContent:
+			parts = append(parts, content.Text)
+		case mcp.EmbeddedResource:
+			resource, ok := content.Resource.(mcp.TextResourceContents)
+			if !ok {
+				return "", false
+			}
+			parts = append(parts, resource.Text)
+		default:
+			return "", false
+		}
+	}
+	return strings.Join(parts, "\n"), true
+}
+
+func (b *Budget) store(tool, text string) (string, error) {
+	var raw [16]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return "", fmt.Errorf("failed to create continuation token: %w", err)
+	}
+	id := hex.EncodeToString(raw[:])
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.results[id] = b.order.PushFront(&cutResult{id: id, tool: tool, text: text})
+	b.bytes += len(text)
+	// The newest result is kept even alone over maxBytes, so that its first
+	// token works
+	for b.order.Len() > b.maxResults || (b.order.Len() > 1 && b.bytes > b.maxBytes) {
+		oldest := b.order.Back()
+		b.order.Remove(oldest)
+		cut := oldest.Value.(*cutResult)
+		delete(b.results, cut.id)
+		b.bytes -= len(cut.text)
+	}
+	return id, nil
+}
+
+func (b *Budget) load(id string) (*cutResult, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	elem, ok := b.results[id]
+	if !ok {
+		return nil, false
+	}
+	b.order.MoveToFront(elem)
+	return elem.Value.(*cutResult), true
+}
+
+func formatToken(id string, offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id + ":" + strconv.Itoa(offset)))
+}
+
+func parseToken(token string) (string, int, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", 0, false
+	}
+	id, offset, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(offset)
+	if err != nil || n < 0 {
+		return "", 0, false
+	}
+	return id, n, true
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,193 @@
+package budget
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tokenPattern = regexp.MustCompile(`with token "([^"]+)"`)
+
+func callTool(t *testing.T, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	return result
+}
+
+func texts(result *mcp.CallToolResult) []string {
+	var texts []string
+	for _, content := range result.Content {
+		texts = append(texts, content.(mcp.TextContent).Text)
+	}
+	return texts
+}
+
+func TestNew(t *testing.T) {
+	b, err := New(0, UnitTokens)
+	require.NoError(t, err)
+	assert.Nil(t, b)
+
+	b, err = New(100, UnitTokens)
+	require.NoError(t, err)
+	assert.Equal(t, 100*CharsPerToken, b.maxChars)
+
+	_, err = New(100, "words")
+	assert.ErrorContains(t, err, `unknown response budget unit "words"`)
+	_, err = New(-1, UnitChars)
+	assert.Error(t, err)
+}
+
+func TestMiddlewareAndContinue(t *testing.T) {
+	b, err := New(10, UnitChars)
+	require.NoError(t, err)
+	text := "0123456789abcdefghij€xyz"
+	handler := b.Middleware(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(text), nil
+	})
+	_, continueHandler := b.ContinueTool(translations.NullTranslationHelper)
+
+	var parts []string
+	result := callTool(t, handler, "get_file_contents", nil)
+	for i := 0; ; i++ {
+		require.Less(t, i, 5, "too many parts")
+		require.False(t, result.IsError)
+		got := texts(result)
+		require.Len(t, got, 2)
+		parts = append(parts, got[0])
+		m := tokenPattern.FindStringSubmatch(got[1])
+		if m == nil {
+			assert.Equal(t, "[End of the result of get_file_contents: characters 20-24 of 24.]", got[1])
+			break
+		}
+		// Tokens can be used again
+		again := callTool(t, continueHandler, ContinueToolName, map[string]any{"token": m[1]})
+		result = callTool(t, continueHandler, ContinueToolName, map[string]any{"token": m[1]})
+		assert.Equal(t, texts(again), texts(result))
+	}
+	assert.Equal(t, []string{"0123456789", "abcdefghij", "€xyz"}, parts)
+	assert.Equal(t, text, strings.Join(parts, ""))
+}
+
+func TestMiddlewareLeavesResultsWhole(t *testing.T) {
+	b, err := New(5, UnitChars)
+	require.NoError(t, err)
+	results := map[string]*mcp.CallToolResult{
+		"small":      mcp.NewToolResultText("tiny"),
+		"error":      mcp.NewToolResultError("a long error message"),
+		"image":      mcp.NewToolResultImage("a long caption", "aW1hZ2U=", "image/png"),
+		"structured": mcp.NewToolResultStructured([]int{1}, "[1]"),
+	}
+	for name, want := range results {
+		handler := b.Middleware(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return want, nil
+		})
+		assert.Same(t, want, callTool(t, handler, name, nil), name)
+	}
+
+	// No budget leaves the handler as is
+	var none *Budget
+	called := false
+	handler := none.Middleware(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText(strings.Repeat("x", 100)), nil
+	})
+	assert.Len(t, texts(callTool(t, handler, "big", nil)), 1)
+	assert.True(t, called)
+}
+
+func TestMiddlewareCutsStructuredResults(t *testing.T) {
+	b, err := New(10, UnitChars)
+	require.NoError(t, err)
+	handler := b.Middleware(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultStructured(map[string]any{"login": "octocat"}, `{"login":"octocat"}`), nil
+	})
+
+	result := callTool(t, handler, "get_me", nil)
+	require.False(t, result.IsError)
+	assert.Nil(t, result.StructuredContent)
+	got := texts(result)
+	require.Len(t, got, 2)
+	assert.Equal(t, `{"login":"`, got[0])
+	assert.Regexp(t, tokenPattern, got[1])
+}
+
+func TestTruncatePrefersLineBreaks(t *testing.T) {
+	assert.Equal(t, "line one\n", truncate("line one\nline two\n", 10))
+	// A break early in the part is not worth the shorter part
+	assert.Equal(t, "a\nbcdefghi", truncate("a\nbcdefghijkl", 10))
+	assert.Equal(t, "short", truncate("short", 10))
+}
+
+func TestContinueToolErrors(t *testing.T) {
+	b, err := New(10, UnitChars)
+	require.NoError(t, err)
+	tool, handler := b.ContinueTool(translations.NullTranslationHelper)
+	assert.Equal(t, ContinueToolName, tool.Name)
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+
+	result := callTool(t, handler, ContinueToolName, map[string]any{"token": "not a token"})
+	assert.True(t, result.IsError)
+	result = callTool(t, handler, ContinueToolName, map[string]any{"token": formatToken("unknown", 0)})
+	assert.True(t, result.IsError)
+	assert.Contains(t, texts(result)[0], "expired or unknown")
+
+	// The oldest results are dropped
+	b.maxResults = 1
+	first, err := b.store("a", "first")
+	require.NoError(t, err)
+	_, err = b.store("b", "second")
+	require.NoError(t, err)
+	_, ok := b.load(first)
+	assert.False(t, ok)
+
+	// Offsets past the end or inside a character are refused
+	id, err := b.store("c", "€uro")
+	require.NoError(t, err)
+	for _, offset := range []int{1, 2, 7} {
+		result = callTool(t, handler, ContinueToolName, map[string]any{"token": formatToken(id, offset)})
+		assert.True(t, result.IsError, "offset %d", offset)
+		assert.Equal(t, "invalid continuation token", texts(result)[0])
+	}
+	result = callTool(t, handler, ContinueToolName, map[string]any{"token": formatToken(id, 3)})
+	assert.False(t, result.IsError)
+	assert.Equal(t, "uro", texts(result)[0])
+}
+
+func TestStoreCapsTotalBytes(t *testing.T) {
+	b, err := New(10, UnitChars)
+	require.NoError(t, err)
+	b.maxBytes = 10
+
+	first, err := b.store("a", "123456")
+	require.NoError(t, err)
+	second, err := b.store("b", "7890")
+	require.NoError(t, err)
+	third, err := b.store("c", "abc")
+	require.NoError(t, err)
+
+	_, ok := b.load(first)
+	assert.False(t, ok)
+	for _, id := range []string{second, third} {
+		_, ok = b.load(id)
+		assert.True(t, ok)
+	}
+	assert.Equal(t, 7, b.bytes)
+
+	// The newest result is kept even when it alone goes over the cap
+	large, err := b.store("d", strings.Repeat("x", 20))
+	require.NoError(t, err)
+	_, ok = b.load(large)
+	assert.True(t, ok)
+	assert.Equal(t, 1, b.order.Len())
+	assert.Equal(t, 20, b.bytes)
+}
@@ -3,7 +3,10 @@ package buffer
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"net/http"
+	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -26,44 +29,169 @@ import (
 // The function uses a ring buffer to efficiently store only the last maxJobLogLines lines.
 // If the response contains more lines than maxJobLogLines, only the most recent lines are kept.
 func ProcessResponseAsRingBufferToEnd(httpResp *http.Response, maxJobLogLines int) (string, int, *http.Response, error) {
-	lines := make([]string, maxJobLogLines)
-	validLines := make([]bool, maxJobLogLines)
-	totalLines := 0
-	writeIndex := 0
+	result, err := Process(httpResp.Body, Options{TailLines: maxJobLogLines})
+	if err != nil {
+		return "", 0, httpResp, err
+	}
+	return result.String(), result.TotalLines, httpResp, nil
+}
+
+// Options selects the lines of a log kept by Process. The lines kept are
+// those of every mode set: the first HeadLines, the last TailLines, and the
+// lines matching Grep with ContextLines lines around each match.
+type Options struct {
+	// HeadLines is the number of lines to keep from the start.
+	HeadLines int
+	// TailLines is the number of lines to keep from the end.
+	TailLines int
+	// Grep selects the lines matching it, with ContextLines lines of context
+	// before and after each.
+	Grep         *regexp.Regexp
+	ContextLines int
+	// MaxMatchLines caps the lines kept by Grep, context included, 0 for no
+	// cap. Later matches are still counted.
+	MaxMatchLines int
+	// StartLine and EndLine, numbered from 1 and inclusive, restrict every
+	// mode to a range of lines. 0 leaves the range open on that side. When no
+	// mode is set, the lines of the range are kept, up to MaxMatchLines.
+	StartLine int
+	EndLine   int
+}
+
+// Line is a line of a log and its number, counted from 1.
+type Line struct {
+	Number int
+	Text   string
+}
+
+// Result holds the lines kept by Process.
+type Result struct {
+	Head    []Line
+	Matches []Line
+	Tail    []Line
+	// TotalLines is the number of lines read, in and out of the range.
+	TotalLines int
+	// MatchCount is the number of lines matching Grep.
+	MatchCount int
+	// Truncated reports whether lines selected by Grep, or of the range,
+	// were dropped to stay under MaxMatchLines.
+	Truncated bool
+}
+
+// Process reads r line by line and keeps the lines opts selects, in one pass.
+// Memory is bounded by the lines kept, however long the log.
+func Process(r io.Reader, opts Options) (*Result, error) {
+	rangeOnly := opts.Grep == nil && opts.HeadLines == 0 && opts.TailLines == 0
+
+	result := &Result{}
+	var tail []Line
+	tailNext := 0
+	var before []Line
+	after := 0
+
+	addMatch := func(line Line) {
+		if opts.MaxMatchLines > 0 && len(result.Matches) >= opts.MaxMatchLines {
+			result.Truncated = true
+			return
+		}
+		result.Matches = append(result.Matches, line)
+	}
 
-	scanner := bufio.NewScanner(httpResp.Body)
+	scanner := bufio.NewScanner(r)
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 
 	for scanner.Scan() {
-		line := scanner.Text()
-		totalLines++
+		result.TotalLines++
+		n := result.TotalLines
+		if n < opts.StartLine || (opts.EndLine > 0 && n > opts.EndLine) {
+			continue
+		}
+		line := Line{Number: n, Text: scanner.Text()}
+
+		switch {
+		case rangeOnly && opts.MaxMatchLines > 0 && len(result.Head) >= opts.MaxMatchLines:
+			result.Truncated = true
+		case rangeOnly || len(result.Head) < opts.HeadLines:
+			result.Head = append(result.Head, line)
+		}
 
-		lines[writeIndex] = line
-		validLines[writeIndex] = true
-		writeIndex = (writeIndex + 1) % maxJobLogLines
+		if opts.TailLines > 0 {
+			if len(tail) < opts.TailLines {
+				tail = append(tail, line)
+			} else {
+				tail[tailNext] = line
+			}
+			tailNext = (tailNext + 1) % opts.TailLines
+		}
+
+		if opts.Grep == nil {
+			continue
+		}
+		switch {
+		case opts.Grep.MatchString(line.Text):
+			result.MatchCount++
+			for _, b := range before {
+				addMatch(b)
+			}
+			before = before[:0]
+			addMatch(line)
+			after = opts.ContextLines
+		case after > 0:
+			addMatch(line)
+			after--
+		case opts.ContextLines > 0:
+			before = append(before, line)
+			if len(before) > opts.ContextLines {
+				before = before[1:]
+			}
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return "", 0, httpResp, fmt.Errorf("failed to read log content: %w", err)
+		return nil, fmt.Errorf("failed to read log content: %w", err)
 	}
 
-	var result []string
-	linesInBuffer := totalLines
-	if linesInBuffer > maxJobLogLines {
-		linesInBuffer = maxJobLogLines
+	if len(tail) == opts.TailLines && tailNext > 0 {
+		tail = append(tail[tailNext:], tail[:tailNext]...)
 	}
+	result.Tail = tail
+	return result, nil
+}
 
-	startIndex := 0
-	if totalLines > maxJobLogLines {
-		startIndex = writeIndex
-	}
+// Lines returns the lines kept, in order and without duplicates.
+func (r *Result) Lines() []Line {
+	all := make([]Line, 0, len(r.Head)+len(r.Matches)+len(r.Tail))
+	all = append(all, r.Head...)
+	all = append(all, r.Matches...)
+	all = append(all, r.Tail...)
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Number < all[j].Number
+	})
 
-	for i := 0; i < linesInBuffer; i++ {
-		idx := (startIndex + i) % maxJobLogLines
-		if validLines[idx] {
-			result = append(result, lines[idx])
+	lines := all[:0]
+	for _, line := range all {
+		if len(lines) > 0 && lines[len(lines)-1].Number == line.Number {
+			continue
 		}
+		lines = append(lines, line)
 	}
+	return lines
+}
 
-	return strings.Join(result, "\n"), totalLines, httpResp, nil
+// String returns the lines kept, separated by newlines, with a marker where
+// lines were left out between them.
+func (r *Result) String() string {
+	var b strings.Builder
+	prev := 0
+	for i, line := range r.Lines() {
+		if i > 0 {
+			b.WriteByte('\n')
+			if skipped := line.Number - prev - 1; skipped > 0 {
+				fmt.Fprintf(&b, "... %d lines omitted ...\n", skipped)
+			}
+		}
+		b.WriteString(line.Text)
+		prev = line.Number
+	}
+	return b.String()
 }
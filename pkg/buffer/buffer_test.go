@@ -0,0 +1,159 @@
+package buffer
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// numberedLog returns a log of n lines, "line 1" to "line n".
+func numberedLog(n int) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func numbers(lines []Line) []int {
+	out := make([]int, len(lines))
+	for i, line := range lines {
+		out[i] = line.Number
+	}
+	return out
+}
+
+func TestProcessResponseAsRingBufferToEnd(t *testing.T) {
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader(numberedLog(10)))}
+	content, total, _, err := ProcessResponseAsRingBufferToEnd(resp, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 10, total)
+	assert.Equal(t, "line 8\nline 9\nline 10", content)
+}
+
+func TestProcess(t *testing.T) {
+	log := strings.Join([]string{
+		"setup",           // 1
+		"ok",              // 2
+		"ok",              // 3
+		"--- FAIL: TestA", // 4
+		"want 1, got 2",   // 5
+		"ok",              // 6
+		"ok",              // 7
+		"ok",              // 8
+		"--- FAIL: TestB", // 9
+		"ok",              // 10
+		"done",            // 11
+	}, "\n")
+
+	tests := []struct {
+		name      string
+		opts      Options
+		want      []int
+		matches   int
+		truncated bool
+	}{
+		{
+			name: "tail",
+			opts: Options{TailLines: 2},
+			want: []int{10, 11},
+		},
+		{
+			name: "tail longer than log",
+			opts: Options{TailLines: 20},
+			want: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+		},
+		{
+			name: "head and tail",
+			opts: Options{HeadLines: 2, TailLines: 2},
+			want: []int{1, 2, 10, 11},
+		},
+		{
+			name:    "grep",
+			opts:    Options{Grep: regexp.MustCompile(`FAIL`)},
+			want:    []int{4, 9},
+			matches: 2,
+		},
+		{
+			name:    "grep with context",
+			opts:    Options{Grep: regexp.MustCompile(`FAIL`), ContextLines: 1},
+			want:    []int{3, 4, 5, 8, 9, 10},
+			matches: 2,
+		},
+		{
+			name:    "grep with overlapping context",
+			opts:    Options{Grep: regexp.MustCompile(`FAIL`), ContextLines: 3},
+			want:    []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+			matches: 2,
+		},
+		{
+			name:      "grep capped",
+			opts:      Options{Grep: regexp.MustCompile(`FAIL`), ContextLines: 1, MaxMatchLines: 4},
+			want:      []int{3, 4, 5, 8},
+			matches:   2,
+			truncated: true,
+		},
+		{
+			name:    "grep and tail",
+			opts:    Options{Grep: regexp.MustCompile(`FAIL: TestA`), TailLines: 1},
+			want:    []int{4, 11},
+			matches: 1,
+		},
+		{
+			name: "range",
+			opts: Options{StartLine: 4, EndLine: 6},
+			want: []int{4, 5, 6},
+		},
+		{
+			name:      "range capped",
+			opts:      Options{StartLine: 4, MaxMatchLines: 2},
+			want:      []int{4, 5},
+			truncated: true,
+		},
+		{
+			name: "tail of range",
+			opts: Options{EndLine: 5, TailLines: 2},
+			want: []int{4, 5},
+		},
+		{
+			name:    "grep in range",
+			opts:    Options{Grep: regexp.MustCompile(`FAIL`), StartLine: 6},
+			want:    []int{9},
+			matches: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Process(strings.NewReader(log), tc.opts)
+			require.NoError(t, err)
+			assert.Equal(t, 11, result.TotalLines)
+			assert.Equal(t, tc.want, numbers(result.Lines()))
+			assert.Equal(t, tc.matches, result.MatchCount)
+			assert.Equal(t, tc.truncated, result.Truncated)
+		})
+	}
+}
+
+func TestResultString(t *testing.T) {
+	result, err := Process(strings.NewReader(numberedLog(10)), Options{HeadLines: 2, Grep: regexp.MustCompile(`^line 5$`), TailLines: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\nline 2\n... 2 lines omitted ...\nline 5\n... 4 lines omitted ...\nline 10", result.String())
+}
+
+func TestProcessBoundedMemory(t *testing.T) {
+	// A long log keeps only the lines selected
+	log := strings.Repeat("noise\n", 100000) + "error: boom\n" + strings.Repeat("noise\n", 100000)
+	result, err := Process(strings.NewReader(log), Options{HeadLines: 5, TailLines: 5, Grep: regexp.MustCompile(`error`), ContextLines: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 200001, result.TotalLines)
+	assert.Len(t, result.Head, 5)
+	assert.Len(t, result.Tail, 5)
+	assert.Equal(t, []int{99999, 100000, 100001, 100002, 100003}, numbers(result.Matches))
+}
@@ -0,0 +1,306 @@
+// Package cassette records GitHub API traffic to disk and replays it without
+// network access, so that agent sessions can be reproduced deterministically.
+//
+// A cassette is a directory holding one JSON Lines file per client (for example
+// rest.jsonl and graphql.jsonl), each line being a single request/response
+// interaction. Credentials are redacted before anything is written.
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	// Redacted replaces any credential removed from a recorded interaction.
+	Redacted = "REDACTED"
+
+	bodyEncodingBase64 = "base64"
+)
+
+// sensitiveHeaders are dropped from recorded requests and responses.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"Proxy-Authorization",
+}
+
+// tokenPattern matches GitHub token formats that could leak through URLs or bodies.
+var tokenPattern = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})`)
+
+// Interaction is a single recorded request and the response it received.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded form of an http.Request.
+type Request struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// Response is the recorded form of an http.Response.
+type Response struct {
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// RedactString removes GitHub tokens from s.
+func RedactString(s string) string {
+	return tokenPattern.ReplaceAllString(s, Redacted)
+}
+
+func redactHeader(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for name, values := range h {
+		redacted := make([]string, len(values))
+		for i, v := range values {
+			redacted[i] = RedactString(v)
+		}
+		out[name] = redacted
+	}
+	for _, name := range sensitiveHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, Redacted)
+		}
+	}
+	return out
+}
+
+// encodeBody stores text bodies verbatim (after redaction) so cassettes stay
+// readable, and falls back to base64 for binary content such as log archives.
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return RedactString(string(body)), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), bodyEncodingBase64
+}
+
+func decodeBody(body, encoding string) ([]byte, error) {
+	if encoding == bodyEncodingBase64 {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
+
+// readBody drains and replaces the body so that it can still be consumed downstream.
+func readBody(body io.ReadCloser) ([]byte, io.ReadCloser, error) {
+	if body == nil || body == http.NoBody {
+		return nil, body, nil
+	}
+	data, err := io.ReadAll(body)
+	_ = body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func cassettePath(dir, name string) string {
+	return filepath.Join(dir, name+".jsonl")
+}
+
+// Recorder is an http.RoundTripper that forwards requests to the underlying
+// transport and appends every interaction to a cassette file.
+type Recorder struct {
+	transport http.RoundTripper
+
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewRecorder creates a Recorder writing to <dir>/<name>.jsonl, truncating
+// any previous recording with the same name.
+func NewRecorder(dir, name string, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	file, err := os.OpenFile(cassettePath(dir, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cassette file: %w", err)
+	}
+	return &Recorder{
+		transport: transport,
+		file:      file,
+		enc:       json.NewEncoder(file),
+	}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, body, err := readBody(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if body != nil {
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, body, err := readBody(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = body
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    RedactString(req.URL.String()),
+			Header: redactHeader(req.Header),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+		},
+	}
+	interaction.Request.Body, interaction.Request.BodyEncoding = encodeBody(reqBody)
+	interaction.Response.Body, interaction.Response.BodyEncoding = encodeBody(respBody)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.enc.Encode(interaction); err != nil {
+		return nil, fmt.Errorf("failed to record interaction: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Close flushes and closes the cassette file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// Replayer is an http.RoundTripper that serves responses from a cassette and
+// never touches the network.
+//
+// Requests are matched on method, URL, Accept header and body, the Accept
+// header telling apart the media types of one URL, such as a file's JSON
+// metadata and its raw content. When the same request was
+// recorded several times, the responses are returned in recorded order and the
+// last one is repeated once they are exhausted.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+	served       map[string]int
+}
+
+// LoadInteractions reads every interaction from <dir>/<name>.jsonl.
+func LoadInteractions(dir, name string) ([]Interaction, error) {
+	data, err := os.ReadFile(cassettePath(dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var interactions []Interaction
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var interaction Interaction
+		if err := dec.Decode(&interaction); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", name, err)
+		}
+		interactions = append(interactions, interaction)
+	}
+	return interactions, nil
+}
+
+// NewReplayer creates a Replayer serving the interactions recorded in <dir>/<name>.jsonl.
+func NewReplayer(dir, name string) (*Replayer, error) {
+	interactions, err := LoadInteractions(dir, name)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayerFromInteractions(interactions), nil
+}
+
+// NewReplayerFromInteractions creates a Replayer serving the given interactions.
+func NewReplayerFromInteractions(interactions []Interaction) *Replayer {
+	r := &Replayer{
+		interactions: make(map[string][]Interaction),
+		served:       make(map[string]int),
+	}
+	for _, interaction := range interactions {
+		request := interaction.Request
+		key := matchKey(request.Method, request.URL, request.Header.Get("Accept"), request.Body)
+		r.interactions[key] = append(r.interactions[key], interaction)
+	}
+	return r
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, _, err := readBody(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	encoded, _ := encodeBody(reqBody)
+	key := matchKey(req.Method, RedactString(req.URL.String()), req.Header.Get("Accept"), encoded)
+
+	r.mu.Lock()
+	candidates := r.interactions[key]
+	if len(candidates) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, req.URL.String())
+	}
+	idx := r.served[key]
+	if idx >= len(candidates) {
+		idx = len(candidates) - 1
+	}
+	r.served[key]++
+	interaction := candidates[idx]
+	r.mu.Unlock()
+
+	body, err := decodeBody(interaction.Response.Body, interaction.Response.BodyEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode recorded body: %w", err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// matchKey normalises JSON bodies so that insignificant whitespace differences
+// in GraphQL queries do not prevent a match.
+func matchKey(method, url, accept, body string) string {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, []byte(body)); err == nil {
+		body = compacted.String()
+	}
+	return method + " " + url + "\n" + accept + "\n" + body
+}
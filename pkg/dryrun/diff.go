@@ -0,0 +1,246 @@
+package dryrun
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+var (
+	contentsPath = regexp.MustCompile(`^(.*/repos/[^/]+/[^/]+)/contents/(.+)$`)
+	treesPath    = regexp.MustCompile(`^(.*/repos/[^/]+/[^/]+)/git/trees$`)
+)
+
+// diff returns the unified diff of the file changes req would make, and false
+// when req does not write files. Files are written through the contents API
+// and by creating trees, as push_files and delete_file do.
+func (t *Transport) diff(req *http.Request, body []byte) (string, bool, error) {
+	path := req.URL.EscapedPath()
+	if m := contentsPath.FindStringSubmatch(path); m != nil && (req.Method == http.MethodPut || req.Method == http.MethodDelete) {
+		diff, err := t.contentsDiff(req, body, m[1], m[2])
+		return diff, true, err
+	}
+	if m := treesPath.FindStringSubmatch(path); m != nil && req.Method == http.MethodPost {
+		diff, err := t.treeDiff(req, body, m[1])
+		return diff, true, err
+	}
+	return "", false, nil
+}
+
+func (t *Transport) contentsDiff(req *http.Request, body []byte, repoPath, escapedPath string) (string, error) {
+	var payload struct {
+		Content string `json:"content"`
+		Branch  string `json:"branch"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("failed to parse request body: %w", err)
+	}
+	path, err := url.PathUnescape(escapedPath)
+	if err != nil {
+		path = escapedPath
+	}
+
+	query := url.Values{}
+	if payload.Branch != "" {
+		query.Set("ref", payload.Branch)
+	}
+	old, exists, err := t.fetchContent(req, repoPath+"/contents/"+escapedPath, query)
+	if err != nil {
+		return "", err
+	}
+
+	if req.Method == http.MethodDelete {
+		return unifiedDiff(path, old, nil, exists, false), nil
+	}
+	content, err := base64.StdEncoding.DecodeString(payload.Content)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode content: %w", err)
+	}
+	return unifiedDiff(path, old, content, exists, true), nil
+}
+
+func (t *Transport) treeDiff(req *http.Request, body []byte, repoPath string) (string, error) {
+	var payload struct {
+		BaseTree string                       `json:"base_tree"`
+		Tree     []map[string]json.RawMessage `json:"tree"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("failed to parse request body: %w", err)
+	}
+
+	// Blob SHAs of the files in the base tree, looked up on first use.
+	var blobs map[string]string
+	var out strings.Builder
+	for _, entry := range payload.Tree {
+		var path string
+		_ = json.Unmarshal(entry["path"], &path)
+		content, hasContent := entry["content"]
+		deleted := string(entry["sha"]) == "null"
+		if path == "" || (!hasContent && !deleted) {
+			// Entries pointing at existing blobs by SHA cannot be diffed.
+			continue
+		}
+
+		var old []byte
+		var exists bool
+		if payload.BaseTree != "" {
+			if blobs == nil {
+				var err error
+				if blobs, err = t.fetchTree(req, repoPath, payload.BaseTree); err != nil {
+					return "", err
+				}
+			}
+			if sha, ok := blobs[path]; ok {
+				var err error
+				if old, exists, err = t.fetchContent(req, repoPath+"/git/blobs/"+sha, nil); err != nil {
+					return "", err
+				}
+			}
+		}
+
+		var newContent string
+		if hasContent {
+			_ = json.Unmarshal(content, &newContent)
+		}
+		out.WriteString(unifiedDiff(path, old, []byte(newContent), exists, !deleted))
+	}
+	return out.String(), nil
+}
+
+func (t *Transport) fetchTree(req *http.Request, repoPath, sha string) (map[string]string, error) {
+	data, _, err := t.fetch(req, repoPath+"/git/trees/"+sha, url.Values{"recursive": {"1"}})
+	if err != nil {
+		return nil, err
+	}
+	var tree struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+			SHA  string `json:"sha"`
+		} `json:"tree"`
+	}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse base tree: %w", err)
+	}
+	blobs := make(map[string]string, len(tree.Tree))
+	for _, entry := range tree.Tree {
+		if entry.Type == "blob" {
+			blobs[entry.Path] = entry.SHA
+		}
+	}
+	return blobs, nil
+}
+
+// fetchContent returns the decoded content of the file or blob behind path,
+// and false when it does not exist.
+func (t *Transport) fetchContent(req *http.Request, path string, query url.Values) ([]byte, bool, error) {
+	data, exists, err := t.fetch(req, path, query)
+	if err != nil || !exists {
+		return nil, exists, err
+	}
+	var file struct {
+		Type     string `json:"type"`
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		// Directories are listed as arrays
+		return nil, false, fmt.Errorf("path is not a file")
+	}
+	if file.Encoding != "base64" {
+		return nil, false, fmt.Errorf("the current content is too large to diff")
+	}
+	content, err := base64.StdEncoding.DecodeString(file.Content)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decode current content: %w", err)
+	}
+	return content, true, nil
+}
+
+// fetch reads the resource behind path on the API host of req, with the
+// credentials of req. A missing resource is reported as not existing rather
+// than as an error.
+func (t *Transport) fetch(req *http.Request, path string, query url.Values) ([]byte, bool, error) {
+	u := *req.URL
+	u.Path, u.RawPath = path, ""
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		u.Path, u.RawPath = unescaped, path
+	}
+	u.RawQuery = query.Encode()
+
+	get, err := http.NewRequestWithContext(req.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	get.Header = req.Header.Clone()
+	get.Header.Del("Content-Type")
+	get.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := t.transport.RoundTrip(get)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch current content: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("failed to fetch current content: %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read current content: %w", err)
+	}
+	return data, true, nil
+}
+
+// unifiedDiff diffs the content of path before and after a change in git's
+// format, using /dev/null for the side of a created or deleted file.
+func unifiedDiff(path string, before, after []byte, oldExists, newExists bool) string {
+	from, to := "a/"+path, "b/"+path
+	if !oldExists {
+		from = "/dev/null"
+	}
+	if !newExists {
+		to = "/dev/null"
+	}
+	if oldExists == newExists && bytes.Equal(before, after) {
+		return ""
+	}
+	if !utf8.Valid(before) || !utf8.Valid(after) {
+		return fmt.Sprintf("Binary files %s and %s differ\n", from, to)
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(before),
+		B:        splitLines(after),
+		FromFile: from,
+		ToFile:   to,
+		Context:  3,
+	})
+	if diff == "" {
+		// Creating or deleting an empty file changes no lines.
+		return fmt.Sprintf("--- %s\n+++ %s\n", from, to)
+	}
+	return diff
+}
+
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n\\ No newline at end of file\n"
+	return lines
+}
@@ -0,0 +1,276 @@
+// Package dryrun lets write tools show what they would do without changing
+// anything on GitHub.
+//
+// A dry-run call runs the tool handler as usual with a recorder in its
+// context. The Transport lets reads through, so inputs are validated and refs
+// and SHAs are resolved against the real repository, but it records mutating
+// requests instead of sending them and answers them with a synthetic
+// response. Values that only a real response would provide, such as the SHA of
+// a new commit, are replaced by placeholders naming the request that would
+// return them. File writes additionally carry a unified diff against the
+// current content.
+package dryrun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Argument is the tool argument requesting a dry run of a single call.
+const Argument = "dry_run"
+
+// Request is a mutating API request that a dry-run call would have sent.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// Body is the exact request body, as JSON when it is JSON.
+	Body json.RawMessage `json:"body,omitempty"`
+	// Diff is a unified diff of the file changes the request would make.
+	Diff string `json:"diff,omitempty"`
+	// DiffError explains why a file change could not be diffed.
+	DiffError string `json:"diff_error,omitempty"`
+}
+
+// Preview is the result of a dry-run call.
+type Preview struct {
+	DryRun   bool      `json:"dry_run"`
+	Tool     string    `json:"tool"`
+	Requests []Request `json:"requests"`
+	// Note explains placeholders in the requests.
+	Note string `json:"note,omitempty"`
+	// Error is the error the tool reported after its requests were recorded,
+	// in which case later requests may be missing from the preview.
+	Error string `json:"error,omitempty"`
+}
+
+const previewNote = "No changes were made. Values that only GitHub's response would provide are shown as dry-run-N, N being the position of the request returning them."
+
+type recorder struct {
+	mu       sync.Mutex
+	requests []Request
+}
+
+// add records r and returns its 1-based position.
+func (r *recorder) add(req Request) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	return len(r.requests)
+}
+
+// setDiff attaches the diff of the request at position n.
+func (r *recorder) setDiff(n int, diff string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests[n-1].Diff = diff
+	if err != nil {
+		r.requests[n-1].DiffError = err.Error()
+	}
+}
+
+func (r *recorder) list() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Request{}, r.requests...)
+}
+
+type recorderKey struct{}
+
+// Active reports whether ctx belongs to a dry-run call.
+func Active(ctx context.Context) bool {
+	return recorderFromContext(ctx) != nil
+}
+
+func recorderFromContext(ctx context.Context) *recorder {
+	rec, _ := ctx.Value(recorderKey{}).(*recorder)
+	return rec
+}
+
+// Requested reports whether request asks for a dry run through its arguments.
+func Requested(request mcp.CallToolRequest) bool {
+	v, _ := request.GetArguments()[Argument].(bool)
+	return v
+}
+
+// Middleware returns a tool middleware that runs write tools in dry-run mode
+// when always is set or the call passes dry_run: true. A call that fails
+// before recording any request, for example on invalid input, returns its
+// error unchanged.
+func Middleware(isWriteTool func(name string) bool, always bool) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !isWriteTool(request.Params.Name) || (!always && !Requested(request)) {
+				return next(ctx, request)
+			}
+
+			rec := &recorder{}
+			result, err := next(context.WithValue(ctx, recorderKey{}, rec), request)
+			requests := rec.list()
+			failed := err != nil || (result != nil && result.IsError)
+			if len(requests) == 0 && failed {
+				return result, err
+			}
+
+			preview := Preview{DryRun: true, Tool: request.Params.Name, Requests: requests}
+			if len(requests) > 0 {
+				preview.Note = previewNote
+			}
+			switch {
+			case err != nil:
+				preview.Error = err.Error()
+			case result != nil && result.IsError:
+				preview.Error = resultText(result)
+			}
+
+			r, err := json.Marshal(preview)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal dry-run preview: %w", err)
+			}
+			return mcp.NewToolResultText(string(r)), nil
+		}
+	}
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Transport is an http.RoundTripper that records mutating requests made in a
+// dry-run context instead of sending them. Other requests are passed to the
+// underlying transport unchanged.
+type Transport struct {
+	transport http.RoundTripper
+}
+
+// NewTransport wraps transport, which defaults to http.DefaultTransport.
+func NewTransport(transport http.RoundTripper) *Transport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Transport{transport: transport}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := recorderFromContext(req.Context())
+	if rec == nil || isSafeMethod(req.Method) {
+		return t.transport.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	graphQL := isGraphQL(req)
+	if graphQL && !isMutation(body) {
+		return t.transport.RoundTrip(req)
+	}
+
+	n := rec.add(Request{Method: req.Method, URL: req.URL.String(), Body: recordedBody(body)})
+	if !graphQL {
+		if diff, ok, err := t.diff(req, body); ok {
+			rec.setDiff(n, diff, err)
+		}
+	}
+	return syntheticResponse(req, body, graphQL, n), nil
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func isGraphQL(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/graphql")
+}
+
+// isMutation reports whether a GraphQL request body holds a mutation. Queries
+// are sent as POST requests too and must go through.
+func isMutation(body []byte) bool {
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return true
+	}
+	return strings.HasPrefix(strings.TrimSpace(payload.Query), "mutation")
+}
+
+func recordedBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return json.RawMessage(body)
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
+
+// Placeholder is the value standing in for a field of the response to the
+// request at 1-based position n.
+func Placeholder(n int) string {
+	return fmt.Sprintf("dry-run-%d", n)
+}
+
+// syntheticResponse answers a recorded request. REST responses only carry
+// placeholder identifiers, which is enough for handlers that chain requests,
+// such as creating a tree and then a commit from it. Request bodies are not
+// echoed because request and response fields of the same name often differ
+// in type. GraphQL mutations are answered with the data they select.
+func syntheticResponse(req *http.Request, body []byte, graphQL bool, n int) *http.Response {
+	status := http.StatusOK
+	var payload []byte
+	switch {
+	case graphQL:
+		payload = mutationResponse(body, n)
+	case req.Method == http.MethodDelete:
+		status = http.StatusNoContent
+	default:
+		if req.Method == http.MethodPost {
+			status = http.StatusCreated
+		}
+		payload, _ = json.Marshal(map[string]string{
+			"sha":     Placeholder(n),
+			"node_id": Placeholder(n),
+		})
+	}
+
+	header := make(http.Header)
+	if payload != nil {
+		header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(payload)),
+		ContentLength: int64(len(payload)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,327 @@
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/githubfake"
+	"github.com/google/go-github/v74/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeRepo starts a fake GitHub with an initialized demo repository and
+// returns clients that send requests through a dry-run Transport.
+func newFakeRepo(t *testing.T) (*githubfake.Server, *github.Client, *githubv4.Client) {
+	t.Helper()
+	srv := githubfake.New()
+	t.Cleanup(srv.Close)
+
+	httpClient := &http.Client{Transport: NewTransport(nil)}
+	rest, err := github.NewClient(httpClient).WithEnterpriseURLs(srv.APIURL(), srv.APIURL())
+	require.NoError(t, err)
+	_, _, err = rest.Repositories.Create(context.Background(), "", &github.Repository{Name: github.Ptr("demo"), AutoInit: github.Ptr(true)})
+	require.NoError(t, err)
+	return srv, rest, githubv4.NewEnterpriseClient(srv.GraphQLURL(), httpClient)
+}
+
+func dryRunContext() (context.Context, *recorder) {
+	rec := &recorder{}
+	return context.WithValue(context.Background(), recorderKey{}, rec), rec
+}
+
+func TestTransportPushFiles(t *testing.T) {
+	_, rest, _ := newFakeRepo(t)
+	owner := githubfake.DefaultLogin
+	ctx, rec := dryRunContext()
+
+	// The requests push_files sends
+	ref, _, err := rest.Git.GetRef(ctx, owner, "demo", "refs/heads/main")
+	require.NoError(t, err)
+	headSHA := ref.GetObject().GetSHA()
+	baseCommit, _, err := rest.Git.GetCommit(ctx, owner, "demo", headSHA)
+	require.NoError(t, err)
+	tree, _, err := rest.Git.CreateTree(ctx, owner, "demo", baseCommit.GetTree().GetSHA(), []*github.TreeEntry{
+		{Path: github.Ptr("README.md"), Mode: github.Ptr("100644"), Type: github.Ptr("blob"), Content: github.Ptr("# demo\n\nHello\n")},
+		{Path: github.Ptr("src/main.go"), Mode: github.Ptr("100644"), Type: github.Ptr("blob"), Content: github.Ptr("package main\n")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "dry-run-1", tree.GetSHA())
+	commit, _, err := rest.Git.CreateCommit(ctx, owner, "demo", &github.Commit{
+		Message: github.Ptr("update"),
+		Tree:    tree,
+		Parents: []*github.Commit{{SHA: baseCommit.SHA}},
+	}, nil)
+	require.NoError(t, err)
+	ref.Object.SHA = commit.SHA
+	_, _, err = rest.Git.UpdateRef(ctx, owner, "demo", ref, false)
+	require.NoError(t, err)
+
+	requests := rec.list()
+	require.Len(t, requests, 3, "only mutating requests are recorded")
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.Contains(t, requests[0].URL, "/repos/"+owner+"/demo/git/trees")
+	assert.Equal(t, "--- a/README.md\n+++ b/README.md\n@@ -1 +1,3 @@\n # demo\n+\n+Hello\n"+
+		"--- /dev/null\n+++ b/src/main.go\n@@ -0,0 +1 @@\n+package main\n", requests[0].Diff)
+	assert.Empty(t, requests[0].DiffError)
+
+	var commitBody map[string]any
+	require.NoError(t, json.Unmarshal(requests[1].Body, &commitBody))
+	assert.Equal(t, "dry-run-1", commitBody["tree"])
+	assert.Equal(t, []any{headSHA}, commitBody["parents"])
+
+	assert.Equal(t, http.MethodPatch, requests[2].Method)
+	assert.JSONEq(t, `{"sha":"dry-run-2","force":false}`, string(requests[2].Body))
+
+	// Nothing was changed
+	ref, _, err = rest.Git.GetRef(context.Background(), owner, "demo", "refs/heads/main")
+	require.NoError(t, err)
+	assert.Equal(t, headSHA, ref.GetObject().GetSHA())
+}
+
+func TestTransportDeleteFileFromTree(t *testing.T) {
+	_, rest, _ := newFakeRepo(t)
+	owner := githubfake.DefaultLogin
+	ctx, rec := dryRunContext()
+
+	ref, _, err := rest.Git.GetRef(ctx, owner, "demo", "refs/heads/main")
+	require.NoError(t, err)
+	baseCommit, _, err := rest.Git.GetCommit(ctx, owner, "demo", ref.GetObject().GetSHA())
+	require.NoError(t, err)
+	_, _, err = rest.Git.CreateTree(ctx, owner, "demo", baseCommit.GetTree().GetSHA(), []*github.TreeEntry{
+		{Path: github.Ptr("README.md"), Mode: github.Ptr("100644"), Type: github.Ptr("blob"), SHA: nil},
+	})
+	require.NoError(t, err)
+
+	requests := rec.list()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "--- a/README.md\n+++ /dev/null\n@@ -1 +0,0 @@\n-# demo\n", requests[0].Diff)
+}
+
+func TestTransportContents(t *testing.T) {
+	_, rest, _ := newFakeRepo(t)
+	owner := githubfake.DefaultLogin
+	ctx, rec := dryRunContext()
+
+	_, _, err := rest.Repositories.CreateFile(ctx, owner, "demo", "README.md", &github.RepositoryContentFileOptions{
+		Message: github.Ptr("update"),
+		Content: []byte("# Demo\n"),
+		Branch:  github.Ptr("main"),
+	})
+	require.NoError(t, err)
+	_, _, err = rest.Repositories.CreateFile(ctx, owner, "demo", "docs/new file.md", &github.RepositoryContentFileOptions{
+		Message: github.Ptr("add"),
+		Content: []byte("new\n"),
+	})
+	require.NoError(t, err)
+	_, _, err = rest.Repositories.DeleteFile(ctx, owner, "demo", "README.md", &github.RepositoryContentFileOptions{
+		Message: github.Ptr("delete"),
+		Branch:  github.Ptr("main"),
+	})
+	require.NoError(t, err)
+
+	requests := rec.list()
+	require.Len(t, requests, 3)
+	assert.Equal(t, http.MethodPut, requests[0].Method)
+	assert.Equal(t, "--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n-# demo\n+# Demo\n", requests[0].Diff)
+	assert.Equal(t, "--- /dev/null\n+++ b/docs/new file.md\n@@ -0,0 +1 @@\n+new\n", requests[1].Diff)
+	assert.Equal(t, http.MethodDelete, requests[2].Method)
+	assert.Equal(t, "--- a/README.md\n+++ /dev/null\n@@ -1 +0,0 @@\n-# demo\n", requests[2].Diff)
+
+	file, _, _, err := rest.Repositories.GetContents(context.Background(), owner, "demo", "README.md", nil)
+	require.NoError(t, err)
+	content, err := file.GetContent()
+	require.NoError(t, err)
+	assert.Equal(t, "# demo\n", content)
+}
+
+func TestTransportGraphQL(t *testing.T) {
+	_, _, gql := newFakeRepo(t)
+	ctx, rec := dryRunContext()
+
+	var query struct {
+		Viewer struct {
+			Login githubv4.String
+		}
+	}
+	require.NoError(t, gql.Query(ctx, &query, nil))
+	assert.Equal(t, githubfake.DefaultLogin, string(query.Viewer.Login))
+	assert.Empty(t, rec.list(), "queries are sent")
+
+	var mutation struct {
+		AddComment struct {
+			ClientMutationID githubv4.String
+		} `graphql:"addComment(input: $input)"`
+	}
+	input := githubv4.AddCommentInput{SubjectID: githubv4.ID("I_1"), Body: githubv4.String("hi")}
+	require.NoError(t, gql.Mutate(ctx, &mutation, input, nil))
+
+	requests := rec.list()
+	require.Len(t, requests, 1)
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.Contains(t, string(requests[0].Body), `"query":"mutation`)
+	assert.Empty(t, requests[0].Diff)
+}
+
+func TestTransportGraphQLMutationResponse(t *testing.T) {
+	_, _, gql := newFakeRepo(t)
+	ctx, _ := dryRunContext()
+
+	var mutation struct {
+		ConvertPullRequestToDraft struct {
+			PullRequest struct {
+				ID      githubv4.ID
+				IsDraft githubv4.Boolean
+				Labels  struct {
+					Nodes []struct {
+						Name githubv4.String
+					}
+				} `graphql:"labels(first: 10)"`
+				Author struct {
+					Login githubv4.String
+				}
+			}
+		} `graphql:"convertPullRequestToDraft(input: $input)"`
+	}
+	input := githubv4.ConvertPullRequestToDraftInput{PullRequestID: githubv4.ID("PR_1")}
+	require.NoError(t, gql.Mutate(ctx, &mutation, input, nil), "expected the synthetic response to decode")
+
+	pr := mutation.ConvertPullRequestToDraft.PullRequest
+	assert.Equal(t, Placeholder(1), pr.ID)
+	assert.False(t, bool(pr.IsDraft))
+	assert.Empty(t, pr.Labels.Nodes)
+}
+
+func TestMutationResponse(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "aliases, arguments and directives",
+			query: `mutation($input:AddCommentInput!){added:addComment(input: $input){commentEdge{node{id,url @include(if: true)}},subject{... on Issue{id}}}}`,
+			want:  `{"data":{"added":{"commentEdge":{"node":{"id":"dry-run-2","url":null}},"subject":{"id":"dry-run-2"}}}}`,
+		},
+		{
+			name:  "strings holding parentheses",
+			query: `mutation{addComment(input: {body: "a (b"}){clientMutationId}}`,
+			want:  `{"data":{"addComment":{"clientMutationId":null}}}`,
+		},
+		{
+			name:  "unparsable",
+			query: `mutation{addComment(input: $input){`,
+			want:  `{"data":null}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(map[string]string{"query": tc.query})
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.want, string(mutationResponse(body, 2)))
+		})
+	}
+}
+
+func TestTransportWithoutDryRun(t *testing.T) {
+	_, rest, _ := newFakeRepo(t)
+	_, _, err := rest.Issues.Create(context.Background(), githubfake.DefaultLogin, "demo", &github.IssueRequest{Title: github.Ptr("real")})
+	require.NoError(t, err)
+
+	issue, _, err := rest.Issues.Get(context.Background(), githubfake.DefaultLogin, "demo", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "real", issue.GetTitle())
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	assert.Empty(t, unifiedDiff("a.txt", []byte("same\n"), []byte("same\n"), true, true))
+	assert.Equal(t, "Binary files a/a.bin and b/a.bin differ\n", unifiedDiff("a.bin", []byte{0xff}, []byte{0xfe}, true, true))
+	assert.Equal(t, "--- /dev/null\n+++ b/empty\n", unifiedDiff("empty", nil, nil, false, true))
+	assert.Equal(t, "--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+a\n",
+		unifiedDiff("a.txt", []byte("a"), []byte("a\n"), true, true))
+}
+
+func TestMiddleware(t *testing.T) {
+	isWrite := func(name string) bool { return name != "get_me" }
+	call := func(middleware server.ToolHandlerMiddleware, name string, args map[string]any, handler func(ctx context.Context) (*mcp.CallToolResult, error)) (*mcp.CallToolResult, error) {
+		var request mcp.CallToolRequest
+		request.Params.Name = name
+		request.Params.Arguments = args
+		return middleware(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handler(ctx)
+		})(context.Background(), request)
+	}
+	recordOne := func(ctx context.Context) (*mcp.CallToolResult, error) {
+		if !Active(ctx) {
+			return mcp.NewToolResultText("sent"), nil
+		}
+		recorderFromContext(ctx).add(Request{Method: http.MethodPut, URL: "https://api.github.com/repos/o/r/pulls/1/merge"})
+		return mcp.NewToolResultText("merged"), nil
+	}
+	preview := func(t *testing.T, result *mcp.CallToolResult) Preview {
+		t.Helper()
+		require.False(t, result.IsError)
+		var p Preview
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &p))
+		return p
+	}
+
+	t.Run("calls run normally without dry run", func(t *testing.T) {
+		result, err := call(Middleware(isWrite, false), "merge_pull_request", map[string]any{Argument: false}, recordOne)
+		require.NoError(t, err)
+		assert.Equal(t, "sent", result.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("the argument requests a dry run", func(t *testing.T) {
+		result, err := call(Middleware(isWrite, false), "merge_pull_request", map[string]any{Argument: true}, recordOne)
+		require.NoError(t, err)
+		p := preview(t, result)
+		assert.True(t, p.DryRun)
+		assert.Equal(t, "merge_pull_request", p.Tool)
+		require.Len(t, p.Requests, 1)
+		assert.Equal(t, http.MethodPut, p.Requests[0].Method)
+		assert.NotEmpty(t, p.Note)
+		assert.Empty(t, p.Error)
+	})
+
+	t.Run("the server option dry-runs every write tool", func(t *testing.T) {
+		result, err := call(Middleware(isWrite, true), "merge_pull_request", nil, recordOne)
+		require.NoError(t, err)
+		assert.Len(t, preview(t, result).Requests, 1)
+
+		result, err = call(Middleware(isWrite, true), "get_me", nil, recordOne)
+		require.NoError(t, err)
+		assert.Equal(t, "sent", result.Content[0].(mcp.TextContent).Text, "read tools are not affected")
+	})
+
+	t.Run("validation errors are returned unchanged", func(t *testing.T) {
+		result, err := call(Middleware(isWrite, true), "merge_pull_request", nil, func(context.Context) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError("missing required parameter: pullNumber"), nil
+		})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "missing required parameter: pullNumber", result.Content[0].(mcp.TextContent).Text)
+
+		_, err = call(Middleware(isWrite, true), "merge_pull_request", nil, func(context.Context) (*mcp.CallToolResult, error) {
+			return nil, errors.New("failed to get GitHub client")
+		})
+		require.EqualError(t, err, "failed to get GitHub client")
+	})
+
+	t.Run("errors after recording are reported with the preview", func(t *testing.T) {
+		result, err := call(Middleware(isWrite, true), "merge_pull_request", nil, func(ctx context.Context) (*mcp.CallToolResult, error) {
+			_, _ = recordOne(ctx)
+			return mcp.NewToolResultError("unexpected response"), nil
+		})
+		require.NoError(t, err)
+		p := preview(t, result)
+		assert.Len(t, p.Requests, 1)
+		assert.Equal(t, "unexpected response", p.Error)
+	})
+}
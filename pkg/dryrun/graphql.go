@@ -0,0 +1,164 @@
+package dryrun
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// mutationResponse returns the body answering the GraphQL mutation in body.
+// Its data has the shape of the mutation's selection set, so that clients
+// decoding strictly into the selected fields accept it: ids are the
+// placeholder of request n, other fields are null, and connection nodes and
+// edges are empty lists, since an object would not decode into a slice. A
+// query that cannot be parsed is answered with null data.
+func mutationResponse(body []byte, n int) []byte {
+	var payload struct {
+		Query string `json:"query"`
+	}
+	var data map[string]any
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if start := strings.IndexByte(payload.Query, '{'); start >= 0 {
+			p := &selectionParser{query: payload.Query, pos: start + 1, id: Placeholder(n)}
+			if fields, ok := p.selectionSet(); ok {
+				data = fields
+			}
+		}
+	}
+	r, _ := json.Marshal(map[string]any{"data": data})
+	return r
+}
+
+// selectionParser reads the selection set of a GraphQL operation, as written
+// by githubv4, into the data a response to it would hold.
+type selectionParser struct {
+	query string
+	pos   int
+	id    string
+}
+
+// selectionSet parses the fields up to the brace closing a selection set,
+// whose opening brace was already read.
+func (p *selectionParser) selectionSet() (map[string]any, bool) {
+	fields := make(map[string]any)
+	for {
+		p.skipIgnored()
+		if p.pos >= len(p.query) {
+			return nil, false
+		}
+		if p.query[p.pos] == '}' {
+			p.pos++
+			return fields, true
+		}
+
+		// The fields of an inline fragment belong to the enclosing object
+		if strings.HasPrefix(p.query[p.pos:], "...") {
+			start := strings.IndexByte(p.query[p.pos:], '{')
+			if start < 0 {
+				return nil, false
+			}
+			p.pos += start + 1
+			fragment, ok := p.selectionSet()
+			if !ok {
+				return nil, false
+			}
+			for k, v := range fragment {
+				fields[k] = v
+			}
+			continue
+		}
+
+		key := p.name()
+		if key == "" {
+			return nil, false
+		}
+		name := key
+		p.skipIgnored()
+		if p.peek(':') {
+			p.pos++
+			p.skipIgnored()
+			if name = p.name(); name == "" {
+				return nil, false
+			}
+			p.skipIgnored()
+		}
+		if p.peek('(') && !p.skipArguments() {
+			return nil, false
+		}
+		p.skipIgnored()
+		for p.peek('@') {
+			p.pos++
+			p.name()
+			p.skipIgnored()
+			if p.peek('(') && !p.skipArguments() {
+				return nil, false
+			}
+			p.skipIgnored()
+		}
+
+		switch {
+		case p.peek('{'):
+			p.pos++
+			nested, ok := p.selectionSet()
+			if !ok {
+				return nil, false
+			}
+			if name == "nodes" || name == "edges" {
+				fields[key] = []any{}
+			} else {
+				fields[key] = nested
+			}
+		case name == "id":
+			fields[key] = p.id
+		default:
+			fields[key] = nil
+		}
+	}
+}
+
+func (p *selectionParser) peek(c byte) bool {
+	return p.pos < len(p.query) && p.query[p.pos] == c
+}
+
+// skipIgnored skips white space and commas, which GraphQL ignores.
+func (p *selectionParser) skipIgnored() {
+	for p.pos < len(p.query) && strings.IndexByte(" \t\r\n,", p.query[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *selectionParser) name() string {
+	start := p.pos
+	for p.pos < len(p.query) {
+		c := p.query[p.pos]
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			break
+		}
+		p.pos++
+	}
+	return p.query[start:p.pos]
+}
+
+// skipArguments skips a parenthesized argument list, along with any strings
+// in it.
+func (p *selectionParser) skipArguments() bool {
+	depth := 0
+	inString := false
+	for ; p.pos < len(p.query); p.pos++ {
+		switch c := p.query[p.pos]; {
+		case inString && c == '\\':
+			p.pos++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				p.pos++
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,313 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Kind is a machine-readable class of GitHub error.
+type Kind string
+
+// Kinds of GitHub errors, from the most to the least specific.
+const (
+	KindSSORequired  Kind = "sso_required"
+	KindMissingScope Kind = "missing_scope"
+	KindRateLimited  Kind = "rate_limited"
+	KindValidation   Kind = "validation_failed"
+	KindUnsupported  Kind = "unsupported_api"
+	KindHidden       Kind = "hidden"
+	KindNotFound     Kind = "not_found"
+	KindOther        Kind = "other"
+)
+
+// Classification is the kind of a GitHub error and a hint on how to fix it.
+type Classification struct {
+	Kind Kind
+	// Status is the HTTP status of the response, or 0 for GraphQL errors.
+	Status int
+	Hint   string
+}
+
+// ClassifyAPIError classifies a REST API error from its response status,
+// headers and body.
+func ClassifyAPIError(e *GitHubAPIError) Classification {
+	var errResp *github.ErrorResponse
+	stderrors.As(e.Err, &errResp)
+
+	var resp *http.Response
+	switch {
+	case e.Response != nil && e.Response.Response != nil:
+		resp = e.Response.Response
+	case errResp != nil && errResp.Response != nil:
+		resp = errResp.Response
+	}
+
+	var rateErr *github.RateLimitError
+	if stderrors.As(e.Err, &rateErr) {
+		return Classification{Kind: KindRateLimited, Status: statusOf(resp), Hint: rateLimitHint(rateErr.Rate.Reset.Time, 0)}
+	}
+	var abuseErr *github.AbuseRateLimitError
+	if stderrors.As(e.Err, &abuseErr) {
+		return Classification{Kind: KindRateLimited, Status: statusOf(resp), Hint: rateLimitHint(time.Time{}, abuseErr.GetRetryAfter())}
+	}
+	if resp == nil {
+		return Classification{Kind: KindOther}
+	}
+
+	c := Classification{Kind: KindOther, Status: resp.StatusCode}
+	header := resp.Header
+	switch {
+	case resp.StatusCode == http.StatusForbidden && strings.HasPrefix(header.Get("X-GitHub-SSO"), "required"):
+		c.Kind = KindSSORequired
+		c.Hint = "The organization enforces SAML single sign-on. Authorize the token for the organization"
+		if u := ssoURL(header.Get("X-GitHub-SSO")); u != "" {
+			c.Hint += " at " + u
+		}
+		c.Hint += ", then retry."
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusForbidden && header.Get("X-RateLimit-Remaining") == "0":
+		c.Kind = KindRateLimited
+		var reset time.Time
+		if v, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			reset = time.Unix(v, 0)
+		}
+		var retryAfter time.Duration
+		if v, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+			retryAfter = time.Duration(v) * time.Second
+		}
+		c.Hint = rateLimitHint(reset, retryAfter)
+	case (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound) && missingScopes(header) != nil:
+		c.Kind = KindMissingScope
+		c.Hint = fmt.Sprintf("The token lacks the OAuth scopes this call needs. Grant one of: %s.", strings.Join(missingScopes(header), ", "))
+	case resp.StatusCode == http.StatusForbidden && header.Get("X-Accepted-GitHub-Permissions") != "":
+		c.Kind = KindMissingScope
+		c.Hint = fmt.Sprintf("The token lacks the permissions this call needs. Grant the token %s and retry.", header.Get("X-Accepted-GitHub-Permissions"))
+	case resp.StatusCode == http.StatusUnprocessableEntity:
+		c.Kind = KindValidation
+		c.Hint = "GitHub rejected the request parameters."
+		if errResp != nil && len(errResp.Errors) > 0 {
+			var problems []string
+			for _, fieldErr := range errResp.Errors {
+				problems = append(problems, validationProblem(fieldErr))
+			}
+			c.Hint += " " + strings.Join(problems, "; ") + "."
+		}
+		c.Hint += " Fix the arguments and retry."
+	case resp.StatusCode == http.StatusUnsupportedMediaType, resp.StatusCode == http.StatusNotImplemented,
+		resp.StatusCode == http.StatusNotFound && errResp != nil && isRouteNotFound(errResp.DocumentationURL):
+		c.Kind = KindUnsupported
+		c.Hint = "The server does not support this API."
+		if version := header.Get("X-GitHub-Enterprise-Version"); version != "" {
+			c.Hint = fmt.Sprintf("GitHub Enterprise Server %s does not support this API. It needs a newer version of GitHub Enterprise Server.", version)
+		}
+	case resp.StatusCode == http.StatusNotFound && header.Get("X-OAuth-Scopes") != "" && !hasScope(header.Get("X-OAuth-Scopes"), "repo"):
+		c.Kind = KindHidden
+		c.Hint = "GitHub reports private resources as not found to tokens without the repo scope. Check the name, or grant the token the repo scope if the resource is private."
+	case resp.StatusCode == http.StatusNotFound:
+		c.Kind = KindNotFound
+		c.Hint = "The resource does not exist, or the token cannot see it. Check the owner, repository and other names; private resources also look not found to tokens without access to them."
+	}
+	return c
+}
+
+// ClassifyGraphQLError classifies a GraphQL error from its message, as the
+// GraphQL client does not expose the error type.
+func ClassifyGraphQLError(e *GitHubGraphQLError) Classification {
+	if e.Err == nil {
+		return Classification{Kind: KindOther}
+	}
+	message := e.Err.Error()
+	switch {
+	case strings.Contains(message, "SAML enforcement"), strings.Contains(message, "SAML SSO"):
+		return Classification{Kind: KindSSORequired, Hint: "The organization enforces SAML single sign-on. Authorize the token for the organization, then retry."}
+	case strings.Contains(message, "has not been granted the required scopes"):
+		return Classification{Kind: KindMissingScope, Hint: "The token lacks the OAuth scopes this query needs; the error message names them. Grant them and retry."}
+	case strings.Contains(message, "Resource not accessible by"):
+		return Classification{Kind: KindMissingScope, Hint: "The token lacks the permissions this query needs. Grant them and retry."}
+	case strings.Contains(message, "rate limit"), strings.Contains(message, "RATE_LIMITED"):
+		return Classification{Kind: KindRateLimited, Hint: rateLimitHint(time.Time{}, 0)}
+	case strings.Contains(message, "doesn't exist on type"), strings.Contains(message, "Cannot query field"), strings.Contains(message, "isn't a defined input type"):
+		return Classification{Kind: KindUnsupported, Hint: "The server's GraphQL schema lacks a field this query uses. It needs a newer version of GitHub Enterprise Server."}
+	case strings.Contains(message, "Could not resolve to"):
+		return Classification{Kind: KindNotFound, Hint: "The resource does not exist, or the token cannot see it. Check the names used."}
+	case strings.Contains(message, "has an invalid value"), strings.Contains(message, "was provided invalid value"):
+		return Classification{Kind: KindValidation, Hint: "GitHub rejected the query arguments. Fix them and retry."}
+	}
+	return Classification{Kind: KindOther}
+}
+
+// CountFunc counts a classified error of a tool call by tool and kind.
+type CountFunc func(tool, kind string)
+
+// Middleware collects the GitHub errors of each tool call, classifies them,
+// logs them, counts them with count by tool and kind, and attaches the kind
+// and remediation hint of the first classified error to the tool result. The
+// kind and hint go into the result's _meta as error_kind and
+// remediation_hint, and the hint is also appended to the content for the
+// model to read.
+func Middleware(logger *slog.Logger, count CountFunc) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			// A holder of its own keeps concurrent calls from seeing each
+			// other's errors.
+			ctx = context.WithValue(ctx, GitHubErrorKey{}, &GitHubCtxErrors{})
+			result, err := next(ctx, request)
+
+			tool := request.Params.Name
+			var classes []Classification
+			apiErrors, _ := GetGitHubAPIErrors(ctx)
+			for _, apiErr := range apiErrors {
+				c := ClassifyAPIError(apiErr)
+				logger.Warn("GitHub API error", "tool", tool, "kind", string(c.Kind), "status", c.Status, "message", apiErr.Message, "error", apiErr.Err)
+				classes = append(classes, c)
+			}
+			gqlErrors, _ := GetGitHubGraphQLErrors(ctx)
+			for _, gqlErr := range gqlErrors {
+				c := ClassifyGraphQLError(gqlErr)
+				logger.Warn("GitHub GraphQL error", "tool", tool, "kind", string(c.Kind), "message", gqlErr.Message, "error", gqlErr.Err)
+				classes = append(classes, c)
+			}
+			if count != nil {
+				for _, c := range classes {
+					count(tool, string(c.Kind))
+				}
+			}
+			if result != nil {
+				for _, c := range classes {
+					if c.Hint != "" {
+						annotate(result, c)
+						break
+					}
+				}
+			}
+			return result, err
+		}
+	}
+}
+
+// annotate attaches the kind and hint of c to result.
+func annotate(result *mcp.CallToolResult, c Classification) {
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields["error_kind"] = string(c.Kind)
+	result.Meta.AdditionalFields["remediation_hint"] = c.Hint
+	result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("[error_kind: %s] %s", c.Kind, c.Hint)))
+}
+
+func statusOf(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
+
+func rateLimitHint(reset time.Time, retryAfter time.Duration) string {
+	switch {
+	case retryAfter > 0:
+		return fmt.Sprintf("GitHub rate limited the token. Wait %s before retrying.", retryAfter)
+	case !reset.IsZero():
+		return fmt.Sprintf("GitHub rate limited the token. The limit resets at %s; wait until then before retrying.", reset.UTC().Format(time.RFC3339))
+	}
+	return "GitHub rate limited the token. Wait a minute before retrying, and make fewer calls."
+}
+
+// ssoURL returns the authorization URL of an X-GitHub-SSO header such as
+// "required; url=https://github.com/orgs/octo/sso?authorization_request=...".
+func ssoURL(header string) string {
+	for _, part := range strings.Split(header, ";") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(part), "url="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// missingScopes returns the scopes accepted for a call, from
+// X-Accepted-OAuth-Scopes, when the token's scopes in X-OAuth-Scopes grant
+// none of them. It returns nil when either header is missing, as they are for
+// tokens other than OAuth tokens and classic personal access tokens.
+func missingScopes(header http.Header) []string {
+	accepted, granted := header.Get("X-Accepted-OAuth-Scopes"), header.Values("X-OAuth-Scopes")
+	if accepted == "" || len(granted) == 0 {
+		return nil
+	}
+	var scopes []string
+	for _, scope := range strings.Split(accepted, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if hasScope(strings.Join(granted, ","), scope) {
+			return nil
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
+// hasScope reports whether the comma-separated granted scopes include
+// scope, directly or through a broader scope: repo grants repo:status,
+// admin:org grants write:org, which grants read:org.
+func hasScope(granted, scope string) bool {
+	for _, g := range strings.Split(granted, ",") {
+		g = strings.TrimSpace(g)
+		if g == scope || strings.HasPrefix(scope, g+":") || (g == "repo" && scope == "public_repo") || (g == "user" && scope == "read:user") {
+			return true
+		}
+		gLevel, gName, gOK := strings.Cut(g, ":")
+		sLevel, sName, sOK := strings.Cut(scope, ":")
+		if gOK && sOK && gName == sName && scopeLevel(gLevel) > scopeLevel(sLevel) && scopeLevel(sLevel) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func scopeLevel(level string) int {
+	switch level {
+	case "read":
+		return 1
+	case "write":
+		return 2
+	case "admin":
+		return 3
+	}
+	return 0
+}
+
+// isRouteNotFound reports whether documentationURL is the generic REST
+// documentation link that GitHub returns for routes it does not have, rather
+// than the link to an endpoint's own documentation returned for missing
+// resources.
+func isRouteNotFound(documentationURL string) bool {
+	u, err := url.Parse(documentationURL)
+	if err != nil || documentationURL == "" || u.Fragment != "" {
+		return false
+	}
+	return strings.HasSuffix(strings.TrimSuffix(u.Path, "/"), "/rest")
+}
+
+func validationProblem(e github.Error) string {
+	switch {
+	case e.Message != "":
+		return e.Message
+	case e.Field != "":
+		return fmt.Sprintf("%s %s is %s", e.Resource, e.Field, e.Code)
+	}
+	return e.Code
+}
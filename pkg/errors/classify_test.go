@@ -0,0 +1,185 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func apiError(status int, header http.Header, err error) *GitHubAPIError {
+	if header == nil {
+		header = http.Header{}
+	}
+	resp := &http.Response{StatusCode: status, Header: header}
+	if errResp, ok := err.(*github.ErrorResponse); ok {
+		errResp.Response = resp
+	}
+	return newGitHubAPIError("failed", &github.Response{Response: resp}, err)
+}
+
+func TestClassifyAPIError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          *GitHubAPIError
+		expectedKind Kind
+		hint         string
+	}{
+		{
+			name: "SSO required",
+			err: apiError(403, http.Header{"X-Github-Sso": {"required; url=https://github.com/orgs/octo/sso?authorization_request=abc"}},
+				&github.ErrorResponse{Message: "Resource protected by organization SAML enforcement."}),
+			expectedKind: KindSSORequired,
+			hint:         "https://github.com/orgs/octo/sso?authorization_request=abc",
+		},
+		{
+			name: "missing scope",
+			err: apiError(403, http.Header{"X-Accepted-Oauth-Scopes": {"admin:org, write:org"}, "X-Oauth-Scopes": {"repo, read:org"}},
+				&github.ErrorResponse{Message: "Must have admin rights to Repository."}),
+			expectedKind: KindMissingScope,
+			hint:         "admin:org, write:org",
+		},
+		{
+			name: "missing fine-grained permission",
+			err: apiError(403, http.Header{"X-Accepted-Github-Permissions": {"issues=write"}},
+				&github.ErrorResponse{Message: "Resource not accessible by personal access token"}),
+			expectedKind: KindMissingScope,
+			hint:         "issues=write",
+		},
+		{
+			name:         "rate limited",
+			err:          newGitHubAPIError("failed", nil, &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: time.Unix(1700000000, 0)}}}),
+			expectedKind: KindRateLimited,
+			hint:         "2023-11-14T22:13:20Z",
+		},
+		{
+			name:         "secondary rate limited",
+			err:          apiError(429, http.Header{"Retry-After": {"30"}}, &github.ErrorResponse{Message: "You have exceeded a secondary rate limit."}),
+			expectedKind: KindRateLimited,
+			hint:         "Wait 30s",
+		},
+		{
+			name: "validation failed",
+			err: apiError(422, nil, &github.ErrorResponse{Message: "Validation Failed", Errors: []github.Error{
+				{Resource: "Issue", Field: "title", Code: "missing_field"},
+				{Message: "No commits between main and main"},
+			}}),
+			expectedKind: KindValidation,
+			hint:         "Issue title is missing_field; No commits between main and main.",
+		},
+		{
+			name: "GHES version unsupported",
+			err: apiError(404, http.Header{"X-Github-Enterprise-Version": {"3.9.0"}},
+				&github.ErrorResponse{Message: "Not Found", DocumentationURL: "https://docs.github.com/enterprise-server@3.9/rest"}),
+			expectedKind: KindUnsupported,
+			hint:         "GitHub Enterprise Server 3.9.0",
+		},
+		{
+			name: "hidden from a token without the repo scope",
+			err: apiError(404, http.Header{"X-Oauth-Scopes": {"public_repo"}},
+				&github.ErrorResponse{Message: "Not Found", DocumentationURL: "https://docs.github.com/rest/repos/repos#get-a-repository"}),
+			expectedKind: KindHidden,
+			hint:         "repo scope",
+		},
+		{
+			name: "not found",
+			err: apiError(404, http.Header{"X-Oauth-Scopes": {"repo"}},
+				&github.ErrorResponse{Message: "Not Found", DocumentationURL: "https://docs.github.com/rest/repos/repos#get-a-repository"}),
+			expectedKind: KindNotFound,
+		},
+		{
+			name:         "server error",
+			err:          apiError(502, nil, &github.ErrorResponse{Message: "Bad Gateway"}),
+			expectedKind: KindOther,
+		},
+		{
+			name:         "no response",
+			err:          newGitHubAPIError("failed", nil, fmt.Errorf("connection refused")),
+			expectedKind: KindOther,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := ClassifyAPIError(tc.err)
+			assert.Equal(t, tc.expectedKind, c.Kind)
+			if tc.expectedKind != KindOther {
+				assert.NotEmpty(t, c.Hint)
+			}
+			assert.Contains(t, c.Hint, tc.hint)
+		})
+	}
+}
+
+func TestClassifyGraphQLError(t *testing.T) {
+	tests := map[string]Kind{
+		"Resource protected by organization SAML enforcement. You must grant your Personal Access token access to this organization.":                     KindSSORequired,
+		"Your token has not been granted the required scopes to execute this query. The 'login' field requires one of the following scopes: ['read:org']": KindMissingScope,
+		"API rate limit exceeded for user ID 1.":                                        KindRateLimited,
+		"Field 'mergeQueue' doesn't exist on type 'Repository'":                         KindUnsupported,
+		"Could not resolve to a Repository with the name 'octo/nope'.":                  KindNotFound,
+		"Argument 'body' on InputObject 'AddCommentInput' has an invalid value (null).": KindValidation,
+		"Something went wrong while executing your query.":                              KindOther,
+	}
+	for message, expected := range tests {
+		assert.Equal(t, expected, ClassifyGraphQLError(newGitHubGraphQLError("failed", fmt.Errorf("%s", message))).Kind, message)
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	assert.True(t, hasScope("repo", "repo:status"))
+	assert.True(t, hasScope("repo", "public_repo"))
+	assert.True(t, hasScope("gist, admin:org", "read:org"))
+	assert.True(t, hasScope("write:packages", "read:packages"))
+	assert.False(t, hasScope("read:org", "write:org"))
+	assert.False(t, hasScope("public_repo", "repo"))
+}
+
+type counter map[string]int
+
+func (c counter) count(tool, kind string) {
+	c[tool+" "+kind]++
+}
+
+func TestMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	counts := counter{}
+	notFound := apiError(404, nil, &github.ErrorResponse{Message: "Not Found", DocumentationURL: "https://docs.github.com/rest/issues/issues#get-an-issue"})
+
+	handler := Middleware(logger, counts.count)(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return NewGitHubAPIErrorResponse(ctx, "failed to get issue", notFound.Response, notFound.Err), nil
+	})
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_issue"
+	result, err := handler(context.Background(), request)
+	require.NoError(t, err)
+
+	require.True(t, result.IsError)
+	require.NotNil(t, result.Meta)
+	assert.Equal(t, "not_found", result.Meta.AdditionalFields["error_kind"])
+	assert.NotEmpty(t, result.Meta.AdditionalFields["remediation_hint"])
+	require.Len(t, result.Content, 2)
+	assert.Contains(t, result.Content[1].(mcp.TextContent).Text, "[error_kind: not_found]")
+	assert.Equal(t, counter{"get_issue not_found": 1}, counts)
+	assert.Contains(t, logs.String(), "kind=not_found")
+	assert.Contains(t, logs.String(), "status=404")
+
+	// Calls without GitHub errors are left alone
+	handler = Middleware(logger, counts.count)(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	result, err = handler(context.Background(), request)
+	require.NoError(t, err)
+	assert.Nil(t, result.Meta)
+	assert.Len(t, result.Content, 1)
+	assert.Len(t, counts, 1)
+}
@@ -1,16 +1,23 @@
 package github
 
 import (
+	"container/list"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/github/github-mcp-server/internal/profiler"
 	buffer "github.com/github/github-mcp-server/pkg/buffer"
 	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/joblog"
+	"github.com/github/github-mcp-server/pkg/runlogs"
 	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/google/go-github/v74/github"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -30,6 +37,7 @@ func ListWorkflows(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_LIST_WORKFLOWS_USER_TITLE", "List workflows"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalWorkflowsResult](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -73,12 +81,15 @@ func ListWorkflows(getClient GetClientFn, t translations.TranslationHelperFunc)
 			}
 			defer func() { _ = resp.Body.Close() }()
 
-			r, err := json.Marshal(workflows)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalWorkflows := make([]MinimalWorkflow, 0, len(workflows.Workflows))
+			for _, workflow := range workflows.Workflows {
+				minimalWorkflows = append(minimalWorkflows, convertToMinimalWorkflow(workflow))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(MinimalWorkflowsResult{
+				TotalCount: workflows.GetTotalCount(),
+				Workflows:  minimalWorkflows,
+			}), nil
 		}
 }
 
@@ -90,6 +101,7 @@ func ListWorkflowRuns(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_LIST_WORKFLOW_RUNS_USER_TITLE", "List workflow runs"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalWorkflowRunsResult](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -212,12 +224,15 @@ func ListWorkflowRuns(getClient GetClientFn, t translations.TranslationHelperFun
 			}
 			defer func() { _ = resp.Body.Close() }()
 
-			r, err := json.Marshal(workflowRuns)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalRuns := make([]MinimalWorkflowRun, 0, len(workflowRuns.WorkflowRuns))
+			for _, run := range workflowRuns.WorkflowRuns {
+				minimalRuns = append(minimalRuns, convertToMinimalWorkflowRun(run))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(MinimalWorkflowRunsResult{
+				TotalCount:   workflowRuns.GetTotalCount(),
+				WorkflowRuns: minimalRuns,
+			}), nil
 		}
 }
 
@@ -248,6 +263,7 @@ func RunWorkflow(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 			mcp.WithObject("inputs",
 				mcp.Description("Inputs the workflow accepts"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -328,6 +344,7 @@ func GetWorkflowRun(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_GET_WORKFLOW_RUN_USER_TITLE", "Get workflow run"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalWorkflowRun](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -367,19 +384,21 @@ func GetWorkflowRun(getClient GetClientFn, t translations.TranslationHelperFunc)
 			}
 			defer func() { _ = resp.Body.Close() }()
 
-			r, err := json.Marshal(workflowRun)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(convertToMinimalWorkflowRun(workflowRun)), nil
 		}
 }
 
-// GetWorkflowRunLogs creates a tool to download logs for a specific workflow run
-func GetWorkflowRunLogs(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+// WorkflowRunLogs is the table of contents of the logs of a workflow run.
+type WorkflowRunLogs struct {
+	RunID   int64         `json:"run_id"`
+	Jobs    []runlogs.Job `json:"jobs"`
+	Message string        `json:"message"`
+}
+
+// GetWorkflowRunLogs creates a tool to download and index the logs of a specific workflow run
+func GetWorkflowRunLogs(getClient GetClientFn, runLogs *RunLogsCache, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("get_workflow_run_logs",
-			mcp.WithDescription(t("TOOL_GET_WORKFLOW_RUN_LOGS_DESCRIPTION", "Download logs for a specific workflow run (EXPENSIVE: downloads ALL logs as ZIP. Consider using get_job_logs with failed_only=true for debugging failed jobs)")),
+			mcp.WithDescription(t("TOOL_GET_WORKFLOW_RUN_LOGS_DESCRIPTION", "Download the logs of a workflow run and return their table of contents: the jobs of the run and the steps of each, with their number of lines and whether they failed. Read or search a job or step log with get_workflow_run_step_log. For debugging failed jobs, analyze_job_failure is cheaper")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
 				Title:        t("TOOL_GET_WORKFLOW_RUN_LOGS_USER_TITLE", "Get workflow run logs"),
 				ReadOnlyHint: ToBoolPtr(true),
@@ -396,6 +415,7 @@ func GetWorkflowRunLogs(getClient GetClientFn, t translations.TranslationHelperF
 				mcp.Required(),
 				mcp.Description("The unique identifier of the workflow run"),
 			),
+			mcp.WithOutputSchema[WorkflowRunLogs](),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -417,31 +437,315 @@ func GetWorkflowRunLogs(getClient GetClientFn, t translations.TranslationHelperF
 				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
 			}
 
-			// Get the download URL for the logs
-			url, resp, err := client.Actions.GetWorkflowRunLogs(ctx, owner, repo, runID, 1)
+			archive, release, resp, err := runLogs.open(ctx, client, owner, repo, runID)
+			if resp != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get workflow run logs", resp, err), nil
+			}
 			if err != nil {
-				return nil, fmt.Errorf("failed to get workflow run logs: %w", err)
+				return mcp.NewToolResultError(err.Error()), nil
 			}
-			defer func() { _ = resp.Body.Close() }()
+			defer release()
 
-			// Create response with the logs URL and information
-			result := map[string]any{
-				"logs_url":         url.String(),
-				"message":          "Workflow run logs are available for download",
-				"note":             "The logs_url provides a download link for the complete workflow run logs as a ZIP archive. You can download this archive to extract and examine individual job logs.",
-				"warning":          "This downloads ALL logs as a ZIP file which can be large and expensive. For debugging failed jobs, consider using get_job_logs with failed_only=true and run_id instead.",
-				"optimization_tip": "Use: get_job_logs with parameters {run_id: " + fmt.Sprintf("%d", runID) + ", failed_only: true} for more efficient failed job debugging",
+			return StructuredResult(WorkflowRunLogs{
+				RunID:   runID,
+				Jobs:    archive.Jobs,
+				Message: fmt.Sprintf("Found the logs of %d jobs. Read or search a job or step log with get_workflow_run_step_log, giving the job name and step number", len(archive.Jobs)),
+			}), nil
+		}
+}
+
+// WorkflowRunStepLog is the log of a job, or of one of its steps, read from
+// the logs of a workflow run.
+type WorkflowRunStepLog struct {
+	RunID          int    `json:"run_id"`
+	Job            string `json:"job"`
+	Step           int    `json:"step,omitempty"`
+	StepName       string `json:"step_name,omitempty"`
+	LogsContent    string `json:"logs_content"`
+	OriginalLength int    `json:"original_length"`
+	MatchedLines   *int   `json:"matched_lines,omitempty"`
+	Note           string `json:"note,omitempty"`
+}
+
+// GetWorkflowRunStepLog creates a tool to read or search the log of a job or step of a workflow run
+func GetWorkflowRunStepLog(getClient GetClientFn, runLogs *RunLogsCache, t translations.TranslationHelperFunc, contentWindowSize int) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_workflow_run_step_log",
+			mcp.WithDescription(t("TOOL_GET_WORKFLOW_RUN_STEP_LOG_DESCRIPTION", "Read or search the log of a job, or of one of its steps, from the logs of a workflow run. Get the job names and step numbers with get_workflow_run_logs")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_WORKFLOW_RUN_STEP_LOG_USER_TITLE", "Get workflow run step log"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithOutputSchema[WorkflowRunStepLog](),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithNumber("run_id",
+				mcp.Required(),
+				mcp.Description("The unique identifier of the workflow run"),
+			),
+			mcp.WithString("job",
+				mcp.Required(),
+				mcp.Description("Name of the job, as listed by get_workflow_run_logs"),
+			),
+			mcp.WithNumber("step",
+				mcp.Description("Number of the step, as listed by get_workflow_run_logs. Reads the whole job log when omitted"),
+			),
+			mcp.WithNumber("tail_lines",
+				mcp.Description("Number of lines to return from the end of the log. Defaults to 500 when no other lines are selected"),
+			),
+			mcp.WithNumber("head_lines",
+				mcp.Description("Number of lines to return from the start of the log, on top of tail_lines"),
+			),
+			mcp.WithString("grep",
+				mcp.Description("Regular expression (RE2 syntax) selecting the log lines to return, on top of head_lines and tail_lines, e.g. 'error|FAIL'"),
+			),
+			mcp.WithNumber("context_lines",
+				mcp.Description("Number of lines to return before and after each line matching grep"),
+			),
+			mcp.WithNumber("start_line",
+				mcp.Description("First line of the log to read, counted from 1. Alone, start_line and end_line return the lines between them"),
+			),
+			mcp.WithNumber("end_line",
+				mcp.Description("Last line of the log to read, inclusive"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			runID, err := RequiredInt(request, "run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			jobName, err := RequiredParam[string](request, "job")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			stepNumber, err := OptionalIntParam(request, "step")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			selection, err := jobLogSelectionParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
 			}
 
-			r, err := json.Marshal(result)
+			client, err := getClient(ctx)
 			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			archive, release, resp, err := runLogs.open(ctx, client, owner, repo, int64(runID))
+			if resp != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get workflow run logs", resp, err), nil
+			}
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			defer release()
+
+			job := archive.Job(jobName)
+			if job == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("job %q not found in the logs of run %d", jobName, runID)), nil
+			}
+			result := WorkflowRunStepLog{
+				RunID: runID,
+				Job:   job.Name,
+			}
+
+			var logs *buffer.Result
+			if stepNumber > 0 {
+				step := job.Step(stepNumber)
+				if step == nil {
+					return mcp.NewToolResultError(fmt.Sprintf("step %d not found in job %q", stepNumber, job.Name)), nil
+				}
+				result.Step = step.Number
+				result.StepName = step.Name
+				logs, err = archive.ReadStep(step, selection.options(contentWindowSize))
+			} else {
+				logs, err = archive.ReadJob(job, selection.options(contentWindowSize))
+			}
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			result.LogsContent = logs.String()
+			result.OriginalLength = logs.TotalLines
+			if selection.grep != nil {
+				result.MatchedLines = &logs.MatchCount
+			}
+			if logs.Truncated {
+				result.Note = fmt.Sprintf("Only the first %d selected lines are returned. Narrow down grep or the line range to see the others.", contentWindowSize)
+			}
+
+			return StructuredResult(result), nil
 		}
 }
 
+// Defaults of NewRunLogsCache.
+const (
+	DefaultRunLogsCacheSize = 4
+	DefaultRunLogsCacheTTL  = 10 * time.Minute
+)
+
+// RunLogsCache keeps the log archives of the most recently read workflow
+// runs open, so that get_workflow_run_logs and the get_workflow_run_step_log
+// calls after it download the logs of a run once. An archive is closed when
+// it expires or is evicted, and no call is still reading it.
+type RunLogsCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	order      *list.List
+	entries    map[string]*list.Element
+}
+
+type cachedRunLogs struct {
+	key     string
+	archive *runlogs.Archive
+	timer   *time.Timer
+	// refs counts the calls reading the archive
+	refs    int
+	evicted bool
+}
+
+// NewRunLogsCache creates a cache holding at most maxEntries archives, each
+// for at most ttl.
+func NewRunLogsCache(maxEntries int, ttl time.Duration) *RunLogsCache {
+	if maxEntries <= 0 {
+		maxEntries = 1
+	}
+	return &RunLogsCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// open returns the log archive of a workflow run, downloading it unless it is
+// cached. The caller calls release when done reading the archive. A non-nil
+// response means the API failed to give the URL of the archive.
+func (c *RunLogsCache) open(ctx context.Context, client *github.Client, owner, repo string, runID int64) (*runlogs.Archive, func(), *github.Response, error) {
+	key := fmt.Sprintf("%s/%s/%d", strings.ToLower(owner), strings.ToLower(repo), runID)
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cachedRunLogs)
+		entry.refs++
+		c.order.MoveToFront(elem)
+		c.mu.Unlock()
+		return entry.archive, c.releaseFunc(entry), nil, nil
+	}
+	c.mu.Unlock()
+
+	archive, resp, err := openRunLogs(ctx, client, owner, repo, runID)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		// Opened by another call meanwhile
+		c.evict(elem)
+	}
+	entry := &cachedRunLogs{key: key, archive: archive, refs: 1}
+	elem := c.order.PushFront(entry)
+	c.entries[key] = elem
+	entry.timer = time.AfterFunc(c.ttl, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if !entry.evicted {
+			c.evict(elem)
+		}
+	})
+	for c.order.Len() > c.maxEntries {
+		c.evict(c.order.Back())
+	}
+	return archive, c.releaseFunc(entry), nil, nil
+}
+
+func (c *RunLogsCache) releaseFunc(entry *cachedRunLogs) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			entry.refs--
+			if entry.evicted && entry.refs == 0 {
+				_ = entry.archive.Close()
+			}
+		})
+	}
+}
+
+// Close evicts every archive, closing those no call is reading and the rest
+// as their calls finish.
+func (c *RunLogsCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.order.Len() > 0 {
+		c.evict(c.order.Back())
+	}
+	return nil
+}
+
+// evict removes elem from the cache, closing its archive unless a call is
+// still reading it. c.mu is held.
+func (c *RunLogsCache) evict(elem *list.Element) {
+	entry := elem.Value.(*cachedRunLogs)
+	c.order.Remove(elem)
+	delete(c.entries, entry.key)
+	entry.evicted = true
+	entry.timer.Stop()
+	if entry.refs == 0 {
+		_ = entry.archive.Close()
+	}
+}
+
+// openRunLogs downloads the log archive of a workflow run and indexes it.
+// The caller closes the archive. A non-nil response means the API failed to
+// give the URL of the archive.
+func openRunLogs(ctx context.Context, client *github.Client, owner, repo string, runID int64) (*runlogs.Archive, *github.Response, error) {
+	url, resp, err := client.Actions.GetWorkflowRunLogs(ctx, owner, repo, runID, 1)
+	if err != nil {
+		return nil, resp, fmt.Errorf("failed to get workflow run logs: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	httpResp, err := downloadLogs(ctx, client, url.String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to download logs: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to download logs: HTTP %d", httpResp.StatusCode)
+	}
+
+	archive, err := runlogs.Open(httpResp.Body, runlogs.Limits{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return archive, nil, nil
+}
+
+// WorkflowJobs is the result of list_workflow_jobs.
+type WorkflowJobs struct {
+	Jobs            MinimalWorkflowJobsResult `json:"jobs"`
+	OptimizationTip string                    `json:"optimization_tip"`
+}
+
 // ListWorkflowJobs creates a tool to list jobs for a specific workflow run
 func ListWorkflowJobs(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("list_workflow_jobs",
@@ -450,6 +754,7 @@ func ListWorkflowJobs(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_LIST_WORKFLOW_JOBS_USER_TITLE", "List workflow jobs"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[WorkflowJobs](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -515,21 +820,52 @@ func ListWorkflowJobs(getClient GetClientFn, t translations.TranslationHelperFun
 			}
 			defer func() { _ = resp.Body.Close() }()
 
-			// Add optimization tip for failed job debugging
-			response := map[string]any{
-				"jobs":             jobs,
-				"optimization_tip": "For debugging failed jobs, consider using get_job_logs with failed_only=true and run_id=" + fmt.Sprintf("%d", runID) + " to get logs directly without needing to list jobs first",
-			}
-
-			r, err := json.Marshal(response)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalJobs := make([]MinimalWorkflowJob, 0, len(jobs.Jobs))
+			for _, job := range jobs.Jobs {
+				minimalJobs = append(minimalJobs, convertToMinimalWorkflowJob(job))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			// Add optimization tip for failed job debugging
+			return StructuredResult(WorkflowJobs{
+				Jobs: MinimalWorkflowJobsResult{
+					TotalCount: jobs.GetTotalCount(),
+					Jobs:       minimalJobs,
+				},
+				OptimizationTip: "For debugging failed jobs, consider using get_job_logs with failed_only=true and run_id=" + fmt.Sprintf("%d", runID) + " to get logs directly without needing to list jobs first",
+			}), nil
 		}
 }
 
+// JobLog is the log of a workflow job, as a download URL or as content.
+type JobLog struct {
+	JobID          int64  `json:"job_id,omitempty"`
+	JobName        string `json:"job_name,omitempty"`
+	LogsURL        string `json:"logs_url,omitempty"`
+	LogsContent    string `json:"logs_content,omitempty"`
+	Message        string `json:"message,omitempty"`
+	OriginalLength *int   `json:"original_length,omitempty"`
+	MatchedLines   *int   `json:"matched_lines,omitempty"`
+	Note           string `json:"note,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// JobLogs is the result of get_job_logs. A single job fills in the embedded
+// JobLog; failed_only fills in the run totals and the log of each failed job.
+type JobLogs struct {
+	JobLog
+	RunID        int64          `json:"run_id,omitempty"`
+	TotalJobs    *int           `json:"total_jobs,omitempty"`
+	FailedJobs   *int           `json:"failed_jobs,omitempty"`
+	Logs         []JobLog       `json:"logs,omitempty"`
+	ReturnFormat *JobLogsFormat `json:"return_format,omitempty"`
+}
+
+// JobLogsFormat reports whether get_job_logs returned log content or URLs.
+type JobLogsFormat struct {
+	Content bool `json:"content"`
+	URLs    bool `json:"urls"`
+}
+
 // GetJobLogs creates a tool to download logs for a specific workflow job or efficiently get all failed job logs for a workflow run
 func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc, contentWindowSize int) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("get_job_logs",
@@ -538,6 +874,7 @@ func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc, con
 				Title:        t("TOOL_GET_JOB_LOGS_USER_TITLE", "Get job logs"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[JobLogs](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -559,9 +896,18 @@ func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc, con
 				mcp.Description("Returns actual log content instead of URLs"),
 			),
 			mcp.WithNumber("tail_lines",
-				mcp.Description("Number of lines to return from the end of the log"),
+				mcp.Description("Number of lines to return from the end of the log. Defaults to 500 when neither head_lines nor grep is given"),
 				mcp.DefaultNumber(500),
 			),
+			mcp.WithNumber("head_lines",
+				mcp.Description("Number of lines to return from the start of the log, on top of tail_lines"),
+			),
+			mcp.WithString("grep",
+				mcp.Description("Regular expression (RE2 syntax) selecting the log lines to return, on top of head_lines and tail_lines, e.g. 'error|FAIL'"),
+			),
+			mcp.WithNumber("context_lines",
+				mcp.Description("Number of lines to return before and after each line matching grep"),
+			),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -590,14 +936,10 @@ func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc, con
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
-			tailLines, err := OptionalIntParam(request, "tail_lines")
+			selection, err := jobLogSelectionParams(request)
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
-			// Default to 500 lines if not specified
-			if tailLines == 0 {
-				tailLines = 500
-			}
 
 			client, err := getClient(ctx)
 			if err != nil {
@@ -614,18 +956,99 @@ func GetJobLogs(getClient GetClientFn, t translations.TranslationHelperFunc, con
 
 			if failedOnly && runID > 0 {
 				// Handle failed-only mode: get logs for all failed jobs in the workflow run
-				return handleFailedJobLogs(ctx, client, owner, repo, int64(runID), returnContent, tailLines, contentWindowSize)
+				return handleFailedJobLogs(ctx, client, owner, repo, int64(runID), returnContent, selection, contentWindowSize)
 			} else if jobID > 0 {
 				// Handle single job mode
-				return handleSingleJobLogs(ctx, client, owner, repo, int64(jobID), returnContent, tailLines, contentWindowSize)
+				return handleSingleJobLogs(ctx, client, owner, repo, int64(jobID), returnContent, selection, contentWindowSize)
 			}
 
 			return mcp.NewToolResultError("Either job_id must be provided for single job logs, or run_id with failed_only=true for failed job logs"), nil
 		}
 }
 
+// jobLogSelection selects the lines of job logs returned as content: the
+// first headLines and last tailLines, and the lines matching grep with
+// contextLines lines around them, all between startLine and endLine.
+type jobLogSelection struct {
+	headLines    int
+	tailLines    int
+	grep         *regexp.Regexp
+	contextLines int
+	startLine    int
+	endLine      int
+}
+
+// jobLogSelectionParams reads the tail_lines, head_lines, grep and
+// context_lines parameters of a request, and start_line and end_line when the
+// tool has them.
+func jobLogSelectionParams(request mcp.CallToolRequest) (jobLogSelection, error) {
+	tailLines, err := OptionalIntParam(request, "tail_lines")
+	if err != nil {
+		return jobLogSelection{}, err
+	}
+	headLines, err := OptionalIntParam(request, "head_lines")
+	if err != nil {
+		return jobLogSelection{}, err
+	}
+	grepPattern, err := OptionalParam[string](request, "grep")
+	if err != nil {
+		return jobLogSelection{}, err
+	}
+	contextLines, err := OptionalIntParam(request, "context_lines")
+	if err != nil {
+		return jobLogSelection{}, err
+	}
+	startLine, err := OptionalIntParam(request, "start_line")
+	if err != nil {
+		return jobLogSelection{}, err
+	}
+	endLine, err := OptionalIntParam(request, "end_line")
+	if err != nil {
+		return jobLogSelection{}, err
+	}
+	if tailLines < 0 || headLines < 0 || contextLines < 0 {
+		return jobLogSelection{}, errors.New("tail_lines, head_lines and context_lines must not be negative")
+	}
+	if startLine < 0 || endLine < 0 || (endLine > 0 && endLine < startLine) {
+		return jobLogSelection{}, errors.New("start_line and end_line must be positive, with end_line not before start_line")
+	}
+
+	selection := jobLogSelection{
+		tailLines:    tailLines,
+		headLines:    headLines,
+		contextLines: contextLines,
+		startLine:    startLine,
+		endLine:      endLine,
+	}
+	if grepPattern != "" {
+		selection.grep, err = regexp.Compile(grepPattern)
+		if err != nil {
+			return jobLogSelection{}, fmt.Errorf("invalid grep pattern: %w", err)
+		}
+	}
+	// Default to the last 500 lines if no lines are selected
+	if selection.tailLines == 0 && selection.headLines == 0 && selection.grep == nil && selection.startLine == 0 && selection.endLine == 0 {
+		selection.tailLines = 500
+	}
+	return selection, nil
+}
+
+// options returns the buffer options of the selection, each part capped at
+// maxLines.
+func (s jobLogSelection) options(maxLines int) buffer.Options {
+	return buffer.Options{
+		HeadLines:     min(s.headLines, maxLines),
+		TailLines:     min(s.tailLines, maxLines),
+		Grep:          s.grep,
+		ContextLines:  s.contextLines,
+		MaxMatchLines: maxLines,
+		StartLine:     s.startLine,
+		EndLine:       s.endLine,
+	}
+}
+
 // handleFailedJobLogs gets logs for all failed jobs in a workflow run
-func handleFailedJobLogs(ctx context.Context, client *github.Client, owner, repo string, runID int64, returnContent bool, tailLines int, contentWindowSize int) (*mcp.CallToolResult, error) {
+func handleFailedJobLogs(ctx context.Context, client *github.Client, owner, repo string, runID int64, returnContent bool, selection jobLogSelection, contentWindowSize int) (*mcp.CallToolResult, error) {
 	// First, get all jobs for the workflow run
 	jobs, resp, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, &github.ListWorkflowJobsOptions{
 		Filter: "latest",
@@ -643,27 +1066,27 @@ func handleFailedJobLogs(ctx context.Context, client *github.Client, owner, repo
 		}
 	}
 
-	if len(failedJobs) == 0 {
-		result := map[string]any{
-			"message":     "No failed jobs found in this workflow run",
-			"run_id":      runID,
-			"total_jobs":  len(jobs.Jobs),
-			"failed_jobs": 0,
-		}
-		r, _ := json.Marshal(result)
-		return mcp.NewToolResultText(string(r)), nil
+	totalJobs := len(jobs.Jobs)
+	failedJobCount := len(failedJobs)
+	if failedJobCount == 0 {
+		return StructuredResult(JobLogs{
+			JobLog:     JobLog{Message: "No failed jobs found in this workflow run"},
+			RunID:      runID,
+			TotalJobs:  &totalJobs,
+			FailedJobs: &failedJobCount,
+		}), nil
 	}
 
 	// Collect logs for all failed jobs
-	var logResults []map[string]any
+	var logResults []JobLog
 	for _, job := range failedJobs {
-		jobResult, resp, err := getJobLogData(ctx, client, owner, repo, job.GetID(), job.GetName(), returnContent, tailLines, contentWindowSize)
+		jobResult, resp, err := getJobLogData(ctx, client, owner, repo, job.GetID(), job.GetName(), returnContent, selection, contentWindowSize)
 		if err != nil {
 			// Continue with other jobs even if one fails
-			jobResult = map[string]any{
-				"job_id":   job.GetID(),
-				"job_name": job.GetName(),
-				"error":    err.Error(),
+			jobResult = JobLog{
+				JobID:   job.GetID(),
+				JobName: job.GetName(),
+				Error:   err.Error(),
 			}
 			// Enable reporting of status codes and error causes
 			_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get job logs", resp, err) // Explicitly ignore error for graceful handling
@@ -672,110 +1095,308 @@ func handleFailedJobLogs(ctx context.Context, client *github.Client, owner, repo
 		logResults = append(logResults, jobResult)
 	}
 
-	result := map[string]any{
-		"message":       fmt.Sprintf("Retrieved logs for %d failed jobs", len(failedJobs)),
-		"run_id":        runID,
-		"total_jobs":    len(jobs.Jobs),
-		"failed_jobs":   len(failedJobs),
-		"logs":          logResults,
-		"return_format": map[string]bool{"content": returnContent, "urls": !returnContent},
-	}
-
-	r, err := json.Marshal(result)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response: %w", err)
-	}
-
-	return mcp.NewToolResultText(string(r)), nil
+	return StructuredResult(JobLogs{
+		JobLog:       JobLog{Message: fmt.Sprintf("Retrieved logs for %d failed jobs", failedJobCount)},
+		RunID:        runID,
+		TotalJobs:    &totalJobs,
+		FailedJobs:   &failedJobCount,
+		Logs:         logResults,
+		ReturnFormat: &JobLogsFormat{Content: returnContent, URLs: !returnContent},
+	}), nil
 }
 
 // handleSingleJobLogs gets logs for a single job
-func handleSingleJobLogs(ctx context.Context, client *github.Client, owner, repo string, jobID int64, returnContent bool, tailLines int, contentWindowSize int) (*mcp.CallToolResult, error) {
-	jobResult, resp, err := getJobLogData(ctx, client, owner, repo, jobID, "", returnContent, tailLines, contentWindowSize)
+func handleSingleJobLogs(ctx context.Context, client *github.Client, owner, repo string, jobID int64, returnContent bool, selection jobLogSelection, contentWindowSize int) (*mcp.CallToolResult, error) {
+	jobResult, resp, err := getJobLogData(ctx, client, owner, repo, jobID, "", returnContent, selection, contentWindowSize)
 	if err != nil {
 		return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get job logs", resp, err), nil
 	}
 
-	r, err := json.Marshal(jobResult)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal response: %w", err)
-	}
-
-	return mcp.NewToolResultText(string(r)), nil
+	return StructuredResult(JobLogs{JobLog: jobResult}), nil
 }
 
 // getJobLogData retrieves log data for a single job, either as URL or content
-func getJobLogData(ctx context.Context, client *github.Client, owner, repo string, jobID int64, jobName string, returnContent bool, tailLines int, contentWindowSize int) (map[string]any, *github.Response, error) {
+func getJobLogData(ctx context.Context, client *github.Client, owner, repo string, jobID int64, jobName string, returnContent bool, selection jobLogSelection, contentWindowSize int) (JobLog, *github.Response, error) {
 	// Get the download URL for the job logs
 	url, resp, err := client.Actions.GetWorkflowJobLogs(ctx, owner, repo, jobID, 1)
 	if err != nil {
-		return nil, resp, fmt.Errorf("failed to get job logs for job %d: %w", jobID, err)
+		return JobLog{}, resp, fmt.Errorf("failed to get job logs for job %d: %w", jobID, err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	result := map[string]any{
-		"job_id": jobID,
-	}
-	if jobName != "" {
-		result["job_name"] = jobName
+	result := JobLog{
+		JobID:   jobID,
+		JobName: jobName,
 	}
 
 	if returnContent {
 		// Download and return the actual log content
-		content, originalLength, httpResp, err := downloadLogContent(ctx, url.String(), tailLines, contentWindowSize) //nolint:bodyclose // Response body is closed in downloadLogContent, but we need to return httpResp
+		logs, httpResp, err := downloadLogContent(ctx, client, url.String(), selection, contentWindowSize) //nolint:bodyclose // Response body is closed in downloadLogContent, but we need to return httpResp
 		if err != nil {
 			// To keep the return value consistent wrap the response as a GitHub Response
 			ghRes := &github.Response{
 				Response: httpResp,
 			}
-			return nil, ghRes, fmt.Errorf("failed to download log content for job %d: %w", jobID, err)
+			return JobLog{}, ghRes, fmt.Errorf("failed to download log content for job %d: %w", jobID, err)
+		}
+		result.LogsContent = logs.String()
+		result.Message = "Job logs content retrieved successfully"
+		result.OriginalLength = &logs.TotalLines
+		if selection.grep != nil {
+			result.MatchedLines = &logs.MatchCount
+			if logs.Truncated {
+				result.Note = fmt.Sprintf("Only the first %d lines of matches and their context are returned. Narrow down grep to see the others.", contentWindowSize)
+			}
 		}
-		result["logs_content"] = content
-		result["message"] = "Job logs content retrieved successfully"
-		result["original_length"] = originalLength
 	} else {
 		// Return just the URL
-		result["logs_url"] = url.String()
-		result["message"] = "Job logs are available for download"
-		result["note"] = "The logs_url provides a download link for the individual job logs in plain text format. Use return_content=true to get the actual log content."
+		result.LogsURL = url.String()
+		result.Message = "Job logs are available for download"
+		result.Note = "The logs_url provides a download link for the individual job logs in plain text format. Use return_content=true to get the actual log content."
 	}
 
 	return result, resp, nil
 }
 
-func downloadLogContent(ctx context.Context, logURL string, tailLines int, maxLines int) (string, int, *http.Response, error) {
+// downloadLogContent downloads a job log and keeps the lines selection
+// selects, each part capped at maxLines.
+func downloadLogContent(ctx context.Context, client *github.Client, logURL string, selection jobLogSelection, maxLines int) (*buffer.Result, *http.Response, error) {
 	prof := profiler.New(nil, profiler.IsProfilingEnabled())
 	finish := prof.Start(ctx, "log_buffer_processing")
 
-	httpResp, err := http.Get(logURL) //nolint:gosec
+	httpResp, err := downloadLogs(ctx, client, logURL)
 	if err != nil {
-		return "", 0, httpResp, fmt.Errorf("failed to download logs: %w", err)
+		return nil, httpResp, fmt.Errorf("failed to download logs: %w", err)
 	}
 	defer func() { _ = httpResp.Body.Close() }()
 
 	if httpResp.StatusCode != http.StatusOK {
-		return "", 0, httpResp, fmt.Errorf("failed to download logs: HTTP %d", httpResp.StatusCode)
+		return nil, httpResp, fmt.Errorf("failed to download logs: HTTP %d", httpResp.StatusCode)
 	}
 
-	bufferSize := tailLines
-	if bufferSize > maxLines {
-		bufferSize = maxLines
+	logs, err := buffer.Process(httpResp.Body, selection.options(maxLines))
+	if err != nil {
+		return nil, httpResp, fmt.Errorf("failed to process log content: %w", err)
 	}
 
-	processedInput, totalLines, httpResp, err := buffer.ProcessResponseAsRingBufferToEnd(httpResp, bufferSize)
+	lines := logs.Lines()
+	var size int64
+	for _, line := range lines {
+		size += int64(len(line.Text)) + 1
+	}
+	_ = finish(len(lines), size)
+
+	return logs, httpResp, nil
+}
+
+// downloadLogs gets the logs at the URL the API redirected to through the
+// client's transport, so the download is cancelled with ctx and recorded,
+// traced and metered like the API calls before it. The response cache only
+// keeps API responses, so the signed download URL is not cached.
+func downloadLogs(ctx context.Context, client *github.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", 0, httpResp, fmt.Errorf("failed to process log content: %w", err)
+		return nil, err
 	}
+	return client.Client().Do(req)
+}
 
-	lines := strings.Split(processedInput, "\n")
-	if len(lines) > tailLines {
-		lines = lines[len(lines)-tailLines:]
+// JobFailureAnalysis is the analysis of the log of a failed job.
+type JobFailureAnalysis struct {
+	JobID      int64  `json:"job_id"`
+	JobName    string `json:"job_name,omitempty"`
+	Conclusion string `json:"conclusion,omitempty"`
+	HTMLURL    string `json:"html_url,omitempty"`
+	// FailedStep is the step the job reports as failed, or else the first
+	// step with an error annotation in the log.
+	FailedStep  string         `json:"failed_step,omitempty"`
+	Summary     string         `json:"summary"`
+	Errors      []joblog.Error `json:"errors"`
+	TotalErrors int            `json:"total_errors"`
+	Steps       []joblog.Step  `json:"steps"`
+	TotalLines  int            `json:"total_lines"`
+	// Error is set when the log of the job could not be analyzed.
+	Error string `json:"error,omitempty"`
+}
+
+// JobFailureAnalysisResult is the output of analyze_job_failure.
+type JobFailureAnalysisResult struct {
+	RunID   int64                `json:"run_id,omitempty"`
+	Message string               `json:"message,omitempty"`
+	Jobs    []JobFailureAnalysis `json:"jobs"`
+}
+
+// AnalyzeJobFailure creates a tool to extract the errors from the logs of failed jobs
+func AnalyzeJobFailure(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("analyze_job_failure",
+			mcp.WithDescription(t("TOOL_ANALYZE_JOB_FAILURE_DESCRIPTION", "Analyze the log of a failed workflow job, or of every failed job in a workflow run, and return a compact summary: the failed step, and the errors found in the log with their file:line, from ##[error] annotations, Go test, pytest, Jest, Maven, Gradle and compilers. Use it before reading raw logs with get_job_logs")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_ANALYZE_JOB_FAILURE_USER_TITLE", "Analyze job failure"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithNumber("job_id",
+				mcp.Description("The unique identifier of the workflow job to analyze"),
+			),
+			mcp.WithNumber("run_id",
+				mcp.Description("Workflow run ID, to analyze every failed job of the run instead of a single job"),
+			),
+			mcp.WithNumber("max_errors",
+				mcp.Description("Maximum number of errors to return per job"),
+				mcp.DefaultNumber(joblog.DefaultMaxErrors),
+			),
+			mcp.WithOutputSchema[JobFailureAnalysisResult](),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			jobID, err := OptionalIntParam(request, "job_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			runID, err := OptionalIntParam(request, "run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			maxErrors, err := OptionalIntParam(request, "max_errors")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if (jobID == 0) == (runID == 0) {
+				return mcp.NewToolResultError("exactly one of job_id and run_id is required"), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			opts := joblog.Options{MaxErrors: maxErrors}
+
+			if jobID > 0 {
+				job, resp, err := client.Actions.GetWorkflowJobByID(ctx, owner, repo, int64(jobID))
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to get workflow job", resp, err), nil
+				}
+				_ = resp.Body.Close()
+
+				analysis, resp, err := analyzeJobLog(ctx, client, owner, repo, job, opts)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to analyze job logs", resp, err), nil
+				}
+				return StructuredResult(JobFailureAnalysisResult{Jobs: []JobFailureAnalysis{analysis}}), nil
+			}
+
+			jobs, resp, err := client.Actions.ListWorkflowJobs(ctx, owner, repo, int64(runID), &github.ListWorkflowJobsOptions{
+				Filter: "latest",
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx, "failed to list workflow jobs", resp, err), nil
+			}
+			_ = resp.Body.Close()
+
+			result := JobFailureAnalysisResult{RunID: int64(runID), Jobs: []JobFailureAnalysis{}}
+			for _, job := range jobs.Jobs {
+				if job.GetConclusion() != "failure" {
+					continue
+				}
+				analysis, resp, err := analyzeJobLog(ctx, client, owner, repo, job, opts)
+				if err != nil {
+					// Continue with other jobs even if one fails
+					analysis = JobFailureAnalysis{JobID: job.GetID(), JobName: job.GetName(), Error: err.Error()}
+					_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to analyze job logs", resp, err)
+				}
+				result.Jobs = append(result.Jobs, analysis)
+			}
+			if len(result.Jobs) == 0 {
+				result.Message = fmt.Sprintf("No failed jobs found in this workflow run, out of %d jobs", len(jobs.Jobs))
+			}
+			return StructuredResult(result), nil
+		}
+}
+
+// analyzeJobLog downloads the log of job and extracts its steps and errors.
+func analyzeJobLog(ctx context.Context, client *github.Client, owner, repo string, job *github.WorkflowJob, opts joblog.Options) (JobFailureAnalysis, *github.Response, error) {
+	url, resp, err := client.Actions.GetWorkflowJobLogs(ctx, owner, repo, job.GetID(), 1)
+	if err != nil {
+		return JobFailureAnalysis{}, resp, fmt.Errorf("failed to get job logs for job %d: %w", job.GetID(), err)
+	}
+	_ = resp.Body.Close()
+
+	httpResp, err := downloadLogs(ctx, client, url.String())
+	if err != nil {
+		return JobFailureAnalysis{}, &github.Response{Response: httpResp}, fmt.Errorf("failed to download logs: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+	if httpResp.StatusCode != http.StatusOK {
+		return JobFailureAnalysis{}, &github.Response{Response: httpResp}, fmt.Errorf("failed to download logs: HTTP %d", httpResp.StatusCode)
 	}
-	finalResult := strings.Join(lines, "\n")
 
-	_ = finish(len(lines), int64(len(finalResult)))
+	analysis, err := joblog.Analyze(httpResp.Body, opts)
+	if err != nil {
+		return JobFailureAnalysis{}, &github.Response{Response: httpResp}, err
+	}
 
-	return finalResult, totalLines, httpResp, nil
+	result := JobFailureAnalysis{
+		JobID:       job.GetID(),
+		JobName:     job.GetName(),
+		Conclusion:  job.GetConclusion(),
+		HTMLURL:     job.GetHTMLURL(),
+		Errors:      analysis.Errors,
+		TotalErrors: analysis.TotalErrors,
+		Steps:       analysis.Steps,
+		TotalLines:  analysis.TotalLines,
+	}
+	for _, step := range job.Steps {
+		if step.GetConclusion() == "failure" {
+			result.FailedStep = step.GetName()
+			break
+		}
+	}
+	if result.FailedStep == "" {
+		if step := analysis.FailedStep(); step != nil {
+			result.FailedStep = step.Name
+		}
+	}
+	result.Summary = failureSummary(result)
+	return result, resp, nil
+}
+
+// failureSummary describes the failure of a job in a sentence.
+func failureSummary(a JobFailureAnalysis) string {
+	var b strings.Builder
+	if a.FailedStep != "" {
+		fmt.Fprintf(&b, "Step %q failed", a.FailedStep)
+	} else {
+		b.WriteString("No failed step found")
+	}
+	if len(a.Errors) == 0 {
+		b.WriteString("; no errors were recognized in the log, read it with get_job_logs")
+		return b.String()
+	}
+	fmt.Fprintf(&b, " with %d error(s)", a.TotalErrors)
+	// Errors found by the matchers come first; of those, the ones pointing at
+	// a file say the most
+	for _, e := range a.Errors {
+		if location := e.Location(); location != "" {
+			fmt.Fprintf(&b, "; first at %s: %s", location, e.Message)
+			return b.String()
+		}
+	}
+	fmt.Fprintf(&b, "; first: %s", a.Errors[0].Message)
+	return b.String()
 }
 
 // RerunWorkflowRun creates a tool to re-run an entire workflow run
@@ -798,6 +1419,7 @@ func RerunWorkflowRun(getClient GetClientFn, t translations.TranslationHelperFun
 				mcp.Required(),
 				mcp.Description("The unique identifier of the workflow run"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -861,6 +1483,7 @@ func RerunFailedJobs(getClient GetClientFn, t translations.TranslationHelperFunc
 				mcp.Required(),
 				mcp.Description("The unique identifier of the workflow run"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -909,8 +1532,9 @@ func CancelWorkflowRun(getClient GetClientFn, t translations.TranslationHelperFu
 	return mcp.NewTool("cancel_workflow_run",
 			mcp.WithDescription(t("TOOL_CANCEL_WORKFLOW_RUN_DESCRIPTION", "Cancel a workflow run")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
-				Title:        t("TOOL_CANCEL_WORKFLOW_RUN_USER_TITLE", "Cancel workflow run"),
-				ReadOnlyHint: ToBoolPtr(false),
+				Title:           t("TOOL_CANCEL_WORKFLOW_RUN_USER_TITLE", "Cancel workflow run"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
 			}),
 			mcp.WithString("owner",
 				mcp.Required(),
@@ -924,6 +1548,7 @@ func CancelWorkflowRun(getClient GetClientFn, t translations.TranslationHelperFu
 				mcp.Required(),
 				mcp.Description("The unique identifier of the workflow run"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -977,6 +1602,7 @@ func ListWorkflowRunArtifacts(getClient GetClientFn, t translations.TranslationH
 				Title:        t("TOOL_LIST_WORKFLOW_RUN_ARTIFACTS_USER_TITLE", "List workflow artifacts"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalArtifactsResult](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -1029,15 +1655,26 @@ func ListWorkflowRunArtifacts(getClient GetClientFn, t translations.TranslationH
 			}
 			defer func() { _ = resp.Body.Close() }()
 
-			r, err := json.Marshal(artifacts)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalArtifacts := make([]MinimalArtifact, 0, len(artifacts.Artifacts))
+			for _, artifact := range artifacts.Artifacts {
+				minimalArtifacts = append(minimalArtifacts, convertToMinimalArtifact(artifact))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(MinimalArtifactsResult{
+				TotalCount: artifacts.GetTotalCount(),
+				Artifacts:  minimalArtifacts,
+			}), nil
 		}
 }
 
+// ArtifactDownload is the temporary download link of a workflow run artifact.
+type ArtifactDownload struct {
+	DownloadURL string `json:"download_url"`
+	Message     string `json:"message"`
+	Note        string `json:"note"`
+	ArtifactID  int64  `json:"artifact_id"`
+}
+
 // DownloadWorkflowRunArtifact creates a tool to download a workflow run artifact
 func DownloadWorkflowRunArtifact(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("download_workflow_run_artifact",
@@ -1046,6 +1683,7 @@ func DownloadWorkflowRunArtifact(getClient GetClientFn, t translations.Translati
 				Title:        t("TOOL_DOWNLOAD_WORKFLOW_RUN_ARTIFACT_USER_TITLE", "Download workflow artifact"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ArtifactDownload](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -1087,19 +1725,12 @@ func DownloadWorkflowRunArtifact(getClient GetClientFn, t translations.Translati
 			defer func() { _ = resp.Body.Close() }()
 
 			// Create response with the download URL and information
-			result := map[string]any{
-				"download_url": url.String(),
-				"message":      "Artifact is available for download",
-				"note":         "The download_url provides a download link for the artifact as a ZIP archive. The link is temporary and expires after a short time.",
-				"artifact_id":  artifactID,
-			}
-
-			r, err := json.Marshal(result)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(ArtifactDownload{
+				DownloadURL: url.String(),
+				Message:     "Artifact is available for download",
+				Note:        "The download_url provides a download link for the artifact as a ZIP archive. The link is temporary and expires after a short time.",
+				ArtifactID:  artifactID,
+			}), nil
 		}
 }
 
@@ -1124,6 +1755,7 @@ func DeleteWorkflowRunLogs(getClient GetClientFn, t translations.TranslationHelp
 				mcp.Required(),
 				mcp.Description("The unique identifier of the workflow run"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -1175,6 +1807,7 @@ func GetWorkflowRunUsage(getClient GetClientFn, t translations.TranslationHelper
 				Title:        t("TOOL_GET_WORKFLOW_RUN_USAGE_USER_TITLE", "Get workflow usage"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[github.WorkflowRunUsage](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description(DescriptionRepositoryOwner),
@@ -1214,11 +1847,6 @@ func GetWorkflowRunUsage(getClient GetClientFn, t translations.TranslationHelper
 			}
 			defer func() { _ = resp.Body.Close() }()
 
-			r, err := json.Marshal(usage)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(usage), nil
 		}
 }
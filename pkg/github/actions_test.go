@@ -1,6 +1,8 @@
 package github
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
@@ -11,9 +13,12 @@ import (
 	"runtime/debug"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/github/github-mcp-server/internal/profiler"
+	"github.com/github/github-mcp-server/internal/toolsnaps"
 	buffer "github.com/github/github-mcp-server/pkg/buffer"
+	"github.com/github/github-mcp-server/pkg/runlogs"
 	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/google/go-github/v74/github"
 	"github.com/migueleliasweb/go-github-mock/src/mock"
@@ -811,6 +816,321 @@ func Test_GetWorkflowRunUsage(t *testing.T) {
 	}
 }
 
+// logDownloadURL is where the mocked logs endpoints redirect to. The mocked
+// client sends every request to its own backend, so the download is served by
+// mockLogDownload.
+const logDownloadURL = "https://results.example.com/logs/download"
+
+// mockLogDownload serves content at logDownloadURL.
+func mockLogDownload(content string) mock.MockBackendOption {
+	return mock.WithRequestMatchHandler(
+		mock.EndpointPattern{Pattern: "/logs/download", Method: "GET"},
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(content))
+		}),
+	)
+}
+
+// runLogsArchive returns a run log archive holding files, by name.
+func runLogsArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return b.Bytes()
+}
+
+// mockRunLogs mocks the download of a run log archive holding files.
+func mockRunLogs(t *testing.T, files map[string]string) *http.Client {
+	t.Helper()
+	return mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposActionsRunsLogsByOwnerByRepoByRunId,
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Location", logDownloadURL)
+				w.WriteHeader(http.StatusFound)
+			}),
+		),
+		mockLogDownload(string(runLogsArchive(t, files))),
+	)
+}
+
+// newRunLogsCache returns a cache whose archives are closed when the test
+// ends.
+func newRunLogsCache(t *testing.T) *RunLogsCache {
+	c := NewRunLogsCache(DefaultRunLogsCacheSize, DefaultRunLogsCacheTTL)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+var runLogFiles = map[string]string{
+	"0_build.txt":            "setup\n##[group]Run go test ./...\n##[endgroup]\nok pkg/a\n--- FAIL: TestAdd (0.00s)\n##[error]Process completed with exit code 1.",
+	"build/1_Set up job.txt": "setup",
+	"build/2_Test.txt":       "##[group]Run go test ./...\n##[endgroup]\nok pkg/a\n--- FAIL: TestAdd (0.00s)\n##[error]Process completed with exit code 1.",
+	"1_lint.txt":             "##[group]Run golangci-lint run\n##[endgroup]\n0 issues.",
+}
+
+func Test_GetWorkflowRunLogs(t *testing.T) {
+	// Verify tool definition once
+	mockClient := github.NewClient(nil)
+	tool, _ := GetWorkflowRunLogs(stubGetClientFn(mockClient), newRunLogsCache(t), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	assert.Equal(t, "get_workflow_run_logs", tool.Name)
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "run_id"})
+
+	client := github.NewClient(mockRunLogs(t, runLogFiles))
+	_, handler := GetWorkflowRunLogs(stubGetClientFn(client), newRunLogsCache(t), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":  "owner",
+		"repo":   "repo",
+		"run_id": float64(456),
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var response WorkflowRunLogs
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+	assert.Equal(t, int64(456), response.RunID)
+	require.Len(t, response.Jobs, 2)
+
+	build := response.Jobs[0]
+	assert.Equal(t, "build", build.Name)
+	assert.Equal(t, 6, build.Lines)
+	assert.True(t, build.Failed)
+	require.Len(t, build.Steps, 2)
+	assert.Equal(t, "Test", build.Steps[1].Name)
+	assert.Equal(t, 5, build.Steps[1].Lines)
+	assert.True(t, build.Steps[1].Failed)
+
+	// The lint job has no step logs, its steps are found in its log
+	lint := response.Jobs[1]
+	assert.False(t, lint.Failed)
+	assert.Equal(t, []runlogs.Step{
+		{Number: 1, Name: "Run golangci-lint run", File: "1_lint.txt", StartLine: 1, EndLine: 3, Lines: 3},
+	}, lint.Steps)
+
+	t.Run("archive not found", func(t *testing.T) {
+		client := github.NewClient(mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposActionsRunsLogsByOwnerByRepoByRunId,
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+				}),
+			),
+		))
+		_, handler := GetWorkflowRunLogs(stubGetClientFn(client), newRunLogsCache(t), translations.NullTranslationHelper)
+		result, err := handler(context.Background(), createMCPRequest(map[string]any{
+			"owner":  "owner",
+			"repo":   "repo",
+			"run_id": float64(456),
+		}))
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "failed to get workflow run logs")
+	})
+
+	t.Run("archive not a ZIP", func(t *testing.T) {
+		client := github.NewClient(mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposActionsRunsLogsByOwnerByRepoByRunId,
+				http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.Header().Set("Location", logDownloadURL)
+					w.WriteHeader(http.StatusFound)
+				}),
+			),
+			mockLogDownload("not a zip"),
+		))
+		_, handler := GetWorkflowRunLogs(stubGetClientFn(client), newRunLogsCache(t), translations.NullTranslationHelper)
+		result, err := handler(context.Background(), createMCPRequest(map[string]any{
+			"owner":  "owner",
+			"repo":   "repo",
+			"run_id": float64(456),
+		}))
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Equal(t, "failed to read run log archive: zip: not a valid zip file", getErrorResult(t, result).Text)
+	})
+}
+
+func Test_RunLogsCache(t *testing.T) {
+	archive := string(runLogsArchive(t, runLogFiles))
+	downloads := 0
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposActionsRunsLogsByOwnerByRepoByRunId,
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Location", logDownloadURL)
+				w.WriteHeader(http.StatusFound)
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			mock.EndpointPattern{Pattern: "/logs/download", Method: "GET"},
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				downloads++
+				_, _ = w.Write([]byte(archive))
+			}),
+		),
+	))
+	ctx := context.Background()
+	c := newRunLogsCache(t)
+	c.maxEntries = 1
+
+	first, release1, _, err := c.open(ctx, client, "owner", "repo", 1)
+	require.NoError(t, err)
+	again, release2, _, err := c.open(ctx, client, "Owner", "Repo", 1)
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+	assert.Equal(t, 1, downloads)
+	release2()
+
+	// Evicting the first archive leaves it open until it is released
+	second, release3, _, err := c.open(ctx, client, "owner", "repo", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, downloads)
+	_, err = first.ReadJob(first.Job("build"), buffer.Options{})
+	require.NoError(t, err)
+	release1()
+	_, err = first.ReadJob(first.Job("build"), buffer.Options{})
+	require.Error(t, err)
+
+	// An expired archive is closed and downloaded again
+	release3()
+	c.ttl = time.Millisecond
+	_, release4, _, err := c.open(ctx, client, "owner", "repo", 3)
+	require.NoError(t, err)
+	release4()
+	_, err = second.ReadJob(second.Job("build"), buffer.Options{})
+	require.Error(t, err)
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.order.Len() == 0
+	}, time.Second, time.Millisecond)
+	third, release5, _, err := c.open(ctx, client, "owner", "repo", 3)
+	require.NoError(t, err)
+	release5()
+	assert.Equal(t, 4, downloads)
+
+	// Closing the cache closes the archives it holds
+	require.NoError(t, c.Close())
+	_, err = third.ReadJob(third.Job("build"), buffer.Options{})
+	require.Error(t, err)
+}
+
+func Test_GetWorkflowRunStepLog(t *testing.T) {
+	// Verify tool definition once
+	mockClient := github.NewClient(nil)
+	tool, _ := GetWorkflowRunStepLog(stubGetClientFn(mockClient), newRunLogsCache(t), translations.NullTranslationHelper, 5000)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	assert.Equal(t, "get_workflow_run_step_log", tool.Name)
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "run_id", "job"})
+
+	tests := []struct {
+		name             string
+		args             map[string]any
+		expectError      bool
+		expectedErrMsg   string
+		expectedContent  string
+		expectedLength   float64
+		expectedStepName string
+	}{
+		{
+			name:             "step log",
+			args:             map[string]any{"job": "build", "step": float64(2)},
+			expectedContent:  "##[group]Run go test ./...\n##[endgroup]\nok pkg/a\n--- FAIL: TestAdd (0.00s)\n##[error]Process completed with exit code 1.",
+			expectedLength:   5,
+			expectedStepName: "Test",
+		},
+		{
+			name:             "grep in step log",
+			args:             map[string]any{"job": "build", "step": float64(2), "grep": "FAIL"},
+			expectedContent:  "--- FAIL: TestAdd (0.00s)",
+			expectedLength:   5,
+			expectedStepName: "Test",
+		},
+		{
+			name:            "line range of job log",
+			args:            map[string]any{"job": "BUILD", "start_line": float64(4), "end_line": float64(5)},
+			expectedContent: "ok pkg/a\n--- FAIL: TestAdd (0.00s)",
+			expectedLength:  6,
+		},
+		{
+			name:             "step found in job log",
+			args:             map[string]any{"job": "lint", "step": float64(1), "tail_lines": float64(1)},
+			expectedContent:  "0 issues.",
+			expectedLength:   3,
+			expectedStepName: "Run golangci-lint run",
+		},
+		{
+			name:           "unknown job",
+			args:           map[string]any{"job": "deploy"},
+			expectError:    true,
+			expectedErrMsg: `job "deploy" not found in the logs of run 456`,
+		},
+		{
+			name:           "unknown step",
+			args:           map[string]any{"job": "build", "step": float64(9)},
+			expectError:    true,
+			expectedErrMsg: `step 9 not found in job "build"`,
+		},
+		{
+			name:           "invalid line range",
+			args:           map[string]any{"job": "build", "start_line": float64(5), "end_line": float64(2)},
+			expectError:    true,
+			expectedErrMsg: "end_line not before start_line",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(mockRunLogs(t, runLogFiles))
+			_, handler := GetWorkflowRunStepLog(stubGetClientFn(client), newRunLogsCache(t), translations.NullTranslationHelper, 5000)
+
+			args := map[string]any{
+				"owner":  "owner",
+				"repo":   "repo",
+				"run_id": float64(456),
+			}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			if tc.expectError {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+			require.False(t, result.IsError)
+
+			var response map[string]any
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+			assert.Equal(t, tc.expectedContent, response["logs_content"])
+			assert.Equal(t, tc.expectedLength, response["original_length"])
+			if tc.expectedStepName != "" {
+				assert.Equal(t, tc.expectedStepName, response["step_name"])
+			} else {
+				assert.NotContains(t, response, "step")
+			}
+		})
+	}
+}
+
 func Test_GetJobLogs(t *testing.T) {
 	// Verify tool definition once
 	mockClient := github.NewClient(nil)
@@ -824,6 +1144,9 @@ func Test_GetJobLogs(t *testing.T) {
 	assert.Contains(t, tool.InputSchema.Properties, "run_id")
 	assert.Contains(t, tool.InputSchema.Properties, "failed_only")
 	assert.Contains(t, tool.InputSchema.Properties, "return_content")
+	assert.Contains(t, tool.InputSchema.Properties, "head_lines")
+	assert.Contains(t, tool.InputSchema.Properties, "grep")
+	assert.Contains(t, tool.InputSchema.Properties, "context_lines")
 	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
 
 	tests := []struct {
@@ -1084,21 +1407,15 @@ func Test_GetJobLogs_WithContentReturn(t *testing.T) {
 	// Test the return_content functionality with a mock HTTP server
 	logContent := "2023-01-01T10:00:00.000Z Starting job...\n2023-01-01T10:00:01.000Z Running tests...\n2023-01-01T10:00:02.000Z Job completed successfully"
 
-	// Create a test server to serve log content
-	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(logContent))
-	}))
-	defer testServer.Close()
-
 	mockedClient := mock.NewMockedHTTPClient(
 		mock.WithRequestMatchHandler(
 			mock.GetReposActionsJobsLogsByOwnerByRepoByJobId,
 			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-				w.Header().Set("Location", testServer.URL)
+				w.Header().Set("Location", logDownloadURL)
 				w.WriteHeader(http.StatusFound)
 			}),
 		),
+		mockLogDownload(logContent),
 	)
 
 	client := github.NewClient(mockedClient)
@@ -1131,21 +1448,15 @@ func Test_GetJobLogs_WithContentReturnAndTailLines(t *testing.T) {
 	logContent := "2023-01-01T10:00:00.000Z Starting job...\n2023-01-01T10:00:01.000Z Running tests...\n2023-01-01T10:00:02.000Z Job completed successfully"
 	expectedLogContent := "2023-01-01T10:00:02.000Z Job completed successfully"
 
-	// Create a test server to serve log content
-	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(logContent))
-	}))
-	defer testServer.Close()
-
 	mockedClient := mock.NewMockedHTTPClient(
 		mock.WithRequestMatchHandler(
 			mock.GetReposActionsJobsLogsByOwnerByRepoByJobId,
 			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-				w.Header().Set("Location", testServer.URL)
+				w.Header().Set("Location", logDownloadURL)
 				w.WriteHeader(http.StatusFound)
 			}),
 		),
+		mockLogDownload(logContent),
 	)
 
 	client := github.NewClient(mockedClient)
@@ -1179,20 +1490,15 @@ func Test_GetJobLogs_WithContentReturnAndLargeTailLines(t *testing.T) {
 	logContent := "Line 1\nLine 2\nLine 3"
 	expectedLogContent := "Line 1\nLine 2\nLine 3"
 
-	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(logContent))
-	}))
-	defer testServer.Close()
-
 	mockedClient := mock.NewMockedHTTPClient(
 		mock.WithRequestMatchHandler(
 			mock.GetReposActionsJobsLogsByOwnerByRepoByJobId,
 			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-				w.Header().Set("Location", testServer.URL)
+				w.Header().Set("Location", logDownloadURL)
 				w.WriteHeader(http.StatusFound)
 			}),
 		),
+		mockLogDownload(logContent),
 	)
 
 	client := github.NewClient(mockedClient)
@@ -1222,6 +1528,230 @@ func Test_GetJobLogs_WithContentReturnAndLargeTailLines(t *testing.T) {
 	assert.NotContains(t, response, "logs_url")
 }
 
+func Test_GetJobLogs_WithGrep(t *testing.T) {
+	logContent := "Line 1\nLine 2\n--- FAIL: TestThing\nthing_test.go:12: want 1, got 2\nLine 5\nLine 6\nLine 7\nLine 8\nLine 9\nDone"
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposActionsJobsLogsByOwnerByRepoByJobId,
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Location", logDownloadURL)
+				w.WriteHeader(http.StatusFound)
+			}),
+		),
+		mockLogDownload(logContent),
+	)
+
+	client := github.NewClient(mockedClient)
+	_, handler := GetJobLogs(stubGetClientFn(client), translations.NullTranslationHelper, 5000)
+
+	tests := []struct {
+		name            string
+		args            map[string]any
+		expectedContent string
+		expectedMatches float64
+	}{
+		{
+			name:            "grep with context",
+			args:            map[string]any{"grep": "FAIL", "context_lines": float64(1)},
+			expectedContent: "Line 2\n--- FAIL: TestThing\nthing_test.go:12: want 1, got 2",
+			expectedMatches: 1,
+		},
+		{
+			name:            "head, grep and tail",
+			args:            map[string]any{"head_lines": float64(1), "grep": "want", "tail_lines": float64(1)},
+			expectedContent: "Line 1\n... 2 lines omitted ...\nthing_test.go:12: want 1, got 2\n... 5 lines omitted ...\nDone",
+			expectedMatches: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args := map[string]any{
+				"owner":          "owner",
+				"repo":           "repo",
+				"job_id":         float64(123),
+				"return_content": true,
+			}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+
+			textContent := getTextResult(t, result)
+			var response map[string]any
+			err = json.Unmarshal([]byte(textContent.Text), &response)
+			require.NoError(t, err)
+
+			assert.Equal(t, float64(10), response["original_length"])
+			assert.Equal(t, tc.expectedContent, response["logs_content"])
+			assert.Equal(t, tc.expectedMatches, response["matched_lines"])
+		})
+	}
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		result, err := handler(context.Background(), createMCPRequest(map[string]any{
+			"owner":          "owner",
+			"repo":           "repo",
+			"job_id":         float64(123),
+			"return_content": true,
+			"grep":           "(",
+		}))
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, getErrorResult(t, result).Text, "invalid grep pattern")
+	})
+}
+
+func Test_AnalyzeJobFailure(t *testing.T) {
+	// Verify tool definition once
+	mockClient := github.NewClient(nil)
+	tool, _ := AnalyzeJobFailure(stubGetClientFn(mockClient), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	assert.Equal(t, "analyze_job_failure", tool.Name)
+	assert.NotEmpty(t, tool.Description)
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	logContent := strings.Join([]string{
+		"2024-05-01T12:00:00.0000000Z ##[group]Run go test ./...",
+		"2024-05-01T12:00:01.0000000Z go test ./...",
+		"2024-05-01T12:00:02.0000000Z ##[endgroup]",
+		"2024-05-01T12:00:03.0000000Z --- FAIL: TestAdd (0.00s)",
+		"2024-05-01T12:00:04.0000000Z     add_test.go:12: want 3, got 4",
+		"2024-05-01T12:00:05.0000000Z ##[error]Process completed with exit code 1.",
+	}, "\n")
+
+	failedJob := &github.WorkflowJob{
+		ID:         github.Ptr(int64(1)),
+		Name:       github.Ptr("test"),
+		Conclusion: github.Ptr("failure"),
+		Steps: []*github.TaskStep{
+			{Name: github.Ptr("Set up job"), Conclusion: github.Ptr("success")},
+			{Name: github.Ptr("Test"), Conclusion: github.Ptr("failure")},
+		},
+	}
+	logsHandler := mock.WithRequestMatchHandler(
+		mock.GetReposActionsJobsLogsByOwnerByRepoByJobId,
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Location", logDownloadURL)
+			w.WriteHeader(http.StatusFound)
+		}),
+	)
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]any
+		expectError    bool
+		expectedErrMsg string
+		expectedJobs   int
+		expectedMsg    string
+	}{
+		{
+			name: "job",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(mock.GetReposActionsJobsByOwnerByRepoByJobId, failedJob),
+				logsHandler,
+				mockLogDownload(logContent),
+			),
+			requestArgs:  map[string]any{"owner": "owner", "repo": "repo", "job_id": float64(1)},
+			expectedJobs: 1,
+		},
+		{
+			name: "failed jobs of a run",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(mock.GetReposActionsRunsJobsByOwnerByRepoByRunId, &github.Jobs{
+					TotalCount: github.Ptr(2),
+					Jobs: []*github.WorkflowJob{
+						failedJob,
+						{ID: github.Ptr(int64(2)), Name: github.Ptr("lint"), Conclusion: github.Ptr("success")},
+					},
+				}),
+				logsHandler,
+				mockLogDownload(logContent),
+			),
+			requestArgs:  map[string]any{"owner": "owner", "repo": "repo", "run_id": float64(456)},
+			expectedJobs: 1,
+		},
+		{
+			name: "run without failed jobs",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(mock.GetReposActionsRunsJobsByOwnerByRepoByRunId, &github.Jobs{
+					TotalCount: github.Ptr(1),
+					Jobs: []*github.WorkflowJob{
+						{ID: github.Ptr(int64(2)), Name: github.Ptr("lint"), Conclusion: github.Ptr("success")},
+					},
+				}),
+			),
+			requestArgs:  map[string]any{"owner": "owner", "repo": "repo", "run_id": float64(456)},
+			expectedJobs: 0,
+			expectedMsg:  "No failed jobs found in this workflow run, out of 1 jobs",
+		},
+		{
+			name:           "neither job_id nor run_id",
+			mockedClient:   mock.NewMockedHTTPClient(),
+			requestArgs:    map[string]any{"owner": "owner", "repo": "repo"},
+			expectError:    true,
+			expectedErrMsg: "exactly one of job_id and run_id is required",
+		},
+		{
+			name: "job not found",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposActionsJobsByOwnerByRepoByJobId,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.WriteHeader(http.StatusNotFound)
+						_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+					}),
+				),
+			),
+			requestArgs:    map[string]any{"owner": "owner", "repo": "repo", "job_id": float64(999)},
+			expectError:    true,
+			expectedErrMsg: "failed to get workflow job",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := AnalyzeJobFailure(stubGetClientFn(client), translations.NullTranslationHelper)
+
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+			require.NoError(t, err)
+
+			if tc.expectError {
+				require.True(t, result.IsError)
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectedErrMsg)
+				return
+			}
+			require.False(t, result.IsError)
+
+			var response JobFailureAnalysisResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &response))
+			assert.Equal(t, response, result.StructuredContent)
+			assert.Equal(t, tc.expectedMsg, response.Message)
+			require.Len(t, response.Jobs, tc.expectedJobs)
+			if tc.expectedJobs == 0 {
+				return
+			}
+
+			job := response.Jobs[0]
+			assert.Equal(t, int64(1), job.JobID)
+			assert.Equal(t, "Test", job.FailedStep)
+			assert.Equal(t, 6, job.TotalLines)
+			assert.Equal(t, 3, job.TotalErrors)
+			require.Len(t, job.Errors, 3)
+			assert.Equal(t, "add_test.go:12", job.Errors[1].Location())
+			assert.Equal(t, `Step "Test" failed with 3 error(s); first at add_test.go:12: want 3, got 4`, job.Summary)
+		})
+	}
+}
+
 func Test_MemoryUsage_SlidingWindow_vs_NoWindow(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping memory profiling test in short mode")
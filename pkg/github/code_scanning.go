@@ -2,7 +2,6 @@ package github
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,6 +20,7 @@ func GetCodeScanningAlert(getClient GetClientFn, t translations.TranslationHelpe
 				Title:        t("TOOL_GET_CODE_SCANNING_ALERT_USER_TITLE", "Get code scanning alert"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalCodeScanningAlert](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("The owner of the repository."),
@@ -71,12 +71,7 @@ func GetCodeScanningAlert(getClient GetClientFn, t translations.TranslationHelpe
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get alert: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(alert)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal alert: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(convertToMinimalCodeScanningAlert(alert)), nil
 		}
 }
 
@@ -87,6 +82,7 @@ func ListCodeScanningAlerts(getClient GetClientFn, t translations.TranslationHel
 				Title:        t("TOOL_LIST_CODE_SCANNING_ALERTS_USER_TITLE", "List code scanning alerts"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalCodeScanningAlert]](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("The owner of the repository."),
@@ -159,11 +155,11 @@ func ListCodeScanningAlerts(getClient GetClientFn, t translations.TranslationHel
 				return mcp.NewToolResultError(fmt.Sprintf("failed to list alerts: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(alerts)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal alerts: %w", err)
+			minimalCodeScanningAlerts := make([]MinimalCodeScanningAlert, 0, len(alerts))
+			for _, alert := range alerts {
+				minimalCodeScanningAlerts = append(minimalCodeScanningAlerts, convertToMinimalCodeScanningAlert(alert))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalCodeScanningAlerts), nil
 		}
 }
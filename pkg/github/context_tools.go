@@ -41,6 +41,7 @@ func GetMe(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Too
 			Title:        t("TOOL_GET_ME_USER_TITLE", "Get my user profile"),
 			ReadOnlyHint: ToBoolPtr(true),
 		}),
+		mcp.WithOutputSchema[MinimalUser](),
 	)
 
 	type args struct{}
@@ -86,7 +87,7 @@ func GetMe(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Too
 			},
 		}
 
-		return MarshalledTextResult(minimalUser), nil
+		return StructuredResult(minimalUser), nil
 	})
 
 	return tool, handler
@@ -113,6 +114,7 @@ func GetTeams(getClient GetClientFn, getGQLClient GetGQLClientFn, t translations
 				Title:        t("TOOL_GET_TEAMS_TITLE", "Get teams"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[OrganizationTeams]](),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			user, err := OptionalParam[string](request, "user")
@@ -186,7 +188,7 @@ func GetTeams(getClient GetClientFn, getGQLClient GetGQLClientFn, t translations
 				organizations = append(organizations, orgTeams)
 			}
 
-			return MarshalledTextResult(organizations), nil
+			return StructuredListResult(organizations), nil
 		}
 }
 
@@ -205,6 +207,7 @@ func GetTeamMembers(getGQLClient GetGQLClientFn, t translations.TranslationHelpe
 				Title:        t("TOOL_GET_TEAM_MEMBERS_TITLE", "Get team members"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[string]](),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			org, err := RequiredParam[string](request, "org")
@@ -246,6 +249,6 @@ func GetTeamMembers(getGQLClient GetGQLClientFn, t translations.TranslationHelpe
 				members = append(members, string(member.Login))
 			}
 
-			return MarshalledTextResult(members), nil
+			return StructuredListResult(members), nil
 		}
 }
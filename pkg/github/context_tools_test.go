@@ -125,6 +125,7 @@ func Test_GetMe(t *testing.T) {
 			var returnedUser MinimalUser
 			err = json.Unmarshal([]byte(textContent.Text), &returnedUser)
 			require.NoError(t, err)
+			assert.Equal(t, returnedUser, result.StructuredContent)
 
 			// Verify minimal user details
 			assert.Equal(t, *tc.expectedUser.Login, returnedUser.Login)
@@ -484,6 +485,8 @@ func Test_GetTeamMembers(t *testing.T) {
 			var members []string
 			err = json.Unmarshal([]byte(textContent.Text), &members)
 			require.NoError(t, err)
+			require.IsType(t, ListResult[string]{}, result.StructuredContent)
+			assert.Len(t, result.StructuredContent.(ListResult[string]).Items, tc.expectedMembersCount)
 
 			assert.Len(t, members, tc.expectedMembersCount)
 
@@ -2,7 +2,6 @@ package github
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -22,6 +21,7 @@ func GetDependabotAlert(getClient GetClientFn, t translations.TranslationHelperF
 				Title:        t("TOOL_GET_DEPENDABOT_ALERT_USER_TITLE", "Get dependabot alert"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalDependabotAlert](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("The owner of the repository."),
@@ -72,12 +72,7 @@ func GetDependabotAlert(getClient GetClientFn, t translations.TranslationHelperF
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get alert: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(alert)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal alert: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(convertToMinimalDependabotAlert(alert)), nil
 		}
 }
 
@@ -89,6 +84,7 @@ func ListDependabotAlerts(getClient GetClientFn, t translations.TranslationHelpe
 				Title:        t("TOOL_LIST_DEPENDABOT_ALERTS_USER_TITLE", "List dependabot alerts"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalDependabotAlert]](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("The owner of the repository."),
@@ -151,11 +147,11 @@ func ListDependabotAlerts(getClient GetClientFn, t translations.TranslationHelpe
 				return mcp.NewToolResultError(fmt.Sprintf("failed to list alerts: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(alerts)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal alerts: %w", err)
+			minimalDependabotAlerts := make([]MinimalDependabotAlert, 0, len(alerts))
+			for _, alert := range alerts {
+				minimalDependabotAlerts = append(minimalDependabotAlerts, convertToMinimalDependabotAlert(alert))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalDependabotAlerts), nil
 		}
 }
@@ -2,7 +2,6 @@ package github
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/github/github-mcp-server/pkg/translations"
@@ -124,6 +123,7 @@ func ListDiscussions(getGQLClient GetGQLClientFn, t translations.TranslationHelp
 				Title:        t("TOOL_LIST_DISCUSSIONS_USER_TITLE", "List discussions"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalListDiscussionsResult](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -236,23 +236,22 @@ func ListDiscussions(getGQLClient GetGQLClientFn, t translations.TranslationHelp
 				totalCount = fragment.TotalCount
 			}
 
-			// Create response with pagination info
-			response := map[string]interface{}{
-				"discussions": discussions,
-				"pageInfo": map[string]interface{}{
-					"hasNextPage":     pageInfo.HasNextPage,
-					"hasPreviousPage": pageInfo.HasPreviousPage,
-					"startCursor":     string(pageInfo.StartCursor),
-					"endCursor":       string(pageInfo.EndCursor),
-				},
-				"totalCount": totalCount,
+			minimalDiscussions := make([]MinimalDiscussion, 0, len(discussions))
+			for _, discussion := range discussions {
+				minimalDiscussions = append(minimalDiscussions, convertToMinimalDiscussion(discussion))
 			}
 
-			out, err := json.Marshal(response)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal discussions: %w", err)
-			}
-			return mcp.NewToolResultText(string(out)), nil
+			// Create response with pagination info
+			return StructuredResult(MinimalListDiscussionsResult{
+				Discussions: minimalDiscussions,
+				PageInfo: MinimalPageInfo{
+					HasNextPage:     bool(pageInfo.HasNextPage),
+					HasPreviousPage: bool(pageInfo.HasPreviousPage),
+					StartCursor:     string(pageInfo.StartCursor),
+					EndCursor:       string(pageInfo.EndCursor),
+				},
+				TotalCount: int(totalCount),
+			}), nil
 		}
 }
 
@@ -263,6 +262,7 @@ func GetDiscussion(getGQLClient GetGQLClientFn, t translations.TranslationHelper
 				Title:        t("TOOL_GET_DISCUSSION_USER_TITLE", "Get discussion"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalDiscussion](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -324,12 +324,7 @@ func GetDiscussion(getGQLClient GetGQLClientFn, t translations.TranslationHelper
 					Name: github.Ptr(string(d.Category.Name)),
 				},
 			}
-			out, err := json.Marshal(discussion)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal discussion: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(out)), nil
+			return StructuredResult(convertToMinimalDiscussion(discussion)), nil
 		}
 }
 
@@ -340,6 +335,7 @@ func GetDiscussionComments(getGQLClient GetGQLClientFn, t translations.Translati
 				Title:        t("TOOL_GET_DISCUSSION_COMMENTS_USER_TITLE", "Get discussion comments"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalDiscussionCommentsResult](),
 			mcp.WithString("owner", mcp.Required(), mcp.Description("Repository owner")),
 			mcp.WithString("repo", mcp.Required(), mcp.Description("Repository name")),
 			mcp.WithNumber("discussionNumber", mcp.Required(), mcp.Description("Discussion Number")),
@@ -415,29 +411,22 @@ func GetDiscussionComments(getGQLClient GetGQLClientFn, t translations.Translati
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 
-			var comments []*github.IssueComment
+			comments := make([]MinimalDiscussionComment, 0, len(q.Repository.Discussion.Comments.Nodes))
 			for _, c := range q.Repository.Discussion.Comments.Nodes {
-				comments = append(comments, &github.IssueComment{Body: github.Ptr(string(c.Body))})
+				comments = append(comments, MinimalDiscussionComment{Body: string(c.Body)})
 			}
 
 			// Create response with pagination info
-			response := map[string]interface{}{
-				"comments": comments,
-				"pageInfo": map[string]interface{}{
-					"hasNextPage":     q.Repository.Discussion.Comments.PageInfo.HasNextPage,
-					"hasPreviousPage": q.Repository.Discussion.Comments.PageInfo.HasPreviousPage,
-					"startCursor":     string(q.Repository.Discussion.Comments.PageInfo.StartCursor),
-					"endCursor":       string(q.Repository.Discussion.Comments.PageInfo.EndCursor),
+			return StructuredResult(MinimalDiscussionCommentsResult{
+				Comments: comments,
+				PageInfo: MinimalPageInfo{
+					HasNextPage:     bool(q.Repository.Discussion.Comments.PageInfo.HasNextPage),
+					HasPreviousPage: bool(q.Repository.Discussion.Comments.PageInfo.HasPreviousPage),
+					StartCursor:     string(q.Repository.Discussion.Comments.PageInfo.StartCursor),
+					EndCursor:       string(q.Repository.Discussion.Comments.PageInfo.EndCursor),
 				},
-				"totalCount": q.Repository.Discussion.Comments.TotalCount,
-			}
-
-			out, err := json.Marshal(response)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal comments: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(out)), nil
+				TotalCount: int(q.Repository.Discussion.Comments.TotalCount),
+			}), nil
 		}
 }
 
@@ -448,6 +437,7 @@ func ListDiscussionCategories(getGQLClient GetGQLClientFn, t translations.Transl
 				Title:        t("TOOL_LIST_DISCUSSION_CATEGORIES_USER_TITLE", "List discussion categories"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalDiscussionCategoriesResult](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -502,30 +492,24 @@ func ListDiscussionCategories(getGQLClient GetGQLClientFn, t translations.Transl
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 
-			var categories []map[string]string
+			categories := make([]MinimalDiscussionCategory, 0, len(q.Repository.DiscussionCategories.Nodes))
 			for _, c := range q.Repository.DiscussionCategories.Nodes {
-				categories = append(categories, map[string]string{
-					"id":   fmt.Sprint(c.ID),
-					"name": string(c.Name),
+				categories = append(categories, MinimalDiscussionCategory{
+					ID:   fmt.Sprint(c.ID),
+					Name: string(c.Name),
 				})
 			}
 
 			// Create response with pagination info
-			response := map[string]interface{}{
-				"categories": categories,
-				"pageInfo": map[string]interface{}{
-					"hasNextPage":     q.Repository.DiscussionCategories.PageInfo.HasNextPage,
-					"hasPreviousPage": q.Repository.DiscussionCategories.PageInfo.HasPreviousPage,
-					"startCursor":     string(q.Repository.DiscussionCategories.PageInfo.StartCursor),
-					"endCursor":       string(q.Repository.DiscussionCategories.PageInfo.EndCursor),
+			return StructuredResult(MinimalDiscussionCategoriesResult{
+				Categories: categories,
+				PageInfo: MinimalPageInfo{
+					HasNextPage:     bool(q.Repository.DiscussionCategories.PageInfo.HasNextPage),
+					HasPreviousPage: bool(q.Repository.DiscussionCategories.PageInfo.HasPreviousPage),
+					StartCursor:     string(q.Repository.DiscussionCategories.PageInfo.StartCursor),
+					EndCursor:       string(q.Repository.DiscussionCategories.PageInfo.EndCursor),
 				},
-				"totalCount": q.Repository.DiscussionCategories.TotalCount,
-			}
-
-			out, err := json.Marshal(response)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal discussion categories: %w", err)
-			}
-			return mcp.NewToolResultText(string(out)), nil
+				TotalCount: int(q.Repository.DiscussionCategories.TotalCount),
+			}), nil
 		}
 }
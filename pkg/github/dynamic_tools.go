@@ -2,8 +2,10 @@ package github
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/github/github-mcp-server/pkg/toolsets"
 	"github.com/github/github-mcp-server/pkg/translations"
@@ -16,6 +18,7 @@ func ToolsetEnum(toolsetGroup *toolsets.ToolsetGroup) mcp.PropertyOption {
 	for name := range toolsetGroup.Toolsets {
 		toolsetNames = append(toolsetNames, name)
 	}
+	sort.Strings(toolsetNames)
 	return mcp.Enum(toolsetNames...)
 }
 
@@ -43,22 +46,178 @@ func EnableToolset(s *server.MCPServer, toolsetGroup *toolsets.ToolsetGroup, t t
 			if toolset == nil {
 				return mcp.NewToolResultError(fmt.Sprintf("Toolset %s not found", toolsetName)), nil
 			}
-			if toolset.Enabled {
+			if toolsetGroup.IsEnabled(toolsetName) {
 				return mcp.NewToolResultText(fmt.Sprintf("Toolset %s is already enabled", toolsetName)), nil
 			}
 
-			toolset.Enabled = true
+			message, err := enableToolset(s, toolsetGroup, toolset)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return mcp.NewToolResultText(message), nil
+		}
+}
 
-			// caution: this currently affects the global tools and notifies all clients:
-			//
-			// Send notification to all initialized sessions
-			// s.sendNotificationToAllClients("notifications/tools/list_changed", nil)
-			s.AddTools(toolset.GetActiveTools()...)
+// enableToolset enables toolset and registers its tools on s, removing the
+// tools of the toolsets evicted to stay within the cap on enabled toolsets.
+// It returns a message for the model describing the change.
+func enableToolset(s *server.MCPServer, toolsetGroup *toolsets.ToolsetGroup, toolset *toolsets.Toolset) (string, error) {
+	evicted, err := toolsetGroup.EnableToolsetWithEviction(toolset.Name)
+	if err != nil {
+		return "", err
+	}
+
+	var evictedNames []string
+	for _, other := range evicted {
+		deleteToolsetTools(s, other)
+		evictedNames = append(evictedNames, other.Name)
+	}
+	s.AddTools(toolset.GetActiveTools()...)
+
+	if len(evictedNames) > 0 {
+		return fmt.Sprintf("Toolset %s enabled; disabled the least recently used toolsets to stay within the limit: %s", toolset.Name, strings.Join(evictedNames, ", ")), nil
+	}
+	return fmt.Sprintf("Toolset %s enabled", toolset.Name), nil
+}
 
-			return mcp.NewToolResultText(fmt.Sprintf("Toolset %s enabled", toolsetName)), nil
+// ToolMatch is a tool found by search_tools.
+type ToolMatch struct {
+	Name        string  `json:"name"`
+	Toolset     string  `json:"toolset"`
+	Description string  `json:"description"`
+	Score       float64 `json:"score"`
+	Enabled     bool    `json:"enabled"`
+}
+
+// SearchToolsResult is the result of search_tools.
+type SearchToolsResult struct {
+	Tools   []ToolMatch `json:"tools"`
+	Message string      `json:"message,omitempty"`
+}
+
+// ToolsetInfo describes a toolset listed by list_available_toolsets.
+type ToolsetInfo struct {
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	CanEnable        string `json:"can_enable"`
+	CurrentlyEnabled string `json:"currently_enabled"`
+}
+
+// ToolsetTool describes a tool listed by get_toolset_tools.
+type ToolsetTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CanEnable   string `json:"can_enable"`
+	Toolset     string `json:"toolset"`
+}
+
+func SearchTools(s *server.MCPServer, toolsetGroup *toolsets.ToolsetGroup, index *toolsets.SearchIndex, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("search_tools",
+			mcp.WithDescription(t("TOOL_SEARCH_TOOLS_DESCRIPTION", "Search every tool the GitHub MCP server offers, enabled or not, by what it does. Use this instead of guessing a toolset from list_available_toolsets; set enable to also enable the toolset of the best match so its tools can be called")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_SEARCH_TOOLS_USER_TITLE", "Search tools"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithOutputSchema[SearchToolsResult](),
+			mcp.WithString("query",
+				mcp.Required(),
+				mcp.Description("What you want to do, in natural language (e.g. \"list comments on a pull request\")"),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description("Maximum number of tools to return (default 5)"),
+				mcp.Min(1),
+				mcp.Max(50),
+			),
+			mcp.WithBoolean("enable",
+				mcp.Description("Enable the toolset of the best matching tool, if it is not enabled yet"),
+			),
+		),
+		func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			query, err := RequiredParam[string](request, "query")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			limit, err := OptionalIntParamWithDefault(request, "limit", 5)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			enable, err := OptionalParam[bool](request, "enable")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			payload := SearchToolsResult{Tools: []ToolMatch{}}
+
+			results := index.Search(query, limit)
+			if enable && len(results) > 0 {
+				toolset := toolsetGroup.Toolsets[results[0].Toolset]
+				if toolset != nil && !toolsetGroup.IsEnabled(toolset.Name) {
+					if payload.Message, err = enableToolset(s, toolsetGroup, toolset); err != nil {
+						return mcp.NewToolResultError(err.Error()), nil
+					}
+				}
+			}
+			for _, result := range results {
+				payload.Tools = append(payload.Tools, ToolMatch{
+					Name:        result.Tool.Name,
+					Toolset:     result.Toolset,
+					Description: result.Tool.Description,
+					Score:       math.Round(result.Score*100) / 100,
+					Enabled:     toolsetGroup.IsEnabled(result.Toolset),
+				})
+			}
+			return StructuredResult(payload), nil
+		}
+}
+
+func DisableToolset(s *server.MCPServer, toolsetGroup *toolsets.ToolsetGroup, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("disable_toolset",
+			mcp.WithDescription(t("TOOL_DISABLE_TOOLSET_DESCRIPTION", "Disable one of the enabled sets of tools the GitHub MCP server provides, removing its tools. Use this to drop toolsets no longer needed for the task; they can be enabled again later")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_DISABLE_TOOLSET_USER_TITLE", "Disable a toolset"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("toolset",
+				mcp.Required(),
+				mcp.Description("The name of the toolset to disable"),
+				ToolsetEnum(toolsetGroup),
+			),
+		),
+		func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			toolsetName, err := RequiredParam[string](request, "toolset")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			toolset := toolsetGroup.Toolsets[toolsetName]
+			if toolset == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Toolset %s not found", toolsetName)), nil
+			}
+			if !toolsetGroup.IsEnabled(toolsetName) {
+				return mcp.NewToolResultText(fmt.Sprintf("Toolset %s is already disabled", toolsetName)), nil
+			}
+
+			if err := toolsetGroup.DisableToolset(toolsetName); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			// Removing the tools notifies clients with notifications/tools/list_changed
+			deleteToolsetTools(s, toolset)
+
+			return mcp.NewToolResultText(fmt.Sprintf("Toolset %s disabled", toolsetName)), nil
 		}
 }
 
+// deleteToolsetTools removes the tools of toolset from s.
+func deleteToolsetTools(s *server.MCPServer, toolset *toolsets.Toolset) {
+	tools := toolset.GetAvailableTools()
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Tool.Name)
+	}
+	if len(names) > 0 {
+		s.DeleteTools(names...)
+	}
+}
+
 func ListAvailableToolsets(toolsetGroup *toolsets.ToolsetGroup, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("list_available_toolsets",
 			mcp.WithDescription(t("TOOL_LIST_AVAILABLE_TOOLSETS_DESCRIPTION", "List all available toolsets this GitHub MCP server can offer, providing the enabled status of each. Use this when a task could be achieved with a GitHub tool and the currently available tools aren't enough. Call get_toolset_tools with these toolset names to discover specific tools you can call")),
@@ -66,30 +225,23 @@ func ListAvailableToolsets(toolsetGroup *toolsets.ToolsetGroup, t translations.T
 				Title:        t("TOOL_LIST_AVAILABLE_TOOLSETS_USER_TITLE", "List available toolsets"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[ToolsetInfo]](),
 		),
 		func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			// We need to convert the toolsetGroup back to a map for JSON serialization
 
-			payload := []map[string]string{}
+			payload := []ToolsetInfo{}
 
 			for name, ts := range toolsetGroup.Toolsets {
-				{
-					t := map[string]string{
-						"name":              name,
-						"description":       ts.Description,
-						"can_enable":        "true",
-						"currently_enabled": fmt.Sprintf("%t", ts.Enabled),
-					}
-					payload = append(payload, t)
-				}
-			}
-
-			r, err := json.Marshal(payload)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal features: %w", err)
+				payload = append(payload, ToolsetInfo{
+					Name:             name,
+					Description:      ts.Description,
+					CanEnable:        "true",
+					CurrentlyEnabled: fmt.Sprintf("%t", toolsetGroup.IsEnabled(name)),
+				})
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(payload), nil
 		}
 }
 
@@ -100,6 +252,7 @@ func GetToolsetsTools(toolsetGroup *toolsets.ToolsetGroup, t translations.Transl
 				Title:        t("TOOL_GET_TOOLSET_TOOLS_USER_TITLE", "List all tools in a toolset"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[ToolsetTool]](),
 			mcp.WithString("toolset",
 				mcp.Required(),
 				mcp.Description("The name of the toolset you want to get the tools for"),
@@ -116,23 +269,17 @@ func GetToolsetsTools(toolsetGroup *toolsets.ToolsetGroup, t translations.Transl
 			if toolset == nil {
 				return mcp.NewToolResultError(fmt.Sprintf("Toolset %s not found", toolsetName)), nil
 			}
-			payload := []map[string]string{}
+			payload := []ToolsetTool{}
 
 			for _, st := range toolset.GetAvailableTools() {
-				tool := map[string]string{
-					"name":        st.Tool.Name,
-					"description": st.Tool.Description,
-					"can_enable":  "true",
-					"toolset":     toolsetName,
-				}
-				payload = append(payload, tool)
-			}
-
-			r, err := json.Marshal(payload)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal features: %w", err)
+				payload = append(payload, ToolsetTool{
+					Name:        st.Tool.Name,
+					Description: st.Tool.Description,
+					CanEnable:   "true",
+					Toolset:     toolsetName,
+				})
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(payload), nil
 		}
 }
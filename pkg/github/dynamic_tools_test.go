@@ -0,0 +1,143 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listChangedSession is an initialized client session counting
+// notifications/tools/list_changed.
+type listChangedSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *listChangedSession) Initialize()       {}
+func (s *listChangedSession) Initialized() bool { return true }
+func (s *listChangedSession) SessionID() string { return "dynamic-tools-test" }
+func (s *listChangedSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func (s *listChangedSession) listChanged() int {
+	count := 0
+	for {
+		select {
+		case n := <-s.notifications:
+			if n.Method == mcp.MethodNotificationToolsListChanged {
+				count++
+			}
+		default:
+			return count
+		}
+	}
+}
+
+func callDynamicTool(t *testing.T, s *server.MCPServer, name, toolset string) string {
+	t.Helper()
+	tool := s.GetTool(name)
+	require.NotNil(t, tool, "tool %s is not registered", name)
+	request := createMCPRequest(map[string]any{"toolset": toolset})
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	require.False(t, result.IsError, getTextResult(t, result).Text)
+	return getTextResult(t, result).Text
+}
+
+func Test_DisableToolset(t *testing.T) {
+	s := NewServer("test")
+	session := &listChangedSession{notifications: make(chan mcp.JSONRPCNotification, 100)}
+	require.NoError(t, s.RegisterSession(context.Background(), session))
+
+	tsg := DefaultToolsetGroup(false, stubGetClientFn(nil), stubGetGQLClientFn(nil), stubGetRawClientFn(nil), newRunLogsCache(t), translations.NullTranslationHelper, 5000)
+	require.NoError(t, tsg.EnableToolsets([]string{"context"}))
+	tsg.RegisterAll(s)
+	dynamic := InitDynamicToolset(s, tsg, translations.NullTranslationHelper)
+	dynamic.RegisterTools(s)
+	session.listChanged()
+
+	assert.Equal(t, "Toolset issues enabled", callDynamicTool(t, s, "enable_toolset", "issues"))
+	assert.NotNil(t, s.GetTool("create_issue"))
+	assert.Equal(t, 1, session.listChanged())
+
+	assert.Equal(t, "Toolset issues disabled", callDynamicTool(t, s, "disable_toolset", "issues"))
+	assert.Nil(t, s.GetTool("create_issue"))
+	assert.False(t, tsg.IsEnabled("issues"))
+	assert.Equal(t, 1, session.listChanged())
+	assert.Equal(t, "Toolset issues is already disabled", callDynamicTool(t, s, "disable_toolset", "issues"))
+	assert.Equal(t, 0, session.listChanged())
+
+	// With a cap, enabling a toolset evicts the least recently used ones
+	tsg.SetMaxEnabled(2)
+	callDynamicTool(t, s, "enable_toolset", "issues")
+	tsg.MarkUsed("get_issue")
+	text := callDynamicTool(t, s, "enable_toolset", "pull_requests")
+	assert.Contains(t, text, "disabled the least recently used toolsets to stay within the limit: context")
+	assert.Nil(t, s.GetTool("get_me"))
+	assert.NotNil(t, s.GetTool("get_issue"))
+	assert.NotNil(t, s.GetTool("get_pull_request"))
+	// Removing the evicted tools and adding the new ones both notify clients
+	assert.Equal(t, 3, session.listChanged())
+}
+
+func Test_SearchTools(t *testing.T) {
+	s := NewServer("test")
+	tsg := DefaultToolsetGroup(false, stubGetClientFn(nil), stubGetGQLClientFn(nil), stubGetRawClientFn(nil), newRunLogsCache(t), translations.NullTranslationHelper, 5000)
+	require.NoError(t, tsg.EnableToolsets([]string{"context"}))
+	tool, handler := SearchTools(s, tsg, toolsets.NewSearchIndex(tsg), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	assert.Equal(t, "search_tools", tool.Name)
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.Contains(t, tool.InputSchema.Properties, "query")
+	assert.Contains(t, tool.InputSchema.Properties, "limit")
+	assert.Contains(t, tool.InputSchema.Properties, "enable")
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"query"})
+
+	type match struct {
+		Name    string `json:"name"`
+		Toolset string `json:"toolset"`
+		Enabled bool   `json:"enabled"`
+	}
+	type payload struct {
+		Tools   []match `json:"tools"`
+		Message string  `json:"message"`
+	}
+	search := func(args map[string]any) payload {
+		result, err := handler(context.Background(), createMCPRequest(args))
+		require.NoError(t, err)
+		require.False(t, result.IsError, getTextResult(t, result).Text)
+		var p payload
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &p))
+		return p
+	}
+
+	p := search(map[string]any{"query": "merge a pull request", "limit": float64(3)})
+	require.Len(t, p.Tools, 3)
+	assert.Equal(t, "merge_pull_request", p.Tools[0].Name)
+	assert.Equal(t, "pull_requests", p.Tools[0].Toolset)
+	assert.False(t, p.Tools[0].Enabled)
+	assert.Empty(t, p.Message)
+	assert.Nil(t, s.GetTool("merge_pull_request"))
+
+	// Enabling registers the toolset of the best match
+	p = search(map[string]any{"query": "merge a pull request", "enable": true})
+	assert.Equal(t, "Toolset pull_requests enabled", p.Message)
+	assert.True(t, p.Tools[0].Enabled)
+	assert.NotNil(t, s.GetTool("merge_pull_request"))
+
+	p = search(map[string]any{"query": "zzz"})
+	assert.Empty(t, p.Tools)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{}))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
@@ -21,6 +21,7 @@ func ListGists(getClient GetClientFn, t translations.TranslationHelperFunc) (too
 				Title:        t("TOOL_LIST_GISTS", "List Gists"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalGist]](),
 			mcp.WithString("username",
 				mcp.Description("GitHub username (omit for authenticated user's gists)"),
 			),
@@ -80,12 +81,12 @@ func ListGists(getClient GetClientFn, t translations.TranslationHelperFunc) (too
 				return mcp.NewToolResultError(fmt.Sprintf("failed to list gists: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(gists)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalGists := make([]MinimalGist, 0, len(gists))
+			for _, gist := range gists {
+				minimalGists = append(minimalGists, convertToMinimalGist(gist))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalGists), nil
 		}
 }
 
@@ -112,6 +113,7 @@ func CreateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 				mcp.Description("Whether the gist is public"),
 				mcp.DefaultBool(false),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			description, err := OptionalParam[string](request, "description")
@@ -201,6 +203,7 @@ func UpdateGist(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 				mcp.Required(),
 				mcp.Description("Content for the file"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			gistID, err := RequiredParam[string](request, "gist_id")
@@ -152,6 +152,7 @@ func GetIssue(getClient GetClientFn, t translations.TranslationHelperFunc) (tool
 				Title:        t("TOOL_GET_ISSUE_USER_TITLE", "Get issue details"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalIssue](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("The owner of the repository"),
@@ -197,12 +198,7 @@ func GetIssue(getClient GetClientFn, t translations.TranslationHelperFunc) (tool
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get issue: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(issue)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal issue: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(convertToMinimalIssue(issue)), nil
 		}
 }
 
@@ -215,6 +211,7 @@ func ListIssueTypes(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_LIST_ISSUE_TYPES_USER_TITLE", "List available issue types"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalIssueType]](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("The organization owner of the repository"),
@@ -244,12 +241,17 @@ func ListIssueTypes(getClient GetClientFn, t translations.TranslationHelperFunc)
 				return mcp.NewToolResultError(fmt.Sprintf("failed to list issue types: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(issueTypes)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal issue types: %w", err)
+			minimalIssueTypes := make([]MinimalIssueType, 0, len(issueTypes))
+			for _, issueType := range issueTypes {
+				minimalIssueTypes = append(minimalIssueTypes, MinimalIssueType{
+					ID:          issueType.GetID(),
+					Name:        issueType.GetName(),
+					Description: issueType.GetDescription(),
+					Color:       issueType.GetColor(),
+				})
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalIssueTypes), nil
 		}
 }
 
@@ -277,6 +279,7 @@ func AddIssueComment(getClient GetClientFn, t translations.TranslationHelperFunc
 				mcp.Required(),
 				mcp.Description("Comment content"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -354,6 +357,7 @@ func AddSubIssue(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 			mcp.WithBoolean("replace_parent",
 				mcp.Description("When true, replaces the sub-issue's current parent issue"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -423,6 +427,7 @@ func ListSubIssues(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_LIST_SUB_ISSUES_USER_TITLE", "List sub-issues"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalIssue]](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -495,12 +500,12 @@ func ListSubIssues(getClient GetClientFn, t translations.TranslationHelperFunc)
 				return mcp.NewToolResultError(fmt.Sprintf("failed to list sub-issues: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(subIssues)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalSubIssues := make([]MinimalIssue, 0, len(subIssues))
+			for _, subIssue := range subIssues {
+				minimalSubIssues = append(minimalSubIssues, convertToMinimalIssue((*github.Issue)(subIssue)))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalSubIssues), nil
 		}
 
 }
@@ -514,8 +519,9 @@ func RemoveSubIssue(getClient GetClientFn, t translations.TranslationHelperFunc)
 	return mcp.NewTool("remove_sub_issue",
 			mcp.WithDescription(t("TOOL_REMOVE_SUB_ISSUE_DESCRIPTION", "Remove a sub-issue from a parent issue in a GitHub repository.")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
-				Title:        t("TOOL_REMOVE_SUB_ISSUE_USER_TITLE", "Remove sub-issue"),
-				ReadOnlyHint: ToBoolPtr(false),
+				Title:           t("TOOL_REMOVE_SUB_ISSUE_USER_TITLE", "Remove sub-issue"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
 			}),
 			mcp.WithString("owner",
 				mcp.Required(),
@@ -533,6 +539,7 @@ func RemoveSubIssue(getClient GetClientFn, t translations.TranslationHelperFunc)
 				mcp.Required(),
 				mcp.Description("The ID of the sub-issue to remove. ID is not the same as issue number"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -618,6 +625,7 @@ func ReprioritizeSubIssue(getClient GetClientFn, t translations.TranslationHelpe
 			mcp.WithNumber("before_id",
 				mcp.Description("The ID of the sub-issue to be prioritized before (either after_id OR before_id should be specified)"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -709,6 +717,7 @@ func SearchIssues(getClient GetClientFn, t translations.TranslationHelperFunc) (
 				Title:        t("TOOL_SEARCH_ISSUES_USER_TITLE", "Search issues"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalSearchIssuesResult](),
 			mcp.WithString("query",
 				mcp.Required(),
 				mcp.Description("Search query using GitHub issues search syntax"),
@@ -791,6 +800,7 @@ func CreateIssue(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 			mcp.WithString("type",
 				mcp.Description("Type of this issue"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -894,6 +904,7 @@ func ListIssues(getGQLClient GetGQLClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_LIST_ISSUES_USER_TITLE", "List issues"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalListIssuesResult](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1080,21 +1091,16 @@ func ListIssues(getGQLClient GetGQLClientFn, t translations.TranslationHelperFun
 			}
 
 			// Create response with issues
-			response := map[string]interface{}{
-				"issues": issues,
-				"pageInfo": map[string]interface{}{
-					"hasNextPage":     pageInfo.HasNextPage,
-					"hasPreviousPage": pageInfo.HasPreviousPage,
-					"startCursor":     string(pageInfo.StartCursor),
-					"endCursor":       string(pageInfo.EndCursor),
+			return StructuredResult(MinimalListIssuesResult{
+				Issues: convertToMinimalIssues(issues),
+				PageInfo: MinimalPageInfo{
+					HasNextPage:     bool(pageInfo.HasNextPage),
+					HasPreviousPage: bool(pageInfo.HasPreviousPage),
+					StartCursor:     string(pageInfo.StartCursor),
+					EndCursor:       string(pageInfo.EndCursor),
 				},
-				"totalCount": totalCount,
-			}
-			out, err := json.Marshal(response)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal issues: %w", err)
-			}
-			return mcp.NewToolResultText(string(out)), nil
+				TotalCount: totalCount,
+			}), nil
 		}
 }
 
@@ -1150,6 +1156,7 @@ func UpdateIssue(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 			mcp.WithString("type",
 				mcp.Description("New issue type"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -1269,6 +1276,7 @@ func GetIssueComments(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_GET_ISSUE_COMMENTS_USER_TITLE", "Get issue comments"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalIssueComment]](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1326,12 +1334,12 @@ func GetIssueComments(getClient GetClientFn, t translations.TranslationHelperFun
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get issue comments: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(comments)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalComments := make([]MinimalIssueComment, 0, len(comments))
+			for _, comment := range comments {
+				minimalComments = append(minimalComments, convertToMinimalIssueComment(comment))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalComments), nil
 		}
 }
 
@@ -1395,6 +1403,7 @@ func AssignCopilotToIssue(getGQLClient GetGQLClientFn, t translations.Translatio
 				mcp.Required(),
 				mcp.Description("Issue number"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var params struct {
@@ -1554,36 +1563,43 @@ func parseISOTimestamp(timestamp string) (time.Time, error) {
 }
 
 func AssignCodingAgentPrompt(t translations.TranslationHelperFunc) (tool mcp.Prompt, handler server.PromptHandlerFunc) {
+	systemText := t("PROMPT_ASSIGN_CODING_AGENT_SYSTEM", "You are a personal assistant for GitHub the Copilot GitHub Coding Agent. Your task is to help the user assign tasks to the Coding Agent based on their open GitHub issues. You can use `assign_copilot_to_issue` tool to assign the Coding Agent to issues that are suitable for autonomous work, and `search_issues` tool to find issues that match the user's criteria. You can also use `list_issues` to get a list of issues in the repository.")
+	requestText := t("PROMPT_ASSIGN_CODING_AGENT_REQUEST", "Please go and get a list of the most recent 10 issues from the %s GitHub repository")
+	acknowledgeText := t("PROMPT_ASSIGN_CODING_AGENT_ACKNOWLEDGE", "Sure! I will get a list of the 10 most recent issues for the repo %s.")
+	criteriaText := t("PROMPT_ASSIGN_CODING_AGENT_CRITERIA", "For each issue, please check if it is a clearly defined coding task with acceptance criteria and a low to medium complexity to identify issues that are suitable for an AI Coding Agent to work on. Then assign each of the identified issues to Copilot.")
+	planText := t("PROMPT_ASSIGN_CODING_AGENT_PLAN", "Certainly! Let me carefully check which ones are clearly scoped issues that are good to assign to the coding agent, and I will summarize and assign them now.")
+	confirmText := t("PROMPT_ASSIGN_CODING_AGENT_CONFIRM", "Great, if you are unsure if an issue is good to assign, ask me first, rather than assigning copilot. If you are certain the issue is clear and suitable you can assign it to Copilot without asking.")
+
 	return mcp.NewPrompt("AssignCodingAgent",
 			mcp.WithPromptDescription(t("PROMPT_ASSIGN_CODING_AGENT_DESCRIPTION", "Assign GitHub Coding Agent to multiple tasks in a GitHub repository.")),
-			mcp.WithArgument("repo", mcp.ArgumentDescription("The repository to assign tasks in (owner/repo)."), mcp.RequiredArgument()),
+			mcp.WithArgument("repo", mcp.ArgumentDescription(t("PROMPT_ASSIGN_CODING_AGENT_ARG_REPO", "The repository to assign tasks in (owner/repo).")), mcp.RequiredArgument()),
 		), func(_ context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
 			repo := request.Params.Arguments["repo"]
 
 			messages := []mcp.PromptMessage{
 				{
 					Role:    "user",
-					Content: mcp.NewTextContent("You are a personal assistant for GitHub the Copilot GitHub Coding Agent. Your task is to help the user assign tasks to the Coding Agent based on their open GitHub issues. You can use `assign_copilot_to_issue` tool to assign the Coding Agent to issues that are suitable for autonomous work, and `search_issues` tool to find issues that match the user's criteria. You can also use `list_issues` to get a list of issues in the repository."),
+					Content: mcp.NewTextContent(systemText),
 				},
 				{
 					Role:    "user",
-					Content: mcp.NewTextContent(fmt.Sprintf("Please go and get a list of the most recent 10 issues from the %s GitHub repository", repo)),
+					Content: mcp.NewTextContent(fmt.Sprintf(requestText, repo)),
 				},
 				{
 					Role:    "assistant",
-					Content: mcp.NewTextContent(fmt.Sprintf("Sure! I will get a list of the 10 most recent issues for the repo %s.", repo)),
+					Content: mcp.NewTextContent(fmt.Sprintf(acknowledgeText, repo)),
 				},
 				{
 					Role:    "user",
-					Content: mcp.NewTextContent("For each issue, please check if it is a clearly defined coding task with acceptance criteria and a low to medium complexity to identify issues that are suitable for an AI Coding Agent to work on. Then assign each of the identified issues to Copilot."),
+					Content: mcp.NewTextContent(criteriaText),
 				},
 				{
 					Role:    "assistant",
-					Content: mcp.NewTextContent("Certainly! Let me carefully check which ones are clearly scoped issues that are good to assign to the coding agent, and I will summarize and assign them now."),
+					Content: mcp.NewTextContent(planText),
 				},
 				{
 					Role:    "user",
-					Content: mcp.NewTextContent("Great, if you are unsure if an issue is good to assign, ask me first, rather than assigning copilot. If you are certain the issue is clear and suitable you can assign it to Copilot without asking."),
+					Content: mcp.NewTextContent(confirmText),
 				},
 			}
 			return &mcp.GetPromptResult{
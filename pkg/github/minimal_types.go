@@ -88,15 +88,26 @@ type MinimalCommit struct {
 
 // MinimalRelease is the trimmed output type for release objects.
 type MinimalRelease struct {
-	ID          int64        `json:"id"`
-	TagName     string       `json:"tag_name"`
-	Name        string       `json:"name,omitempty"`
-	Body        string       `json:"body,omitempty"`
-	HTMLURL     string       `json:"html_url"`
-	PublishedAt string       `json:"published_at,omitempty"`
-	Prerelease  bool         `json:"prerelease"`
-	Draft       bool         `json:"draft"`
-	Author      *MinimalUser `json:"author,omitempty"`
+	ID          int64                 `json:"id"`
+	TagName     string                `json:"tag_name"`
+	Name        string                `json:"name,omitempty"`
+	Body        string                `json:"body,omitempty"`
+	HTMLURL     string                `json:"html_url"`
+	PublishedAt string                `json:"published_at,omitempty"`
+	Prerelease  bool                  `json:"prerelease"`
+	Draft       bool                  `json:"draft"`
+	Author      *MinimalUser          `json:"author,omitempty"`
+	Assets      []MinimalReleaseAsset `json:"assets,omitempty"`
+}
+
+// MinimalReleaseAsset is the trimmed output type for release asset objects.
+type MinimalReleaseAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	ContentType        string `json:"content_type,omitempty"`
+	Size               int    `json:"size"`
+	DownloadCount      int    `json:"download_count"`
+	BrowserDownloadURL string `json:"browser_download_url,omitempty"`
 }
 
 // MinimalBranch is the trimmed output type for branch objects.
@@ -106,6 +117,581 @@ type MinimalBranch struct {
 	Protected bool   `json:"protected"`
 }
 
+// MinimalRepositoryRef is the trimmed output type for the repository an
+// object belongs to.
+type MinimalRepositoryRef struct {
+	FullName string `json:"full_name"`
+	HTMLURL  string `json:"html_url,omitempty"`
+}
+
+// MinimalLabel is the trimmed output type for label objects.
+type MinimalLabel struct {
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// MinimalMilestone is the trimmed output type for milestone objects.
+type MinimalMilestone struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state,omitempty"`
+	DueOn  string `json:"due_on,omitempty"`
+}
+
+// MinimalIssue is the trimmed output type for issue objects.
+type MinimalIssue struct {
+	ID            int64             `json:"id,omitempty"`
+	Number        int               `json:"number"`
+	Title         string            `json:"title"`
+	Body          string            `json:"body,omitempty"`
+	State         string            `json:"state"`
+	StateReason   string            `json:"state_reason,omitempty"`
+	HTMLURL       string            `json:"html_url,omitempty"`
+	RepositoryURL string            `json:"repository_url,omitempty"`
+	User          *MinimalUser      `json:"user,omitempty"`
+	Labels        []MinimalLabel    `json:"labels,omitempty"`
+	Assignees     []MinimalUser     `json:"assignees,omitempty"`
+	Milestone     *MinimalMilestone `json:"milestone,omitempty"`
+	IssueType     string            `json:"issue_type,omitempty"`
+	Comments      int               `json:"comments"`
+	Draft         bool              `json:"draft,omitempty"`
+	PullRequest   bool              `json:"is_pull_request,omitempty"`
+	CreatedAt     string            `json:"created_at,omitempty"`
+	UpdatedAt     string            `json:"updated_at,omitempty"`
+	ClosedAt      string            `json:"closed_at,omitempty"`
+}
+
+// MinimalIssueComment is the trimmed output type for issue comment objects.
+type MinimalIssueComment struct {
+	ID                int64        `json:"id"`
+	Body              string       `json:"body"`
+	User              *MinimalUser `json:"user,omitempty"`
+	HTMLURL           string       `json:"html_url,omitempty"`
+	AuthorAssociation string       `json:"author_association,omitempty"`
+	CreatedAt         string       `json:"created_at,omitempty"`
+	UpdatedAt         string       `json:"updated_at,omitempty"`
+}
+
+// MinimalIssueType is the trimmed output type for issue type objects.
+type MinimalIssueType struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Color       string `json:"color,omitempty"`
+}
+
+// MinimalPageInfo is the cursor pagination of results listed through the
+// GraphQL API.
+type MinimalPageInfo struct {
+	HasNextPage     bool   `json:"hasNextPage"`
+	HasPreviousPage bool   `json:"hasPreviousPage"`
+	StartCursor     string `json:"startCursor"`
+	EndCursor       string `json:"endCursor"`
+}
+
+// MinimalListIssuesResult is the trimmed output type for issue lists.
+type MinimalListIssuesResult struct {
+	Issues     []MinimalIssue  `json:"issues"`
+	PageInfo   MinimalPageInfo `json:"pageInfo"`
+	TotalCount int             `json:"totalCount"`
+}
+
+// MinimalSearchIssuesResult is the trimmed output type for issue and pull
+// request search results.
+type MinimalSearchIssuesResult struct {
+	TotalCount        int            `json:"total_count"`
+	IncompleteResults bool           `json:"incomplete_results"`
+	Items             []MinimalIssue `json:"items"`
+}
+
+// MinimalPullRequestBranch is the trimmed output type for the head and base
+// branches of a pull request.
+type MinimalPullRequestBranch struct {
+	Label string                `json:"label,omitempty"`
+	Ref   string                `json:"ref"`
+	SHA   string                `json:"sha"`
+	Repo  *MinimalRepositoryRef `json:"repo,omitempty"`
+}
+
+// MinimalPullRequest is the trimmed output type for pull request objects.
+type MinimalPullRequest struct {
+	ID                 int64                     `json:"id,omitempty"`
+	Number             int                       `json:"number"`
+	Title              string                    `json:"title"`
+	Body               string                    `json:"body,omitempty"`
+	State              string                    `json:"state"`
+	Draft              bool                      `json:"draft"`
+	Merged             bool                      `json:"merged"`
+	Mergeable          *bool                     `json:"mergeable,omitempty"`
+	MergeableState     string                    `json:"mergeable_state,omitempty"`
+	HTMLURL            string                    `json:"html_url"`
+	User               *MinimalUser              `json:"user,omitempty"`
+	Labels             []MinimalLabel            `json:"labels,omitempty"`
+	Assignees          []MinimalUser             `json:"assignees,omitempty"`
+	RequestedReviewers []MinimalUser             `json:"requested_reviewers,omitempty"`
+	Milestone          *MinimalMilestone         `json:"milestone,omitempty"`
+	Head               *MinimalPullRequestBranch `json:"head,omitempty"`
+	Base               *MinimalPullRequestBranch `json:"base,omitempty"`
+	Commits            int                       `json:"commits,omitempty"`
+	Additions          int                       `json:"additions,omitempty"`
+	Deletions          int                       `json:"deletions,omitempty"`
+	ChangedFiles       int                       `json:"changed_files,omitempty"`
+	Comments           int                       `json:"comments,omitempty"`
+	ReviewComments     int                       `json:"review_comments,omitempty"`
+	MergedBy           *MinimalUser              `json:"merged_by,omitempty"`
+	MergeCommitSHA     string                    `json:"merge_commit_sha,omitempty"`
+	CreatedAt          string                    `json:"created_at,omitempty"`
+	UpdatedAt          string                    `json:"updated_at,omitempty"`
+	ClosedAt           string                    `json:"closed_at,omitempty"`
+	MergedAt           string                    `json:"merged_at,omitempty"`
+}
+
+// MinimalPullRequestFile is the trimmed output type for the files changed by
+// a pull request.
+type MinimalPullRequestFile struct {
+	Filename         string `json:"filename"`
+	Status           string `json:"status,omitempty"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+	Changes          int    `json:"changes"`
+	Patch            string `json:"patch,omitempty"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
+}
+
+// MinimalStatus is the trimmed output type for commit status objects.
+type MinimalStatus struct {
+	Context     string `json:"context"`
+	State       string `json:"state"`
+	Description string `json:"description,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
+
+// MinimalCombinedStatus is the trimmed output type for the combined status
+// of a commit.
+type MinimalCombinedStatus struct {
+	State      string          `json:"state"`
+	SHA        string          `json:"sha"`
+	TotalCount int             `json:"total_count"`
+	Statuses   []MinimalStatus `json:"statuses"`
+}
+
+// MinimalPullRequestComment is the trimmed output type for pull request
+// review comment objects.
+type MinimalPullRequestComment struct {
+	ID        int64        `json:"id"`
+	InReplyTo int64        `json:"in_reply_to_id,omitempty"`
+	Body      string       `json:"body"`
+	Path      string       `json:"path,omitempty"`
+	Line      int          `json:"line,omitempty"`
+	StartLine int          `json:"start_line,omitempty"`
+	Side      string       `json:"side,omitempty"`
+	DiffHunk  string       `json:"diff_hunk,omitempty"`
+	CommitID  string       `json:"commit_id,omitempty"`
+	User      *MinimalUser `json:"user,omitempty"`
+	HTMLURL   string       `json:"html_url,omitempty"`
+	CreatedAt string       `json:"created_at,omitempty"`
+	UpdatedAt string       `json:"updated_at,omitempty"`
+}
+
+// MinimalPullRequestReview is the trimmed output type for pull request review
+// objects.
+type MinimalPullRequestReview struct {
+	ID          int64        `json:"id"`
+	State       string       `json:"state"`
+	Body        string       `json:"body,omitempty"`
+	User        *MinimalUser `json:"user,omitempty"`
+	CommitID    string       `json:"commit_id,omitempty"`
+	HTMLURL     string       `json:"html_url,omitempty"`
+	SubmittedAt string       `json:"submitted_at,omitempty"`
+}
+
+// MinimalTagCommit is the commit a tag points to.
+type MinimalTagCommit struct {
+	SHA string `json:"sha"`
+}
+
+// MinimalTag is the trimmed output type for repository tag objects.
+type MinimalTag struct {
+	Name   string            `json:"name"`
+	Commit *MinimalTagCommit `json:"commit,omitempty"`
+}
+
+// MinimalGitObject is the trimmed output type for the object an annotated
+// tag points to.
+type MinimalGitObject struct {
+	Type string `json:"type"`
+	SHA  string `json:"sha"`
+}
+
+// MinimalAnnotatedTag is the trimmed output type for annotated tag objects.
+type MinimalAnnotatedTag struct {
+	Tag     string               `json:"tag"`
+	SHA     string               `json:"sha"`
+	Message string               `json:"message,omitempty"`
+	Tagger  *MinimalCommitAuthor `json:"tagger,omitempty"`
+	Object  *MinimalGitObject    `json:"object,omitempty"`
+}
+
+// MinimalContent is the trimmed output type for the entries of a directory.
+type MinimalContent struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	SHA     string `json:"sha,omitempty"`
+	Size    int    `json:"size,omitempty"`
+	HTMLURL string `json:"html_url,omitempty"`
+}
+
+// MinimalCodeResult is the trimmed output type for code search results.
+type MinimalCodeResult struct {
+	Name       string                `json:"name"`
+	Path       string                `json:"path"`
+	SHA        string                `json:"sha,omitempty"`
+	HTMLURL    string                `json:"html_url,omitempty"`
+	Repository *MinimalRepositoryRef `json:"repository,omitempty"`
+}
+
+// MinimalSearchCodeResult is the trimmed output type for code search results.
+type MinimalSearchCodeResult struct {
+	TotalCount        int                 `json:"total_count"`
+	IncompleteResults bool                `json:"incomplete_results"`
+	Items             []MinimalCodeResult `json:"items"`
+}
+
+// MinimalDiscussionCategory is the trimmed output type for discussion
+// category objects.
+type MinimalDiscussionCategory struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// MinimalDiscussion is the trimmed output type for discussion objects.
+type MinimalDiscussion struct {
+	Number    int                        `json:"number"`
+	Title     string                     `json:"title"`
+	Body      string                     `json:"body,omitempty"`
+	HTMLURL   string                     `json:"html_url"`
+	User      *MinimalUser               `json:"user,omitempty"`
+	Category  *MinimalDiscussionCategory `json:"category,omitempty"`
+	CreatedAt string                     `json:"created_at,omitempty"`
+	UpdatedAt string                     `json:"updated_at,omitempty"`
+}
+
+// MinimalListDiscussionsResult is the trimmed output type for discussion lists.
+type MinimalListDiscussionsResult struct {
+	Discussions []MinimalDiscussion `json:"discussions"`
+	PageInfo    MinimalPageInfo     `json:"pageInfo"`
+	TotalCount  int                 `json:"totalCount"`
+}
+
+// MinimalDiscussionComment is the trimmed output type for discussion comment
+// objects.
+type MinimalDiscussionComment struct {
+	Body string `json:"body"`
+}
+
+// MinimalDiscussionCommentsResult is the trimmed output type for discussion
+// comment lists.
+type MinimalDiscussionCommentsResult struct {
+	Comments   []MinimalDiscussionComment `json:"comments"`
+	PageInfo   MinimalPageInfo            `json:"pageInfo"`
+	TotalCount int                        `json:"totalCount"`
+}
+
+// MinimalDiscussionCategoriesResult is the trimmed output type for discussion
+// category lists.
+type MinimalDiscussionCategoriesResult struct {
+	Categories []MinimalDiscussionCategory `json:"categories"`
+	PageInfo   MinimalPageInfo             `json:"pageInfo"`
+	TotalCount int                         `json:"totalCount"`
+}
+
+// MinimalNotificationSubject is the trimmed output type for the subject of a
+// notification.
+type MinimalNotificationSubject struct {
+	Title            string `json:"title"`
+	Type             string `json:"type"`
+	URL              string `json:"url,omitempty"`
+	LatestCommentURL string `json:"latest_comment_url,omitempty"`
+}
+
+// MinimalNotification is the trimmed output type for notification objects.
+type MinimalNotification struct {
+	ID         string                      `json:"id"`
+	Reason     string                      `json:"reason"`
+	Unread     bool                        `json:"unread"`
+	Subject    *MinimalNotificationSubject `json:"subject,omitempty"`
+	Repository *MinimalRepositoryRef       `json:"repository,omitempty"`
+	UpdatedAt  string                      `json:"updated_at,omitempty"`
+	LastReadAt string                      `json:"last_read_at,omitempty"`
+}
+
+// MinimalGistFile is the trimmed output type for the files of a gist.
+type MinimalGistFile struct {
+	Filename string `json:"filename"`
+	Language string `json:"language,omitempty"`
+	Size     int    `json:"size,omitempty"`
+	RawURL   string `json:"raw_url,omitempty"`
+}
+
+// MinimalGist is the trimmed output type for gist objects.
+type MinimalGist struct {
+	ID          string                     `json:"id"`
+	Description string                     `json:"description,omitempty"`
+	Public      bool                       `json:"public"`
+	HTMLURL     string                     `json:"html_url"`
+	Owner       *MinimalUser               `json:"owner,omitempty"`
+	Files       map[string]MinimalGistFile `json:"files,omitempty"`
+	CreatedAt   string                     `json:"created_at,omitempty"`
+	UpdatedAt   string                     `json:"updated_at,omitempty"`
+}
+
+// MinimalCodeScanningRule is the trimmed output type for the rule of a code
+// scanning alert.
+type MinimalCodeScanningRule struct {
+	ID                    string   `json:"id"`
+	Name                  string   `json:"name,omitempty"`
+	Severity              string   `json:"severity,omitempty"`
+	SecuritySeverityLevel string   `json:"security_severity_level,omitempty"`
+	Description           string   `json:"description,omitempty"`
+	Tags                  []string `json:"tags,omitempty"`
+}
+
+// MinimalCodeScanningTool is the trimmed output type for the tool that raised
+// a code scanning alert.
+type MinimalCodeScanningTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// MinimalCodeScanningMessage is the message of a code scanning alert instance.
+type MinimalCodeScanningMessage struct {
+	Text string `json:"text"`
+}
+
+// MinimalCodeScanningLocation is the location of a code scanning alert
+// instance.
+type MinimalCodeScanningLocation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+}
+
+// MinimalCodeScanningInstance is the trimmed output type for the most recent
+// instance of a code scanning alert.
+type MinimalCodeScanningInstance struct {
+	Ref       string                       `json:"ref,omitempty"`
+	State     string                       `json:"state,omitempty"`
+	CommitSHA string                       `json:"commit_sha,omitempty"`
+	Message   *MinimalCodeScanningMessage  `json:"message,omitempty"`
+	Location  *MinimalCodeScanningLocation `json:"location,omitempty"`
+}
+
+// MinimalCodeScanningAlert is the trimmed output type for code scanning alert
+// objects.
+type MinimalCodeScanningAlert struct {
+	Number             int                          `json:"number"`
+	State              string                       `json:"state"`
+	HTMLURL            string                       `json:"html_url"`
+	Rule               *MinimalCodeScanningRule     `json:"rule,omitempty"`
+	Tool               *MinimalCodeScanningTool     `json:"tool,omitempty"`
+	MostRecentInstance *MinimalCodeScanningInstance `json:"most_recent_instance,omitempty"`
+	CreatedAt          string                       `json:"created_at,omitempty"`
+	UpdatedAt          string                       `json:"updated_at,omitempty"`
+	FixedAt            string                       `json:"fixed_at,omitempty"`
+	DismissedAt        string                       `json:"dismissed_at,omitempty"`
+	DismissedReason    string                       `json:"dismissed_reason,omitempty"`
+	DismissedComment   string                       `json:"dismissed_comment,omitempty"`
+}
+
+// MinimalPackage is the trimmed output type for the package a vulnerability
+// affects.
+type MinimalPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// MinimalDependency is the trimmed output type for the dependency of a
+// Dependabot alert.
+type MinimalDependency struct {
+	Package      *MinimalPackage `json:"package,omitempty"`
+	ManifestPath string          `json:"manifest_path,omitempty"`
+	Scope        string          `json:"scope,omitempty"`
+}
+
+// MinimalCVSS is the CVSS score of a security advisory.
+type MinimalCVSS struct {
+	Score        *float64 `json:"score,omitempty"`
+	VectorString string   `json:"vector_string,omitempty"`
+}
+
+// MinimalCWE is a weakness a security advisory is classified under.
+type MinimalCWE struct {
+	CWEID string `json:"cwe_id"`
+	Name  string `json:"name,omitempty"`
+}
+
+// MinimalVulnerability is the trimmed output type for the vulnerabilities of
+// a security advisory. FirstPatchedVersion is the first version fixing it,
+// and PatchedVersions the versions fixing it, for repository advisories.
+type MinimalVulnerability struct {
+	Package                *MinimalPackage `json:"package,omitempty"`
+	Severity               string          `json:"severity,omitempty"`
+	VulnerableVersionRange string          `json:"vulnerable_version_range,omitempty"`
+	FirstPatchedVersion    string          `json:"first_patched_version,omitempty"`
+	PatchedVersions        string          `json:"patched_versions,omitempty"`
+}
+
+// MinimalSecurityAdvisory is the trimmed output type for global, repository
+// and Dependabot security advisory objects.
+type MinimalSecurityAdvisory struct {
+	GHSAID          string                 `json:"ghsa_id"`
+	CVEID           string                 `json:"cve_id,omitempty"`
+	Summary         string                 `json:"summary"`
+	Description     string                 `json:"description,omitempty"`
+	Severity        string                 `json:"severity,omitempty"`
+	Type            string                 `json:"type,omitempty"`
+	State           string                 `json:"state,omitempty"`
+	HTMLURL         string                 `json:"html_url,omitempty"`
+	CVSS            *MinimalCVSS           `json:"cvss,omitempty"`
+	CWEs            []MinimalCWE           `json:"cwes,omitempty"`
+	Vulnerabilities []MinimalVulnerability `json:"vulnerabilities,omitempty"`
+	PublishedAt     string                 `json:"published_at,omitempty"`
+	UpdatedAt       string                 `json:"updated_at,omitempty"`
+	WithdrawnAt     string                 `json:"withdrawn_at,omitempty"`
+}
+
+// MinimalDependabotAlert is the trimmed output type for Dependabot alert
+// objects.
+type MinimalDependabotAlert struct {
+	Number                int                      `json:"number"`
+	State                 string                   `json:"state"`
+	HTMLURL               string                   `json:"html_url"`
+	Dependency            *MinimalDependency       `json:"dependency,omitempty"`
+	SecurityAdvisory      *MinimalSecurityAdvisory `json:"security_advisory,omitempty"`
+	SecurityVulnerability *MinimalVulnerability    `json:"security_vulnerability,omitempty"`
+	CreatedAt             string                   `json:"created_at,omitempty"`
+	UpdatedAt             string                   `json:"updated_at,omitempty"`
+	FixedAt               string                   `json:"fixed_at,omitempty"`
+	DismissedAt           string                   `json:"dismissed_at,omitempty"`
+	DismissedReason       string                   `json:"dismissed_reason,omitempty"`
+	DismissedComment      string                   `json:"dismissed_comment,omitempty"`
+}
+
+// MinimalSecretScanningAlert is the trimmed output type for secret scanning
+// alert objects.
+type MinimalSecretScanningAlert struct {
+	Number                 int          `json:"number"`
+	State                  string       `json:"state"`
+	HTMLURL                string       `json:"html_url"`
+	SecretType             string       `json:"secret_type,omitempty"`
+	SecretTypeDisplayName  string       `json:"secret_type_display_name,omitempty"`
+	Secret                 string       `json:"secret,omitempty"`
+	Validity               string       `json:"validity,omitempty"`
+	Resolution             string       `json:"resolution,omitempty"`
+	ResolutionComment      string       `json:"resolution_comment,omitempty"`
+	ResolvedBy             *MinimalUser `json:"resolved_by,omitempty"`
+	ResolvedAt             string       `json:"resolved_at,omitempty"`
+	PushProtectionBypassed bool         `json:"push_protection_bypassed,omitempty"`
+	PubliclyLeaked         bool         `json:"publicly_leaked,omitempty"`
+	CreatedAt              string       `json:"created_at,omitempty"`
+	UpdatedAt              string       `json:"updated_at,omitempty"`
+}
+
+// MinimalWorkflow is the trimmed output type for workflow objects.
+type MinimalWorkflow struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	State     string `json:"state"`
+	HTMLURL   string `json:"html_url,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// MinimalWorkflowRun is the trimmed output type for workflow run objects.
+type MinimalWorkflowRun struct {
+	ID           int64        `json:"id"`
+	Name         string       `json:"name,omitempty"`
+	DisplayTitle string       `json:"display_title,omitempty"`
+	WorkflowID   int64        `json:"workflow_id,omitempty"`
+	RunNumber    int          `json:"run_number,omitempty"`
+	RunAttempt   int          `json:"run_attempt,omitempty"`
+	Event        string       `json:"event,omitempty"`
+	Status       string       `json:"status"`
+	Conclusion   string       `json:"conclusion,omitempty"`
+	HeadBranch   string       `json:"head_branch,omitempty"`
+	HeadSHA      string       `json:"head_sha,omitempty"`
+	HTMLURL      string       `json:"html_url,omitempty"`
+	Actor        *MinimalUser `json:"actor,omitempty"`
+	CreatedAt    string       `json:"created_at,omitempty"`
+	UpdatedAt    string       `json:"updated_at,omitempty"`
+	RunStartedAt string       `json:"run_started_at,omitempty"`
+}
+
+// MinimalWorkflowStep is the trimmed output type for the steps of a workflow
+// job.
+type MinimalWorkflowStep struct {
+	Number     int64  `json:"number"`
+	Name       string `json:"name"`
+	Status     string `json:"status,omitempty"`
+	Conclusion string `json:"conclusion,omitempty"`
+}
+
+// MinimalWorkflowJob is the trimmed output type for workflow job objects.
+type MinimalWorkflowJob struct {
+	ID          int64                 `json:"id"`
+	RunID       int64                 `json:"run_id,omitempty"`
+	Name        string                `json:"name"`
+	Status      string                `json:"status"`
+	Conclusion  string                `json:"conclusion,omitempty"`
+	HTMLURL     string                `json:"html_url,omitempty"`
+	HeadBranch  string                `json:"head_branch,omitempty"`
+	HeadSHA     string                `json:"head_sha,omitempty"`
+	Labels      []string              `json:"labels,omitempty"`
+	RunnerName  string                `json:"runner_name,omitempty"`
+	StartedAt   string                `json:"started_at,omitempty"`
+	CompletedAt string                `json:"completed_at,omitempty"`
+	Steps       []MinimalWorkflowStep `json:"steps,omitempty"`
+}
+
+// MinimalArtifact is the trimmed output type for workflow run artifact
+// objects.
+type MinimalArtifact struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	SizeInBytes int64  `json:"size_in_bytes"`
+	Expired     bool   `json:"expired"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	ExpiresAt   string `json:"expires_at,omitempty"`
+}
+
+// MinimalWorkflowsResult is the trimmed output type for workflow lists.
+type MinimalWorkflowsResult struct {
+	TotalCount int               `json:"total_count"`
+	Workflows  []MinimalWorkflow `json:"workflows"`
+}
+
+// MinimalWorkflowRunsResult is the trimmed output type for workflow run lists.
+type MinimalWorkflowRunsResult struct {
+	TotalCount   int                  `json:"total_count"`
+	WorkflowRuns []MinimalWorkflowRun `json:"workflow_runs"`
+}
+
+// MinimalWorkflowJobsResult is the trimmed output type for workflow job lists.
+type MinimalWorkflowJobsResult struct {
+	TotalCount int                  `json:"total_count"`
+	Jobs       []MinimalWorkflowJob `json:"jobs"`
+}
+
+// MinimalArtifactsResult is the trimmed output type for artifact lists.
+type MinimalArtifactsResult struct {
+	TotalCount int64             `json:"total_count"`
+	Artifacts  []MinimalArtifact `json:"artifacts"`
+}
+
 // MinimalResponse represents a minimal response for all CRUD operations.
 // Success is implicit in the HTTP response status, and all other information
 // can be derived from the URL or fetched separately if needed.
@@ -202,3 +788,646 @@ func convertToMinimalBranch(branch *github.Branch) MinimalBranch {
 		Protected: branch.GetProtected(),
 	}
 }
+
+// formatTimestamp formats ts the way the GitHub API does, or returns "" for
+// a missing timestamp.
+func formatTimestamp(ts *github.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.Format("2006-01-02T15:04:05Z")
+}
+
+// convertToMinimalUser converts a GitHub API User to MinimalUser, or returns
+// nil for a missing user.
+func convertToMinimalUser(user *github.User) *MinimalUser {
+	if user == nil {
+		return nil
+	}
+	return &MinimalUser{
+		Login:      user.GetLogin(),
+		ID:         user.GetID(),
+		ProfileURL: user.GetHTMLURL(),
+		AvatarURL:  user.GetAvatarURL(),
+	}
+}
+
+// convertToMinimalUsers converts GitHub API Users to MinimalUsers.
+func convertToMinimalUsers(users []*github.User) []MinimalUser {
+	var minimalUsers []MinimalUser
+	for _, user := range users {
+		if user != nil {
+			minimalUsers = append(minimalUsers, *convertToMinimalUser(user))
+		}
+	}
+	return minimalUsers
+}
+
+// convertToMinimalRepositoryRef converts a GitHub API Repository to
+// MinimalRepositoryRef, or returns nil for a missing repository.
+func convertToMinimalRepositoryRef(repo *github.Repository) *MinimalRepositoryRef {
+	if repo == nil {
+		return nil
+	}
+	return &MinimalRepositoryRef{
+		FullName: repo.GetFullName(),
+		HTMLURL:  repo.GetHTMLURL(),
+	}
+}
+
+// convertToMinimalRepository converts a GitHub API Repository to MinimalRepository
+func convertToMinimalRepository(repo *github.Repository) MinimalRepository {
+	return MinimalRepository{
+		ID:            repo.GetID(),
+		Name:          repo.GetName(),
+		FullName:      repo.GetFullName(),
+		Description:   repo.GetDescription(),
+		HTMLURL:       repo.GetHTMLURL(),
+		Language:      repo.GetLanguage(),
+		Stars:         repo.GetStargazersCount(),
+		Forks:         repo.GetForksCount(),
+		OpenIssues:    repo.GetOpenIssuesCount(),
+		UpdatedAt:     formatTimestamp(repo.UpdatedAt),
+		CreatedAt:     formatTimestamp(repo.CreatedAt),
+		Topics:        repo.Topics,
+		Private:       repo.GetPrivate(),
+		Fork:          repo.GetFork(),
+		Archived:      repo.GetArchived(),
+		DefaultBranch: repo.GetDefaultBranch(),
+	}
+}
+
+// convertToMinimalLabels converts GitHub API Labels to MinimalLabels.
+func convertToMinimalLabels(labels []*github.Label) []MinimalLabel {
+	var minimalLabels []MinimalLabel
+	for _, label := range labels {
+		if label != nil {
+			minimalLabels = append(minimalLabels, MinimalLabel{
+				Name:        label.GetName(),
+				Color:       label.GetColor(),
+				Description: label.GetDescription(),
+			})
+		}
+	}
+	return minimalLabels
+}
+
+// convertToMinimalMilestone converts a GitHub API Milestone to
+// MinimalMilestone, or returns nil for a missing milestone.
+func convertToMinimalMilestone(milestone *github.Milestone) *MinimalMilestone {
+	if milestone == nil {
+		return nil
+	}
+	return &MinimalMilestone{
+		Number: milestone.GetNumber(),
+		Title:  milestone.GetTitle(),
+		State:  milestone.GetState(),
+		DueOn:  formatTimestamp(milestone.DueOn),
+	}
+}
+
+// convertToMinimalIssue converts a GitHub API Issue to MinimalIssue
+func convertToMinimalIssue(issue *github.Issue) MinimalIssue {
+	return MinimalIssue{
+		ID:            issue.GetID(),
+		Number:        issue.GetNumber(),
+		Title:         issue.GetTitle(),
+		Body:          issue.GetBody(),
+		State:         issue.GetState(),
+		StateReason:   issue.GetStateReason(),
+		HTMLURL:       issue.GetHTMLURL(),
+		RepositoryURL: issue.GetRepositoryURL(),
+		User:          convertToMinimalUser(issue.User),
+		Labels:        convertToMinimalLabels(issue.Labels),
+		Assignees:     convertToMinimalUsers(issue.Assignees),
+		Milestone:     convertToMinimalMilestone(issue.Milestone),
+		IssueType:     issue.GetType().GetName(),
+		Comments:      issue.GetComments(),
+		Draft:         issue.GetDraft(),
+		PullRequest:   issue.IsPullRequest(),
+		CreatedAt:     formatTimestamp(issue.CreatedAt),
+		UpdatedAt:     formatTimestamp(issue.UpdatedAt),
+		ClosedAt:      formatTimestamp(issue.ClosedAt),
+	}
+}
+
+// convertToMinimalIssues converts GitHub API Issues to MinimalIssues.
+func convertToMinimalIssues(issues []*github.Issue) []MinimalIssue {
+	minimalIssues := make([]MinimalIssue, 0, len(issues))
+	for _, issue := range issues {
+		minimalIssues = append(minimalIssues, convertToMinimalIssue(issue))
+	}
+	return minimalIssues
+}
+
+// convertToMinimalIssueComment converts a GitHub API IssueComment to MinimalIssueComment
+func convertToMinimalIssueComment(comment *github.IssueComment) MinimalIssueComment {
+	return MinimalIssueComment{
+		ID:                comment.GetID(),
+		Body:              comment.GetBody(),
+		User:              convertToMinimalUser(comment.User),
+		HTMLURL:           comment.GetHTMLURL(),
+		AuthorAssociation: comment.GetAuthorAssociation(),
+		CreatedAt:         formatTimestamp(comment.CreatedAt),
+		UpdatedAt:         formatTimestamp(comment.UpdatedAt),
+	}
+}
+
+// convertToMinimalPullRequestBranch converts a GitHub API PullRequestBranch
+// to MinimalPullRequestBranch, or returns nil for a missing branch.
+func convertToMinimalPullRequestBranch(branch *github.PullRequestBranch) *MinimalPullRequestBranch {
+	if branch == nil {
+		return nil
+	}
+	return &MinimalPullRequestBranch{
+		Label: branch.GetLabel(),
+		Ref:   branch.GetRef(),
+		SHA:   branch.GetSHA(),
+		Repo:  convertToMinimalRepositoryRef(branch.Repo),
+	}
+}
+
+// convertToMinimalPullRequest converts a GitHub API PullRequest to MinimalPullRequest
+func convertToMinimalPullRequest(pr *github.PullRequest) MinimalPullRequest {
+	return MinimalPullRequest{
+		ID:                 pr.GetID(),
+		Number:             pr.GetNumber(),
+		Title:              pr.GetTitle(),
+		Body:               pr.GetBody(),
+		State:              pr.GetState(),
+		Draft:              pr.GetDraft(),
+		Merged:             pr.GetMerged(),
+		Mergeable:          pr.Mergeable,
+		MergeableState:     pr.GetMergeableState(),
+		HTMLURL:            pr.GetHTMLURL(),
+		User:               convertToMinimalUser(pr.User),
+		Labels:             convertToMinimalLabels(pr.Labels),
+		Assignees:          convertToMinimalUsers(pr.Assignees),
+		RequestedReviewers: convertToMinimalUsers(pr.RequestedReviewers),
+		Milestone:          convertToMinimalMilestone(pr.Milestone),
+		Head:               convertToMinimalPullRequestBranch(pr.Head),
+		Base:               convertToMinimalPullRequestBranch(pr.Base),
+		Commits:            pr.GetCommits(),
+		Additions:          pr.GetAdditions(),
+		Deletions:          pr.GetDeletions(),
+		ChangedFiles:       pr.GetChangedFiles(),
+		Comments:           pr.GetComments(),
+		ReviewComments:     pr.GetReviewComments(),
+		MergedBy:           convertToMinimalUser(pr.MergedBy),
+		MergeCommitSHA:     pr.GetMergeCommitSHA(),
+		CreatedAt:          formatTimestamp(pr.CreatedAt),
+		UpdatedAt:          formatTimestamp(pr.UpdatedAt),
+		ClosedAt:           formatTimestamp(pr.ClosedAt),
+		MergedAt:           formatTimestamp(pr.MergedAt),
+	}
+}
+
+// convertToMinimalPullRequestComment converts a GitHub API PullRequestComment to MinimalPullRequestComment
+func convertToMinimalPullRequestComment(comment *github.PullRequestComment) MinimalPullRequestComment {
+	return MinimalPullRequestComment{
+		ID:        comment.GetID(),
+		InReplyTo: comment.GetInReplyTo(),
+		Body:      comment.GetBody(),
+		Path:      comment.GetPath(),
+		Line:      comment.GetLine(),
+		StartLine: comment.GetStartLine(),
+		Side:      comment.GetSide(),
+		DiffHunk:  comment.GetDiffHunk(),
+		CommitID:  comment.GetCommitID(),
+		User:      convertToMinimalUser(comment.User),
+		HTMLURL:   comment.GetHTMLURL(),
+		CreatedAt: formatTimestamp(comment.CreatedAt),
+		UpdatedAt: formatTimestamp(comment.UpdatedAt),
+	}
+}
+
+// convertToMinimalPullRequestReview converts a GitHub API PullRequestReview to MinimalPullRequestReview
+func convertToMinimalPullRequestReview(review *github.PullRequestReview) MinimalPullRequestReview {
+	return MinimalPullRequestReview{
+		ID:          review.GetID(),
+		State:       review.GetState(),
+		Body:        review.GetBody(),
+		User:        convertToMinimalUser(review.User),
+		CommitID:    review.GetCommitID(),
+		HTMLURL:     review.GetHTMLURL(),
+		SubmittedAt: formatTimestamp(review.SubmittedAt),
+	}
+}
+
+// convertToMinimalCombinedStatus converts a GitHub API CombinedStatus to MinimalCombinedStatus
+func convertToMinimalCombinedStatus(status *github.CombinedStatus) MinimalCombinedStatus {
+	minimalStatus := MinimalCombinedStatus{
+		State:      status.GetState(),
+		SHA:        status.GetSHA(),
+		TotalCount: status.GetTotalCount(),
+		Statuses:   make([]MinimalStatus, 0, len(status.Statuses)),
+	}
+	for _, s := range status.Statuses {
+		minimalStatus.Statuses = append(minimalStatus.Statuses, MinimalStatus{
+			Context:     s.GetContext(),
+			State:       s.GetState(),
+			Description: s.GetDescription(),
+			TargetURL:   s.GetTargetURL(),
+			UpdatedAt:   formatTimestamp(s.UpdatedAt),
+		})
+	}
+	return minimalStatus
+}
+
+// convertToMinimalRelease converts a GitHub API RepositoryRelease to MinimalRelease
+func convertToMinimalRelease(release *github.RepositoryRelease) MinimalRelease {
+	var assets []MinimalReleaseAsset
+	for _, asset := range release.Assets {
+		if asset != nil {
+			assets = append(assets, MinimalReleaseAsset{
+				ID:                 asset.GetID(),
+				Name:               asset.GetName(),
+				ContentType:        asset.GetContentType(),
+				Size:               asset.GetSize(),
+				DownloadCount:      asset.GetDownloadCount(),
+				BrowserDownloadURL: asset.GetBrowserDownloadURL(),
+			})
+		}
+	}
+
+	return MinimalRelease{
+		ID:          release.GetID(),
+		TagName:     release.GetTagName(),
+		Name:        release.GetName(),
+		Body:        release.GetBody(),
+		HTMLURL:     release.GetHTMLURL(),
+		PublishedAt: formatTimestamp(release.PublishedAt),
+		Prerelease:  release.GetPrerelease(),
+		Draft:       release.GetDraft(),
+		Author:      convertToMinimalUser(release.Author),
+		Assets:      assets,
+	}
+}
+
+// convertToMinimalDiscussion converts a GitHub API Discussion to MinimalDiscussion
+func convertToMinimalDiscussion(discussion *github.Discussion) MinimalDiscussion {
+	minimalDiscussion := MinimalDiscussion{
+		Number:    discussion.GetNumber(),
+		Title:     discussion.GetTitle(),
+		Body:      discussion.GetBody(),
+		HTMLURL:   discussion.GetHTMLURL(),
+		CreatedAt: formatTimestamp(discussion.CreatedAt),
+		UpdatedAt: formatTimestamp(discussion.UpdatedAt),
+	}
+	if discussion.User != nil {
+		minimalDiscussion.User = &MinimalUser{Login: discussion.User.GetLogin()}
+	}
+	if discussion.DiscussionCategory != nil {
+		minimalDiscussion.Category = &MinimalDiscussionCategory{Name: discussion.DiscussionCategory.GetName()}
+	}
+	return minimalDiscussion
+}
+
+// convertToMinimalNotification converts a GitHub API Notification to MinimalNotification
+func convertToMinimalNotification(notification *github.Notification) MinimalNotification {
+	minimalNotification := MinimalNotification{
+		ID:         notification.GetID(),
+		Reason:     notification.GetReason(),
+		Unread:     notification.GetUnread(),
+		Repository: convertToMinimalRepositoryRef(notification.Repository),
+		UpdatedAt:  formatTimestamp(notification.UpdatedAt),
+		LastReadAt: formatTimestamp(notification.LastReadAt),
+	}
+	if subject := notification.Subject; subject != nil {
+		minimalNotification.Subject = &MinimalNotificationSubject{
+			Title:            subject.GetTitle(),
+			Type:             subject.GetType(),
+			URL:              subject.GetURL(),
+			LatestCommentURL: subject.GetLatestCommentURL(),
+		}
+	}
+	return minimalNotification
+}
+
+// convertToMinimalGist converts a GitHub API Gist to MinimalGist
+func convertToMinimalGist(gist *github.Gist) MinimalGist {
+	minimalGist := MinimalGist{
+		ID:          gist.GetID(),
+		Description: gist.GetDescription(),
+		Public:      gist.GetPublic(),
+		HTMLURL:     gist.GetHTMLURL(),
+		Owner:       convertToMinimalUser(gist.Owner),
+		CreatedAt:   formatTimestamp(gist.CreatedAt),
+		UpdatedAt:   formatTimestamp(gist.UpdatedAt),
+	}
+	if len(gist.Files) > 0 {
+		minimalGist.Files = make(map[string]MinimalGistFile, len(gist.Files))
+		for name, file := range gist.Files {
+			minimalGist.Files[string(name)] = MinimalGistFile{
+				Filename: file.GetFilename(),
+				Language: file.GetLanguage(),
+				Size:     file.GetSize(),
+				RawURL:   file.GetRawURL(),
+			}
+		}
+	}
+	return minimalGist
+}
+
+// convertToMinimalCodeScanningAlert converts a GitHub API Alert to MinimalCodeScanningAlert
+func convertToMinimalCodeScanningAlert(alert *github.Alert) MinimalCodeScanningAlert {
+	minimalAlert := MinimalCodeScanningAlert{
+		Number:           alert.GetNumber(),
+		State:            alert.GetState(),
+		HTMLURL:          alert.GetHTMLURL(),
+		CreatedAt:        formatTimestamp(alert.CreatedAt),
+		UpdatedAt:        formatTimestamp(alert.UpdatedAt),
+		FixedAt:          formatTimestamp(alert.FixedAt),
+		DismissedAt:      formatTimestamp(alert.DismissedAt),
+		DismissedReason:  alert.GetDismissedReason(),
+		DismissedComment: alert.GetDismissedComment(),
+	}
+	if rule := alert.Rule; rule != nil {
+		minimalAlert.Rule = &MinimalCodeScanningRule{
+			ID:                    rule.GetID(),
+			Name:                  rule.GetName(),
+			Severity:              rule.GetSeverity(),
+			SecuritySeverityLevel: rule.GetSecuritySeverityLevel(),
+			Description:           rule.GetDescription(),
+			Tags:                  rule.Tags,
+		}
+	}
+	if tool := alert.Tool; tool != nil {
+		minimalAlert.Tool = &MinimalCodeScanningTool{
+			Name:    tool.GetName(),
+			Version: tool.GetVersion(),
+		}
+	}
+	if instance := alert.MostRecentInstance; instance != nil {
+		minimalAlert.MostRecentInstance = &MinimalCodeScanningInstance{
+			Ref:       instance.GetRef(),
+			State:     instance.GetState(),
+			CommitSHA: instance.GetCommitSHA(),
+		}
+		if instance.Message != nil {
+			minimalAlert.MostRecentInstance.Message = &MinimalCodeScanningMessage{Text: instance.Message.GetText()}
+		}
+		if location := instance.Location; location != nil {
+			minimalAlert.MostRecentInstance.Location = &MinimalCodeScanningLocation{
+				Path:      location.GetPath(),
+				StartLine: location.GetStartLine(),
+				EndLine:   location.GetEndLine(),
+			}
+		}
+	}
+	return minimalAlert
+}
+
+// convertToMinimalPackage converts a GitHub API VulnerabilityPackage to
+// MinimalPackage, or returns nil for a missing package.
+func convertToMinimalPackage(pkg *github.VulnerabilityPackage) *MinimalPackage {
+	if pkg == nil {
+		return nil
+	}
+	return &MinimalPackage{
+		Ecosystem: pkg.GetEcosystem(),
+		Name:      pkg.GetName(),
+	}
+}
+
+// convertToMinimalVulnerability converts a GitHub API AdvisoryVulnerability to MinimalVulnerability
+func convertToMinimalVulnerability(vulnerability *github.AdvisoryVulnerability) MinimalVulnerability {
+	return MinimalVulnerability{
+		Package:                convertToMinimalPackage(vulnerability.Package),
+		Severity:               vulnerability.GetSeverity(),
+		VulnerableVersionRange: vulnerability.GetVulnerableVersionRange(),
+		FirstPatchedVersion:    vulnerability.GetFirstPatchedVersion().GetIdentifier(),
+		PatchedVersions:        vulnerability.GetPatchedVersions(),
+	}
+}
+
+// convertToMinimalCVSS converts a GitHub API AdvisoryCVSS to MinimalCVSS, or
+// returns nil for a missing score.
+func convertToMinimalCVSS(cvss *github.AdvisoryCVSS) *MinimalCVSS {
+	if cvss == nil {
+		return nil
+	}
+	return &MinimalCVSS{
+		Score:        cvss.Score,
+		VectorString: cvss.GetVectorString(),
+	}
+}
+
+// convertToMinimalCWEs converts GitHub API AdvisoryCWEs to MinimalCWEs.
+func convertToMinimalCWEs(cwes []*github.AdvisoryCWEs) []MinimalCWE {
+	var minimalCWEs []MinimalCWE
+	for _, cwe := range cwes {
+		if cwe != nil {
+			minimalCWEs = append(minimalCWEs, MinimalCWE{
+				CWEID: cwe.GetCWEID(),
+				Name:  cwe.GetName(),
+			})
+		}
+	}
+	return minimalCWEs
+}
+
+// convertToMinimalSecurityAdvisory converts a GitHub API SecurityAdvisory, as
+// found in repository advisories, to MinimalSecurityAdvisory
+func convertToMinimalSecurityAdvisory(advisory *github.SecurityAdvisory) MinimalSecurityAdvisory {
+	minimalAdvisory := MinimalSecurityAdvisory{
+		GHSAID:      advisory.GetGHSAID(),
+		CVEID:       advisory.GetCVEID(),
+		Summary:     advisory.GetSummary(),
+		Description: advisory.GetDescription(),
+		Severity:    advisory.GetSeverity(),
+		State:       advisory.GetState(),
+		HTMLURL:     advisory.GetHTMLURL(),
+		CVSS:        convertToMinimalCVSS(advisory.CVSS),
+		CWEs:        convertToMinimalCWEs(advisory.CWEs),
+		PublishedAt: formatTimestamp(advisory.PublishedAt),
+		UpdatedAt:   formatTimestamp(advisory.UpdatedAt),
+		WithdrawnAt: formatTimestamp(advisory.WithdrawnAt),
+	}
+	for _, vulnerability := range advisory.Vulnerabilities {
+		if vulnerability != nil {
+			minimalAdvisory.Vulnerabilities = append(minimalAdvisory.Vulnerabilities, convertToMinimalVulnerability(vulnerability))
+		}
+	}
+	return minimalAdvisory
+}
+
+// convertToMinimalGlobalSecurityAdvisory converts a GitHub API GlobalSecurityAdvisory to MinimalSecurityAdvisory
+func convertToMinimalGlobalSecurityAdvisory(advisory *github.GlobalSecurityAdvisory) MinimalSecurityAdvisory {
+	minimalAdvisory := convertToMinimalSecurityAdvisory(&advisory.SecurityAdvisory)
+	minimalAdvisory.Type = advisory.GetType()
+	minimalAdvisory.Vulnerabilities = nil
+	for _, vulnerability := range advisory.Vulnerabilities {
+		if vulnerability != nil {
+			minimalAdvisory.Vulnerabilities = append(minimalAdvisory.Vulnerabilities, MinimalVulnerability{
+				Package:                convertToMinimalPackage(vulnerability.Package),
+				VulnerableVersionRange: vulnerability.GetVulnerableVersionRange(),
+				FirstPatchedVersion:    vulnerability.GetFirstPatchedVersion(),
+			})
+		}
+	}
+	return minimalAdvisory
+}
+
+// convertToMinimalDependabotAlert converts a GitHub API DependabotAlert to MinimalDependabotAlert
+func convertToMinimalDependabotAlert(alert *github.DependabotAlert) MinimalDependabotAlert {
+	minimalAlert := MinimalDependabotAlert{
+		Number:           alert.GetNumber(),
+		State:            alert.GetState(),
+		HTMLURL:          alert.GetHTMLURL(),
+		CreatedAt:        formatTimestamp(alert.CreatedAt),
+		UpdatedAt:        formatTimestamp(alert.UpdatedAt),
+		FixedAt:          formatTimestamp(alert.FixedAt),
+		DismissedAt:      formatTimestamp(alert.DismissedAt),
+		DismissedReason:  alert.GetDismissedReason(),
+		DismissedComment: alert.GetDismissedComment(),
+	}
+	if dependency := alert.Dependency; dependency != nil {
+		minimalAlert.Dependency = &MinimalDependency{
+			Package:      convertToMinimalPackage(dependency.Package),
+			ManifestPath: dependency.GetManifestPath(),
+			Scope:        dependency.GetScope(),
+		}
+	}
+	if advisory := alert.SecurityAdvisory; advisory != nil {
+		minimalAlert.SecurityAdvisory = &MinimalSecurityAdvisory{
+			GHSAID:      advisory.GetGHSAID(),
+			CVEID:       advisory.GetCVEID(),
+			Summary:     advisory.GetSummary(),
+			Description: advisory.GetDescription(),
+			Severity:    advisory.GetSeverity(),
+			CVSS:        convertToMinimalCVSS(advisory.CVSS),
+			CWEs:        convertToMinimalCWEs(advisory.CWEs),
+			PublishedAt: formatTimestamp(advisory.PublishedAt),
+			UpdatedAt:   formatTimestamp(advisory.UpdatedAt),
+			WithdrawnAt: formatTimestamp(advisory.WithdrawnAt),
+		}
+	}
+	if alert.SecurityVulnerability != nil {
+		vulnerability := convertToMinimalVulnerability(alert.SecurityVulnerability)
+		minimalAlert.SecurityVulnerability = &vulnerability
+	}
+	return minimalAlert
+}
+
+// convertToMinimalSecretScanningAlert converts a GitHub API SecretScanningAlert to MinimalSecretScanningAlert
+func convertToMinimalSecretScanningAlert(alert *github.SecretScanningAlert) MinimalSecretScanningAlert {
+	return MinimalSecretScanningAlert{
+		Number:                 alert.GetNumber(),
+		State:                  alert.GetState(),
+		HTMLURL:                alert.GetHTMLURL(),
+		SecretType:             alert.GetSecretType(),
+		SecretTypeDisplayName:  alert.GetSecretTypeDisplayName(),
+		Secret:                 alert.GetSecret(),
+		Validity:               alert.GetValidity(),
+		Resolution:             alert.GetResolution(),
+		ResolutionComment:      alert.GetResolutionComment(),
+		ResolvedBy:             convertToMinimalUser(alert.ResolvedBy),
+		ResolvedAt:             formatTimestamp(alert.ResolvedAt),
+		PushProtectionBypassed: alert.GetPushProtectionBypassed(),
+		PubliclyLeaked:         alert.GetPubliclyLeaked(),
+		CreatedAt:              formatTimestamp(alert.CreatedAt),
+		UpdatedAt:              formatTimestamp(alert.UpdatedAt),
+	}
+}
+
+// convertToMinimalWorkflowRun converts a GitHub API WorkflowRun to MinimalWorkflowRun
+func convertToMinimalWorkflowRun(run *github.WorkflowRun) MinimalWorkflowRun {
+	return MinimalWorkflowRun{
+		ID:           run.GetID(),
+		Name:         run.GetName(),
+		DisplayTitle: run.GetDisplayTitle(),
+		WorkflowID:   run.GetWorkflowID(),
+		RunNumber:    run.GetRunNumber(),
+		RunAttempt:   run.GetRunAttempt(),
+		Event:        run.GetEvent(),
+		Status:       run.GetStatus(),
+		Conclusion:   run.GetConclusion(),
+		HeadBranch:   run.GetHeadBranch(),
+		HeadSHA:      run.GetHeadSHA(),
+		HTMLURL:      run.GetHTMLURL(),
+		Actor:        convertToMinimalUser(run.Actor),
+		CreatedAt:    formatTimestamp(run.CreatedAt),
+		UpdatedAt:    formatTimestamp(run.UpdatedAt),
+		RunStartedAt: formatTimestamp(run.RunStartedAt),
+	}
+}
+
+// convertToMinimalWorkflowJob converts a GitHub API WorkflowJob to MinimalWorkflowJob
+func convertToMinimalWorkflowJob(job *github.WorkflowJob) MinimalWorkflowJob {
+	minimalJob := MinimalWorkflowJob{
+		ID:          job.GetID(),
+		RunID:       job.GetRunID(),
+		Name:        job.GetName(),
+		Status:      job.GetStatus(),
+		Conclusion:  job.GetConclusion(),
+		HTMLURL:     job.GetHTMLURL(),
+		HeadBranch:  job.GetHeadBranch(),
+		HeadSHA:     job.GetHeadSHA(),
+		Labels:      job.Labels,
+		RunnerName:  job.GetRunnerName(),
+		StartedAt:   formatTimestamp(job.StartedAt),
+		CompletedAt: formatTimestamp(job.CompletedAt),
+	}
+	for _, step := range job.Steps {
+		if step != nil {
+			minimalJob.Steps = append(minimalJob.Steps, MinimalWorkflowStep{
+				Number:     step.GetNumber(),
+				Name:       step.GetName(),
+				Status:     step.GetStatus(),
+				Conclusion: step.GetConclusion(),
+			})
+		}
+	}
+	return minimalJob
+}
+
+// convertToMinimalAnnotatedTag converts a GitHub API Tag to MinimalAnnotatedTag
+func convertToMinimalAnnotatedTag(tag *github.Tag) MinimalAnnotatedTag {
+	minimalTag := MinimalAnnotatedTag{
+		Tag:     tag.GetTag(),
+		SHA:     tag.GetSHA(),
+		Message: tag.GetMessage(),
+	}
+	if tagger := tag.Tagger; tagger != nil {
+		minimalTag.Tagger = &MinimalCommitAuthor{
+			Name:  tagger.GetName(),
+			Email: tagger.GetEmail(),
+		}
+		if tagger.Date != nil {
+			minimalTag.Tagger.Date = formatTimestamp(tagger.Date)
+		}
+	}
+	if object := tag.Object; object != nil {
+		minimalTag.Object = &MinimalGitObject{
+			Type: object.GetType(),
+			SHA:  object.GetSHA(),
+		}
+	}
+	return minimalTag
+}
+
+// convertToMinimalWorkflow converts a GitHub API Workflow to MinimalWorkflow
+func convertToMinimalWorkflow(workflow *github.Workflow) MinimalWorkflow {
+	return MinimalWorkflow{
+		ID:        workflow.GetID(),
+		Name:      workflow.GetName(),
+		Path:      workflow.GetPath(),
+		State:     workflow.GetState(),
+		HTMLURL:   workflow.GetHTMLURL(),
+		CreatedAt: formatTimestamp(workflow.CreatedAt),
+		UpdatedAt: formatTimestamp(workflow.UpdatedAt),
+	}
+}
+
+// convertToMinimalArtifact converts a GitHub API Artifact to MinimalArtifact
+func convertToMinimalArtifact(artifact *github.Artifact) MinimalArtifact {
+	return MinimalArtifact{
+		ID:          artifact.GetID(),
+		Name:        artifact.GetName(),
+		SizeInBytes: artifact.GetSizeInBytes(),
+		Expired:     artifact.GetExpired(),
+		CreatedAt:   formatTimestamp(artifact.CreatedAt),
+		ExpiresAt:   formatTimestamp(artifact.ExpiresAt),
+	}
+}
@@ -30,6 +30,7 @@ func ListNotifications(getClient GetClientFn, t translations.TranslationHelperFu
 				Title:        t("TOOL_LIST_NOTIFICATIONS_USER_TITLE", "List notifications"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalNotification]](),
 			mcp.WithString("filter",
 				mcp.Description("Filter notifications to, use default unless specified. Read notifications are ones that have already been acknowledged by the user. Participating notifications are those that the user is directly involved in, such as issues or pull requests they have commented on or created."),
 				mcp.Enum(FilterDefault, FilterIncludeRead, FilterOnlyParticipating),
@@ -135,13 +136,12 @@ func ListNotifications(getClient GetClientFn, t translations.TranslationHelperFu
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get notifications: %s", string(body))), nil
 			}
 
-			// Marshal response to JSON
-			r, err := json.Marshal(notifications)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalNotifications := make([]MinimalNotification, 0, len(notifications))
+			for _, notification := range notifications {
+				minimalNotifications = append(minimalNotifications, convertToMinimalNotification(notification))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalNotifications), nil
 		}
 }
 
@@ -158,6 +158,7 @@ func DismissNotification(getclient GetClientFn, t translations.TranslationHelper
 				mcp.Description("The ID of the notification thread"),
 			),
 			mcp.WithString("state", mcp.Description("The new state of the notification (read/done)"), mcp.Enum("read", "done")),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			client, err := getclient(ctx)
@@ -229,6 +230,7 @@ func MarkAllNotificationsRead(getClient GetClientFn, t translations.TranslationH
 			mcp.WithString("repo",
 				mcp.Description("Optional repository name. If provided with owner, only notifications for this repository are marked as read."),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			client, err := getClient(ctx)
@@ -299,6 +301,7 @@ func GetNotificationDetails(getClient GetClientFn, t translations.TranslationHel
 				Title:        t("TOOL_GET_NOTIFICATION_DETAILS_USER_TITLE", "Get notification details"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalNotification](),
 			mcp.WithString("notificationID",
 				mcp.Required(),
 				mcp.Description("The ID of the notification"),
@@ -333,12 +336,7 @@ func GetNotificationDetails(getClient GetClientFn, t translations.TranslationHel
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get notification details: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(thread)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(convertToMinimalNotification(thread)), nil
 		}
 }
 
@@ -366,6 +364,7 @@ func ManageNotificationSubscription(getClient GetClientFn, t translations.Transl
 				mcp.Description("Action to perform: ignore, watch, or delete the notification subscription."),
 				mcp.Enum(NotificationActionIgnore, NotificationActionWatch, NotificationActionDelete),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			client, err := getClient(ctx)
@@ -455,6 +454,7 @@ func ManageRepositoryNotificationSubscription(getClient GetClientFn, t translati
 				mcp.Description("Action to perform: ignore, watch, or delete the repository notification subscription."),
 				mcp.Enum(RepositorySubscriptionActionIgnore, RepositorySubscriptionActionWatch, RepositorySubscriptionActionDelete),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			client, err := getClient(ctx)
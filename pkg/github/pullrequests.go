@@ -25,6 +25,7 @@ func GetPullRequest(getClient GetClientFn, t translations.TranslationHelperFunc)
 				Title:        t("TOOL_GET_PULL_REQUEST_USER_TITLE", "Get pull request details"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalPullRequest](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -74,12 +75,7 @@ func GetPullRequest(getClient GetClientFn, t translations.TranslationHelperFunc)
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get pull request: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(pr)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(convertToMinimalPullRequest(pr)), nil
 		}
 }
 
@@ -120,6 +116,7 @@ func CreatePullRequest(getClient GetClientFn, t translations.TranslationHelperFu
 			mcp.WithBoolean("maintainer_can_modify",
 				mcp.Description("Allow maintainer edits"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -252,6 +249,7 @@ func UpdatePullRequest(getClient GetClientFn, getGQLClient GetGQLClientFn, t tra
 					"type": "string",
 				}),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -491,6 +489,7 @@ func ListPullRequests(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_LIST_PULL_REQUESTS_USER_TITLE", "List pull requests"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalPullRequest]](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -586,12 +585,12 @@ func ListPullRequests(getClient GetClientFn, t translations.TranslationHelperFun
 				return mcp.NewToolResultError(fmt.Sprintf("failed to list pull requests: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(prs)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalPRs := make([]MinimalPullRequest, 0, len(prs))
+			for _, pr := range prs {
+				minimalPRs = append(minimalPRs, convertToMinimalPullRequest(pr))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalPRs), nil
 		}
 }
 
@@ -600,8 +599,9 @@ func MergePullRequest(getClient GetClientFn, t translations.TranslationHelperFun
 	return mcp.NewTool("merge_pull_request",
 			mcp.WithDescription(t("TOOL_MERGE_PULL_REQUEST_DESCRIPTION", "Merge a pull request in a GitHub repository.")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
-				Title:        t("TOOL_MERGE_PULL_REQUEST_USER_TITLE", "Merge pull request"),
-				ReadOnlyHint: ToBoolPtr(false),
+				Title:           t("TOOL_MERGE_PULL_REQUEST_USER_TITLE", "Merge pull request"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
 			}),
 			mcp.WithString("owner",
 				mcp.Required(),
@@ -625,6 +625,7 @@ func MergePullRequest(getClient GetClientFn, t translations.TranslationHelperFun
 				mcp.Description("Merge method"),
 				mcp.Enum("merge", "squash", "rebase"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -696,6 +697,7 @@ func SearchPullRequests(getClient GetClientFn, t translations.TranslationHelperF
 				Title:        t("TOOL_SEARCH_PULL_REQUESTS_USER_TITLE", "Search pull requests"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalSearchIssuesResult](),
 			mcp.WithString("query",
 				mcp.Required(),
 				mcp.Description("Search query using GitHub pull request search syntax"),
@@ -741,6 +743,7 @@ func GetPullRequestFiles(getClient GetClientFn, t translations.TranslationHelper
 				Title:        t("TOOL_GET_PULL_REQUEST_FILES_USER_TITLE", "Get pull request files"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalPullRequestFile]](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -799,12 +802,20 @@ func GetPullRequestFiles(getClient GetClientFn, t translations.TranslationHelper
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get pull request files: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(files)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalFiles := make([]MinimalPullRequestFile, 0, len(files))
+			for _, file := range files {
+				minimalFiles = append(minimalFiles, MinimalPullRequestFile{
+					Filename:         file.GetFilename(),
+					Status:           file.GetStatus(),
+					Additions:        file.GetAdditions(),
+					Deletions:        file.GetDeletions(),
+					Changes:          file.GetChanges(),
+					Patch:            file.GetPatch(),
+					PreviousFilename: file.GetPreviousFilename(),
+				})
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalFiles), nil
 		}
 }
 
@@ -816,6 +827,7 @@ func GetPullRequestStatus(getClient GetClientFn, t translations.TranslationHelpe
 				Title:        t("TOOL_GET_PULL_REQUEST_STATUS_USER_TITLE", "Get pull request status checks"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalCombinedStatus](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -884,12 +896,7 @@ func GetPullRequestStatus(getClient GetClientFn, t translations.TranslationHelpe
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get combined status: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(status)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(convertToMinimalCombinedStatus(status)), nil
 		}
 }
 
@@ -916,6 +923,7 @@ func UpdatePullRequestBranch(getClient GetClientFn, t translations.TranslationHe
 			mcp.WithString("expectedHeadSha",
 				mcp.Description("The expected SHA of the pull request's HEAD ref"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -983,6 +991,7 @@ func GetPullRequestComments(getClient GetClientFn, t translations.TranslationHel
 				Title:        t("TOOL_GET_PULL_REQUEST_COMMENTS_USER_TITLE", "Get pull request comments"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalPullRequestComment]](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1038,12 +1047,12 @@ func GetPullRequestComments(getClient GetClientFn, t translations.TranslationHel
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get pull request comments: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(comments)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalComments := make([]MinimalPullRequestComment, 0, len(comments))
+			for _, comment := range comments {
+				minimalComments = append(minimalComments, convertToMinimalPullRequestComment(comment))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalComments), nil
 		}
 }
 
@@ -1055,6 +1064,7 @@ func GetPullRequestReviews(getClient GetClientFn, t translations.TranslationHelp
 				Title:        t("TOOL_GET_PULL_REQUEST_REVIEWS_USER_TITLE", "Get pull request reviews"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalPullRequestReview]](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1104,12 +1114,12 @@ func GetPullRequestReviews(getClient GetClientFn, t translations.TranslationHelp
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get pull request reviews: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(reviews)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalReviews := make([]MinimalPullRequestReview, 0, len(reviews))
+			for _, review := range reviews {
+				minimalReviews = append(minimalReviews, convertToMinimalPullRequestReview(review))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalReviews), nil
 		}
 }
 
@@ -1147,6 +1157,7 @@ func CreateAndSubmitPullRequestReview(getGQLClient GetGQLClientFn, t translation
 			mcp.WithString("commitID",
 				mcp.Description("SHA of commit to review"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var params struct {
@@ -1241,6 +1252,7 @@ func CreatePendingPullRequestReview(getGQLClient GetGQLClientFn, t translations.
 			mcp.WithString("commitID",
 				mcp.Description("SHA of commit to review"),
 			),
+			WithDryRun(),
 			// Event is omitted here because we always want to create a pending review.
 			// Threads are omitted for the moment, and we'll see if the LLM can use the appropriate tool.
 		),
@@ -1363,6 +1375,7 @@ func AddCommentToPendingReview(getGQLClient GetGQLClientFn, t translations.Trans
 				mcp.Description("For multi-line comments, the starting side of the diff that the comment applies to. LEFT indicates the previous state, RIGHT indicates the new state"),
 				mcp.Enum("LEFT", "RIGHT"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var params struct {
@@ -1505,6 +1518,7 @@ func SubmitPendingPullRequestReview(getGQLClient GetGQLClientFn, t translations.
 			mcp.WithString("body",
 				mcp.Description("The text of the review comment"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var params struct {
@@ -1612,8 +1626,9 @@ func DeletePendingPullRequestReview(getGQLClient GetGQLClientFn, t translations.
 	return mcp.NewTool("delete_pending_pull_request_review",
 			mcp.WithDescription(t("TOOL_DELETE_PENDING_PULL_REQUEST_REVIEW_DESCRIPTION", "Delete the requester's latest pending pull request review. Use this after the user decides not to submit a pending review, if you don't know if they already created one then check first.")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
-				Title:        t("TOOL_DELETE_PENDING_PULL_REQUEST_REVIEW_USER_TITLE", "Delete the requester's latest pending pull request review"),
-				ReadOnlyHint: ToBoolPtr(false),
+				Title:           t("TOOL_DELETE_PENDING_PULL_REQUEST_REVIEW_USER_TITLE", "Delete the requester's latest pending pull request review"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
 			}),
 			// Ideally, for performance sake this would just accept the pullRequestReviewID. However, we would need to
 			// add a new tool to get that ID for clients that aren't in the same context as the original pending review
@@ -1631,6 +1646,7 @@ func DeletePendingPullRequestReview(getGQLClient GetGQLClientFn, t translations.
 				mcp.Required(),
 				mcp.Description("Pull request number"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			var params struct {
@@ -1727,6 +1743,12 @@ func DeletePendingPullRequestReview(getGQLClient GetGQLClientFn, t translations.
 		}
 }
 
+// PullRequestDiff is the structured result of get_pull_request_diff. The
+// text content carries the same diff unwrapped.
+type PullRequestDiff struct {
+	Diff string `json:"diff"`
+}
+
 func GetPullRequestDiff(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.NewTool("get_pull_request_diff",
 			mcp.WithDescription(t("TOOL_GET_PULL_REQUEST_DIFF_DESCRIPTION", "Get the diff of a pull request.")),
@@ -1734,6 +1756,7 @@ func GetPullRequestDiff(getClient GetClientFn, t translations.TranslationHelperF
 				Title:        t("TOOL_GET_PULL_REQUEST_DIFF_USER_TITLE", "Get pull request diff"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[PullRequestDiff](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1787,8 +1810,8 @@ func GetPullRequestDiff(getClient GetClientFn, t translations.TranslationHelperF
 
 			defer func() { _ = resp.Body.Close() }()
 
-			// Return the raw response
-			return mcp.NewToolResultText(string(raw)), nil
+			// Return the raw diff as text, and wrapped for clients that read structured content
+			return mcp.NewToolResultStructured(PullRequestDiff{Diff: string(raw)}, string(raw)), nil
 		}
 }
 
@@ -1814,6 +1837,7 @@ func RequestCopilotReview(getClient GetClientFn, t translations.TranslationHelpe
 				mcp.Required(),
 				mcp.Description("Pull request number"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
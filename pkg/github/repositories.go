@@ -42,6 +42,7 @@ func GetCommit(getClient GetClientFn, t translations.TranslationHelperFunc) (too
 				mcp.DefaultBool(true),
 			),
 			WithPagination(),
+			mcp.WithOutputSchema[MinimalCommit](),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -95,12 +96,7 @@ func GetCommit(getClient GetClientFn, t translations.TranslationHelperFunc) (too
 			// Convert to minimal commit
 			minimalCommit := convertToMinimalCommit(commit, includeDiff)
 
-			r, err := json.Marshal(minimalCommit)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(minimalCommit), nil
 		}
 }
 
@@ -127,6 +123,7 @@ func ListCommits(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 				mcp.Description("Author username or email address to filter commits by"),
 			),
 			WithPagination(),
+			mcp.WithOutputSchema[ListResult[MinimalCommit]](),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -191,12 +188,7 @@ func ListCommits(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 				minimalCommits[i] = convertToMinimalCommit(commit, false)
 			}
 
-			r, err := json.Marshal(minimalCommits)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalCommits), nil
 		}
 }
 
@@ -217,6 +209,7 @@ func ListBranches(getClient GetClientFn, t translations.TranslationHelperFunc) (
 				mcp.Description("Repository name"),
 			),
 			WithPagination(),
+			mcp.WithOutputSchema[ListResult[MinimalBranch]](),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -268,12 +261,7 @@ func ListBranches(getClient GetClientFn, t translations.TranslationHelperFunc) (
 				minimalBranches = append(minimalBranches, convertToMinimalBranch(branch))
 			}
 
-			r, err := json.Marshal(minimalBranches)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalBranches), nil
 		}
 }
 
@@ -312,6 +300,7 @@ func CreateOrUpdateFile(getClient GetClientFn, t translations.TranslationHelperF
 			mcp.WithString("sha",
 				mcp.Description("Required if updating an existing file. The blob SHA of the file being replaced."),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -414,6 +403,7 @@ func CreateRepository(getClient GetClientFn, t translations.TranslationHelperFun
 			mcp.WithBoolean("autoInit",
 				mcp.Description("Initialize with README"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			name, err := RequiredParam[string](request, "name")
@@ -481,6 +471,21 @@ func CreateRepository(getClient GetClientFn, t translations.TranslationHelperFun
 }
 
 // GetFileContents creates a tool to get the contents of a file or directory from a GitHub repository.
+// FileContentsResult is the structured result of get_file_contents. Type is
+// "file" or "dir" when the path was found, and "matches" when it only matched
+// entries of the git tree. Text is set for text files only.
+type FileContentsResult struct {
+	Type     string           `json:"type"`
+	Path     string           `json:"path,omitempty"`
+	SHA      string           `json:"sha,omitempty"`
+	MIMEType string           `json:"mime_type,omitempty"`
+	URI      string           `json:"uri,omitempty"`
+	Text     string           `json:"text,omitempty"`
+	Entries  []MinimalContent `json:"entries,omitempty"`
+	Matches  []string         `json:"matches,omitempty"`
+	Ref      string           `json:"ref,omitempty"`
+}
+
 func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("get_file_contents",
 			mcp.WithDescription(t("TOOL_GET_FILE_CONTENTS_DESCRIPTION", "Get the contents of a file or directory from a GitHub repository")),
@@ -488,6 +493,7 @@ func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t t
 				Title:        t("TOOL_GET_FILE_CONTENTS_USER_TITLE", "Get file or directory contents"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[FileContentsResult](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner (username or organization)"),
@@ -600,17 +606,30 @@ func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t t
 						}
 					}
 
+					fileResult := FileContentsResult{
+						Type:     "file",
+						Path:     path,
+						SHA:      fileSHA,
+						MIMEType: contentType,
+						URI:      resourceURI,
+					}
+
 					if strings.HasPrefix(contentType, "application") || strings.HasPrefix(contentType, "text") {
 						result := mcp.TextResourceContents{
 							URI:      resourceURI,
 							Text:     string(body),
 							MIMEType: contentType,
 						}
+						fileResult.Text = string(body)
 						// Include SHA in the result metadata
+						var toolResult *mcp.CallToolResult
 						if fileSHA != "" {
-							return mcp.NewToolResultResource(fmt.Sprintf("successfully downloaded text file (SHA: %s)", fileSHA), result), nil
+							toolResult = mcp.NewToolResultResource(fmt.Sprintf("successfully downloaded text file (SHA: %s)", fileSHA), result)
+						} else {
+							toolResult = mcp.NewToolResultResource("successfully downloaded text file", result)
 						}
-						return mcp.NewToolResultResource("successfully downloaded text file", result), nil
+						toolResult.StructuredContent = fileResult
+						return toolResult, nil
 					}
 
 					result := mcp.BlobResourceContents{
@@ -619,10 +638,14 @@ func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t t
 						MIMEType: contentType,
 					}
 					// Include SHA in the result metadata
+					var toolResult *mcp.CallToolResult
 					if fileSHA != "" {
-						return mcp.NewToolResultResource(fmt.Sprintf("successfully downloaded binary file (SHA: %s)", fileSHA), result), nil
+						toolResult = mcp.NewToolResultResource(fmt.Sprintf("successfully downloaded binary file (SHA: %s)", fileSHA), result)
+					} else {
+						toolResult = mcp.NewToolResultResource("successfully downloaded binary file", result)
 					}
-					return mcp.NewToolResultResource("successfully downloaded binary file", result), nil
+					toolResult.StructuredContent = fileResult
+					return toolResult, nil
 
 				}
 			}
@@ -635,11 +658,27 @@ func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t t
 				_, dirContent, resp, err := client.Repositories.GetContents(ctx, owner, repo, path, opts)
 				if err == nil && resp.StatusCode == http.StatusOK {
 					defer func() { _ = resp.Body.Close() }()
-					r, err := json.Marshal(dirContent)
+					entries := make([]MinimalContent, 0, len(dirContent))
+					for _, entry := range dirContent {
+						entries = append(entries, MinimalContent{
+							Type:    entry.GetType(),
+							Name:    entry.GetName(),
+							Path:    entry.GetPath(),
+							SHA:     entry.GetSHA(),
+							Size:    entry.GetSize(),
+							HTMLURL: entry.GetHTMLURL(),
+						})
+					}
+					r, err := json.Marshal(entries)
 					if err != nil {
 						return mcp.NewToolResultError("failed to marshal response"), nil
 					}
-					return mcp.NewToolResultText(string(r)), nil
+					return mcp.NewToolResultStructured(FileContentsResult{
+						Type:    "dir",
+						Path:    path,
+						Entries: entries,
+						Ref:     ref,
+					}, string(r)), nil
 				}
 			}
 
@@ -669,7 +708,12 @@ func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t t
 				if err != nil {
 					return mcp.NewToolResultError(fmt.Sprintf("failed to marshal resolved refs: %s", err)), nil
 				}
-				return mcp.NewToolResultText(fmt.Sprintf("Path did not point to a file or directory, but resolved git ref to %s with possible path matches: %s", resolvedRefs, matchingFilesJSON)), nil
+				return mcp.NewToolResultStructured(FileContentsResult{
+					Type:    "matches",
+					Path:    path,
+					Matches: matchingFiles,
+					Ref:     ref,
+				}, fmt.Sprintf("Path did not point to a file or directory, but resolved git ref to %s with possible path matches: %s", resolvedRefs, matchingFilesJSON)), nil
 			}
 
 			return mcp.NewToolResultError("Failed to get file contents. The path does not point to a file or directory, or the file does not exist in the repository."), nil
@@ -695,6 +739,7 @@ func ForkRepository(getClient GetClientFn, t translations.TranslationHelperFunc)
 			mcp.WithString("organization",
 				mcp.Description("Organization to fork to"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -790,6 +835,7 @@ func DeleteFile(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 				mcp.Required(),
 				mcp.Description("Branch to delete the file from"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -955,6 +1001,7 @@ func CreateBranch(getClient GetClientFn, t translations.TranslationHelperFunc) (
 			mcp.WithString("from_branch",
 				mcp.Description("Source branch (defaults to repo default)"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -1077,6 +1124,7 @@ func PushFiles(getClient GetClientFn, t translations.TranslationHelperFunc) (too
 				mcp.Required(),
 				mcp.Description("Commit message"),
 			),
+			WithDryRun(),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -1213,6 +1261,7 @@ func ListTags(getClient GetClientFn, t translations.TranslationHelperFunc) (tool
 				Title:        t("TOOL_LIST_TAGS_USER_TITLE", "List tags"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalTag]](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1265,12 +1314,16 @@ func ListTags(getClient GetClientFn, t translations.TranslationHelperFunc) (tool
 				return mcp.NewToolResultError(fmt.Sprintf("failed to list tags: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(tags)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalTags := make([]MinimalTag, 0, len(tags))
+			for _, tag := range tags {
+				minimalTag := MinimalTag{Name: tag.GetName()}
+				if tag.Commit != nil {
+					minimalTag.Commit = &MinimalTagCommit{SHA: tag.Commit.GetSHA()}
+				}
+				minimalTags = append(minimalTags, minimalTag)
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalTags), nil
 		}
 }
 
@@ -1282,6 +1335,7 @@ func GetTag(getClient GetClientFn, t translations.TranslationHelperFunc) (tool m
 				Title:        t("TOOL_GET_TAG_USER_TITLE", "Get tag details"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalAnnotatedTag](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1352,12 +1406,7 @@ func GetTag(getClient GetClientFn, t translations.TranslationHelperFunc) (tool m
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get tag object: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(tagObj)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(convertToMinimalAnnotatedTag(tagObj)), nil
 		}
 }
 
@@ -1369,6 +1418,7 @@ func ListReleases(getClient GetClientFn, t translations.TranslationHelperFunc) (
 				Title:        t("TOOL_LIST_RELEASES_USER_TITLE", "List releases"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[ListResult[MinimalRelease]](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1417,12 +1467,12 @@ func ListReleases(getClient GetClientFn, t translations.TranslationHelperFunc) (
 				return mcp.NewToolResultError(fmt.Sprintf("failed to list releases: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(releases)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalReleases := make([]MinimalRelease, 0, len(releases))
+			for _, release := range releases {
+				minimalReleases = append(minimalReleases, convertToMinimalRelease(release))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredListResult(minimalReleases), nil
 		}
 }
 
@@ -1434,6 +1484,7 @@ func GetLatestRelease(getClient GetClientFn, t translations.TranslationHelperFun
 				Title:        t("TOOL_GET_LATEST_RELEASE_USER_TITLE", "Get latest release"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalRelease](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1472,12 +1523,7 @@ func GetLatestRelease(getClient GetClientFn, t translations.TranslationHelperFun
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get latest release: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(release)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(convertToMinimalRelease(release)), nil
 		}
 }
 
@@ -1488,6 +1534,7 @@ func GetReleaseByTag(getClient GetClientFn, t translations.TranslationHelperFunc
 				Title:        t("TOOL_GET_RELEASE_BY_TAG_USER_TITLE", "Get a release by tag name"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalRelease](),
 			mcp.WithString("owner",
 				mcp.Required(),
 				mcp.Description("Repository owner"),
@@ -1538,12 +1585,7 @@ func GetReleaseByTag(getClient GetClientFn, t translations.TranslationHelperFunc
 				return mcp.NewToolResultError(fmt.Sprintf("failed to get release by tag: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(release)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
-			}
-
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(convertToMinimalRelease(release)), nil
 		}
 }
 
@@ -0,0 +1,47 @@
+package github
+
+// toolsetScopes are the classic OAuth scopes a token needs for the tools of a
+// toolset, on private resources. Public resources need no scope to read.
+var toolsetScopes = map[string][]string{
+	"context":             {"read:user"},
+	"repos":               {"repo"},
+	"issues":              {"repo"},
+	"orgs":                {"read:org"},
+	"users":               {"read:user"},
+	"pull_requests":       {"repo"},
+	"actions":             {"repo"},
+	"code_security":       {"security_events"},
+	"secret_protection":   {"security_events"},
+	"dependabot":          {"security_events"},
+	"notifications":       {"notifications"},
+	"discussions":         {"repo"},
+	"gists":               {"gist"},
+	"security_advisories": {"repo"},
+}
+
+// toolScopes are the scopes of the tools needing other scopes than their
+// toolset. An empty list means the tool needs no scope.
+var toolScopes = map[string][]string{
+	"get_me":                          {},
+	"get_teams":                       {"read:org"},
+	"get_team_members":                {"read:org"},
+	"create_or_update_file":           {"repo", "workflow"},
+	"delete_file":                     {"repo", "workflow"},
+	"push_files":                      {"repo", "workflow"},
+	"search_users":                    {},
+	"search_orgs":                     {},
+	"list_global_security_advisories": {},
+	"get_global_security_advisory":    {},
+	"list_org_repository_security_advisories": {"repo", "read:org"},
+}
+
+// RequiredScopes returns the classic OAuth scopes a token needs to call the
+// tool of the toolset on private resources: all of them, with workflow only
+// for changes to workflow files. It returns nil for tools that do not call
+// GitHub, such as those of the dynamic toolset.
+func RequiredScopes(toolset, tool string) []string {
+	if scopes, ok := toolScopes[tool]; ok {
+		return scopes
+	}
+	return toolsetScopes[toolset]
+}
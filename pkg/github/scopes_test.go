@@ -0,0 +1,35 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredScopes(t *testing.T) {
+	assert.Equal(t, []string{"repo"}, RequiredScopes("issues", "get_issue"))
+	assert.Equal(t, []string{"repo", "workflow"}, RequiredScopes("repos", "push_files"))
+	assert.Equal(t, []string{}, RequiredScopes("context", "get_me"))
+	assert.Nil(t, RequiredScopes("dynamic", "enable_toolset"))
+}
+
+func TestRequiredScopesCoverEveryTool(t *testing.T) {
+	tsg := DefaultToolsetGroup(false, stubGetClientFn(nil), stubGetGQLClientFn(nil), stubGetRawClientFn(nil), newRunLogsCache(t), translations.NullTranslationHelper, 5000)
+
+	tools := make(map[string]bool)
+	for name, toolset := range tsg.Toolsets {
+		for _, tool := range toolset.GetAvailableTools() {
+			tools[tool.Tool.Name] = true
+			assert.NotNil(t, RequiredScopes(name, tool.Tool.Name), "tool %s of toolset %s has no scopes", tool.Tool.Name, name)
+		}
+	}
+	for name := range toolsetScopes {
+		_, err := tsg.GetToolset(name)
+		require.NoError(t, err, "scopes of unknown toolset %s", name)
+	}
+	for name := range toolScopes {
+		assert.True(t, tools[name], "scopes of unknown tool %s", name)
+	}
+}
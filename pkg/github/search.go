@@ -2,7 +2,6 @@ package github
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 
@@ -22,6 +21,7 @@ func SearchRepositories(getClient GetClientFn, t translations.TranslationHelperF
 				Title:        t("TOOL_SEARCH_REPOSITORIES_USER_TITLE", "Search repositories"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalSearchRepositoriesResult](),
 			mcp.WithString("query",
 				mcp.Required(),
 				mcp.Description("Repository search query. Examples: 'machine learning in:name stars:>1000 language:python', 'topic:react', 'user:facebook'. Supports advanced search syntax for precise filtering."),
@@ -75,57 +75,20 @@ func SearchRepositories(getClient GetClientFn, t translations.TranslationHelperF
 			}
 
 			// Return either minimal or full response based on parameter
-			var r []byte
-			if minimalOutput {
-				minimalRepos := make([]MinimalRepository, 0, len(result.Repositories))
-				for _, repo := range result.Repositories {
-					minimalRepo := MinimalRepository{
-						ID:            repo.GetID(),
-						Name:          repo.GetName(),
-						FullName:      repo.GetFullName(),
-						Description:   repo.GetDescription(),
-						HTMLURL:       repo.GetHTMLURL(),
-						Language:      repo.GetLanguage(),
-						Stars:         repo.GetStargazersCount(),
-						Forks:         repo.GetForksCount(),
-						OpenIssues:    repo.GetOpenIssuesCount(),
-						Private:       repo.GetPrivate(),
-						Fork:          repo.GetFork(),
-						Archived:      repo.GetArchived(),
-						DefaultBranch: repo.GetDefaultBranch(),
-					}
-
-					if repo.UpdatedAt != nil {
-						minimalRepo.UpdatedAt = repo.UpdatedAt.Format("2006-01-02T15:04:05Z")
-					}
-					if repo.CreatedAt != nil {
-						minimalRepo.CreatedAt = repo.CreatedAt.Format("2006-01-02T15:04:05Z")
-					}
-					if repo.Topics != nil {
-						minimalRepo.Topics = repo.Topics
-					}
-
-					minimalRepos = append(minimalRepos, minimalRepo)
-				}
-
-				minimalResult := &MinimalSearchRepositoriesResult{
-					TotalCount:        result.GetTotal(),
-					IncompleteResults: result.GetIncompleteResults(),
-					Items:             minimalRepos,
-				}
+			if !minimalOutput {
+				return StructuredResult(result), nil
+			}
 
-				r, err = json.Marshal(minimalResult)
-				if err != nil {
-					return nil, fmt.Errorf("failed to marshal minimal response: %w", err)
-				}
-			} else {
-				r, err = json.Marshal(result)
-				if err != nil {
-					return nil, fmt.Errorf("failed to marshal full response: %w", err)
-				}
+			minimalRepos := make([]MinimalRepository, 0, len(result.Repositories))
+			for _, repo := range result.Repositories {
+				minimalRepos = append(minimalRepos, convertToMinimalRepository(repo))
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(&MinimalSearchRepositoriesResult{
+				TotalCount:        result.GetTotal(),
+				IncompleteResults: result.GetIncompleteResults(),
+				Items:             minimalRepos,
+			}), nil
 		}
 }
 
@@ -137,6 +100,7 @@ func SearchCode(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 				Title:        t("TOOL_SEARCH_CODE_USER_TITLE", "Search code"),
 				ReadOnlyHint: ToBoolPtr(true),
 			}),
+			mcp.WithOutputSchema[MinimalSearchCodeResult](),
 			mcp.WithString("query",
 				mcp.Required(),
 				mcp.Description("Search query using GitHub's powerful code search syntax. Examples: 'content:Skill language:Java org:github', 'NOT is:archived language:Python OR language:go', 'repo:github/github-mcp-server'. Supports exact matching, language filters, path filters, and more."),
@@ -200,12 +164,22 @@ func SearchCode(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 				return mcp.NewToolResultError(fmt.Sprintf("failed to search code: %s", string(body))), nil
 			}
 
-			r, err := json.Marshal(result)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			minimalResults := make([]MinimalCodeResult, 0, len(result.CodeResults))
+			for _, codeResult := range result.CodeResults {
+				minimalResults = append(minimalResults, MinimalCodeResult{
+					Name:       codeResult.GetName(),
+					Path:       codeResult.GetPath(),
+					SHA:        codeResult.GetSHA(),
+					HTMLURL:    codeResult.GetHTMLURL(),
+					Repository: convertToMinimalRepositoryRef(codeResult.Repository),
+				})
 			}
 
-			return mcp.NewToolResultText(string(r)), nil
+			return StructuredResult(MinimalSearchCodeResult{
+				TotalCount:        result.GetTotal(),
+				IncompleteResults: result.GetIncompleteResults(),
+				Items:             minimalResults,
+			}), nil
 		}
 }
 
@@ -289,11 +263,7 @@ func userOrOrgHandler(accountType string, getClient GetClientFn) server.ToolHand
 			minimalResp.IncompleteResults = *result.IncompleteResults
 		}
 
-		r, err := json.Marshal(minimalResp)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal response: %w", err)
-		}
-		return mcp.NewToolResultText(string(r)), nil
+		return StructuredResult(minimalResp), nil
 	}
 }
 
@@ -318,6 +288,7 @@ func SearchUsers(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 			mcp.Enum("asc", "desc"),
 		),
 		WithPagination(),
+		mcp.WithOutputSchema[MinimalSearchUsersResult](),
 	), userOrOrgHandler("user", getClient)
 }
 
@@ -343,5 +314,6 @@ func SearchOrgs(getClient GetClientFn, t translations.TranslationHelperFunc) (to
 			mcp.Enum("asc", "desc"),
 		),
 		WithPagination(),
+		mcp.WithOutputSchema[MinimalSearchUsersResult](),
 	), userOrOrgHandler("org", getClient)
 }
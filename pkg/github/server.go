@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/github/github-mcp-server/pkg/dryrun"
 	"github.com/google/go-github/v74/github"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -242,6 +243,14 @@ func WithCursorPagination() mcp.ToolOption {
 	}
 }
 
+// WithDryRun adds the dry_run parameter to a write tool. The parameter is
+// handled by the dry-run middleware rather than the tool handler.
+func WithDryRun() mcp.ToolOption {
+	return mcp.WithBoolean(dryrun.Argument,
+		mcp.Description("Validate the inputs and return the exact API requests this call would send, with diffs for file changes, without making any changes"),
+	)
+}
+
 type PaginationParams struct {
 	Page    int
 	PerPage int
@@ -341,3 +350,36 @@ func MarshalledTextResult(v any) *mcp.CallToolResult {
 
 	return mcp.NewToolResultText(string(data))
 }
+
+// ListResult is the structured content of tools returning a list, since
+// structured content must be a JSON object.
+type ListResult[T any] struct {
+	Items []T `json:"items"`
+}
+
+// StructuredResult returns v as structured content, matching the output
+// schema of the tool, along with its JSON text for clients that only read
+// text content.
+func StructuredResult(v any) *mcp.CallToolResult {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to marshal structured result to json", err)
+	}
+
+	return mcp.NewToolResultStructured(v, string(data))
+}
+
+// StructuredListResult returns items as a ListResult in structured content,
+// along with the JSON text of the bare list, as returned before tools had
+// output schemas.
+func StructuredListResult[T any](items []T) *mcp.CallToolResult {
+	if items == nil {
+		items = []T{}
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to marshal structured result to json", err)
+	}
+
+	return mcp.NewToolResultStructured(ListResult[T]{Items: items}, string(data))
+}
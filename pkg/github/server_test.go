@@ -560,3 +560,16 @@ func TestOptionalPaginationParams(t *testing.T) {
 		})
 	}
 }
+
+func TestStructuredListResult(t *testing.T) {
+	result := StructuredListResult([]MinimalBranch{{Name: "main", SHA: "abc"}})
+	assert.Equal(t, ListResult[MinimalBranch]{Items: []MinimalBranch{{Name: "main", SHA: "abc"}}}, result.StructuredContent)
+	// The text keeps the bare list
+	assert.JSONEq(t, `[{"name":"main","sha":"abc","protected":false}]`, getTextResult(t, result).Text)
+
+	// Structured content and text always hold a list, as the output schema requires
+	var none []string
+	result = StructuredListResult(none)
+	assert.Equal(t, ListResult[string]{Items: []string{}}, result.StructuredContent)
+	assert.Equal(t, "[]", getTextResult(t, result).Text)
+}
@@ -0,0 +1,240 @@
+// Package httpcache provides an http.RoundTripper that caches GET responses and
+// revalidates them with conditional requests, so that unchanged resources come
+// back as 304 Not Modified and do not count against the GitHub rate limit.
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	// ResultHit is counted when a 304 is served from the cache.
+	ResultHit = "hit"
+	// ResultMiss is counted when a cacheable request had to be fetched in full.
+	ResultMiss = "miss"
+
+	// DefaultMaxEntries is the default number of entries kept in memory.
+	DefaultMaxEntries = 1000
+	// DefaultMaxEntryBytes is the default upper bound on the size of a single cached body.
+	DefaultMaxEntryBytes = 5 * 1024 * 1024
+)
+
+// Transport is an http.RoundTripper that stores responses carrying an ETag or
+// Last-Modified validator and sends If-None-Match / If-Modified-Since on
+// subsequent requests for the same resource.
+//
+// Cached entries are never served without revalidation, so results are always
+// as fresh as an uncached request would be.
+type Transport struct {
+	transport     http.RoundTripper
+	store         Store
+	maxEntryBytes int64
+	hosts         []string
+	count         CountFunc
+}
+
+// CountFunc counts a cacheable request by its result, ResultHit or ResultMiss.
+type CountFunc func(result string)
+
+// Option configures a Transport.
+type Option func(*Transport)
+
+// WithHosts limits caching to requests sent to hosts. Requests to other hosts,
+// such as the storage that log and artifact downloads redirect to, pass
+// through untouched, as their URLs are signed for a single use.
+func WithHosts(hosts ...string) Option {
+	return func(t *Transport) {
+		t.hosts = hosts
+	}
+}
+
+// WithCount calls count for every cacheable request, so that the hit rate can
+// be exported as a metric.
+func WithCount(count CountFunc) Option {
+	return func(t *Transport) {
+		t.count = count
+	}
+}
+
+// WithMaxEntryBytes limits the size of a response body that will be cached.
+func WithMaxEntryBytes(n int64) Option {
+	return func(t *Transport) {
+		t.maxEntryBytes = n
+	}
+}
+
+// NewTransport wraps transport with a conditional-request cache backed by store.
+// If transport is nil, http.DefaultTransport is used.
+func NewTransport(transport http.RoundTripper, store Store, opts ...Option) *Transport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	t := &Transport{
+		transport:     transport,
+		store:         store,
+		maxEntryBytes: DefaultMaxEntryBytes,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isCacheable(req) || !t.cachesHost(req.URL.Host) {
+		return t.transport.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	cached, ok := t.store.Get(key)
+	if ok {
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		t.countResult(ResultHit)
+		_ = resp.Body.Close()
+		return cachedResponse(req, cached, resp.Header), nil
+	}
+
+	t.countResult(ResultMiss)
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+			t.store.Delete(key)
+		}
+		return resp, nil
+	}
+
+	entry, err := t.capture(resp)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		t.store.Set(key, entry)
+	}
+	return resp, nil
+}
+
+func (t *Transport) cachesHost(host string) bool {
+	return len(t.hosts) == 0 || slices.Contains(t.hosts, host)
+}
+
+func (t *Transport) countResult(result string) {
+	if t.count != nil {
+		t.count(result)
+	}
+}
+
+// capture reads the response body so it can be cached, and replaces it with an
+// equivalent reader for the caller. It returns a nil entry if the response
+// should not be cached.
+func (t *Transport) capture(resp *http.Response) (*Entry, error) {
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return nil, nil
+	}
+	if strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return nil, nil
+	}
+	if resp.ContentLength > t.maxEntryBytes {
+		return nil, nil
+	}
+
+	// Read one byte past the limit so we can tell whether the body was truncated
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.maxEntryBytes+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > t.maxEntryBytes {
+		resp.Body = &multiReadCloser{
+			Reader: io.MultiReader(bytes.NewReader(body), resp.Body),
+			closer: resp.Body,
+		}
+		return nil, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return &Entry{
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		ETag:         etag,
+		LastModified: lastModified,
+	}, nil
+}
+
+// cachedResponse builds a response from a cached entry, refreshing headers
+// such as rate limit information from the 304 response.
+func cachedResponse(req *http.Request, entry *Entry, fresh http.Header) *http.Response {
+	header := entry.Header.Clone()
+	for name, values := range fresh {
+		header[name] = values
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+		StatusCode:    entry.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}
+}
+
+func isCacheable(req *http.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	// Range and caller-supplied conditional requests are left untouched
+	if req.Header.Get("Range") != "" || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return false
+	}
+	return true
+}
+
+// cacheKey identifies a cached representation. The Accept header is included
+// because GitHub serves different media types (e.g. diffs) from the same URL,
+// and a digest of the Authorization header keeps entries for different
+// credentials apart.
+func cacheKey(req *http.Request) string {
+	auth := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return strings.Join([]string{
+		req.URL.String(),
+		req.Header.Get("Accept"),
+		hex.EncodeToString(auth[:8]),
+	}, "\x00")
+}
+
+type multiReadCloser struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (m *multiReadCloser) Close() error {
+	return m.closer.Close()
+}
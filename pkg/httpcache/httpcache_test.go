@@ -0,0 +1,228 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newETagServer(t *testing.T, body string, fullResponses *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(fullResponses, 1)
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func get(t *testing.T, client *http.Client, url string, headers map[string]string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestTransport(t *testing.T) {
+	t.Run("revalidates and serves 304 from cache", func(t *testing.T) {
+		var fullResponses int32
+		srv := newETagServer(t, "hello", &fullResponses)
+		defer srv.Close()
+
+		counts := map[string]int{}
+		count := func(result string) { counts[result]++ }
+		client := &http.Client{Transport: NewTransport(nil, NewMemoryStore(10), WithCount(count))}
+
+		resp, body := get(t, client, srv.URL+"/repos/o/r", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "hello", body)
+
+		resp, body = get(t, client, srv.URL+"/repos/o/r", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "hello", body)
+		assert.Equal(t, "4999", resp.Header.Get("X-RateLimit-Remaining"))
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&fullResponses))
+		assert.Equal(t, map[string]int{ResultMiss: 1, ResultHit: 1}, counts)
+	})
+
+	t.Run("keys entries by accept header", func(t *testing.T) {
+		var fullResponses int32
+		srv := newETagServer(t, "diff", &fullResponses)
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewTransport(nil, NewMemoryStore(10))}
+		_, _ = get(t, client, srv.URL+"/pulls/1", map[string]string{"Accept": "application/json"})
+		_, _ = get(t, client, srv.URL+"/pulls/1", map[string]string{"Accept": "application/vnd.github.diff"})
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fullResponses))
+	})
+
+	t.Run("does not cache non-GET requests", func(t *testing.T) {
+		store := NewMemoryStore(10)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("ETag", `"v1"`)
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer srv.Close()
+
+		client := &http.Client{Transport: NewTransport(nil, store)}
+		resp, err := client.Post(srv.URL+"/repos/o/r/issues", "application/json", nil)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		assert.Equal(t, 0, store.Len())
+	})
+
+	t.Run("skips bodies over the size limit", func(t *testing.T) {
+		var fullResponses int32
+		srv := newETagServer(t, "a large body", &fullResponses)
+		defer srv.Close()
+
+		store := NewMemoryStore(10)
+		client := &http.Client{Transport: NewTransport(nil, store, WithMaxEntryBytes(4))}
+
+		_, body := get(t, client, srv.URL+"/big", nil)
+		assert.Equal(t, "a large body", body)
+		assert.Equal(t, 0, store.Len())
+	})
+
+	t.Run("leaves redirects to other hosts uncached", func(t *testing.T) {
+		var fullResponses int32
+		download := newETagServer(t, "log", &fullResponses)
+		defer download.Close()
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, download.URL+"/logs.txt?sig=abc", http.StatusFound)
+		}))
+		defer api.Close()
+
+		store := NewMemoryStore(10)
+		client := &http.Client{Transport: NewTransport(nil, store, WithHosts(strings.TrimPrefix(api.URL, "http://")))}
+
+		for range 2 {
+			_, body := get(t, client, api.URL+"/repos/o/r/actions/jobs/1/logs", nil)
+			assert.Equal(t, "log", body)
+		}
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fullResponses))
+		assert.Equal(t, 0, store.Len())
+	})
+}
+
+func TestMemoryStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewMemoryStore(2)
+	store.Set("a", &Entry{ETag: "a"})
+	store.Set("b", &Entry{ETag: "b"})
+
+	// Touch "a" so that "b" becomes the eviction candidate
+	_, ok := store.Get("a")
+	require.True(t, ok)
+
+	store.Set("c", &Entry{ETag: "c"})
+
+	_, ok = store.Get("b")
+	assert.False(t, ok)
+	_, ok = store.Get("a")
+	assert.True(t, ok)
+	_, ok = store.Get("c")
+	assert.True(t, ok)
+}
+
+func TestTieredStorePromotesFromDisk(t *testing.T) {
+	disk, err := NewDiskStore(t.TempDir(), 0)
+	require.NoError(t, err)
+	disk.Set("key", &Entry{StatusCode: http.StatusOK, Body: []byte("body"), ETag: `"v1"`})
+
+	mem := NewMemoryStore(10)
+	tiered := NewTieredStore(mem, disk)
+
+	entry, ok := tiered.Get("key")
+	require.True(t, ok)
+	assert.Equal(t, "body", string(entry.Body))
+	assert.Equal(t, 1, mem.Len())
+
+	tiered.Delete("key")
+	_, ok = disk.Get("key")
+	assert.False(t, ok)
+}
+
+func TestDiskStorePrunesLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	entry := &Entry{StatusCode: http.StatusOK, Body: []byte("body"), ETag: `"v1"`}
+
+	unbounded, err := NewDiskStore(dir, 0)
+	require.NoError(t, err)
+	unbounded.Set("a", entry)
+	entrySize := unbounded.Size()
+	require.Positive(t, entrySize)
+
+	store, err := NewDiskStore(dir, 2*entrySize)
+	require.NoError(t, err)
+	assert.Equal(t, entrySize, store.Size())
+	store.Set("b", entry)
+
+	// Age both entries, then read "a" so that "b" becomes the pruning candidate
+	old := time.Now().Add(-time.Hour)
+	for _, key := range []string{"a", "b"} {
+		require.NoError(t, os.Chtimes(store.path(key), old, old))
+	}
+	_, ok := store.Get("a")
+	require.True(t, ok)
+
+	store.Set("c", entry)
+
+	_, ok = store.Get("b")
+	assert.False(t, ok)
+	_, ok = store.Get("a")
+	assert.True(t, ok)
+	_, ok = store.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 2*entrySize, store.Size())
+}
+
+func TestDiskStorePrunesExistingEntriesOnOpen(t *testing.T) {
+	dir := t.TempDir()
+	unbounded, err := NewDiskStore(dir, 0)
+	require.NoError(t, err)
+	for _, key := range []string{"a", "b", "c"} {
+		unbounded.Set(key, &Entry{StatusCode: http.StatusOK, Body: []byte("body")})
+	}
+	entrySize := unbounded.Size() / 3
+
+	store, err := NewDiskStore(dir, entrySize)
+	require.NoError(t, err)
+	assert.Equal(t, entrySize, store.Size())
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestDiskStoreSkipsEntriesLargerThanTheCap(t *testing.T) {
+	store, err := NewDiskStore(t.TempDir(), 10)
+	require.NoError(t, err)
+	store.Set("key", &Entry{StatusCode: http.StatusOK, Body: []byte("a body larger than the cap")})
+
+	_, ok := store.Get("key")
+	assert.False(t, ok)
+	assert.Zero(t, store.Size())
+}
@@ -0,0 +1,282 @@
+package httpcache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Entry is a cached response along with the validators needed to revalidate it.
+type Entry struct {
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+}
+
+// Store persists cache entries by key.
+type Store interface {
+	Get(key string) (*Entry, bool)
+	Set(key string, entry *Entry)
+	Delete(key string)
+}
+
+// MemoryStore is a bounded, concurrency safe LRU store.
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	items      map[string]*list.Element
+}
+
+type memoryItem struct {
+	key   string
+	entry *Entry
+}
+
+// NewMemoryStore creates an LRU store holding at most maxEntries entries.
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = 1
+	}
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (s *MemoryStore) Get(key string) (*Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	s.order.MoveToFront(elem)
+	return elem.Value.(*memoryItem).entry, true
+}
+
+func (s *MemoryStore) Set(key string, entry *Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.items[key]; ok {
+		elem.Value.(*memoryItem).entry = entry
+		s.order.MoveToFront(elem)
+		return
+	}
+	s.items[key] = s.order.PushFront(&memoryItem{key: key, entry: entry})
+	for s.order.Len() > s.maxEntries {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(*memoryItem).key)
+	}
+}
+
+func (s *MemoryStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.items[key]; ok {
+		s.order.Remove(elem)
+		delete(s.items, key)
+	}
+}
+
+// Len returns the number of entries currently held.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// DiskStore keeps one JSON file per entry in a directory, so that the cache
+// survives server restarts. When the files grow past maxBytes, the entries
+// least recently read or written are removed.
+type DiskStore struct {
+	dir      string
+	maxBytes int64
+
+	mu   sync.Mutex
+	size int64
+}
+
+// NewDiskStore creates a store rooted at dir, creating the directory if needed,
+// that holds at most maxBytes of entries. A maxBytes of 0 or less leaves the
+// store unbounded.
+func NewDiskStore(dir string, maxBytes int64) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	s := &DiskStore{dir: dir, maxBytes: maxBytes}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune()
+	return s, nil
+}
+
+func (s *DiskStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *DiskStore) Get(key string) (*Entry, bool) {
+	path := s.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	// The modification time orders entries for pruning, so reading an entry
+	// keeps it in the cache
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return &entry, true
+}
+
+func (s *DiskStore) Set(key string, entry *Entry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if s.maxBytes > 0 && int64(len(data)) > s.maxBytes {
+		return
+	}
+	// Write to a temporary file first so readers never observe a partial entry
+	tmp, err := os.CreateTemp(s.dir, "entry-*.tmp")
+	if err != nil {
+		return
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := s.path(key)
+	previous := fileSize(path)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	s.size += int64(len(data)) - previous
+	if s.maxBytes > 0 && s.size > s.maxBytes {
+		s.prune()
+	}
+}
+
+func (s *DiskStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := s.path(key)
+	size := fileSize(path)
+	if err := os.Remove(path); err == nil {
+		s.size -= size
+	}
+}
+
+// Size returns the number of bytes of entries currently held.
+func (s *DiskStore) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// prune recounts the entries in the directory, which other processes may share,
+// and removes the least recently used ones until they fit in maxBytes. The
+// caller must hold s.mu.
+func (s *DiskStore) prune() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{path: filepath.Join(s.dir, e.Name()), size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+	if s.maxBytes > 0 && total > s.maxBytes {
+		sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+		for _, f := range files {
+			if total <= s.maxBytes {
+				break
+			}
+			if err := os.Remove(f.path); err == nil || errors.Is(err, fs.ErrNotExist) {
+				total -= f.size
+			}
+		}
+	}
+	s.size = total
+}
+
+// fileSize returns the size of the file at path, or 0 if it cannot be read.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// TieredStore consults a fast store first and falls back to a slower one,
+// promoting entries found in the slower store.
+type TieredStore struct {
+	fast Store
+	slow Store
+}
+
+// NewTieredStore combines a fast store (typically a MemoryStore) with a slow
+// store (typically a DiskStore).
+func NewTieredStore(fast, slow Store) *TieredStore {
+	return &TieredStore{fast: fast, slow: slow}
+}
+
+func (s *TieredStore) Get(key string) (*Entry, bool) {
+	if entry, ok := s.fast.Get(key); ok {
+		return entry, true
+	}
+	entry, ok := s.slow.Get(key)
+	if ok {
+		s.fast.Set(key, entry)
+	}
+	return entry, ok
+}
+
+func (s *TieredStore) Set(key string, entry *Entry) {
+	s.fast.Set(key, entry)
+	s.slow.Set(key, entry)
+}
+
+func (s *TieredStore) Delete(key string) {
+	s.fast.Delete(key)
+	s.slow.Delete(key)
+}
@@ -0,0 +1,273 @@
+// Package joblog analyzes GitHub Actions job logs.
+//
+// A job log is split into the steps it ran, from the ##[group]Run markers the
+// runner prints at the start of each step, and the errors in it are extracted:
+// ##[error] annotations and the errors Go, pytest, Jest, Maven, Gradle and
+// common compilers print, with the file and line they point at. The log is
+// read in one pass, keeping only the steps and errors.
+package joblog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Defaults of Options.
+const (
+	DefaultMaxErrors    = 20
+	DefaultExcerptLines = 6
+)
+
+// setUpStep is the name of the lines before the first step, where the
+// runner sets up the job.
+const setUpStep = "Set up job"
+
+var (
+	timestampPattern = regexp.MustCompile(`^\x{feff}?(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?Z) ?`)
+	ansiPattern      = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+)
+
+// Options tune Analyze.
+type Options struct {
+	// MaxErrors caps the errors returned, DefaultMaxErrors when 0. Errors
+	// past it are only counted.
+	MaxErrors int
+	// ExcerptLines caps the lines of log kept with each error,
+	// DefaultExcerptLines when 0.
+	ExcerptLines int
+}
+
+// Step is a step of a job, as found in its log.
+type Step struct {
+	Name      string `json:"name"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	StartedAt string `json:"started_at,omitempty"`
+	// Failed reports whether the runner annotated an error in the step.
+	Failed bool `json:"failed"`
+}
+
+// Error is an error found in a log.
+type Error struct {
+	// Matcher names what printed the error: "actions" for ##[error]
+	// annotations, or the tool, such as "go test", "pytest" or "gcc".
+	Matcher string `json:"matcher"`
+	Message string `json:"message"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Step    string `json:"step"`
+	// LogLine is the line of the log the error starts at, counted from 1.
+	LogLine int      `json:"log_line"`
+	Excerpt []string `json:"excerpt,omitempty"`
+}
+
+// Location returns the file:line:column the error points at, as much of it as
+// is known.
+func (e *Error) Location() string {
+	switch {
+	case e.File == "":
+		return ""
+	case e.Line == 0:
+		return e.File
+	case e.Column == 0:
+		return fmt.Sprintf("%s:%d", e.File, e.Line)
+	default:
+		return fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column)
+	}
+}
+
+// Analysis is the structure and errors of a job log.
+type Analysis struct {
+	Steps []Step `json:"steps"`
+	// Errors are those found by the tool matchers, then the ##[error]
+	// annotations, each in log order.
+	Errors []Error `json:"errors"`
+	// TotalErrors counts the errors found, including those past MaxErrors.
+	TotalErrors int `json:"total_errors"`
+	TotalLines  int `json:"total_lines"`
+}
+
+// FailedStep returns the first step with an error annotation, or nil.
+func (a *Analysis) FailedStep() *Step {
+	for i := range a.Steps {
+		if a.Steps[i].Failed {
+			return &a.Steps[i]
+		}
+	}
+	return nil
+}
+
+// analyzer holds the state of Analyze between lines.
+type analyzer struct {
+	opts      Options
+	analysis  *Analysis
+	matched   []*Error
+	annotated []*Error
+	seen      map[string]bool
+	inHeader  bool
+
+	// open is the last error found, whose excerpt and location may go on in
+	// the next lines.
+	open         *Error
+	excerptLeft  int
+	location     *matcher
+	locationLeft int
+}
+
+// Analyze reads a job log and returns its steps and errors.
+func Analyze(r io.Reader, opts Options) (*Analysis, error) {
+	if opts.MaxErrors <= 0 {
+		opts.MaxErrors = DefaultMaxErrors
+	}
+	if opts.ExcerptLines <= 0 {
+		opts.ExcerptLines = DefaultExcerptLines
+	}
+	a := &analyzer{
+		opts:     opts,
+		analysis: &Analysis{Steps: []Step{}, Errors: []Error{}},
+		seen:     make(map[string]bool),
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		a.analysis.TotalLines++
+		a.line(a.analysis.TotalLines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read log content: %w", err)
+	}
+
+	for _, e := range append(a.matched, a.annotated...) {
+		a.analysis.Errors = append(a.analysis.Errors, *e)
+	}
+	return a.analysis, nil
+}
+
+func (a *analyzer) line(n int, raw string) {
+	timestamp := ""
+	if groups := timestampPattern.FindStringSubmatch(raw); groups != nil {
+		timestamp = groups[1]
+		raw = raw[len(groups[0]):]
+	}
+	text := ansiPattern.ReplaceAllString(raw, "")
+
+	if name, ok := stepStart(text); ok {
+		a.startStep(n, name, timestamp)
+		a.inHeader = strings.HasPrefix(text, "##[group]")
+		return
+	}
+	if len(a.analysis.Steps) == 0 {
+		a.startStep(n, setUpStep, timestamp)
+	}
+	step := &a.analysis.Steps[len(a.analysis.Steps)-1]
+	step.EndLine = n
+
+	if a.inHeader {
+		// The header of a step echoes its script and environment
+		if text == "##[endgroup]" {
+			a.inHeader = false
+		}
+		return
+	}
+
+	if message, ok := strings.CutPrefix(text, "##[error]"); ok {
+		step.Failed = true
+		a.closeOpen()
+		e, m := match(message)
+		if e == nil {
+			e = &Error{Matcher: "actions", Message: message}
+		}
+		a.add(e, m, n, step.Name, message, &a.annotated)
+		return
+	}
+	if strings.HasPrefix(text, "##[") {
+		return
+	}
+
+	if e, m := match(text); e != nil {
+		a.closeOpen()
+		a.add(e, m, n, step.Name, text, &a.matched)
+		return
+	}
+	a.extendOpen(text)
+}
+
+// stepStart reports whether text starts a step, and its name.
+func stepStart(text string) (string, bool) {
+	if name, ok := strings.CutPrefix(text, "##[group]Run "); ok {
+		return "Run " + name, true
+	}
+	switch text {
+	case "Post job cleanup.":
+		return "Post job cleanup", true
+	case "Cleaning up orphan processes":
+		return "Complete job", true
+	}
+	return "", false
+}
+
+func (a *analyzer) startStep(n int, name, timestamp string) {
+	a.closeOpen()
+	a.analysis.Steps = append(a.analysis.Steps, Step{Name: name, StartLine: n, EndLine: n, StartedAt: timestamp})
+}
+
+// add records e, found by m at line n of step, unless the same error was found
+// before, as when a problem matcher annotates an error a compiler printed.
+func (a *analyzer) add(e *Error, m *matcher, n int, step, text string, to *[]*Error) {
+	key := fmt.Sprintf("%s\x00%d\x00%s", e.File, e.Line, e.Message)
+	if a.seen[key] {
+		return
+	}
+	a.seen[key] = true
+	a.analysis.TotalErrors++
+	if len(a.matched)+len(a.annotated) >= a.opts.MaxErrors {
+		return
+	}
+	e.Step = step
+	e.LogLine = n
+	e.Excerpt = []string{text}
+	*to = append(*to, e)
+
+	a.open = e
+	a.excerptLeft = a.opts.ExcerptLines - 1
+	if e.File == "" && m != nil && m.location != nil {
+		a.location = m
+		a.locationLeft = m.locationWindow
+	}
+}
+
+// extendOpen adds text, a line following the last error, to its excerpt
+// and looks for its location in it.
+func (a *analyzer) extendOpen(text string) {
+	e := a.open
+	if e == nil {
+		return
+	}
+	if a.location != nil {
+		if groups := a.location.location.FindStringSubmatch(text); groups != nil {
+			setGroups(e, a.location.location, groups)
+			a.location = nil
+		} else if a.locationLeft--; a.locationLeft <= 0 {
+			a.location = nil
+		}
+	}
+	if a.excerptLeft > 0 && strings.TrimSpace(text) != "" {
+		e.Excerpt = append(e.Excerpt, text)
+		a.excerptLeft--
+	} else {
+		a.excerptLeft = 0
+	}
+	if a.excerptLeft == 0 && a.location == nil {
+		a.open = nil
+	}
+}
+
+func (a *analyzer) closeOpen() {
+	a.open = nil
+	a.location = nil
+}
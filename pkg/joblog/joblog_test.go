@@ -0,0 +1,226 @@
+package joblog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// actionsLog returns lines as the runner logs them, with timestamps.
+func actionsLog(lines ...string) string {
+	for i, line := range lines {
+		lines[i] = fmt.Sprintf("2024-05-01T12:00:%02d.0000000Z %s", i%60, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestAnalyzeSteps(t *testing.T) {
+	log := "\ufeff" + actionsLog(
+		"Current runner version: '2.317.0'",            // 1
+		"##[group]Operating System",                    // 2
+		"Ubuntu",                                       // 3
+		"##[endgroup]",                                 // 4
+		"##[group]Run actions/checkout@v4",             // 5
+		"with:",                                        // 6
+		"##[endgroup]",                                 // 7
+		"Syncing repository: octo/repo",                // 8
+		"##[group]Run go test ./...",                   // 9
+		"go test ./...",                                // 10
+		"shell: /usr/bin/bash -e {0}",                  // 11
+		"##[endgroup]",                                 // 12
+		"--- FAIL: TestAdd (0.00s)",                    // 13
+		"    add_test.go:12: want 3, got 4",            // 14
+		"FAIL",                                         // 15
+		"##[error]Process completed with exit code 1.", // 16
+		"Post job cleanup.",                            // 17
+		"Cleaning up orphan processes",                 // 18
+	)
+
+	analysis, err := Analyze(strings.NewReader(log), Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 18, analysis.TotalLines)
+	assert.Equal(t, []Step{
+		{Name: "Set up job", StartLine: 1, EndLine: 4, StartedAt: "2024-05-01T12:00:00.0000000Z"},
+		{Name: "Run actions/checkout@v4", StartLine: 5, EndLine: 8, StartedAt: "2024-05-01T12:00:04.0000000Z"},
+		{Name: "Run go test ./...", StartLine: 9, EndLine: 16, StartedAt: "2024-05-01T12:00:08.0000000Z", Failed: true},
+		{Name: "Post job cleanup", StartLine: 17, EndLine: 17, StartedAt: "2024-05-01T12:00:16.0000000Z"},
+		{Name: "Complete job", StartLine: 18, EndLine: 18, StartedAt: "2024-05-01T12:00:17.0000000Z"},
+	}, analysis.Steps)
+	require.NotNil(t, analysis.FailedStep())
+	assert.Equal(t, "Run go test ./...", analysis.FailedStep().Name)
+
+	require.Len(t, analysis.Errors, 3)
+	assert.Equal(t, "go test", analysis.Errors[0].Matcher)
+	assert.Equal(t, "TestAdd", analysis.Errors[0].Message)
+	assert.Equal(t, 13, analysis.Errors[0].LogLine)
+	assert.Equal(t, "add_test.go:12", analysis.Errors[1].Location())
+	assert.Equal(t, "want 3, got 4", analysis.Errors[1].Message)
+	assert.Equal(t, []string{"    add_test.go:12: want 3, got 4", "FAIL"}, analysis.Errors[1].Excerpt)
+	assert.Equal(t, Error{
+		Matcher: "actions",
+		Message: "Process completed with exit code 1.",
+		Step:    "Run go test ./...",
+		LogLine: 16,
+		Excerpt: []string{"Process completed with exit code 1."},
+	}, analysis.Errors[2])
+	assert.Equal(t, 3, analysis.TotalErrors)
+}
+
+func TestAnalyzeMatchers(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		matcher  string
+		message  string
+		location string
+	}{
+		{
+			name:     "go build",
+			lines:    []string{"# example.com/pkg", "./main.go:10:2: undefined: foo"},
+			matcher:  "go",
+			message:  "undefined: foo",
+			location: "./main.go:10:2",
+		},
+		{
+			name:    "go panic",
+			lines:   []string{"panic: runtime error: index out of range [recovered]"},
+			matcher: "go test",
+			message: "runtime error: index out of range [recovered]",
+		},
+		{
+			name:     "pytest summary",
+			lines:    []string{"FAILED tests/test_math.py::test_add - assert 3 == 4"},
+			matcher:  "pytest",
+			message:  "FAILED tests/test_math.py::test_add - assert 3 == 4",
+			location: "tests/test_math.py",
+		},
+		{
+			name:     "pytest assertion",
+			lines:    []string{"tests/test_math.py:7: AssertionError"},
+			matcher:  "pytest",
+			message:  "AssertionError",
+			location: "tests/test_math.py:7",
+		},
+		{
+			name: "jest",
+			lines: []string{
+				"  ● math › adds numbers",
+				"",
+				"    expect(received).toBe(expected)",
+				"      at Object.<anonymous> (src/math.test.ts:5:17)",
+			},
+			matcher:  "jest",
+			message:  "math › adds numbers",
+			location: "src/math.test.ts:5:17",
+		},
+		{
+			name:     "maven compiler",
+			lines:    []string{"[ERROR] /home/runner/work/app/src/main/java/App.java:[12,5] cannot find symbol"},
+			matcher:  "maven",
+			message:  "cannot find symbol",
+			location: "/home/runner/work/app/src/main/java/App.java:12:5",
+		},
+		{
+			name:    "maven surefire",
+			lines:   []string{"[ERROR]   CalculatorTest.testAdd:12 expected:<3> but was:<4>"},
+			matcher: "maven",
+			message: "CalculatorTest.testAdd:12 expected:<3> but was:<4>",
+		},
+		{
+			name:     "gradle kotlin",
+			lines:    []string{"e: file:///home/runner/work/app/src/Main.kt:3:9 Unresolved reference: foo"},
+			matcher:  "gradle",
+			message:  "Unresolved reference: foo",
+			location: "/home/runner/work/app/src/Main.kt:3:9",
+		},
+		{
+			name: "gradle test",
+			lines: []string{
+				"AppTest > testGreeting() FAILED",
+				"    org.opentest4j.AssertionFailedError: expected: <hi> but was: <hello>",
+				"        at app//AppTest.testGreeting(AppTest.java:14)",
+			},
+			matcher:  "gradle",
+			message:  "AppTest > testGreeting() FAILED",
+			location: "AppTest.java:14",
+		},
+		{
+			name:     "javac",
+			lines:    []string{"src/App.java:7: error: ';' expected"},
+			matcher:  "javac",
+			message:  "';' expected",
+			location: "src/App.java:7",
+		},
+		{
+			name:     "gcc",
+			lines:    []string{"src/main.c:4:10: fatal error: missing.h: No such file or directory"},
+			matcher:  "gcc",
+			message:  "missing.h: No such file or directory",
+			location: "src/main.c:4:10",
+		},
+		{
+			name:     "rustc",
+			lines:    []string{"error[E0425]: cannot find value `x` in this scope", " --> src/main.rs:2:13"},
+			matcher:  "rustc",
+			message:  "cannot find value `x` in this scope",
+			location: "src/main.rs:2:13",
+		},
+		{
+			name:     "tsc",
+			lines:    []string{"src/index.ts(3,7): error TS2322: Type 'string' is not assignable to type 'number'."},
+			matcher:  "tsc",
+			message:  "TS2322: Type 'string' is not assignable to type 'number'.",
+			location: "src/index.ts:3:7",
+		},
+		{
+			name:     "problem matcher annotation",
+			lines:    []string{"##[error]src/index.ts(3,7): error TS2322: Type 'string' is not assignable to type 'number'."},
+			matcher:  "tsc",
+			message:  "TS2322: Type 'string' is not assignable to type 'number'.",
+			location: "src/index.ts:3:7",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			analysis, err := Analyze(strings.NewReader(actionsLog(tc.lines...)), Options{})
+			require.NoError(t, err)
+			require.NotEmpty(t, analysis.Errors)
+			e := analysis.Errors[0]
+			assert.Equal(t, tc.matcher, e.Matcher)
+			assert.Equal(t, tc.message, e.Message)
+			assert.Equal(t, tc.location, e.Location())
+		})
+	}
+}
+
+func TestAnalyzeDeduplicatesAnnotations(t *testing.T) {
+	// A problem matcher annotates the error the compiler printed
+	log := actionsLog(
+		"src/index.ts(3,7): error TS2322: Type 'string' is not assignable to type 'number'.",
+		"##[error]src/index.ts(3,7): error TS2322: Type 'string' is not assignable to type 'number'.",
+		"##[error]Process completed with exit code 2.",
+	)
+	analysis, err := Analyze(strings.NewReader(log), Options{})
+	require.NoError(t, err)
+	require.Len(t, analysis.Errors, 2)
+	assert.Equal(t, "tsc", analysis.Errors[0].Matcher)
+	assert.Equal(t, "actions", analysis.Errors[1].Matcher)
+	assert.True(t, analysis.Steps[0].Failed)
+}
+
+func TestAnalyzeMaxErrors(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 5; i++ {
+		lines = append(lines, fmt.Sprintf("--- FAIL: Test%d (0.00s)", i))
+	}
+	analysis, err := Analyze(strings.NewReader(actionsLog(lines...)), Options{MaxErrors: 2, ExcerptLines: 1})
+	require.NoError(t, err)
+	assert.Len(t, analysis.Errors, 2)
+	assert.Equal(t, 5, analysis.TotalErrors)
+	assert.Equal(t, []string{"--- FAIL: Test1 (0.00s)"}, analysis.Errors[0].Excerpt)
+}
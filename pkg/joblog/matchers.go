@@ -0,0 +1,101 @@
+package joblog
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// matcher finds the errors a tool prints. Pattern matches the first line of
+// an error, with the optional named groups file, line, column and message
+// (the whole line when missing). When the line names no file, location is
+// looked for in the next locationWindow lines, as with stack traces.
+type matcher struct {
+	name           string
+	pattern        *regexp.Regexp
+	location       *regexp.Regexp
+	locationWindow int
+}
+
+// matchers are tried in order; the first to match a line wins.
+var matchers = []matcher{
+	// Go: compiler and vet errors, then test failures
+	{name: "go", pattern: regexp.MustCompile(`^(?:# )?(?P<file>[\w./-]+\.go):(?P<line>\d+):(?P<column>\d+): (?P<message>.+)$`)},
+	{name: "go test", pattern: regexp.MustCompile(`^\s+(?P<file>[\w./-]+_test\.go):(?P<line>\d+): (?P<message>.+)$`)},
+	{name: "go test", pattern: regexp.MustCompile(`^\s*--- FAIL: (?P<message>\S+)`)},
+	{name: "go test", pattern: regexp.MustCompile(`^panic: (?P<message>.+)$`)},
+
+	// pytest: the short test summary, then the location of assertion errors
+	{name: "pytest", pattern: regexp.MustCompile(`^(?:FAILED|ERROR) (?P<file>[\w./-]+\.py)(?:::\S+)?(?: - .+)?$`)},
+	{name: "pytest", pattern: regexp.MustCompile(`^(?P<file>[\w./-]+\.py):(?P<line>\d+): (?P<message>\w+(?:Error|Exception)\b.*)$`)},
+
+	// Jest: a failed test, located by the first frame of its stack
+	{
+		name:           "jest",
+		pattern:        regexp.MustCompile(`^\s*● (?P<message>.+)$`),
+		location:       regexp.MustCompile(`\((?P<file>[^\s()]+\.[cm]?[jt]sx?):(?P<line>\d+):(?P<column>\d+)\)`),
+		locationWindow: 20,
+	},
+
+	// Maven: compiler errors, Surefire failures and failed goals
+	{name: "maven", pattern: regexp.MustCompile(`^\[ERROR\] (?P<file>\S+\.(?:java|kt|scala)):\[(?P<line>\d+),(?P<column>\d+)\] (?P<message>.+)$`)},
+	{name: "maven", pattern: regexp.MustCompile(`^\[ERROR\]\s+(?P<message>[\w.$]+:\d+ .+)$`)},
+	{name: "maven", pattern: regexp.MustCompile(`^\[ERROR\] (?P<message>Failed to execute goal .+)$`)},
+
+	// Gradle: Kotlin and Java compiler errors, failed tests and tasks
+	{name: "gradle", pattern: regexp.MustCompile(`^e: (?:file://)?(?P<file>\S+\.kts?):(?P<line>\d+):(?P<column>\d+):? (?P<message>.+)$`)},
+	{name: "gradle", pattern: regexp.MustCompile(`^e: (?P<file>\S+\.kts?): \((?P<line>\d+), (?P<column>\d+)\): (?P<message>.+)$`)},
+	{
+		name:           "gradle",
+		pattern:        regexp.MustCompile(`^(?P<message>\S+ > .+ FAILED)$`),
+		location:       regexp.MustCompile(`at \S+\((?P<file>[\w$]+\.(?:java|kt|groovy|scala)):(?P<line>\d+)\)`),
+		locationWindow: 10,
+	},
+	{name: "gradle", pattern: regexp.MustCompile(`^> Task (?P<message>\S+) FAILED$`)},
+	{name: "javac", pattern: regexp.MustCompile(`^(?P<file>\S+\.java):(?P<line>\d+): error: (?P<message>.+)$`)},
+
+	// Compilers: GCC and Clang, rustc and tsc
+	{name: "gcc", pattern: regexp.MustCompile(`^(?P<file>[^\s:]+\.(?:c|cc|cpp|cxx|h|hh|hpp|m|mm)):(?P<line>\d+):(?P<column>\d+): (?:fatal )?error: (?P<message>.+)$`)},
+	{
+		name:           "rustc",
+		pattern:        regexp.MustCompile(`^error(?:\[E\d+\])?: (?P<message>.+)$`),
+		location:       regexp.MustCompile(`^\s*--> (?P<file>[^\s:]+):(?P<line>\d+):(?P<column>\d+)`),
+		locationWindow: 3,
+	},
+	{name: "tsc", pattern: regexp.MustCompile(`^(?P<file>\S+\.tsx?)\((?P<line>\d+),(?P<column>\d+)\): error (?P<message>TS\d+: .+)$`)},
+	{name: "tsc", pattern: regexp.MustCompile(`^(?P<file>\S+\.tsx?):(?P<line>\d+):(?P<column>\d+) - error (?P<message>TS\d+: .+)$`)},
+}
+
+// match returns the error text starts and the matcher that found it, or nil
+// when no matcher matches it.
+func match(text string) (*Error, *matcher) {
+	for i := range matchers {
+		m := &matchers[i]
+		groups := m.pattern.FindStringSubmatch(text)
+		if groups == nil {
+			continue
+		}
+		e := &Error{Matcher: m.name, Message: text}
+		setGroups(e, m.pattern, groups)
+		return e, m
+	}
+	return nil, nil
+}
+
+func setGroups(e *Error, pattern *regexp.Regexp, groups []string) {
+	for i, name := range pattern.SubexpNames() {
+		value := groups[i]
+		if value == "" {
+			continue
+		}
+		switch name {
+		case "file":
+			e.File = value
+		case "line":
+			e.Line, _ = strconv.Atoi(value)
+		case "column":
+			e.Column, _ = strconv.Atoi(value)
+		case "message":
+			e.Message = value
+		}
+	}
+}
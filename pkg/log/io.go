@@ -1,45 +1,340 @@
 package log
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"log/slog"
 )
 
+// Redacted replaces secrets removed from logged messages.
+const Redacted = "REDACTED"
+
+// DefaultMaxPayload is the default number of bytes of each message logged.
+const DefaultMaxPayload = 4096
+
+const (
+	// maxFrameBytes bounds the bytes buffered for a single message; longer
+	// messages are logged by size only.
+	maxFrameBytes = 8 << 20
+	// maxPending bounds the requests awaiting a response, so that requests
+	// never answered cannot grow the logger without limit.
+	maxPending = 1024
+)
+
+// DefaultRedactPatterns match GitHub token formats and credentials in
+// Authorization header values.
+var DefaultRedactPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})`),
+	regexp.MustCompile(`(?i)\b(bearer|basic|token)\s+[A-Za-z0-9._~+/=-]{8,}`),
+}
+
+// sensitiveArgument matches tool argument names whose values are never logged.
+var sensitiveArgument = regexp.MustCompile(`(?i)(token|secret|password|passphrase|private_?key|credential|authorization)`)
+
 // IOLogger is a wrapper around io.Reader and io.Writer that can be used
-// to log the data being read and written from the underlying streams
+// to log the data being read and written from the underlying streams.
+//
+// The streams carry newline-delimited JSON-RPC messages. IOLogger reassembles
+// complete messages from the chunks read and written, and logs one structured
+// record per message with its method, id, tool name, size and, for responses,
+// the duration since the request. The message itself is logged, cut to a
+// maximum size, after redacting secrets.
 type IOLogger struct {
 	reader io.Reader
 	writer io.Writer
 	logger *slog.Logger
+
+	redact     []*regexp.Regexp
+	sensitive  map[string]bool
+	maxPayload int
+
+	mu      sync.Mutex
+	in      frame
+	out     frame
+	pending map[string]pendingRequest
+}
+
+// Option configures an IOLogger.
+type Option func(*IOLogger)
+
+// WithRedactPatterns redacts matches of patterns, in addition to
+// DefaultRedactPatterns, from logged messages.
+func WithRedactPatterns(patterns ...*regexp.Regexp) Option {
+	return func(l *IOLogger) {
+		l.redact = append(l.redact, patterns...)
+	}
+}
+
+// WithSensitiveArguments redacts the values of the named tool arguments, in
+// addition to arguments with secret-looking names such as token or password.
+func WithSensitiveArguments(names ...string) Option {
+	return func(l *IOLogger) {
+		for _, name := range names {
+			l.sensitive[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// WithMaxPayload cuts each logged message to n bytes. 0 logs no message
+// content, only the structured fields.
+func WithMaxPayload(n int) Option {
+	return func(l *IOLogger) {
+		l.maxPayload = n
+	}
+}
+
+// CompileRedactPatterns compiles regular expressions for WithRedactPatterns.
+func CompileRedactPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// frame accumulates the bytes of one direction until a message is complete.
+type frame struct {
+	direction string
+	buf       []byte
+	// oversized counts the bytes of a message that went over maxFrameBytes
+	oversized int
+}
+
+type pendingRequest struct {
+	start  time.Time
+	method string
+	tool   string
+}
+
+// message is the part of a JSON-RPC message that is logged.
+type message struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
 }
 
 // NewIOLogger creates a new IOLogger instance
-func NewIOLogger(r io.Reader, w io.Writer, logger *slog.Logger) *IOLogger {
-	return &IOLogger{
-		reader: r,
-		writer: w,
-		logger: logger,
+func NewIOLogger(r io.Reader, w io.Writer, logger *slog.Logger, opts ...Option) *IOLogger {
+	l := &IOLogger{
+		reader:     r,
+		writer:     w,
+		logger:     logger,
+		redact:     append([]*regexp.Regexp(nil), DefaultRedactPatterns...),
+		sensitive:  make(map[string]bool),
+		maxPayload: DefaultMaxPayload,
+		in:         frame{direction: "stdin"},
+		out:        frame{direction: "stdout"},
+		pending:    make(map[string]pendingRequest),
+	}
+	for _, opt := range opts {
+		opt(l)
 	}
+	return l
 }
 
-// Read reads data from the underlying io.Reader and logs it.
+// Read reads data from the underlying io.Reader and logs the messages it
+// completes.
 func (l *IOLogger) Read(p []byte) (n int, err error) {
 	if l.reader == nil {
 		return 0, io.EOF
 	}
 	n, err = l.reader.Read(p)
 	if n > 0 {
-		l.logger.Info("[stdin]: received bytes", "count", n, "data", string(p[:n]))
+		l.mu.Lock()
+		l.feed(&l.in, p[:n])
+		l.mu.Unlock()
 	}
 	return n, err
 }
 
-// Write writes data to the underlying io.Writer and logs it.
+// Write writes data to the underlying io.Writer and logs the messages it
+// completes.
 func (l *IOLogger) Write(p []byte) (n int, err error) {
 	if l.writer == nil {
 		return 0, io.ErrClosedPipe
 	}
-	l.logger.Info("[stdout]: sending bytes", "count", len(p), "data", string(p))
+	l.mu.Lock()
+	l.feed(&l.out, p)
+	l.mu.Unlock()
 	return l.writer.Write(p)
 }
+
+// feed appends p to f and logs every message it completes.
+func (l *IOLogger) feed(f *frame, p []byte) {
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		chunk := p
+		if i >= 0 {
+			chunk = p[:i]
+		}
+		switch {
+		case f.oversized > 0 || len(f.buf)+len(chunk) > maxFrameBytes:
+			f.oversized += len(f.buf) + len(chunk)
+			f.buf = f.buf[:0]
+		default:
+			f.buf = append(f.buf, chunk...)
+		}
+		if i < 0 {
+			return
+		}
+		p = p[i+1:]
+
+		if f.oversized > 0 {
+			l.logger.Warn(fmt.Sprintf("[%s]: message too large to log", f.direction), "size", f.oversized)
+			f.oversized = 0
+		} else if line := bytes.TrimSpace(f.buf); len(line) > 0 {
+			l.logLine(f.direction, line)
+		}
+		f.buf = f.buf[:0]
+	}
+}
+
+// logLine logs a complete line, which holds a message or a batch of them.
+func (l *IOLogger) logLine(direction string, line []byte) {
+	if line[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(line, &batch); err == nil {
+			for _, raw := range batch {
+				l.logMessage(direction, raw)
+			}
+			return
+		}
+	}
+	l.logMessage(direction, line)
+}
+
+func (l *IOLogger) logMessage(direction string, raw []byte) {
+	var msg message
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		attrs := []any{"size", len(raw), "error", err}
+		if l.maxPayload > 0 {
+			attrs = append(attrs, "payload", l.payload(raw, nil))
+		}
+		l.logger.Warn(fmt.Sprintf("[%s]: invalid JSON-RPC message", direction), attrs...)
+		return
+	}
+
+	id := string(msg.ID)
+	if id == "null" {
+		id = ""
+	}
+	attrs := []any{"size", len(raw)}
+	var kind string
+	switch {
+	case msg.Method != "":
+		kind = "request"
+		if id == "" {
+			kind = "notification"
+		}
+		attrs = append(attrs, "method", msg.Method)
+		if id != "" {
+			attrs = append(attrs, "id", id)
+		}
+		tool := toolName(msg)
+		if tool != "" {
+			attrs = append(attrs, "tool", tool)
+		}
+		if id != "" && len(l.pending) < maxPending {
+			l.pending[direction+id] = pendingRequest{start: time.Now(), method: msg.Method, tool: tool}
+		}
+	default:
+		kind = "response"
+		attrs = append(attrs, "id", id)
+		key := opposite(direction) + id
+		if req, ok := l.pending[key]; ok {
+			delete(l.pending, key)
+			attrs = append(attrs, "method", req.method)
+			if req.tool != "" {
+				attrs = append(attrs, "tool", req.tool)
+			}
+			attrs = append(attrs, "duration", time.Since(req.start))
+		}
+		if msg.Error != nil {
+			kind = "error response"
+			attrs = append(attrs, "error_code", msg.Error.Code, "error", msg.Error.Message)
+		} else {
+			attrs = append(attrs, "result_size", len(msg.Result))
+		}
+	}
+	if l.maxPayload > 0 {
+		attrs = append(attrs, "payload", l.payload(raw, &msg))
+	}
+	l.logger.Info(fmt.Sprintf("[%s]: %s", direction, kind), attrs...)
+}
+
+// payload returns raw with secrets redacted, cut to the maximum payload size.
+func (l *IOLogger) payload(raw []byte, msg *message) string {
+	if msg != nil && msg.Method != "" && len(msg.Params) > 0 {
+		var decoded map[string]any
+		if err := json.Unmarshal(raw, &decoded); err == nil {
+			if params, ok := decoded["params"].(map[string]any); ok {
+				if args, ok := params["arguments"].(map[string]any); ok {
+					params["arguments"] = l.redactArguments(args)
+				}
+			}
+			if b, err := json.Marshal(decoded); err == nil {
+				raw = b
+			}
+		}
+	}
+	s := string(raw)
+	for _, re := range l.redact {
+		s = re.ReplaceAllString(s, Redacted)
+	}
+	if len(s) <= l.maxPayload {
+		return s
+	}
+	cut := l.maxPayload
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s…[%d more bytes]", s[:cut], len(s)-cut)
+}
+
+func (l *IOLogger) redactArguments(args map[string]any) map[string]any {
+	out := make(map[string]any, len(args))
+	for k, v := range args {
+		if l.sensitive[strings.ToLower(k)] || sensitiveArgument.MatchString(k) {
+			out[k] = Redacted
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// toolName returns the tool called by a tools/call request.
+func toolName(msg message) string {
+	if msg.Method != "tools/call" {
+		return ""
+	}
+	var params struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(msg.Params, &params)
+	return params.Name
+}
+
+func opposite(direction string) string {
+	if direction == "stdin" {
+		return "stdout"
+	}
+	return "stdin"
+}
@@ -2,18 +2,21 @@ package log
 
 import (
 	"bytes"
+	"io"
+	"regexp"
 	"strings"
 	"testing"
 
 	"log/slog"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoggedReadWriter(t *testing.T) {
 	t.Run("Read method logs and passes data", func(t *testing.T) {
 		// Setup
-		inputData := "test input data"
+		inputData := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}` + "\n"
 		reader := strings.NewReader(inputData)
 
 		// Create logger with buffer to capture output
@@ -30,13 +33,13 @@ func TestLoggedReadWriter(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, len(inputData), n)
 		assert.Equal(t, inputData, string(buf[:n]))
-		assert.Contains(t, logBuffer.String(), "[stdin]")
-		assert.Contains(t, logBuffer.String(), inputData)
+		assert.Contains(t, logBuffer.String(), "[stdin]: request")
+		assert.Contains(t, logBuffer.String(), "method=tools/list")
 	})
 
 	t.Run("Write method logs and passes data", func(t *testing.T) {
 		// Setup
-		outputData := "test output data"
+		outputData := `{"jsonrpc":"2.0","id":1,"result":{"tools":[]}}` + "\n"
 		var writeBuffer bytes.Buffer
 
 		// Create logger with buffer to capture output
@@ -52,11 +55,94 @@ func TestLoggedReadWriter(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, len(outputData), n)
 		assert.Equal(t, outputData, writeBuffer.String())
-		assert.Contains(t, logBuffer.String(), "[stdout]")
-		assert.Contains(t, logBuffer.String(), outputData)
+		assert.Contains(t, logBuffer.String(), "[stdout]: response")
+		assert.Contains(t, logBuffer.String(), "result_size=12")
 	})
 }
 
+func TestIOLoggerReassemblesMessages(t *testing.T) {
+	var logBuffer bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logBuffer, &slog.HandlerOptions{ReplaceAttr: removeTimeAttr}))
+	request := `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"get_me","arguments":{}}}` + "\n" +
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n"
+	lrw := NewIOLogger(chunkedReader{strings.NewReader(request), 5}, io.Discard, logger)
+
+	// Messages read in small chunks are logged once each, when complete
+	_, err := io.ReadAll(lrw)
+	require.NoError(t, err)
+	_, err = lrw.Write([]byte(`{"jsonrpc":"2.0","id":7,"result":`))
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(logBuffer.String(), "\n"))
+	_, err = lrw.Write([]byte(`{"content":[]}}` + "\n"))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(logBuffer.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"msg":"[stdin]: request"`)
+	assert.Contains(t, lines[0], `"tool":"get_me"`)
+	assert.Contains(t, lines[0], `"id":"7"`)
+	assert.Contains(t, lines[1], `"msg":"[stdin]: notification"`)
+	assert.Contains(t, lines[2], `"msg":"[stdout]: response"`)
+	assert.Contains(t, lines[2], `"method":"tools/call"`)
+	assert.Contains(t, lines[2], `"tool":"get_me"`)
+	assert.Contains(t, lines[2], `"duration":`)
+	assert.Contains(t, lines[2], `"result_size":14`)
+}
+
+func TestIOLoggerRedacts(t *testing.T) {
+	var logBuffer bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{ReplaceAttr: removeTimeAttr}))
+	lrw := NewIOLogger(nil, io.Discard, logger,
+		WithRedactPatterns(regexp.MustCompile(`octo-secret-\d+`)),
+		WithSensitiveArguments("body"),
+		WithMaxPayload(200),
+	)
+
+	_, err := lrw.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_issue","arguments":{"owner":"octo","body":"private notes","api_token":"x","title":"ghp_abcdefghijklmnopqrstuvwxyz and octo-secret-42"}}}` + "\n"))
+	require.NoError(t, err)
+	_, err = lrw.Write([]byte(`{"jsonrpc":"2.0","id":2,"result":{"text":"Authorization: Bearer abcdefgh12345678"}}` + "\n"))
+	require.NoError(t, err)
+
+	logs := logBuffer.String()
+	assert.Contains(t, logs, "owner")
+	assert.NotContains(t, logs, "private notes")
+	assert.NotContains(t, logs, `"api_token":"x"`)
+	assert.NotContains(t, logs, "ghp_abcdefghijklmnopqrstuvwxyz")
+	assert.NotContains(t, logs, "octo-secret-42")
+	assert.NotContains(t, logs, "abcdefgh12345678")
+	assert.Contains(t, logs, Redacted)
+
+	// Large messages are cut
+	logBuffer.Reset()
+	_, err = lrw.Write([]byte(`{"jsonrpc":"2.0","id":3,"result":{"text":"` + strings.Repeat("é", 200) + `"}}` + "\n"))
+	require.NoError(t, err)
+	assert.Contains(t, logBuffer.String(), "more bytes]")
+}
+
+func TestIOLoggerInvalidMessage(t *testing.T) {
+	var logBuffer bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{ReplaceAttr: removeTimeAttr}))
+	lrw := NewIOLogger(nil, io.Discard, logger, WithMaxPayload(0))
+
+	_, err := lrw.Write([]byte("not json\n\n"))
+	require.NoError(t, err)
+	assert.Contains(t, logBuffer.String(), "[stdout]: invalid JSON-RPC message")
+	assert.Equal(t, 1, strings.Count(logBuffer.String(), "\n"))
+}
+
+// chunkedReader reads at most n bytes at a time from r.
+type chunkedReader struct {
+	r io.Reader
+	n int
+}
+
+func (r chunkedReader) Read(p []byte) (int, error) {
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	return r.r.Read(p)
+}
+
 func removeTimeAttr(groups []string, a slog.Attr) slog.Attr {
 	if a.Key == slog.TimeKey && len(groups) == 0 {
 		return slog.Attr{}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/github/github-mcp-server/pkg/toolsets"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -25,6 +26,11 @@ func GetFileList() server.ServerTool {
 func getFileListImpl() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.NewTool("get_file_list",
 			mcp.WithDescription("Return every file path in the default branch of the *current* repo (paginated)."),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "List repository files",
+				ReadOnlyHint: boolPtr(true),
+			}),
+			mcp.WithOutputSchema[FileList](),
 			mcp.WithNumber("per_page",
 				mcp.Description("Items per page (max 100)"),
 				mcp.DefaultNumber(100),
@@ -54,6 +60,11 @@ func GetFileContent() server.ServerTool {
 func getFileContentImpl() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.NewTool("get_file_content",
 			mcp.WithDescription("Return the UTF-8 decoded content of any file in the current repo (default branch)."),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Get repository file content",
+				ReadOnlyHint: boolPtr(true),
+			}),
+			mcp.WithOutputSchema[FileContent](),
 			mcp.WithString("path",
 				mcp.Required(),
 				mcp.Description("Repository-relative path, e.g. 'src/utils.py'"),
@@ -76,6 +87,11 @@ func ExtractSignatures() server.ServerTool {
 func extractSignaturesImpl() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.NewTool("extract_signatures",
 			mcp.WithDescription("Parse Python or JavaScript/TypeScript source and emit every top-level function/class with its signature + docstring."),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Extract function signatures",
+				ReadOnlyHint: boolPtr(true),
+			}),
+			mcp.WithOutputSchema[Signatures](),
 			mcp.WithString("code",
 				mcp.Required(),
 				mcp.Description("Full source code to analyse"),
@@ -103,6 +119,11 @@ func EmitToolJSON() server.ServerTool {
 func emitToolJSONImpl() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.NewTool("emit_tool_json",
 			mcp.WithDescription("Convert a list of function/class descriptors into a single JSON array of OpenAI-style tool descriptions."),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        "Emit tool definitions",
+				ReadOnlyHint: boolPtr(true),
+			}),
+			mcp.WithOutputSchema[ToolDefinitions](),
 			mcp.WithArray("functions",
 				mcp.Required(),
 				mcp.Description("Each item must have: name, description, parameters (object), required (array[string])"),
@@ -204,7 +225,7 @@ func handleGetFileList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 	end := start + int(perPage)
 
 	if start >= len(allFiles) {
-		return mcp.NewToolResultText("[]"), nil
+		return mcp.NewToolResultStructured(FileList{Files: []string{}}, "[]"), nil
 	}
 
 	if end > len(allFiles) {
@@ -219,7 +240,7 @@ func handleGetFileList(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal file list: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(string(result)), nil
+	return mcp.NewToolResultStructured(FileList{Files: pageFiles}, string(result)), nil
 }
 
 func handleGetFileContent(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -258,7 +279,7 @@ func handleGetFileContent(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 		return mcp.NewToolResultError(fmt.Sprintf("failed to read file: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(string(content)), nil
+	return mcp.NewToolResultStructured(FileContent{Path: path, Content: string(content)}, string(content)), nil
 }
 
 func handleExtractSignatures(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -291,8 +312,11 @@ func handleExtractSignatures(ctx context.Context, req mcp.CallToolRequest) (*mcp
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal signatures: %v", err)), nil
 	}
+	if signatures == nil {
+		signatures = []FunctionSignature{}
+	}
 
-	return mcp.NewToolResultText(string(result)), nil
+	return mcp.NewToolResultStructured(Signatures{Signatures: signatures}, string(result)), nil
 }
 
 func handleEmitToolJSON(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -333,7 +357,24 @@ func handleEmitToolJSON(ctx context.Context, req mcp.CallToolRequest) (*mcp.Call
 		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal tool definitions: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(string(result)), nil
+	return mcp.NewToolResultStructured(ToolDefinitions{Tools: tools}, string(result)), nil
+}
+
+// Tools returns the repository analysis tools.
+func Tools() []server.ServerTool {
+	return []server.ServerTool{
+		GetFileList(),
+		GetFileContent(),
+		ExtractSignatures(),
+		EmitToolJSON(),
+	}
+}
+
+// Toolset returns the "repository" toolset of the repository analysis tools,
+// which work on the local checkout rather than the GitHub API.
+func Toolset() *toolsets.Toolset {
+	return toolsets.NewToolset("repository", "Repository analysis tools working on the local checkout").
+		AddReadTools(Tools()...)
 }
 
 // GetFileListTool returns the tool and handler separately for direct MCP server registration
@@ -356,6 +397,10 @@ func EmitToolJSONTool() (mcp.Tool, server.ToolHandlerFunc) {
 	return emitToolJSONImpl()
 }
 
+func boolPtr(b bool) *bool {
+	return &b
+}
+
 // Parameter helper functions (copied from github package)
 func RequiredParam[T comparable](r mcp.CallToolRequest, p string) (T, error) {
 	var zero T
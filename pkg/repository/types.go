@@ -29,4 +29,25 @@ type FunctionDef struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	Parameters  map[string]interface{} `json:"parameters"`
-}
\ No newline at end of file
+}
+
+// FileList is the structured result of get_file_list
+type FileList struct {
+	Files []string `json:"files"`
+}
+
+// FileContent is the structured result of get_file_content
+type FileContent struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// Signatures is the structured result of extract_signatures
+type Signatures struct {
+	Signatures []FunctionSignature `json:"signatures"`
+}
+
+// ToolDefinitions is the structured result of emit_tool_json
+type ToolDefinitions struct {
+	Tools []ToolDefinition `json:"tools"`
+}
@@ -0,0 +1,358 @@
+// Package runlogs indexes the log archive of a GitHub Actions workflow run.
+//
+// The archive is a ZIP holding a log per job, named "<n>_<job>.txt", and
+// usually a directory per job holding a log per step, named
+// "<job>/<n>_<step>.txt". Open spools the archive to a temporary file, within
+// Limits, and lists its jobs and steps; when a job has no step logs, its
+// steps are found in the job log with joblog. ReadJob and ReadStep then read
+// a log with the windowing of buffer.
+package runlogs
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/github/github-mcp-server/pkg/buffer"
+	"github.com/github/github-mcp-server/pkg/joblog"
+)
+
+// Defaults of Limits.
+const (
+	DefaultMaxArchiveSize = 256 << 20
+	DefaultMaxEntrySize   = 64 << 20
+	DefaultMaxTotalSize   = 1 << 30
+	DefaultMaxEntries     = 2000
+)
+
+// ErrTooLarge is returned when an archive is over its Limits.
+var ErrTooLarge = errors.New("run log archive too large")
+
+var entryPattern = regexp.MustCompile(`^(\d+)_(.+)\.txt$`)
+
+// Limits cap what Open reads, against large archives and ZIP bombs. Zero
+// fields take their default.
+type Limits struct {
+	// MaxArchiveSize caps the size of the compressed archive.
+	MaxArchiveSize int64
+	// MaxEntrySize caps the bytes read of each log. Longer logs are cut and
+	// marked Truncated.
+	MaxEntrySize int64
+	// MaxTotalSize caps the bytes read of all the logs while indexing.
+	MaxTotalSize int64
+	// MaxEntries caps the number of files in the archive.
+	MaxEntries int
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.MaxArchiveSize <= 0 {
+		l.MaxArchiveSize = DefaultMaxArchiveSize
+	}
+	if l.MaxEntrySize <= 0 {
+		l.MaxEntrySize = DefaultMaxEntrySize
+	}
+	if l.MaxTotalSize <= 0 {
+		l.MaxTotalSize = DefaultMaxTotalSize
+	}
+	if l.MaxEntries <= 0 {
+		l.MaxEntries = DefaultMaxEntries
+	}
+	return l
+}
+
+// Job is a job of a run and the steps in its log.
+type Job struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	// File is the archive entry of the job log, empty when the archive only
+	// has step logs.
+	File   string `json:"file,omitempty"`
+	Lines  int    `json:"lines"`
+	Failed bool   `json:"failed"`
+	// Truncated reports whether the job log is over Limits.MaxEntrySize and
+	// was cut.
+	Truncated bool   `json:"truncated,omitempty"`
+	Steps     []Step `json:"steps"`
+}
+
+// Step is a step of a job.
+type Step struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	// File is the archive entry of the step log, or of the job log when the
+	// step was found in it, between StartLine and EndLine.
+	File      string `json:"file"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	Lines     int    `json:"lines"`
+	// Failed reports whether the runner annotated an error in the step.
+	Failed    bool `json:"failed"`
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Archive is an opened run log archive. Close removes its temporary file.
+type Archive struct {
+	Jobs []Job
+
+	limits  Limits
+	file    *os.File
+	entries map[string]*zip.File
+	read    int64
+}
+
+// Open reads a run log archive from r, which is read to the end once, and
+// indexes it.
+func Open(r io.Reader, limits Limits) (*Archive, error) {
+	limits = limits.withDefaults()
+
+	f, err := os.CreateTemp("", "run-logs-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	a := &Archive{limits: limits, file: f, entries: make(map[string]*zip.File)}
+
+	size, err := io.Copy(f, io.LimitReader(r, limits.MaxArchiveSize+1))
+	if err != nil {
+		_ = a.Close()
+		return nil, fmt.Errorf("failed to download run log archive: %w", err)
+	}
+	if size > limits.MaxArchiveSize {
+		_ = a.Close()
+		return nil, fmt.Errorf("%w: over %d bytes", ErrTooLarge, limits.MaxArchiveSize)
+	}
+
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		_ = a.Close()
+		return nil, fmt.Errorf("failed to read run log archive: %w", err)
+	}
+	if len(zr.File) > limits.MaxEntries {
+		_ = a.Close()
+		return nil, fmt.Errorf("%w: over %d files", ErrTooLarge, limits.MaxEntries)
+	}
+	if err := a.index(zr); err != nil {
+		_ = a.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// Close removes the temporary file of the archive.
+func (a *Archive) Close() error {
+	name := a.file.Name()
+	err := a.file.Close()
+	if rmErr := os.Remove(name); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// Job returns the job named name, compared case-insensitively, or nil.
+func (a *Archive) Job(name string) *Job {
+	for i := range a.Jobs {
+		if strings.EqualFold(a.Jobs[i].Name, name) {
+			return &a.Jobs[i]
+		}
+	}
+	return nil
+}
+
+// Step returns the step of the job numbered number, or nil.
+func (j *Job) Step(number int) *Step {
+	for i := range j.Steps {
+		if j.Steps[i].Number == number {
+			return &j.Steps[i]
+		}
+	}
+	return nil
+}
+
+// ReadJob reads the log of job, keeping the lines opts selects.
+func (a *Archive) ReadJob(job *Job, opts buffer.Options) (*buffer.Result, error) {
+	if job.File == "" {
+		return nil, fmt.Errorf("the archive has no log for job %q, read its steps instead", job.Name)
+	}
+	return a.process(job.File, opts)
+}
+
+// ReadStep reads the log of step, keeping the lines opts selects. When the
+// step was found in the job log, line numbers are those of the job log and
+// opts.StartLine and opts.EndLine are narrowed to the step.
+func (a *Archive) ReadStep(step *Step, opts buffer.Options) (*buffer.Result, error) {
+	if step.StartLine > 0 {
+		opts.StartLine = max(opts.StartLine, step.StartLine)
+		if opts.EndLine == 0 || opts.EndLine > step.EndLine {
+			opts.EndLine = step.EndLine
+		}
+	}
+	return a.process(step.File, opts)
+}
+
+func (a *Archive) process(name string, opts buffer.Options) (*buffer.Result, error) {
+	rc, err := a.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	return buffer.Process(rc, opts)
+}
+
+// open opens an entry of the archive, reading at most Limits.MaxEntrySize
+// bytes of it.
+func (a *Archive) open(name string) (io.ReadCloser, error) {
+	f, ok := a.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("%q not found in run log archive", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %q: %w", name, err)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(rc, a.limits.MaxEntrySize), rc}, nil
+}
+
+// analyze reads an entry with joblog, and reports whether it was cut at
+// Limits.MaxEntrySize.
+func (a *Archive) analyze(name string) (*joblog.Analysis, bool, error) {
+	rc, err := a.open(name)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	counter := &countingReader{r: rc}
+	analysis, err := joblog.Analyze(counter, joblog.Options{})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %q: %w", name, err)
+	}
+	a.read += counter.n
+	if a.read > a.limits.MaxTotalSize {
+		return nil, false, fmt.Errorf("%w: logs over %d bytes", ErrTooLarge, a.limits.MaxTotalSize)
+	}
+	return analysis, counter.n >= a.limits.MaxEntrySize, nil
+}
+
+func (a *Archive) index(zr *zip.Reader) error {
+	jobs := make(map[string]*Job)
+	job := func(name string) *Job {
+		if jobs[name] == nil {
+			jobs[name] = &Job{Name: name, Steps: []Step{}}
+		}
+		return jobs[name]
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		dir, base := path.Split(f.Name)
+		number, name, ok := parseEntry(base)
+		if !ok {
+			continue
+		}
+		a.entries[f.Name] = f
+
+		switch dir = strings.TrimSuffix(dir, "/"); {
+		case dir == "":
+			j := job(name)
+			j.Number = number
+			j.File = f.Name
+		case !strings.Contains(dir, "/"):
+			j := job(dir)
+			j.Steps = append(j.Steps, Step{Number: number, Name: name, File: f.Name})
+		}
+	}
+
+	for _, j := range jobs {
+		if err := a.indexJob(j); err != nil {
+			return err
+		}
+		a.Jobs = append(a.Jobs, *j)
+	}
+	sort.Slice(a.Jobs, func(i, k int) bool {
+		if a.Jobs[i].Number != a.Jobs[k].Number {
+			return a.Jobs[i].Number < a.Jobs[k].Number
+		}
+		return a.Jobs[i].Name < a.Jobs[k].Name
+	})
+	return nil
+}
+
+// indexJob counts the lines of the logs of j, and finds its steps in the job
+// log when the archive has no step logs.
+func (a *Archive) indexJob(j *Job) error {
+	if j.File != "" {
+		analysis, truncated, err := a.analyze(j.File)
+		if err != nil {
+			return err
+		}
+		j.Lines = analysis.TotalLines
+		j.Truncated = truncated
+		if len(j.Steps) == 0 {
+			for i, s := range analysis.Steps {
+				j.Steps = append(j.Steps, Step{
+					Number:    i + 1,
+					Name:      s.Name,
+					File:      j.File,
+					StartLine: s.StartLine,
+					EndLine:   s.EndLine,
+					Lines:     s.EndLine - s.StartLine + 1,
+					Failed:    s.Failed,
+				})
+			}
+			j.Failed = analysis.FailedStep() != nil
+			return nil
+		}
+	}
+
+	sort.Slice(j.Steps, func(i, k int) bool { return j.Steps[i].Number < j.Steps[k].Number })
+	for i := range j.Steps {
+		s := &j.Steps[i]
+		analysis, truncated, err := a.analyze(s.File)
+		if err != nil {
+			return err
+		}
+		s.Lines = analysis.TotalLines
+		s.Failed = analysis.FailedStep() != nil
+		s.Truncated = truncated
+		j.Failed = j.Failed || s.Failed
+		if j.File == "" {
+			j.Lines += s.Lines
+		}
+	}
+	return nil
+}
+
+// parseEntry splits the base name of a log, "<n>_<name>.txt".
+func parseEntry(base string) (int, string, bool) {
+	groups := entryPattern.FindStringSubmatch(base)
+	if groups == nil {
+		return 0, "", false
+	}
+	number, err := strconv.Atoi(groups[1])
+	if err != nil {
+		return 0, "", false
+	}
+	return number, groups[2], true
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,161 @@
+package runlogs
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/buffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archive returns a ZIP holding files, by name.
+func archive(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return &b
+}
+
+const buildLog = "##[group]Run actions/checkout@v4\n" +
+	"##[endgroup]\n" +
+	"checked out\n" +
+	"##[group]Run go test ./...\n" +
+	"##[endgroup]\n" +
+	"--- FAIL: TestAdd (0.00s)\n" +
+	"##[error]Process completed with exit code 1."
+
+func TestOpenWithStepLogs(t *testing.T) {
+	a, err := Open(archive(t, map[string]string{
+		"0_build.txt":                      buildLog,
+		"build/1_Set up job.txt":           "runner\n",
+		"build/2_Run actions checkout.txt": "##[group]Run actions/checkout@v4\n##[endgroup]\nchecked out",
+		"build/3_Test.txt":                 "##[group]Run go test ./...\n##[endgroup]\n--- FAIL: TestAdd (0.00s)\n##[error]Process completed with exit code 1.",
+		"1_lint.txt":                       "ok\n",
+	}), Limits{})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, a.Close()) }()
+
+	require.Len(t, a.Jobs, 2)
+	assert.Equal(t, "build", a.Jobs[0].Name)
+	assert.Equal(t, 7, a.Jobs[0].Lines)
+	assert.True(t, a.Jobs[0].Failed)
+	assert.Equal(t, []Step{
+		{Number: 1, Name: "Set up job", File: "build/1_Set up job.txt", Lines: 1},
+		{Number: 2, Name: "Run actions checkout", File: "build/2_Run actions checkout.txt", Lines: 3},
+		{Number: 3, Name: "Test", File: "build/3_Test.txt", Lines: 4, Failed: true},
+	}, a.Jobs[0].Steps)
+	assert.Equal(t, "lint", a.Jobs[1].Name)
+	assert.False(t, a.Jobs[1].Failed)
+
+	job := a.Job("BUILD")
+	require.NotNil(t, job)
+	step := job.Step(3)
+	require.NotNil(t, step)
+	result, err := a.ReadStep(step, buffer.Options{Grep: regexp.MustCompile(`FAIL`)})
+	require.NoError(t, err)
+	assert.Equal(t, "--- FAIL: TestAdd (0.00s)", result.String())
+	assert.Nil(t, job.Step(4))
+	assert.Nil(t, a.Job("deploy"))
+}
+
+func TestOpenFindsStepsInJobLog(t *testing.T) {
+	a, err := Open(archive(t, map[string]string{"0_build.txt": buildLog}), Limits{})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, a.Close()) }()
+
+	require.Len(t, a.Jobs, 1)
+	job := &a.Jobs[0]
+	assert.Equal(t, []Step{
+		{Number: 1, Name: "Run actions/checkout@v4", File: "0_build.txt", StartLine: 1, EndLine: 3, Lines: 3},
+		{Number: 2, Name: "Run go test ./...", File: "0_build.txt", StartLine: 4, EndLine: 7, Lines: 4, Failed: true},
+	}, job.Steps)
+
+	// Reading a step keeps to its lines
+	result, err := a.ReadStep(job.Step(1), buffer.Options{TailLines: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, numbers(result.Lines()))
+
+	result, err = a.ReadStep(job.Step(2), buffer.Options{StartLine: 6})
+	require.NoError(t, err)
+	assert.Equal(t, []int{6, 7}, numbers(result.Lines()))
+
+	result, err = a.ReadJob(job, buffer.Options{HeadLines: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "##[group]Run actions/checkout@v4", result.String())
+}
+
+func TestOpenWithoutJobLogs(t *testing.T) {
+	a, err := Open(archive(t, map[string]string{
+		"build/1_Set up job.txt": "runner\n",
+		"build/2_Test.txt":       "one\ntwo\n",
+	}), Limits{})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, a.Close()) }()
+
+	require.Len(t, a.Jobs, 1)
+	assert.Equal(t, 3, a.Jobs[0].Lines)
+	_, err = a.ReadJob(&a.Jobs[0], buffer.Options{})
+	assert.ErrorContains(t, err, "read its steps instead")
+}
+
+func TestOpenLimits(t *testing.T) {
+	files := map[string]string{
+		"0_build.txt": strings.Repeat("line\n", 100),
+		"1_lint.txt":  "ok\n",
+	}
+
+	tests := []struct {
+		name   string
+		limits Limits
+		err    string
+	}{
+		{name: "archive size", limits: Limits{MaxArchiveSize: 10}, err: "over 10 bytes"},
+		{name: "entries", limits: Limits{MaxEntries: 1}, err: "over 1 files"},
+		{name: "total size", limits: Limits{MaxTotalSize: 100}, err: "logs over 100 bytes"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Open(archive(t, files), tc.limits)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrTooLarge))
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+
+	t.Run("entry size", func(t *testing.T) {
+		a, err := Open(archive(t, files), Limits{MaxEntrySize: 50})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, a.Close()) }()
+
+		job := a.Job("build")
+		require.NotNil(t, job)
+		assert.True(t, job.Truncated)
+		assert.Equal(t, 10, job.Lines)
+		assert.False(t, a.Job("lint").Truncated)
+	})
+
+	t.Run("not a zip", func(t *testing.T) {
+		_, err := Open(strings.NewReader("not a zip"), Limits{})
+		assert.ErrorContains(t, err, "failed to read run log archive")
+	})
+}
+
+func numbers(lines []buffer.Line) []int {
+	out := make([]int, len(lines))
+	for i, line := range lines {
+		out[i] = line.Number
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+package toolsets
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// BM25 parameters: k1 saturates repeated terms, b normalizes by length.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+	// nameWeight counts the terms of a tool's name this many times, since a
+	// name is the densest description of what a tool does.
+	nameWeight = 3
+)
+
+// SearchResult is a tool matching a query.
+type SearchResult struct {
+	Toolset string
+	Tool    mcp.Tool
+	Score   float64
+}
+
+// SearchIndex ranks the tools of a ToolsetGroup against free-text queries
+// with BM25 over their names, descriptions and parameter descriptions.
+type SearchIndex struct {
+	docs      []searchDoc
+	docFreq   map[string]int
+	avgLength float64
+}
+
+type searchDoc struct {
+	toolset string
+	tool    mcp.Tool
+	terms   map[string]int
+	length  int
+}
+
+// NewSearchIndex indexes the available tools of every toolset of tg, enabled
+// or not. The index is a snapshot: tools added to tg later are not searched.
+func NewSearchIndex(tg *ToolsetGroup) *SearchIndex {
+	names := make([]string, 0, len(tg.Toolsets))
+	for name := range tg.Toolsets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	idx := &SearchIndex{docFreq: make(map[string]int)}
+	total := 0
+	for _, name := range names {
+		for _, tool := range tg.Toolsets[name].GetAvailableTools() {
+			doc := searchDoc{toolset: name, tool: tool.Tool, terms: make(map[string]int)}
+			for _, term := range toolTerms(tool.Tool) {
+				doc.terms[term]++
+				doc.length++
+			}
+			for term := range doc.terms {
+				idx.docFreq[term]++
+			}
+			total += doc.length
+			idx.docs = append(idx.docs, doc)
+		}
+	}
+	if len(idx.docs) > 0 {
+		idx.avgLength = float64(total) / float64(len(idx.docs))
+	}
+	return idx
+}
+
+// Search returns up to limit tools matching query, best first. Tools sharing
+// no term with the query are left out.
+func (idx *SearchIndex) Search(query string, limit int) []SearchResult {
+	queryTerms := tokenize(query)
+	n := float64(len(idx.docs))
+
+	var results []SearchResult
+	for _, doc := range idx.docs {
+		score := 0.0
+		for _, term := range queryTerms {
+			tf := float64(doc.terms[term])
+			if tf == 0 {
+				continue
+			}
+			df := float64(idx.docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := 1 - bm25B + bm25B*float64(doc.length)/idx.avgLength
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+		if score > 0 {
+			results = append(results, SearchResult{Toolset: doc.toolset, Tool: doc.tool, Score: score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// toolTerms returns the terms indexed for tool.
+func toolTerms(tool mcp.Tool) []string {
+	var terms []string
+	name := tokenize(tool.Name)
+	for i := 0; i < nameWeight; i++ {
+		terms = append(terms, name...)
+	}
+	terms = append(terms, tokenize(tool.Description)...)
+	for param, schema := range tool.InputSchema.Properties {
+		terms = append(terms, tokenize(param)...)
+		if prop, ok := schema.(map[string]any); ok {
+			if description, ok := prop["description"].(string); ok {
+				terms = append(terms, tokenize(description)...)
+			}
+		}
+	}
+	return terms
+}
+
+// tokenize splits text into lower-case words, breaking on anything but
+// letters and digits so that "get_issue" gives "get" and "issue", and strips
+// plural endings so that "issues" matches "issue".
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := words[:0]
+	for _, word := range words {
+		if len(word) < 2 {
+			continue
+		}
+		terms = append(terms, stem(word))
+	}
+	return terms
+}
+
+// stem removes the plural ending of word.
+func stem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us"):
+		return word[:len(word)-1]
+	}
+	return word
+}
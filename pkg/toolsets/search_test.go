@@ -0,0 +1,68 @@
+package toolsets
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestSearchIndex(t *testing.T) {
+	readOnly, notReadOnly := true, false
+	readTool := func(name, description string, opts ...mcp.ToolOption) server.ServerTool {
+		opts = append(opts, mcp.WithDescription(description), mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly}))
+		return NewServerTool(mcp.NewTool(name, opts...), nil)
+	}
+	issues := NewToolset("issues", "Issues")
+	issues.AddReadTools(
+		readTool("get_issue", "Get details of a specific issue in a GitHub repository"),
+		readTool("list_issues", "List issues in a GitHub repository", mcp.WithString("labels", mcp.Description("Filter by labels"))),
+	)
+	issues.AddWriteTools(NewServerTool(mcp.NewTool("add_issue_comment",
+		mcp.WithDescription("Add a comment to an issue"),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &notReadOnly}),
+	), nil))
+	pullRequests := NewToolset("pull_requests", "Pull requests")
+	pullRequests.AddReadTools(
+		readTool("get_pull_request_comments", "Get comments for a specific pull request"),
+		readTool("list_pull_requests", "List pull requests in a GitHub repository"),
+	)
+	tsg := NewToolsetGroup(false)
+	tsg.AddToolset(issues)
+	tsg.AddToolset(pullRequests)
+	if err := tsg.EnableToolset("issues"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Disabled toolsets are searched too
+	results := NewSearchIndex(tsg).Search("comments on a pull request", 0)
+	if len(results) == 0 || results[0].Tool.Name != "get_pull_request_comments" || results[0].Toolset != "pull_requests" {
+		t.Fatalf("expected get_pull_request_comments first, got %v", results)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Score > results[i-1].Score {
+			t.Errorf("results are not sorted by score: %v", results)
+		}
+	}
+
+	// Parameter descriptions are searched, and plurals match singulars
+	results = NewSearchIndex(tsg).Search("filter issue by label", 1)
+	if len(results) != 1 || results[0].Tool.Name != "list_issues" {
+		t.Errorf("expected list_issues, got %v", results)
+	}
+
+	if results := NewSearchIndex(tsg).Search("workflow", 5); len(results) != 0 {
+		t.Errorf("expected no results, got %v", results)
+	}
+
+	// Filtered and read-only tools are left out
+	filter, _ := NewToolFilter(nil, []string{"list_*"})
+	tsg = NewToolsetGroup(true)
+	tsg.SetToolFilter(filter)
+	tsg.AddToolset(issues)
+	for _, result := range NewSearchIndex(tsg).Search("issue", 0) {
+		if result.Tool.Name != "get_issue" {
+			t.Errorf("expected only get_issue, got %s", result.Tool.Name)
+		}
+	}
+}
@@ -2,6 +2,9 @@ package toolsets
 
 import (
 	"fmt"
+	"path"
+	"sort"
+	"sync"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -47,12 +50,70 @@ func NewServerPrompt(prompt mcp.Prompt, handler server.PromptHandlerFunc) server
 	}
 }
 
+// ToolFilter narrows the tools of the enabled toolsets down by name, with glob
+// patterns such as "get_*". A nil ToolFilter allows every tool.
+type ToolFilter struct {
+	include []string
+	exclude []string
+	within  [][]string
+}
+
+// NewToolFilter returns a filter allowing the tools matching any of include,
+// or every tool when include is empty, except those matching any of exclude.
+// Each non-empty list of within further narrows the allowed tools to those
+// also matching one of its patterns. It returns nil when all are empty.
+func NewToolFilter(include, exclude []string, within ...[]string) (*ToolFilter, error) {
+	patterns := append(append([]string{}, include...), exclude...)
+	var narrow [][]string
+	for _, w := range within {
+		if len(w) > 0 {
+			patterns = append(patterns, w...)
+			narrow = append(narrow, w)
+		}
+	}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+	}
+	if len(include) == 0 && len(exclude) == 0 && len(narrow) == 0 {
+		return nil, nil
+	}
+	return &ToolFilter{include: include, exclude: exclude, within: narrow}, nil
+}
+
+// Allows reports whether the tool called name passes the filter.
+func (f *ToolFilter) Allows(name string) bool {
+	if f == nil {
+		return true
+	}
+	if matchAny(f.exclude, name) {
+		return false
+	}
+	for _, patterns := range f.within {
+		if !matchAny(patterns, name) {
+			return false
+		}
+	}
+	return len(f.include) == 0 || matchAny(f.include, name)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Toolset represents a collection of MCP functionality that can be enabled or disabled as a group.
 type Toolset struct {
 	Name        string
 	Description string
 	Enabled     bool
 	readOnly    bool
+	filter      *ToolFilter
 	writeTools  []server.ServerTool
 	readTools   []server.ServerTool
 	// resources are not tools, but the community seems to be moving towards namespaces as a broader concept
@@ -64,33 +125,32 @@ type Toolset struct {
 
 func (t *Toolset) GetActiveTools() []server.ServerTool {
 	if t.Enabled {
-		if t.readOnly {
-			return t.readTools
-		}
-		return append(t.readTools, t.writeTools...)
+		return t.GetAvailableTools()
 	}
 	return nil
 }
 
 func (t *Toolset) GetAvailableTools() []server.ServerTool {
-	if t.readOnly {
-		return t.readTools
+	tools := t.readTools
+	if !t.readOnly {
+		tools = append(tools[:len(tools):len(tools)], t.writeTools...)
+	}
+	if t.filter == nil {
+		return tools
+	}
+	allowed := make([]server.ServerTool, 0, len(tools))
+	for _, tool := range tools {
+		if t.filter.Allows(tool.Tool.Name) {
+			allowed = append(allowed, tool)
+		}
 	}
-	return append(t.readTools, t.writeTools...)
+	return allowed
 }
 
 func (t *Toolset) RegisterTools(s *server.MCPServer) {
-	if !t.Enabled {
-		return
-	}
-	for _, tool := range t.readTools {
+	for _, tool := range t.GetActiveTools() {
 		s.AddTool(tool.Tool, tool.Handler)
 	}
-	if !t.readOnly {
-		for _, tool := range t.writeTools {
-			s.AddTool(tool.Tool, tool.Handler)
-		}
-	}
 }
 
 func (t *Toolset) AddResourceTemplates(templates ...server.ServerResourceTemplate) *Toolset {
@@ -114,6 +174,17 @@ func (t *Toolset) GetAvailableResourceTemplates() []server.ServerResourceTemplat
 	return t.resourceTemplates
 }
 
+func (t *Toolset) GetActivePrompts() []server.ServerPrompt {
+	if !t.Enabled {
+		return nil
+	}
+	return t.prompts
+}
+
+func (t *Toolset) GetAvailablePrompts() []server.ServerPrompt {
+	return t.prompts
+}
+
 func (t *Toolset) RegisterResourcesTemplates(s *server.MCPServer) {
 	if !t.Enabled {
 		return
@@ -137,6 +208,27 @@ func (t *Toolset) SetReadOnly() {
 	t.readOnly = true
 }
 
+// hasTool reports whether name is a read or write tool of the toolset.
+func (t *Toolset) hasTool(name string) bool {
+	for _, tool := range t.readTools {
+		if tool.Tool.Name == name {
+			return true
+		}
+	}
+	for _, tool := range t.writeTools {
+		if tool.Tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetToolFilter hides the tools f does not allow. They are neither registered
+// nor listed as available.
+func (t *Toolset) SetToolFilter(f *ToolFilter) {
+	t.filter = f
+}
+
 func (t *Toolset) AddWriteTools(tools ...server.ServerTool) *Toolset {
 	// Silently ignore if the toolset is read-only to avoid any breach of that contract
 	for _, tool := range tools {
@@ -164,6 +256,15 @@ type ToolsetGroup struct {
 	Toolsets     map[string]*Toolset
 	everythingOn bool
 	readOnly     bool
+	filter       *ToolFilter
+
+	// maxEnabled caps the toolsets enabled by EnableToolsets and
+	// EnableToolsetWithEviction, 0 for no cap. mu guards the Enabled flags
+	// and the use clock of the toolsets.
+	maxEnabled int
+	mu         sync.Mutex
+	clock      uint64
+	lastUsed   map[string]uint64
 }
 
 func NewToolsetGroup(readOnly bool) *ToolsetGroup {
@@ -178,9 +279,21 @@ func (tg *ToolsetGroup) AddToolset(ts *Toolset) {
 	if tg.readOnly {
 		ts.SetReadOnly()
 	}
+	if tg.filter != nil {
+		ts.SetToolFilter(tg.filter)
+	}
 	tg.Toolsets[ts.Name] = ts
 }
 
+// SetToolFilter applies f to the toolsets of the group, including those added
+// later.
+func (tg *ToolsetGroup) SetToolFilter(f *ToolFilter) {
+	tg.filter = f
+	for _, toolset := range tg.Toolsets {
+		toolset.SetToolFilter(f)
+	}
+}
+
 func NewToolset(name string, description string) *Toolset {
 	return &Toolset{
 		Name:        name,
@@ -190,6 +303,9 @@ func NewToolset(name string, description string) *Toolset {
 	}
 }
 
+// IsEnabled reports whether the toolset called name is enabled. Unlike the
+// Enabled field, it is safe to call while toolsets are enabled and disabled
+// at runtime.
 func (tg *ToolsetGroup) IsEnabled(name string) bool {
 	// If everythingOn is true, all features are enabled
 	if tg.everythingOn {
@@ -200,9 +316,14 @@ func (tg *ToolsetGroup) IsEnabled(name string) bool {
 	if !exists {
 		return false
 	}
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
 	return feature.Enabled
 }
 
+// EnableToolsets enables the toolsets called names, or every toolset when
+// names holds "all". Enabling more toolsets than the cap set with
+// SetMaxEnabled is an error.
 func (tg *ToolsetGroup) EnableToolsets(names []string) error {
 	// Special case for "all"
 	for _, name := range names {
@@ -223,8 +344,22 @@ func (tg *ToolsetGroup) EnableToolsets(names []string) error {
 				return err
 			}
 		}
+	}
+
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	if tg.maxEnabled == 0 {
 		return nil
 	}
+	enabled := 0
+	for _, toolset := range tg.Toolsets {
+		if toolset.Enabled {
+			enabled++
+		}
+	}
+	if enabled > tg.maxEnabled {
+		return fmt.Errorf("%d toolsets enabled, more than the maximum of %d", enabled, tg.maxEnabled)
+	}
 	return nil
 }
 
@@ -233,11 +368,101 @@ func (tg *ToolsetGroup) EnableToolset(name string) error {
 	if !exists {
 		return NewToolsetDoesNotExistError(name)
 	}
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
 	toolset.Enabled = true
-	tg.Toolsets[name] = toolset
 	return nil
 }
 
+// DisableToolset marks the toolset called name as disabled. Its tools are
+// left for the caller to remove from the server.
+func (tg *ToolsetGroup) DisableToolset(name string) error {
+	toolset, exists := tg.Toolsets[name]
+	if !exists {
+		return NewToolsetDoesNotExistError(name)
+	}
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	toolset.Enabled = false
+	return nil
+}
+
+// SetMaxEnabled caps the number of toolsets enabled at once by EnableToolsets
+// and EnableToolsetWithEviction. Zero means no cap.
+func (tg *ToolsetGroup) SetMaxEnabled(n int) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	tg.maxEnabled = n
+}
+
+// MarkUsed records a call to the tool called toolName as a use of its
+// toolset, for EnableToolsetWithEviction to pick the least recently used
+// toolsets.
+func (tg *ToolsetGroup) MarkUsed(toolName string) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	if tg.maxEnabled == 0 {
+		return
+	}
+	for name, toolset := range tg.Toolsets {
+		if toolset.hasTool(toolName) {
+			tg.touch(name)
+			return
+		}
+	}
+}
+
+// touch records a use of the toolset called name. tg.mu must be held.
+func (tg *ToolsetGroup) touch(name string) {
+	if tg.lastUsed == nil {
+		tg.lastUsed = make(map[string]uint64)
+	}
+	tg.clock++
+	tg.lastUsed[name] = tg.clock
+}
+
+// EnableToolsetWithEviction enables the toolset called name and, when that
+// takes the number of enabled toolsets over the cap set with SetMaxEnabled,
+// disables the least recently used others. Toolsets never used since they
+// were enabled go first, in name order. It returns the disabled toolsets,
+// whose tools are left for the caller to remove from the server.
+func (tg *ToolsetGroup) EnableToolsetWithEviction(name string) ([]*Toolset, error) {
+	toolset, exists := tg.Toolsets[name]
+	if !exists {
+		return nil, NewToolsetDoesNotExistError(name)
+	}
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	toolset.Enabled = true
+	tg.touch(name)
+	if tg.maxEnabled == 0 {
+		return nil, nil
+	}
+
+	var enabled []string
+	for other, ts := range tg.Toolsets {
+		if ts.Enabled && other != name {
+			enabled = append(enabled, other)
+		}
+	}
+	excess := len(enabled) + 1 - tg.maxEnabled
+	if excess <= 0 {
+		return nil, nil
+	}
+	sort.Slice(enabled, func(i, j int) bool {
+		if tg.lastUsed[enabled[i]] != tg.lastUsed[enabled[j]] {
+			return tg.lastUsed[enabled[i]] < tg.lastUsed[enabled[j]]
+		}
+		return enabled[i] < enabled[j]
+	})
+	evicted := make([]*Toolset, 0, excess)
+	for _, other := range enabled[:excess] {
+		tg.Toolsets[other].Enabled = false
+		evicted = append(evicted, tg.Toolsets[other])
+	}
+	return evicted, nil
+}
+
 func (tg *ToolsetGroup) RegisterAll(s *server.MCPServer) {
 	for _, toolset := range tg.Toolsets {
 		toolset.RegisterTools(s)
@@ -246,6 +471,32 @@ func (tg *ToolsetGroup) RegisterAll(s *server.MCPServer) {
 	}
 }
 
+// IsWriteTool reports whether name is a write tool of any toolset, enabled or not.
+func (tg *ToolsetGroup) IsWriteTool(name string) bool {
+	for _, toolset := range tg.Toolsets {
+		for _, tool := range toolset.writeTools {
+			if tool.Tool.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsDestructiveTool reports whether name is a tool of any toolset, enabled or
+// not, annotated as destructive.
+func (tg *ToolsetGroup) IsDestructiveTool(name string) bool {
+	for _, toolset := range tg.Toolsets {
+		for _, tool := range toolset.writeTools {
+			if tool.Tool.Name == name {
+				hint := tool.Tool.Annotations.DestructiveHint
+				return hint != nil && *hint
+			}
+		}
+	}
+	return false
+}
+
 func (tg *ToolsetGroup) GetToolset(name string) (*Toolset, error) {
 	toolset, exists := tg.Toolsets[name]
 	if !exists {
@@ -3,6 +3,8 @@ package toolsets
 import (
 	"errors"
 	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func TestNewToolsetGroupIsEmptyWithoutEverythingOn(t *testing.T) {
@@ -250,3 +252,172 @@ func TestToolsetGroup_GetToolset(t *testing.T) {
 		t.Errorf("expected error to be ToolsetDoesNotExistError, got %v", err)
 	}
 }
+
+func TestToolsetGroup_IsWriteTool(t *testing.T) {
+	readOnly := false
+	tsg := NewToolsetGroup(false)
+	toolset := NewToolset("my-toolset", "desc")
+	toolset.AddWriteTools(NewServerTool(mcp.NewTool("create_thing", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly})), nil))
+	tsg.AddToolset(toolset)
+
+	// Write tools are known whether or not their toolset is enabled
+	if !tsg.IsWriteTool("create_thing") {
+		t.Error("expected create_thing to be a write tool")
+	}
+	if tsg.IsWriteTool("get_thing") {
+		t.Error("expected get_thing not to be a write tool")
+	}
+}
+
+func TestToolsetGroup_IsDestructiveTool(t *testing.T) {
+	readOnly, destructive := false, true
+	tsg := NewToolsetGroup(false)
+	toolset := NewToolset("my-toolset", "desc")
+	toolset.AddWriteTools(
+		NewServerTool(mcp.NewTool("create_thing", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly})), nil),
+		NewServerTool(mcp.NewTool("delete_thing", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly, DestructiveHint: &destructive})), nil),
+	)
+	tsg.AddToolset(toolset)
+
+	if !tsg.IsDestructiveTool("delete_thing") {
+		t.Error("expected delete_thing to be a destructive tool")
+	}
+	if tsg.IsDestructiveTool("create_thing") {
+		t.Error("expected create_thing not to be a destructive tool")
+	}
+}
+
+func TestToolsetGroup_SetToolFilter(t *testing.T) {
+	readOnly, notReadOnly := true, false
+	toolset := NewToolset("my-toolset", "desc")
+	toolset.AddReadTools(
+		NewServerTool(mcp.NewTool("get_thing", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly})), nil),
+		NewServerTool(mcp.NewTool("list_things", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly})), nil),
+	)
+	toolset.AddWriteTools(
+		NewServerTool(mcp.NewTool("create_thing", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &notReadOnly})), nil),
+		NewServerTool(mcp.NewTool("delete_thing", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &notReadOnly})), nil),
+	)
+	tsg := NewToolsetGroup(false)
+	filter, err := NewToolFilter([]string{"get_*", "*_thing"}, []string{"delete_*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tsg.SetToolFilter(filter)
+	// The filter also applies to toolsets added afterwards
+	tsg.AddToolset(toolset)
+
+	if tools := toolset.GetActiveTools(); len(tools) != 0 {
+		t.Errorf("expected no active tools while the toolset is disabled, got %d", len(tools))
+	}
+	if err := tsg.EnableToolset("my-toolset"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, tool := range toolset.GetActiveTools() {
+		names = append(names, tool.Tool.Name)
+	}
+	if len(names) != 2 || names[0] != "get_thing" || names[1] != "create_thing" {
+		t.Errorf("expected get_thing and create_thing, got %v", names)
+	}
+	if len(toolset.GetAvailableTools()) != 2 {
+		t.Errorf("expected filtered tools not to be available, got %d", len(toolset.GetAvailableTools()))
+	}
+	// Filtered tools keep their kind, for middleware checking calls by name
+	if !tsg.IsWriteTool("delete_thing") {
+		t.Error("expected delete_thing to be a write tool")
+	}
+
+	if _, err := NewToolFilter(nil, []string{"delete_["}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+	narrowed, err := NewToolFilter([]string{"get_*", "list_*"}, nil, []string{"*"}, []string{"get_*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !narrowed.Allows("get_thing") || narrowed.Allows("list_things") {
+		t.Error("expected only the tools matching every list to be allowed")
+	}
+	if filter, _ := NewToolFilter(nil, nil, nil); filter != nil {
+		t.Error("expected no filter for empty lists")
+	}
+	var none *ToolFilter
+	if !none.Allows("anything") {
+		t.Error("expected a nil filter to allow every tool")
+	}
+}
+
+func TestToolsetGroup_EnableToolsetWithEviction(t *testing.T) {
+	readOnly := true
+	tsg := NewToolsetGroup(false)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		toolset := NewToolset(name, "desc")
+		toolset.AddReadTools(NewServerTool(mcp.NewTool("get_"+name, mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: &readOnly})), nil))
+		tsg.AddToolset(toolset)
+	}
+	if err := tsg.EnableToolsets([]string{"a", "b"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Without a cap nothing is evicted
+	evicted, err := tsg.EnableToolsetWithEviction("c")
+	if err != nil || len(evicted) != 0 {
+		t.Fatalf("expected no eviction, got %v, %v", evicted, err)
+	}
+	if err := tsg.DisableToolset("c"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tsg.IsEnabled("c") {
+		t.Error("expected c to be disabled")
+	}
+
+	tsg.SetMaxEnabled(2)
+	tsg.MarkUsed("get_a")
+	// b was never used, so it goes first
+	evicted, err = tsg.EnableToolsetWithEviction("c")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(evicted) != 1 || evicted[0].Name != "b" || tsg.IsEnabled("b") {
+		t.Fatalf("expected b to be evicted, got %v", evicted)
+	}
+	// c was enabled after a was last used
+	evicted, _ = tsg.EnableToolsetWithEviction("d")
+	if len(evicted) != 1 || evicted[0].Name != "a" {
+		t.Fatalf("expected a to be evicted, got %v", evicted)
+	}
+	tsg.MarkUsed("get_c")
+	evicted, _ = tsg.EnableToolsetWithEviction("b")
+	if len(evicted) != 1 || evicted[0].Name != "d" {
+		t.Fatalf("expected d to be evicted, got %v", evicted)
+	}
+	if !tsg.IsEnabled("b") || !tsg.IsEnabled("c") {
+		t.Error("expected b and c to be enabled")
+	}
+
+	if _, err := tsg.EnableToolsetWithEviction("nope"); !errors.Is(err, NewToolsetDoesNotExistError("nope")) {
+		t.Errorf("expected ToolsetDoesNotExistError, got %v", err)
+	}
+	if err := tsg.DisableToolset("nope"); !errors.Is(err, NewToolsetDoesNotExistError("nope")) {
+		t.Errorf("expected ToolsetDoesNotExistError, got %v", err)
+	}
+}
+
+func TestEnableToolsetsWithMaxEnabled(t *testing.T) {
+	tsg := NewToolsetGroup(false)
+	for _, name := range []string{"a", "b", "c"} {
+		tsg.AddToolset(NewToolset(name, "desc"))
+	}
+	tsg.SetMaxEnabled(2)
+
+	if err := tsg.EnableToolsets([]string{"a", "b"}); err != nil {
+		t.Fatalf("expected no error at the cap, got: %v", err)
+	}
+	// Enabling a toolset twice does not count against the cap
+	if err := tsg.EnableToolsets([]string{"a"}); err != nil {
+		t.Fatalf("expected no error re-enabling a toolset, got: %v", err)
+	}
+	if err := tsg.EnableToolsets([]string{"c"}); err == nil {
+		t.Error("expected an error when enabling more toolsets than the cap")
+	}
+}
@@ -0,0 +1,139 @@
+package translations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is the locale of the text built into the server.
+const DefaultLocale = "en"
+
+// Bundle is the translations of a locale, keyed by translation key. A bundle
+// file, <locale>.json, has the format of the file written by
+// DumpTranslationKeyMap.
+type Bundle map[string]string
+
+// FallbackChain returns the locales to look translations up in, in order:
+// each of locales followed by its parent locales, so that pt-BR, es gives
+// pt-BR, pt, es. Underscores are read as hyphens, and repeated locales are
+// dropped.
+func FallbackChain(locales ...string) []string {
+	var chain []string
+	seen := make(map[string]bool)
+	for _, locale := range locales {
+		locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+		for locale != "" {
+			if !seen[strings.ToLower(locale)] {
+				seen[strings.ToLower(locale)] = true
+				chain = append(chain, locale)
+			}
+			i := strings.LastIndex(locale, "-")
+			if i < 0 {
+				break
+			}
+			locale = locale[:i]
+		}
+	}
+	return chain
+}
+
+// ReadBundle reads the bundle file at path.
+func ReadBundle(path string) (Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse locale bundle %s: %w", path, err)
+	}
+	bundle := make(Bundle, len(raw))
+	for key, value := range raw {
+		bundle[strings.ToUpper(key)] = value
+	}
+	return bundle, nil
+}
+
+// LoadBundle reads the bundles in dir of the fallback chain of locales and
+// merges them, earlier locales taking precedence. Locales without a bundle are
+// skipped, but it is an error if no locale of the chain has one, unless the
+// chain includes DefaultLocale, whose text is built in. Text missing from
+// every bundle falls back to the built-in text.
+func LoadBundle(dir string, locales ...string) (Bundle, error) {
+	chain := FallbackChain(locales...)
+	bundle := Bundle{}
+	found := false
+	for i := len(chain) - 1; i >= 0; i-- {
+		b, err := ReadBundle(filepath.Join(dir, chain[i]+".json"))
+		if errors.Is(err, fs.ErrNotExist) {
+			found = found || strings.EqualFold(chain[i], DefaultLocale)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		for key, value := range b {
+			bundle[key] = value
+		}
+	}
+	if !found && len(chain) > 0 {
+		return nil, fmt.Errorf("no locale bundle for %s in %s", strings.Join(chain, ", "), dir)
+	}
+	return bundle, nil
+}
+
+// BundleLocales returns the locales of the bundles in dir.
+func BundleLocales(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var locales []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			locales = append(locales, name)
+		}
+	}
+	sort.Strings(locales)
+	return locales, nil
+}
+
+// Check compares bundle with keys, the translation keys of the server, and
+// returns the keys missing from bundle and the keys of bundle the server does
+// not use, both sorted.
+func Check(keys map[string]string, bundle Bundle) (missing, unused []string) {
+	for key := range keys {
+		if _, ok := bundle[strings.ToUpper(key)]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	used := make(map[string]bool, len(keys))
+	for key := range keys {
+		used[strings.ToUpper(key)] = true
+	}
+	for key := range bundle {
+		if !used[key] {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unused)
+	return missing, unused
+}
+
+// KeyRecorder returns a TranslationHelperFunc returning the default text of
+// every key, and the map it records the keys and their default text in.
+func KeyRecorder() (TranslationHelperFunc, map[string]string) {
+	keys := make(map[string]string)
+	return func(key string, defaultValue string) string {
+		keys[strings.ToUpper(key)] = defaultValue
+		return defaultValue
+	}, keys
+}
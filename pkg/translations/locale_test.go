@@ -0,0 +1,76 @@
+package translations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeBundle(t *testing.T, dir, locale, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, locale+".json"), []byte(content), 0o600))
+}
+
+func TestFallbackChain(t *testing.T) {
+	assert.Equal(t, []string{"pt-BR", "pt", "es"}, FallbackChain("pt-BR", "es"))
+	assert.Equal(t, []string{"zh-Hant-TW", "zh-Hant", "zh"}, FallbackChain("zh_Hant_TW", "zh"))
+	assert.Empty(t, FallbackChain())
+}
+
+func TestLoadBundle(t *testing.T) {
+	dir := t.TempDir()
+	writeBundle(t, dir, "pt", `{"tool_get_me_description": "Detalhes do usuário", "TOOL_GET_ME_USER_TITLE": "Meu perfil"}`)
+	writeBundle(t, dir, "pt-BR", `{"TOOL_GET_ME_USER_TITLE": "Meu perfil no GitHub"}`)
+	writeBundle(t, dir, "es", `{"TOOL_GET_ISSUE_DESCRIPTION": "Obtener una incidencia"}`)
+
+	bundle, err := LoadBundle(dir, "pt-BR", "es")
+	require.NoError(t, err)
+	assert.Equal(t, Bundle{
+		"TOOL_GET_ME_USER_TITLE":     "Meu perfil no GitHub",
+		"TOOL_GET_ME_DESCRIPTION":    "Detalhes do usuário",
+		"TOOL_GET_ISSUE_DESCRIPTION": "Obtener una incidencia",
+	}, bundle)
+
+	_, err = LoadBundle(dir, "fr-CA")
+	assert.ErrorContains(t, err, "no locale bundle for fr-CA, fr")
+	// English is built in
+	bundle, err = LoadBundle(dir, "en-GB")
+	require.NoError(t, err)
+	assert.Empty(t, bundle)
+
+	writeBundle(t, dir, "de", `not json`)
+	_, err = LoadBundle(dir, "de")
+	assert.ErrorContains(t, err, "failed to parse locale bundle")
+
+	locales, err := BundleLocales(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"de", "es", "pt", "pt-BR"}, locales)
+}
+
+func TestTranslationHelperWithLocale(t *testing.T) {
+	t.Setenv("GITHUB_MCP_TOOL_GET_ISSUE_DESCRIPTION", "From the environment")
+	bundle := Bundle{
+		"TOOL_GET_ME_DESCRIPTION":    "Detalhes do usuário",
+		"TOOL_GET_ISSUE_DESCRIPTION": "Obter uma issue",
+		"TOOL_GET_FILE_DESCRIPTION":  "Obter um arquivo",
+	}
+	helper, _ := TranslationHelperWithLocale(map[string]string{"TOOL_GET_FILE_DESCRIPTION": "Overridden"}, bundle)
+
+	assert.Equal(t, "Detalhes do usuário", helper("TOOL_GET_ME_DESCRIPTION", "Get my user"))
+	assert.Equal(t, "From the environment", helper("TOOL_GET_ISSUE_DESCRIPTION", "Get an issue"))
+	assert.Equal(t, "Overridden", helper("TOOL_GET_FILE_DESCRIPTION", "Get a file"))
+	assert.Equal(t, "List issues", helper("TOOL_LIST_ISSUES_DESCRIPTION", "List issues"))
+}
+
+func TestCheck(t *testing.T) {
+	record, keys := KeyRecorder()
+	assert.Equal(t, "Get my user", record("tool_get_me_description", "Get my user"))
+	record("TOOL_GET_ISSUE_DESCRIPTION", "Get an issue")
+
+	missing, unused := Check(keys, Bundle{"TOOL_GET_ME_DESCRIPTION": "Detalhes", "TOOL_REMOVED_DESCRIPTION": "Removida"})
+	assert.Equal(t, []string{"TOOL_GET_ISSUE_DESCRIPTION"}, missing)
+	assert.Equal(t, []string{"TOOL_REMOVED_DESCRIPTION"}, unused)
+}
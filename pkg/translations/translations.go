@@ -17,6 +17,20 @@ func NullTranslationHelper(_ string, defaultValue string) string {
 }
 
 func TranslationHelper() (TranslationHelperFunc, func()) {
+	return TranslationHelperWithOverrides(nil)
+}
+
+// TranslationHelperWithOverrides is TranslationHelper with overrides taking
+// precedence over github-mcp-server-config.json but not over GITHUB_MCP_
+// environment variables.
+func TranslationHelperWithOverrides(overrides map[string]string) (TranslationHelperFunc, func()) {
+	return TranslationHelperWithLocale(overrides, nil)
+}
+
+// TranslationHelperWithLocale is TranslationHelperWithOverrides with the text
+// of bundle, from LoadBundle, used for keys that neither the environment, the
+// overrides nor github-mcp-server-config.json set.
+func TranslationHelperWithLocale(overrides map[string]string, bundle Bundle) (TranslationHelperFunc, func()) {
 	var translationKeyMap = map[string]string{}
 	v := viper.New()
 
@@ -45,6 +59,16 @@ func TranslationHelper() (TranslationHelperFunc, func()) {
 				return value
 			}
 
+			if value, exists := overrides[key]; exists {
+				translationKeyMap[key] = value
+				return value
+			}
+
+			if value, exists := bundle[key]; exists && !v.IsSet(key) {
+				translationKeyMap[key] = value
+				return value
+			}
+
 			v.SetDefault(key, defaultValue)
 			translationKeyMap[key] = v.GetString(key)
 			return translationKeyMap[key]